@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger"
 	"github.com/andreaskoch/allmark/common/logger/console"
 	"github.com/andreaskoch/allmark/common/logger/loglevel"
 	"github.com/andreaskoch/allmark/common/shutdown"
@@ -33,6 +34,15 @@ const (
 	// CommandNameServe contains the name of the serve action
 	CommandNameServe = "serve"
 
+	// CommandNamePlan contains the name of the plan action
+	CommandNamePlan = "plan"
+
+	// CommandNameRender contains the name of the render action
+	CommandNameRender = "render"
+
+	// CommandNameExport contains the name of the export action
+	CommandNameExport = "export"
+
 	// CommandNameVersion contains the name of the version action
 	CommandNameVersion = "version"
 )
@@ -45,6 +55,11 @@ var (
 	logLevelOverride = serveFlags.String("loglevel", "", "Log level")
 	reindex          = serveFlags.Bool("reindex", false, "Enable reindexing")
 	livereload       = serveFlags.Bool("livereload", false, "Enable live-reload")
+	preview          = serveFlags.Bool("preview", false, "Include drafts")
+	typographer      = serveFlags.Bool("typographer", false, "Convert quotes, dashes and ellipses to their typographic forms")
+	checkLinks       = serveFlags.Bool("checklinks", false, "Report broken internal links after rendering")
+	strictLinks      = serveFlags.Bool("strictlinks", false, "Fail the render if there are broken internal links")
+	exportOutput     = serveFlags.String("output", "site.zip", "The ZIP file the exported site is written to")
 )
 
 func main() {
@@ -78,6 +93,18 @@ func main() {
 			serve(repositoryPath)
 			return true
 
+		case CommandNamePlan:
+			plan(repositoryPath)
+			return true
+
+		case CommandNameRender:
+			render(repositoryPath)
+			return true
+
+		case CommandNameExport:
+			export(repositoryPath)
+			return true
+
 		case CommandNameVersion:
 			printVersionInformation()
 			return true
@@ -148,6 +175,9 @@ func printUsageInformation(args []string) {
 	fmt.Fprintf(os.Stderr, "\nAvailable commands:\n")
 	fmt.Fprintf(os.Stderr, "  %7s  %s\n", CommandNameInit, "Initialize the configuration")
 	fmt.Fprintf(os.Stderr, "  %7s  %s\n", CommandNameServe, "Start serving the supplied repository via HTTP and HTTPs")
+	fmt.Fprintf(os.Stderr, "  %7s  %s\n", CommandNamePlan, "Print which items a render would write, skip or delete (JSON)")
+	fmt.Fprintf(os.Stderr, "  %7s  %s\n", CommandNameRender, "Render all changed items of the supplied repository into static files")
+	fmt.Fprintf(os.Stderr, "  %7s  %s\n", CommandNameExport, "Render the supplied repository into a ZIP file (-output)")
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "Fork me on GitHub %q\n", "https://github.com/andreaskoch/allmark")
 
@@ -176,6 +206,16 @@ func serve(repositoryPath string) bool {
 		configuration.LiveReload.Enabled = true
 	}
 
+	// check if drafts shall be included
+	if *preview {
+		configuration.Web.Preview = true
+	}
+
+	// check if the typographer is enabled
+	if *typographer {
+		configuration.Markdown.Typographer = true
+	}
+
 	// create a logger
 	logger := console.New(loglevel.FromString(configuration.LogLevel))
 	if *logLevelOverride != "" {
@@ -202,7 +242,7 @@ func serve(repositoryPath string) bool {
 		thumbnailIndex = thumbnail.NewIndex(logger, thumbnailIndexFilePath, thumbnailFolder)
 
 		// thumbnail conversion service
-		thumbnail.NewConversionService(logger, repository, thumbnailIndex)
+		thumbnail.NewConversionService(logger, repository, thumbnailIndex, thumbnail.GallerySize(configuration.ThumbnailMaxDimension()))
 
 	}
 
@@ -227,6 +267,126 @@ func serve(repositoryPath string) bool {
 	return true
 }
 
+// plan prints the render plan of the supplied repository as JSON without rendering anything.
+func plan(repositoryPath string) bool {
+
+	server, logger := newRenderServer(repositoryPath)
+	if server == nil {
+		return false
+	}
+
+	renderPlan, err := server.RenderPlan()
+	if err != nil {
+		logger.Error("Unable to create the render plan. Error: %s", err.Error())
+		return false
+	}
+
+	fmt.Println(renderPlan.JSON())
+	return true
+}
+
+// render renders all changed items of the supplied repository and prints the executed plan as JSON.
+func render(repositoryPath string) bool {
+
+	server, logger := newRenderServer(repositoryPath)
+	if server == nil {
+		return false
+	}
+
+	renderReport, err := server.Render()
+	if err != nil {
+		logger.Error("Unable to render the repository. Error: %s", err.Error())
+		return false
+	}
+
+	fmt.Println(renderReport.Plan.JSON())
+
+	if !*checkLinks && !*strictLinks {
+		return true
+	}
+
+	brokenLinks, err := server.CheckLinks()
+	if err != nil {
+		logger.Error("Unable to check the links of the rendered items. Error: %s", err.Error())
+		return false
+	}
+
+	// report the broken links regardless of the log level
+	for _, brokenLink := range brokenLinks {
+		fmt.Fprintf(os.Stderr, "Broken link in %q: %q does not exist.\n", brokenLink.Source, brokenLink.Target)
+	}
+
+	if len(brokenLinks) > 0 && *strictLinks {
+		logger.Error("The rendered items contain %d broken link(s).", len(brokenLinks))
+		os.Exit(1)
+	}
+
+	return true
+}
+
+// export renders the complete repository and writes the rendered files to the ZIP file supplied with the output flag.
+func export(repositoryPath string) bool {
+
+	server, logger := newRenderServer(repositoryPath)
+	if server == nil {
+		return false
+	}
+
+	file, err := os.Create(*exportOutput)
+	if err != nil {
+		logger.Error("Unable to create the export file %q. Error: %s", *exportOutput, err.Error())
+		return false
+	}
+
+	defer file.Close()
+
+	report, err := server.Export(file)
+	if err != nil {
+		logger.Error("Unable to export the repository. Error: %s", err.Error())
+		return false
+	}
+
+	logger.Info("Exported %d items, %d redirects, %d feeds and %d assets to %q.", report.Rendered, report.Redirects, report.Feeds, report.Assets, *exportOutput)
+	return true
+}
+
+// newRenderServer creates a server for the supplied repository which is not started
+// but only used to render the items of the repository.
+func newRenderServer(repositoryPath string) (*server.Server, logger.Logger) {
+
+	configuration := config.Get(repositoryPath)
+	if *preview {
+		configuration.Web.Preview = true
+	}
+
+	if *typographer {
+		configuration.Markdown.Typographer = true
+	}
+
+	logger := console.New(loglevel.FromString(configuration.LogLevel))
+	if *logLevelOverride != "" {
+		logger = console.New(loglevel.FromString(*logLevelOverride))
+	}
+
+	repository, err := filesystem.NewRepository(logger, repositoryPath, *configuration)
+	if err != nil {
+		logger.Fatal("Unable to create a repository. Error: %s", err)
+	}
+
+	itemParser, err := parser.New(logger)
+	if err != nil {
+		logger.Fatal("Unable to instantiate a parser. Error: %s", err)
+	}
+
+	renderServer, err := server.New(logger, *configuration, repository, itemParser, thumbnail.EmptyIndex())
+	if err != nil {
+		logger.Error("Unable to instantiate a server. Error: %s", err.Error())
+		return nil, logger
+	}
+
+	return renderServer, logger
+}
+
 func initialize(repositoryPath string) bool {
 
 	config := config.Get(repositoryPath)
@@ -14,6 +14,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/andreaskoch/allmark/common/certificates"
 	"github.com/andreaskoch/allmark/common/logger/loglevel"
@@ -29,10 +32,14 @@ const (
 	FilesDirectoryName     = "files"
 	ConfigurationFileName  = "config"
 	ThemeFolderName        = "theme"
+	ThemesFolderName       = "themes"
 	TemplatesFolderName    = "templates"
 	ThumbnailIndexFileName = "thumbnail.index"
 	ThumbnailsFolderName   = "thumbnails"
 	SSLCertsFolderName     = "certs"
+	RenderManifestFileName = "render.manifest"
+	RenderReportFileName   = "render.report.json"
+	RenderFolderName       = "render"
 )
 
 // Global default values.
@@ -47,18 +54,49 @@ const (
 	DefaultLogLevel                  = loglevel.Error
 	DefaultIndexingEnabled           = false
 	DefaultIndexingIntervalInSeconds = 60
+	DefaultIndexingChildOrder        = "filename asc"
 	DefaultLiveReloadEnabled         = false
+	DefaultLiveReloadDebounceTime    = 250 // milliseconds
+	DefaultCommentsEnabled           = false
+	DefaultCommentsInterval          = 60 // seconds
 	DefaultConversionDocxEnabled     = true
+	DefaultConversionPDFEnabled      = true
+	DefaultThumbnailMaxDimension     = 300
+	DefaultFeedItemCount             = 20
+	DefaultChildrenPageSize          = 50
+	DefaultCompressionMinimumSize    = 1024
+	DefaultPresentationGotoKey       = 71 // 'g'
+	DefaultPresentationToggleKey     = 16 // <shift> (together with <ctrl>)
+	DefaultPresentationNotesKey      = 78 // 'n'
+	DefaultPresentationTimerPauseKey = 80 // 'p'
+	DefaultPresentationDuration      = 20 // minutes
+	DefaultPresentationWarningTime   = 5  // minutes
+	DefaultPresentationSwipeDistance = 50 // pixels
+	DefaultMathDelimiters            = MathDelimitersDollars
+	DefaultThemeName                 = "default"
+	DefaultMathKaTeXURL              = "https://cdn.jsdelivr.net/npm/katex@0.16.3/dist"
 	DefaultAuthenticationEnabled     = false
 	DefaultUserStoreFileName         = "users.htpasswd"
 )
 
+// The supported math delimiters.
+const (
+	// MathDelimitersDollars selects $...$ for inline and $$...$$ for block math.
+	MathDelimitersDollars = "dollars"
+
+	// MathDelimitersBrackets selects \(...\) for inline and \[...\] for block math.
+	MathDelimitersBrackets = "brackets"
+)
+
 // homeDirectory returns the current users home directory path.
 var homeDirectory func() string
 
 // A flag indicating whether the DOCX conversion tool is available
 var docxConversionToolIsAvailable bool
 
+// A flag indicating whether the PDF conversion tool is available
+var pdfConversionToolIsAvailable bool
+
 var conversionEndpointBinding *TCPBinding
 
 func init() {
@@ -78,6 +116,11 @@ func init() {
 		docxConversionToolIsAvailable = true
 	}
 
+	// check if wkhtmltopdf is available in the path
+	if err := exec.Command(DefaultPDFConversionToolPath, "--version").Run(); err == nil {
+		pdfConversionToolIsAvailable = true
+	}
+
 	// conversion endpoint binding
 	conversionEndpointBinding = &TCPBinding{
 		Network: "tcp4",
@@ -189,7 +232,13 @@ func Default(baseFolder string) *Config {
 	config.Server.Authentication.Enabled = DefaultAuthenticationEnabled
 	config.Server.Authentication.UserStoreFileName = DefaultUserStoreFileName
 
+	// Compression
+	config.Server.CompressionMinimumSize = DefaultCompressionMinimumSize
+
 	config.Web.DefaultLanguage = DefaultLanguage
+	config.Web.FeedItemCount = DefaultFeedItemCount
+	config.Web.ChildrenPageSize = DefaultChildrenPageSize
+	config.Web.Theme = DefaultThemeName
 
 	// Publisher Information
 	config.Web.Publisher = UserInformation{}
@@ -202,19 +251,42 @@ func Default(baseFolder string) *Config {
 	// Thumbnail conversion
 	config.Conversion.Thumbnails.IndexFileName = ThumbnailIndexFileName
 	config.Conversion.Thumbnails.FolderName = ThumbnailsFolderName
+	config.Conversion.Thumbnails.MaxDimension = DefaultThumbnailMaxDimension
 
 	// DOCX Conversion
 	config.Conversion.DOCX.Enabled = DefaultConversionDocxEnabled
 
+	// PDF Conversion
+	config.Conversion.PDF.Enabled = DefaultConversionPDFEnabled
+
 	// Logging
 	config.LogLevel = DefaultLogLevel.String()
 
 	// Indexing
 	config.Indexing.Enabled = DefaultIndexingEnabled
 	config.Indexing.IntervalInSeconds = DefaultIndexingIntervalInSeconds
+	config.Indexing.ChildOrder = DefaultIndexingChildOrder
 
 	// Live-Reload
 	config.LiveReload.Enabled = DefaultLiveReloadEnabled
+	config.LiveReload.DebounceIntervalInMilliseconds = DefaultLiveReloadDebounceTime
+
+	// Comments
+	config.Comments.Enabled = DefaultCommentsEnabled
+	config.Comments.MinimumIntervalInSeconds = DefaultCommentsInterval
+
+	// Presentations
+	config.Presentation.GotoKey = DefaultPresentationGotoKey
+	config.Presentation.ToggleKey = DefaultPresentationToggleKey
+	config.Presentation.NotesKey = DefaultPresentationNotesKey
+	config.Presentation.TimerPauseKey = DefaultPresentationTimerPauseKey
+	config.Presentation.Duration = DefaultPresentationDuration
+	config.Presentation.WarningTime = DefaultPresentationWarningTime
+	config.Presentation.SwipeDistance = DefaultPresentationSwipeDistance
+
+	// Math
+	config.Math.Delimiters = DefaultMathDelimiters
+	config.Math.KaTeXURL = DefaultMathKaTeXURL
 
 	return config
 }
@@ -287,10 +359,38 @@ type Authentication struct {
 
 	// UserStoreFileName defines the file name for the authentication user-store file (e.g. "users.htpasswd").
 	UserStoreFileName string
+
+	// Username and Password define a single user which is used instead of the user-store file if both are set.
+	Username string
+	Password string
 }
 
 // Web contains all web-site related properties such as the language, authors and publisher information.
 type Web struct {
+	// BaseURL is the base URL of the site (e.g. "https://example.com") which is used for absolute links
+	// in sitemaps and feeds. If empty, the base URL is derived from the request.
+	BaseURL string
+
+	// FeedItemCount is the maximum number of items per page of the RSS feed.
+	FeedItemCount int
+
+	// ChildrenPageSize is the maximum number of child items which are listed on a single page.
+	ChildrenPageSize int
+
+	// RobotsTxtDisallow contains the path prefixes (e.g. "drafts/") which crawlers should not index.
+	RobotsTxtDisallow []string
+
+	// Theme is the name of the active theme: a built-in theme (e.g. "default")
+	// or a theme with a manifest (theme.json) in a sub folder of the themes folder.
+	Theme string
+
+	// Preview publishes draft items as well (e.g. to review them locally).
+	Preview bool
+
+	// SocialImage is the path (e.g. "/theme/logo.png") or URL of the image which is used in the
+	// OpenGraph and Twitter Card meta tags of items which don't have an image of their own.
+	SocialImage string
+
 	DefaultLanguage string
 	DefaultAuthor   string
 	Publisher       UserInformation
@@ -315,25 +415,64 @@ type Server struct {
 	HTTP            HTTP
 	HTTPS           HTTPS
 	Authentication  Authentication
+
+	// CompressionMinimumSize is the minimum size (in bytes) of responses which are compressed.
+	CompressionMinimumSize int
+
+	// Minify enables the removal of comments and redundant whitespace from all HTML, CSS and JavaScript
+	// responses and rendered files (default: false).
+	Minify bool
+
+	// FingerprintAssets enables content-hash fingerprints in the URLs of theme files and item files
+	// (e.g. "/theme/presentation.<hash>.js") so they can be cached forever (default: false).
+	FingerprintAssets bool
 }
 
 // Indexing defines the reindexing parameters of the repository.
 type Indexing struct {
 	Enabled           bool
 	IntervalInSeconds int
+
+	// ChildOrder defines how the children of an item are sorted
+	// ("filename", "title" or "date", optionally followed by "asc" or "desc").
+	ChildOrder string
+
+	// Workers defines the number of items that are parsed in parallel
+	// (default: 0 = one worker per CPU).
+	Workers int
 }
 
 // LiveReload defines the live-reload capabilities.
 type LiveReload struct {
 	Enabled bool
+
+	// DebounceIntervalInMilliseconds defines the time window in which
+	// multiple file system changes are combined into a single update.
+	DebounceIntervalInMilliseconds int
+}
+
+// Comments defines whether and how often readers can submit comments.
+type Comments struct {
+	// Enabled allows readers to submit comments which are stored as comment items below the commented item.
+	Enabled bool
+
+	// MinimumIntervalInSeconds is the minimum time between two comments from the same client.
+	MinimumIntervalInSeconds int
 }
 
 // Conversion defines the rich-text and thumbnail conversion paramters.
 type Conversion struct {
 	DOCX       DOCXConversion
+	PDF        PDFConversion
 	Thumbnails ThumbnailConversion
 }
 
+// EndpointIsRequired returns a flag indicating whether the local conversion endpoint
+// is needed by any of the enabled conversions.
+func (c Conversion) EndpointIsRequired() bool {
+	return c.DOCX.IsEnabled() || c.PDF.IsEnabled()
+}
+
 // EndpointBinding returns the TCPBinding of the conversion endpoint
 func (c Conversion) EndpointBinding() *TCPBinding {
 	return conversionEndpointBinding
@@ -356,11 +495,31 @@ func (docx DOCXConversion) IsEnabled() bool {
 	return docx.Enabled && docxConversionToolIsAvailable
 }
 
+// PDFConversion contains the parameters of the PDF conversion of presentations.
+type PDFConversion struct {
+	Enabled bool
+}
+
+// Tool returns the path of the external PDF conversion tool (wkhtmltopdf) used
+// to create PDF documents from presentations.
+func (pdf PDFConversion) Tool() string {
+	return DefaultPDFConversionToolPath
+}
+
+// IsEnabled returns a flag indicating if PDF conversion is enabled or not.
+// PDF conversion can only be enabled if the conversion tool was found in the PATH on startup.
+func (pdf PDFConversion) IsEnabled() bool {
+	return pdf.Enabled && pdfConversionToolIsAvailable
+}
+
 // ThumbnailConversion defines the image-thumbnail conversion capabilities.
 type ThumbnailConversion struct {
 	Enabled       bool
 	IndexFileName string
 	FolderName    string
+
+	// MaxDimension is the maximum width and height of the gallery thumbnails (default: 300).
+	MaxDimension uint
 }
 
 // Analytics defines the web-analytics parameters of the web-server.
@@ -375,6 +534,96 @@ type GoogleAnalytics struct {
 	TrackingID string
 }
 
+// Presentation contains the keyboard shortcuts, the touch navigation, the progress indicator and the timer settings of presentations.
+type Presentation struct {
+	// GotoKey is the key code of the key which opens the "go to slide" dialog (default: 71, 'g').
+	GotoKey int
+
+	// ToggleKey is the key code of the key which toggles the presentation mode if pressed together with <ctrl> (default: 16, <shift>).
+	ToggleKey int
+
+	// NotesKey is the key code of the key which opens the presenter view with the speaker notes (default: 78, 'n').
+	NotesKey int
+
+	// HideProgress disables the slide number and progress indicator.
+	HideProgress bool
+
+	// ProgressAlwaysVisible keeps the progress indicator visible when the presentation mode hides the page elements.
+	ProgressAlwaysVisible bool
+
+	// ShowTimer enables the timer which shows the elapsed time while the presentation mode is active.
+	ShowTimer bool
+
+	// TimerPauseKey is the key code of the key which pauses and resumes the timer (default: 80, 'p').
+	TimerPauseKey int
+
+	// Duration is the planned duration of a presentation in minutes (default: 20).
+	Duration int
+
+	// WarningTime is the remaining time in minutes at which the timer displays a warning (default: 5).
+	WarningTime int
+	// DisableSwipe disables the touch navigation (swipe left for the next and right for the previous slide).
+	DisableSwipe bool
+
+	// SwipeDistance is the minimum horizontal distance in pixels of a swipe (default: 50).
+	SwipeDistance int
+}
+
+// Math contains the settings for the rendering of math expressions with KaTeX.
+type Math struct {
+	// Disabled disables the rendering of math expressions.
+	Disabled bool
+
+	// Delimiters selects the math delimiters: "dollars" ($...$ and $$...$$, default)
+	// or "brackets" (\(...\) and \[...\]; the backslash must be escaped in markdown: \\(...\\)).
+	Delimiters string
+
+	// KaTeXURL is the base URL of the KaTeX distribution (default: the jsDelivr CDN).
+	// The files of the default distribution are verified with their subresource integrity hashes;
+	// if they cannot be loaded the copy in the "vendor/katex" folder of the theme is used.
+	KaTeXURL string
+}
+
+// Markdown contains the extensions of the markdown renderer which is used for the item bodies.
+// All extensions except for the typographer are enabled by default (similar to GitHub flavored markdown).
+type Markdown struct {
+	// DisableTables disables pipe tables.
+	DisableTables bool
+
+	// DisableFootnotes disables footnotes ("text[^1]" and "[^1]: the footnote").
+	DisableFootnotes bool
+
+	// DisableStrikethrough disables strikethrough text ("~~text~~").
+	DisableStrikethrough bool
+
+	// DisableTaskLists disables task lists ("- [ ] open" and "- [x] done"), which are rendered as disabled checkboxes.
+	DisableTaskLists bool
+
+	// Typographer converts straight quotes, dashes and ellipses in the text to their typographic forms
+	// ("quotes" -> “quotes”, "--" -> "–", "---" -> "—", "..." -> "…"). Code is not changed.
+	Typographer bool
+}
+
+// Render contains the settings for rendering the repository into static files.
+type Render struct {
+	// TargetFolder is the folder the rendered files are written to (default: ".allmark/render").
+	// Relative paths are relative to the repository.
+	TargetFolder string
+
+	// ManifestFile is the file which contains the content hashes of the last render (default: ".allmark/render.manifest").
+	// Relative paths are relative to the repository. Delete the file to force a full render.
+	ManifestFile string
+
+	// ReportFile is the file the statistics and timings of the last render are written to (default: ".allmark/render.report.json").
+	// Relative paths are relative to the repository.
+	ReportFile string
+
+	// PermalinkPattern is the path documents, presentations and messages are rendered to
+	// (e.g. "/:year/:month/:slug/"). Available tokens: :year, :month, :day, :slug and :type.
+	// If empty every item is rendered to the folder of its route.
+	PermalinkPattern string
+}
+
 // Config is the main configuration model for all parts of allmark.
 type Config struct {
 	Server     Server
@@ -385,6 +634,12 @@ type Config struct {
 	LiveReload LiveReload
 	Analytics  Analytics
 
+	Presentation Presentation
+	Math         Math
+	Markdown     Markdown
+	Render       Render
+	Comments     Comments
+
 	baseFolder      string
 	metaDataFolder  string
 	themeFolderBase string
@@ -481,6 +736,20 @@ func (config *Config) Filepath() string {
 	return filepath.Join(config.MetaDataFolder(), ConfigurationFileName)
 }
 
+// ThemesFolder returns the path of the folder which contains the selectable themes (one sub folder per theme).
+func (config *Config) ThemesFolder() string {
+	return filepath.Join(config.themeFolderBase, ThemesFolderName)
+}
+
+// ThemeName returns the name of the active theme.
+func (config *Config) ThemeName() string {
+	if name := strings.TrimSpace(config.Web.Theme); name != "" {
+		return name
+	}
+
+	return DefaultThemeName
+}
+
 // ThemeFolder returns the path of the theme folder.
 func (config *Config) ThemeFolder() string {
 	themeFolderName := ThemeFolderName
@@ -491,6 +760,18 @@ func (config *Config) ThemeFolder() string {
 	return filepath.Join(config.themeFolderBase, themeFolderName)
 }
 
+// ThemeOverrideFolder returns the path of the folder whose files override the files of the active theme
+// or an empty string if a theme other than the default theme is selected.
+// The theme folder contains a copy of the default theme (see "allmark init"), so it would otherwise
+// replace every file of the selected theme.
+func (config *Config) ThemeOverrideFolder() string {
+	if config.ThemeName() != DefaultThemeName {
+		return ""
+	}
+
+	return config.ThemeFolder()
+}
+
 // ThumbnailIndexFilePath returns the path of the thumbnail index file.
 func (config *Config) ThumbnailIndexFilePath() string {
 	filename := ThumbnailIndexFileName
@@ -501,6 +782,204 @@ func (config *Config) ThumbnailIndexFilePath() string {
 	return filepath.Join(config.MetaDataFolder(), filename)
 }
 
+// RenderManifestFilePath returns the path of the file which contains the content hashes of the last render.
+func (config *Config) RenderManifestFilePath() string {
+	if config.Render.ManifestFile != "" {
+		return config.repositoryPath(config.Render.ManifestFile)
+	}
+
+	return filepath.Join(config.MetaDataFolder(), RenderManifestFileName)
+}
+
+// RenderReportFilePath returns the path of the file which contains the statistics and timings of the last render.
+func (config *Config) RenderReportFilePath() string {
+	if config.Render.ReportFile != "" {
+		return config.repositoryPath(config.Render.ReportFile)
+	}
+
+	return filepath.Join(config.MetaDataFolder(), RenderReportFileName)
+}
+
+// RenderTargetFolder returns the path of the folder the rendered files are written to.
+func (config *Config) RenderTargetFolder() string {
+	if config.Render.TargetFolder != "" {
+		return config.repositoryPath(config.Render.TargetFolder)
+	}
+
+	return filepath.Join(config.MetaDataFolder(), RenderFolderName)
+}
+
+// repositoryPath returns the supplied path or, if it is relative, the path relative to the base folder.
+func (config *Config) repositoryPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(config.BaseFolder(), path)
+}
+
+// ThumbnailMaxDimension returns the maximum width and height of gallery thumbnails.
+func (config *Config) ThumbnailMaxDimension() uint {
+	if config.Conversion.Thumbnails.MaxDimension > 0 {
+		return config.Conversion.Thumbnails.MaxDimension
+	}
+
+	return DefaultThumbnailMaxDimension
+}
+
+// FeedItemCount returns the configured number of feed items per page or the default if no valid number is configured.
+func (config *Config) FeedItemCount() int {
+	if config.Web.FeedItemCount > 0 {
+		return config.Web.FeedItemCount
+	}
+
+	return DefaultFeedItemCount
+}
+
+// ChildrenPageSize returns the configured number of child items per page or the default if no valid number is configured.
+func (config *Config) ChildrenPageSize() int {
+	if config.Web.ChildrenPageSize > 0 {
+		return config.Web.ChildrenPageSize
+	}
+
+	return DefaultChildrenPageSize
+}
+
+// CompressionMinimumSize returns the configured minimum size (in bytes) of compressed responses
+// or the default if no valid size is configured.
+func (config *Config) CompressionMinimumSize() int {
+	if config.Server.CompressionMinimumSize > 0 {
+		return config.Server.CompressionMinimumSize
+	}
+
+	return DefaultCompressionMinimumSize
+}
+
+// PresentationGotoKey returns the configured key code for the "go to slide" dialog of presentations
+// or the default if no valid key code is configured.
+func (config *Config) PresentationGotoKey() int {
+	if isValidKeyCode(config.Presentation.GotoKey) {
+		return config.Presentation.GotoKey
+	}
+
+	return DefaultPresentationGotoKey
+}
+
+// PresentationToggleKey returns the configured key code for toggling the presentation mode
+// or the default if no valid key code is configured.
+func (config *Config) PresentationToggleKey() int {
+	if isValidKeyCode(config.Presentation.ToggleKey) {
+		return config.Presentation.ToggleKey
+	}
+
+	return DefaultPresentationToggleKey
+}
+
+// PresentationNotesKey returns the configured key code for opening the presenter view of presentations
+// or the default if no valid key code is configured.
+func (config *Config) PresentationNotesKey() int {
+	if isValidKeyCode(config.Presentation.NotesKey) {
+		return config.Presentation.NotesKey
+	}
+
+	return DefaultPresentationNotesKey
+}
+
+// PresentationTimerPauseKey returns the configured key code for pausing the presentation timer
+// or the default if no valid key code is configured.
+func (config *Config) PresentationTimerPauseKey() int {
+	if isValidKeyCode(config.Presentation.TimerPauseKey) {
+		return config.Presentation.TimerPauseKey
+	}
+
+	return DefaultPresentationTimerPauseKey
+}
+
+// PresentationDuration returns the configured duration of presentations in minutes
+// or the default if no valid duration is configured.
+func (config *Config) PresentationDuration() int {
+	if config.Presentation.Duration > 0 {
+		return config.Presentation.Duration
+	}
+
+	return DefaultPresentationDuration
+}
+
+// PresentationWarningTime returns the configured remaining time in minutes at which the presentation timer
+// displays a warning or the default if no valid time is configured.
+func (config *Config) PresentationWarningTime() int {
+	if config.Presentation.WarningTime > 0 && config.Presentation.WarningTime < config.PresentationDuration() {
+		return config.Presentation.WarningTime
+	}
+
+	return DefaultPresentationWarningTime
+}
+
+// PresentationSwipeDistance returns the configured minimum distance in pixels of swipes in presentations
+// or the default if no valid distance is configured.
+func (config *Config) PresentationSwipeDistance() int {
+	if config.Presentation.SwipeDistance > 0 {
+		return config.Presentation.SwipeDistance
+	}
+
+	return DefaultPresentationSwipeDistance
+}
+
+// IndexingWorkers returns the configured number of items that are parsed in parallel
+// or the number of usable CPUs if no valid number is configured.
+func (config *Config) IndexingWorkers() int {
+	if config.Indexing.Workers > 0 {
+		return config.Indexing.Workers
+	}
+
+	return runtime.GOMAXPROCS(0)
+}
+
+// LiveReloadDebounceInterval returns the configured time window in which file system changes are combined
+// into a single update or the default if no valid interval is configured.
+func (config *Config) LiveReloadDebounceInterval() time.Duration {
+	if config.LiveReload.DebounceIntervalInMilliseconds > 0 {
+		return time.Millisecond * time.Duration(config.LiveReload.DebounceIntervalInMilliseconds)
+	}
+
+	return time.Millisecond * DefaultLiveReloadDebounceTime
+}
+
+// CommentsMinimumInterval returns the minimum time between two comments from the same client.
+func (config *Config) CommentsMinimumInterval() time.Duration {
+	if config.Comments.MinimumIntervalInSeconds > 0 {
+		return time.Second * time.Duration(config.Comments.MinimumIntervalInSeconds)
+	}
+
+	return time.Second * DefaultCommentsInterval
+}
+
+// MathDelimiters returns the configured math delimiters ("dollars" or "brackets").
+// If the configured value is not supported the default value will be returned.
+func (config *Config) MathDelimiters() string {
+	switch config.Math.Delimiters {
+	case MathDelimitersDollars, MathDelimitersBrackets:
+		return config.Math.Delimiters
+	}
+
+	return DefaultMathDelimiters
+}
+
+// MathKaTeXURL returns the configured base URL of the KaTeX distribution.
+// If no URL is configured the default value will be returned.
+func (config *Config) MathKaTeXURL() string {
+	if url := strings.TrimSpace(config.Math.KaTeXURL); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+
+	return DefaultMathKaTeXURL
+}
+
+// isValidKeyCode returns true if the supplied number is a valid JavaScript key code.
+func isValidKeyCode(keyCode int) bool {
+	return keyCode > 0 && keyCode < 256
+}
+
 // ThumbnailFolder returns the path of the thumbnail folder.
 func (config *Config) ThumbnailFolder() string {
 	folderName := ThumbnailsFolderName
@@ -539,6 +1018,11 @@ func (config *Config) Load() (*Config, error) {
 	config.Indexing = loadedConfig.Indexing
 	config.LiveReload = loadedConfig.LiveReload
 	config.Analytics = loadedConfig.Analytics
+	config.Presentation = loadedConfig.Presentation
+	config.Math = loadedConfig.Math
+	config.Markdown = loadedConfig.Markdown
+	config.Render = loadedConfig.Render
+	config.Comments = loadedConfig.Comments
 
 	return config, nil
 }
@@ -587,6 +1071,11 @@ func (config *Config) apply(newConfig *Config) (*Config, error) {
 	config.Indexing = newConfig.Indexing
 	config.LiveReload = newConfig.LiveReload
 	config.Analytics = newConfig.Analytics
+	config.Presentation = newConfig.Presentation
+	config.Math = newConfig.Math
+	config.Markdown = newConfig.Markdown
+	config.Render = newConfig.Render
+	config.Comments = newConfig.Comments
 
 	return config, nil
 }
@@ -607,6 +1096,14 @@ func (config *Config) AuthenticationIsEnabled() bool {
 	return true
 }
 
+// AuthenticationCredentials returns the configured username and password
+// and a flag indicating whether both are set.
+func (config *Config) AuthenticationCredentials() (username, password string, configured bool) {
+	username = config.Server.Authentication.Username
+	password = config.Server.Authentication.Password
+	return username, password, username != "" && password != ""
+}
+
 // AuthenticationFilePath returns the path of the authentication file.
 func (config *Config) AuthenticationFilePath() string {
 
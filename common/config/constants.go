@@ -7,5 +7,6 @@
 package config
 
 const (
-	DefaultConversionToolPath = "pandoc"
+	DefaultConversionToolPath    = "pandoc"
+	DefaultPDFConversionToolPath = "wkhtmltopdf"
 )
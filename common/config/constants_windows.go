@@ -5,5 +5,6 @@
 package config
 
 const (
-	DefaultConversionToolPath = "pandoc.exe"
+	DefaultConversionToolPath    = "pandoc.exe"
+	DefaultPDFConversionToolPath = "wkhtmltopdf.exe"
 )
@@ -194,6 +194,49 @@ func (currentNode *Node) Walk(expression func(node *Node)) {
 	}
 }
 
+// WalkErr visits the nodes in the same order as Walk but stops as soon as the expression returns an error.
+// The error is returned to the caller; nodes after the failing one are not visited.
+func (currentNode *Node) WalkErr(expression func(node *Node) error) error {
+
+	// children first
+	for _, child := range currentNode.Children() {
+		if err := expression(child); err != nil {
+			return err
+		}
+	}
+
+	// recurse
+	for _, child := range currentNode.Children() {
+		if err := child.WalkErr(expression); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WalkDepth visits the nodes in the same order as Walk and passes the depth of each node relative to the current node
+// (the children of the current node have the depth 1). If the expression returns false, the children of that node are skipped.
+func (currentNode *Node) WalkDepth(expression func(node *Node, depth int) bool) {
+	currentNode.walkDepth(expression, 1)
+}
+
+func (currentNode *Node) walkDepth(expression func(node *Node, depth int) bool, depth int) {
+
+	// children first
+	descend := make([]*Node, 0, len(currentNode.Children()))
+	for _, child := range currentNode.Children() {
+		if expression(child, depth) {
+			descend = append(descend, child)
+		}
+	}
+
+	// recurse
+	for _, child := range descend {
+		child.walkDepth(expression, depth+1)
+	}
+}
+
 func getNodeLevel(node *Node) int {
 	if node == nil {
 		panic("Node cannot be nil.")
@@ -122,3 +122,31 @@ func (tree *Tree) Walk(expression func(node *Node)) {
 
 	tree.Root().Walk(expression)
 }
+
+// WalkErr visits the nodes in the same order as Walk (the root, every child of the root and then the children recursively)
+// but stops at the first error returned by the expression and returns it.
+func (tree *Tree) WalkErr(expression func(node *Node) error) error {
+	if tree.Root() == nil {
+		return nil
+	}
+
+	if err := expression(tree.Root()); err != nil {
+		return err
+	}
+
+	return tree.Root().WalkErr(expression)
+}
+
+// WalkDepth visits the nodes in the same order as Walk and passes the depth of each node to the expression (the root has the depth 0).
+// If the expression returns false, the children of that node are not visited.
+func (tree *Tree) WalkDepth(expression func(node *Node, depth int) bool) {
+	if tree.Root() == nil {
+		return
+	}
+
+	if !expression(tree.Root(), 0) {
+		return
+	}
+
+	tree.Root().WalkDepth(expression)
+}
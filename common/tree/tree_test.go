@@ -5,6 +5,7 @@
 package tree
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -285,3 +286,120 @@ func Test_Tree_GetNode_ComplexTree_ExistingPath_ResultIsNotNull(t *testing.T) {
 		t.Errorf("Requesting a node from the tree %s with the path %q should return a node but returned %q instead.", tree, path, result)
 	}
 }
+
+func Test_Tree_WalkErr_ErrorOnThirdNode_TraversalStops(t *testing.T) {
+	// arrange
+	tree := New("root", nil)
+	tree.Insert(NewPath("child 1", "child 1.1"), nil)
+	tree.Insert(NewPath("child 2", "child 2.1"), nil)
+
+	visitedNodes := make([]string, 0)
+	expectedError := fmt.Errorf("Error on node 3")
+
+	// act
+	err := tree.WalkErr(func(node *Node) error {
+		visitedNodes = append(visitedNodes, node.Name())
+		if len(visitedNodes) == 3 {
+			return expectedError
+		}
+
+		return nil
+	})
+
+	// assert
+	if err != expectedError {
+		t.Errorf("WalkErr should return the error of the expression (%q) but returned %q.", expectedError, err)
+	}
+
+	expectedNodes := []string{"root", "child 1", "child 2"}
+	if len(visitedNodes) != len(expectedNodes) {
+		t.Errorf("WalkErr should have visited %v but visited %v.", expectedNodes, visitedNodes)
+		return
+	}
+
+	for index, name := range expectedNodes {
+		if visitedNodes[index] != name {
+			t.Errorf("WalkErr should have visited %v but visited %v.", expectedNodes, visitedNodes)
+		}
+	}
+}
+
+func Test_Tree_WalkErr_NoError_AllNodesAreVisited(t *testing.T) {
+	// arrange
+	tree := New("root", nil)
+	tree.Insert(NewPath("child 1", "child 1.1"), nil)
+	tree.Insert(NewPath("child 2", "child 2.1"), nil)
+
+	numberOfVisitedNodes := 0
+
+	// act
+	err := tree.WalkErr(func(node *Node) error {
+		numberOfVisitedNodes++
+		return nil
+	})
+
+	// assert
+	if err != nil {
+		t.Errorf("WalkErr should not return an error but returned %q.", err)
+	}
+
+	if numberOfVisitedNodes != 5 {
+		t.Errorf("WalkErr should have visited 5 nodes but visited %d.", numberOfVisitedNodes)
+	}
+}
+
+func Test_Tree_WalkDepth_DepthIsPassed(t *testing.T) {
+	// arrange
+	tree := New("root", nil)
+	tree.Insert(NewPath("child 1", "child 1.1", "child 1.1.1"), nil)
+
+	depths := make(map[string]int)
+
+	// act
+	tree.WalkDepth(func(node *Node, depth int) bool {
+		depths[node.Name()] = depth
+		return true
+	})
+
+	// assert
+	expectedDepths := map[string]int{
+		"root":        0,
+		"child 1":     1,
+		"child 1.1":   2,
+		"child 1.1.1": 3,
+	}
+
+	for name, expectedDepth := range expectedDepths {
+		if depth, visited := depths[name]; !visited || depth != expectedDepth {
+			t.Errorf("The depth of %q should be %d but was %d (visited: %t).", name, expectedDepth, depth, visited)
+		}
+	}
+}
+
+func Test_Tree_WalkDepth_PrunedSubtreeIsNotVisited(t *testing.T) {
+	// arrange
+	tree := New("root", nil)
+	tree.Insert(NewPath("child 1", "child 1.1", "child 1.1.1"), nil)
+	tree.Insert(NewPath("child 2", "child 2.1"), nil)
+
+	visitedNodes := make(map[string]bool)
+
+	// act
+	tree.WalkDepth(func(node *Node, depth int) bool {
+		visitedNodes[node.Name()] = true
+		return node.Name() != "child 1"
+	})
+
+	// assert
+	for _, name := range []string{"root", "child 1", "child 2", "child 2.1"} {
+		if !visitedNodes[name] {
+			t.Errorf("WalkDepth should have visited %q.", name)
+		}
+	}
+
+	for _, name := range []string{"child 1.1", "child 1.1.1"} {
+		if visitedNodes[name] {
+			t.Errorf("WalkDepth should not have visited %q because its parent was pruned.", name)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package exifutil reads a selected set of EXIF tags (capture date, camera model and GPS position) from JPEG images.
+package exifutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The names of the EXIF tags returned by Parse.
+const (
+	DateTimeOriginal = "DateTimeOriginal"
+	Model            = "Model"
+	GPSLatitude      = "GPSLatitude"
+	GPSLongitude     = "GPSLongitude"
+)
+
+// The EXIF tag ids.
+const (
+	tagModel            = 0x0110
+	tagExifIFDPointer   = 0x8769
+	tagGPSIFDPointer    = 0x8825
+	tagDateTimeOriginal = 0x9003
+	tagGPSLatitudeRef   = 0x0001
+	tagGPSLatitude      = 0x0002
+	tagGPSLongitudeRef  = 0x0003
+	tagGPSLongitude     = 0x0004
+)
+
+// The EXIF data types.
+const (
+	typeASCII    = 2
+	typeShort    = 3
+	typeLong     = 4
+	typeRational = 5
+)
+
+// The JPEG markers.
+const (
+	markerStartOfImage = 0xD8
+	markerEndOfImage   = 0xD9
+	markerStartOfScan  = 0xDA
+	markerAPP1         = 0xE1
+)
+
+// Parse reads the EXIF data of the supplied JPEG image and returns the selected tags
+// (DateTimeOriginal, Model, GPSLatitude and GPSLongitude). GPS coordinates are returned in decimal degrees.
+// Images without EXIF data and images which are no JPEGs result in an empty map.
+// An error is only returned if the image cannot be read.
+func Parse(reader io.Reader) (map[string]string, error) {
+
+	tags := make(map[string]string)
+
+	data, err := readExifSegment(reader)
+	if err != nil {
+		return tags, err
+	}
+
+	if data == nil {
+		return tags, nil
+	}
+
+	tiff, ok := newTIFFReader(data)
+	if !ok {
+		return tags, nil
+	}
+
+	// IFD0
+	ifd0 := tiff.readIFD(tiff.firstIFDOffset())
+	if model, ok := ifd0[tagModel]; ok {
+		tags[Model] = tiff.getString(model)
+	}
+
+	// Exif IFD
+	if pointer, ok := ifd0[tagExifIFDPointer]; ok {
+		exifIFD := tiff.readIFD(tiff.getUint(pointer))
+		if dateTime, ok := exifIFD[tagDateTimeOriginal]; ok {
+			tags[DateTimeOriginal] = tiff.getString(dateTime)
+		}
+	}
+
+	// GPS IFD
+	if pointer, ok := ifd0[tagGPSIFDPointer]; ok {
+		gpsIFD := tiff.readIFD(tiff.getUint(pointer))
+		if latitude, ok := tiff.getCoordinate(gpsIFD, tagGPSLatitude, tagGPSLatitudeRef, "S"); ok {
+			tags[GPSLatitude] = latitude
+		}
+
+		if longitude, ok := tiff.getCoordinate(gpsIFD, tagGPSLongitude, tagGPSLongitudeRef, "W"); ok {
+			tags[GPSLongitude] = longitude
+		}
+	}
+
+	// remove empty values
+	for name, value := range tags {
+		if value == "" {
+			delete(tags, name)
+		}
+	}
+
+	return tags, nil
+}
+
+// readExifSegment returns the TIFF data of the EXIF APP1 segment of the supplied JPEG image
+// or nil if the image is no JPEG or does not contain EXIF data.
+func readExifSegment(reader io.Reader) ([]byte, error) {
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if header[0] != 0xFF || header[1] != markerStartOfImage {
+		return nil, nil
+	}
+
+	for {
+		marker := make([]byte, 4)
+		if _, err := io.ReadFull(reader, marker); err != nil {
+			return nil, nil
+		}
+
+		if marker[0] != 0xFF || marker[1] == markerEndOfImage || marker[1] == markerStartOfScan {
+			return nil, nil
+		}
+
+		segmentLength := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if segmentLength < 0 {
+			return nil, nil
+		}
+
+		segment := make([]byte, segmentLength)
+		if _, err := io.ReadFull(reader, segment); err != nil {
+			return nil, nil
+		}
+
+		if marker[1] == markerAPP1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+	}
+}
+
+// A tiffReader reads values from the TIFF structure of an EXIF segment.
+type tiffReader struct {
+	data      []byte
+	byteOrder binary.ByteOrder
+}
+
+// An ifdEntry is a single entry of an image file directory.
+type ifdEntry struct {
+	dataType uint16
+	count    uint32
+	value    []byte // the value or the offset of the value
+}
+
+func newTIFFReader(data []byte) (*tiffReader, bool) {
+	if len(data) < 8 {
+		return nil, false
+	}
+
+	var byteOrder binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		byteOrder = binary.LittleEndian
+	case "MM":
+		byteOrder = binary.BigEndian
+	default:
+		return nil, false
+	}
+
+	if byteOrder.Uint16(data[2:4]) != 42 {
+		return nil, false
+	}
+
+	return &tiffReader{data, byteOrder}, true
+}
+
+func (tiff *tiffReader) firstIFDOffset() uint32 {
+	return tiff.byteOrder.Uint32(tiff.data[4:8])
+}
+
+// readIFD reads the entries of the image file directory at the given offset.
+func (tiff *tiffReader) readIFD(offset uint32) map[uint16]ifdEntry {
+	entries := make(map[uint16]ifdEntry)
+
+	if uint64(offset)+2 > uint64(len(tiff.data)) {
+		return entries
+	}
+
+	count := int(tiff.byteOrder.Uint16(tiff.data[offset:]))
+	for index := 0; index < count; index++ {
+		entryOffset := int(offset) + 2 + index*12
+		if entryOffset+12 > len(tiff.data) {
+			break
+		}
+
+		entry := tiff.data[entryOffset:(entryOffset + 12)]
+		entries[tiff.byteOrder.Uint16(entry[0:2])] = ifdEntry{
+			dataType: tiff.byteOrder.Uint16(entry[2:4]),
+			count:    tiff.byteOrder.Uint32(entry[4:8]),
+			value:    entry[8:12],
+		}
+	}
+
+	return entries
+}
+
+// getValueData returns the raw data of the given entry.
+func (tiff *tiffReader) getValueData(entry ifdEntry) []byte {
+	var size uint64
+	switch entry.dataType {
+	case typeASCII:
+		size = uint64(entry.count)
+	case typeShort:
+		size = uint64(entry.count) * 2
+	case typeLong:
+		size = uint64(entry.count) * 4
+	case typeRational:
+		size = uint64(entry.count) * 8
+	default:
+		return nil
+	}
+
+	// values of up to four bytes are stored in the entry itself
+	if size <= 4 {
+		return entry.value[:size]
+	}
+
+	offset := uint64(tiff.byteOrder.Uint32(entry.value))
+	if offset+size > uint64(len(tiff.data)) {
+		return nil
+	}
+
+	return tiff.data[offset:(offset + size)]
+}
+
+func (tiff *tiffReader) getString(entry ifdEntry) string {
+	if entry.dataType != typeASCII {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimRight(string(tiff.getValueData(entry)), "\x00"))
+}
+
+func (tiff *tiffReader) getUint(entry ifdEntry) uint32 {
+	switch entry.dataType {
+	case typeShort:
+		return uint32(tiff.byteOrder.Uint16(entry.value))
+	case typeLong:
+		return tiff.byteOrder.Uint32(entry.value)
+	}
+
+	return 0
+}
+
+// getCoordinate returns the GPS coordinate (degrees, minutes and seconds) of the given tag in decimal degrees.
+// The coordinate is negative if the reference tag equals the supplied negativeReference (e.g. "S" or "W").
+func (tiff *tiffReader) getCoordinate(ifd map[uint16]ifdEntry, valueTag, referenceTag uint16, negativeReference string) (string, bool) {
+	entry, ok := ifd[valueTag]
+	if !ok || entry.dataType != typeRational || entry.count != 3 {
+		return "", false
+	}
+
+	data := tiff.getValueData(entry)
+	if data == nil {
+		return "", false
+	}
+
+	var components [3]float64
+	for index := range components {
+		numerator := tiff.byteOrder.Uint32(data[(index * 8):])
+		denominator := tiff.byteOrder.Uint32(data[(index*8 + 4):])
+		if denominator == 0 {
+			return "", false
+		}
+
+		components[index] = float64(numerator) / float64(denominator)
+	}
+
+	coordinate := components[0] + components[1]/60 + components[2]/3600
+	if reference, ok := ifd[referenceTag]; ok && strings.EqualFold(tiff.getString(reference), negativeReference) {
+		coordinate = -coordinate
+	}
+
+	return fmt.Sprintf("%.6f", coordinate), true
+}
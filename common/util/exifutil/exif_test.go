@@ -0,0 +1,147 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package exifutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+// getJPEGFixture returns a small JPEG image with the supplied EXIF segment (if not nil).
+func getJPEGFixture(exifSegment []byte) []byte {
+	buffer := new(bytes.Buffer)
+	jpeg.Encode(buffer, image.NewRGBA(image.Rect(0, 0, 2, 2)), nil)
+	imageData := buffer.Bytes()
+
+	if exifSegment == nil {
+		return imageData
+	}
+
+	// insert the APP1 segment right after the start of image marker
+	segment := append([]byte("Exif\x00\x00"), exifSegment...)
+	app1 := []byte{0xFF, markerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	fixture := append([]byte{}, imageData[:2]...)
+	fixture = append(fixture, app1...)
+	fixture = append(fixture, segment...)
+	return append(fixture, imageData[2:]...)
+}
+
+// getTIFFFixture returns a big-endian TIFF structure with the camera model "TestCam",
+// the capture date "2015:03:01 21:13:00" and the GPS position 48°8'24" N, 11°34'30" W.
+func getTIFFFixture() []byte {
+	buffer := new(bytes.Buffer)
+	write := func(values ...interface{}) {
+		for _, value := range values {
+			binary.Write(buffer, binary.BigEndian, value)
+		}
+	}
+
+	entry := func(tag, dataType uint16, count, value uint32) {
+		write(tag, dataType, count, value)
+	}
+
+	inlineString := func(value string) uint32 {
+		var data [4]byte
+		copy(data[:], value)
+		return binary.BigEndian.Uint32(data[:])
+	}
+
+	// header
+	buffer.WriteString("MM")
+	write(uint16(42), uint32(8))
+
+	// IFD0 (offset 8)
+	write(uint16(3))
+	entry(tagModel, typeASCII, 8, 122)
+	entry(tagExifIFDPointer, typeLong, 1, 50)
+	entry(tagGPSIFDPointer, typeLong, 1, 68)
+	write(uint32(0))
+
+	// Exif IFD (offset 50)
+	write(uint16(1))
+	entry(tagDateTimeOriginal, typeASCII, 20, 130)
+	write(uint32(0))
+
+	// GPS IFD (offset 68)
+	write(uint16(4))
+	entry(tagGPSLatitudeRef, typeASCII, 2, inlineString("N"))
+	entry(tagGPSLatitude, typeRational, 3, 150)
+	entry(tagGPSLongitudeRef, typeASCII, 2, inlineString("W"))
+	entry(tagGPSLongitude, typeRational, 3, 174)
+	write(uint32(0))
+
+	// values (offset 122)
+	buffer.WriteString("TestCam\x00")
+	buffer.WriteString("2015:03:01 21:13:00\x00")
+	write(uint32(48), uint32(1), uint32(8), uint32(1), uint32(24), uint32(1))
+	write(uint32(11), uint32(1), uint32(34), uint32(1), uint32(3000), uint32(100))
+
+	return buffer.Bytes()
+}
+
+func Test_Parse_JPEGWithExifData_SelectedTagsAreReturned(t *testing.T) {
+	// arrange
+	fixture := getJPEGFixture(getTIFFFixture())
+
+	expectedTags := map[string]string{
+		DateTimeOriginal: "2015:03:01 21:13:00",
+		Model:            "TestCam",
+		GPSLatitude:      "48.140000",
+		GPSLongitude:     "-11.575000",
+	}
+
+	// act
+	tags, err := Parse(bytes.NewReader(fixture))
+
+	// assert
+	if err != nil {
+		t.Errorf("Parse should not return an error but returned: %s", err)
+	}
+
+	if len(tags) != len(expectedTags) {
+		t.Errorf("Parse should return %d tags but returned %v.", len(expectedTags), tags)
+	}
+
+	for name, expectedValue := range expectedTags {
+		if tags[name] != expectedValue {
+			t.Errorf("The %s tag should be %q but was %q.", name, expectedValue, tags[name])
+		}
+	}
+}
+
+func Test_Parse_JPEGWithoutExifData_EmptyResultIsReturned(t *testing.T) {
+	// arrange
+	fixture := getJPEGFixture(nil)
+
+	// act
+	tags, err := Parse(bytes.NewReader(fixture))
+
+	// assert
+	if err != nil {
+		t.Errorf("Parse should not return an error for images without EXIF data but returned: %s", err)
+	}
+
+	if len(tags) != 0 {
+		t.Errorf("Parse should return an empty result for images without EXIF data but returned %v.", tags)
+	}
+}
+
+func Test_Parse_NoJPEG_EmptyResultIsReturned(t *testing.T) {
+	// arrange
+	input := bytes.NewReader([]byte("\x89PNG\x0D\x0A\x1A\x0A"))
+
+	// act
+	tags, err := Parse(input)
+
+	// assert
+	if err != nil || len(tags) != 0 {
+		t.Errorf("Parse should return an empty result for files which are no JPEGs but returned (%v, %v).", tags, err)
+	}
+}
@@ -5,6 +5,8 @@
 package hashutil
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -29,3 +31,37 @@ func FromBytes(bytes []byte) string {
 	crc := crc32.ChecksumIEEE(bytes)
 	return fmt.Sprintf(`%d-%08X`, len(bytes), crc)
 }
+
+// ShortHashLength defines the number of characters of a short hash (6 bytes in hex encoding).
+const ShortHashLength = 12
+
+// GetSHA1 returns the full-length, hex-encoded SHA-1 digest of the data from the supplied reader.
+func GetSHA1(reader io.Reader) (string, error) {
+	hash := sha1.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// SHA1FromString returns the full-length, hex-encoded SHA-1 digest of the supplied text.
+func SHA1FromString(text string) string {
+	return SHA1FromBytes([]byte(text))
+}
+
+// SHA1FromBytes returns the full-length, hex-encoded SHA-1 digest of the supplied bytes.
+func SHA1FromBytes(bytes []byte) string {
+	digest := sha1.Sum(bytes)
+	return hex.EncodeToString(digest[:])
+}
+
+// Short returns the short form of the supplied hash which is meant for display purposes only.
+// Short hashes are not unique enough to be used as cache keys.
+func Short(hash string) string {
+	if len(hash) <= ShortHashLength {
+		return hash
+	}
+
+	return hash[:ShortHashLength]
+}
@@ -6,6 +6,7 @@ package hashutil
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 )
 
@@ -39,3 +40,60 @@ func Test_GetHash_CorrectSHA1IsReturned(t *testing.T) {
 		t.Errorf("The GetHash function should return the correct hash for the string %q. (Expected: %q, Actual: %q)", inputString, expectedResult, result)
 	}
 }
+
+func Test_GetSHA1_FullLengthDigestIsReturned(t *testing.T) {
+
+	// arrange
+	inputString := "La di da"
+	input := bytes.NewReader([]byte(inputString))
+	expectedResult := SHA1FromString(inputString)
+
+	// act
+	result, _ := GetSHA1(input)
+
+	// assert
+	if len(result) != 40 {
+		t.Errorf("GetSHA1 should return a full-length (40 characters) SHA-1 but the result was %q.", result)
+	}
+
+	if result != expectedResult {
+		t.Errorf("GetSHA1 and SHA1FromString should return the same hash for %q. (Expected: %q, Actual: %q)", inputString, expectedResult, result)
+	}
+}
+
+func Test_SHA1FromString_ManyItems_NoCollisions(t *testing.T) {
+
+	// arrange
+	numberOfItems := 5000
+	hashes := make(map[string]string, numberOfItems)
+
+	for i := 0; i < numberOfItems; i++ {
+		input := fmt.Sprintf("documents/item-%d/document.md # Item %d", i, i)
+
+		// act
+		result := SHA1FromString(input)
+
+		// assert
+		if existingInput, exists := hashes[result]; exists {
+			t.Errorf("The hash of %q collides with the hash of %q (%s).", input, existingInput, result)
+			return
+		}
+
+		hashes[result] = input
+	}
+}
+
+func Test_Short_LongHash_ShortHashIsReturned(t *testing.T) {
+
+	// arrange
+	inputString := SHA1FromString("La di da")
+	expectedResult := inputString[:ShortHashLength]
+
+	// act
+	result := Short(inputString)
+
+	// assert
+	if result != expectedResult {
+		t.Errorf("Short should return the first %d characters of %q. (Expected: %q, Actual: %q)", ShortHashLength, inputString, expectedResult, result)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package slugutil creates URL-friendly names from arbitrary text.
+package slugutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliterations contains the ASCII replacements for common non-ASCII letters.
+var transliterations = map[rune]string{
+	'ä': "ae", 'ö': "oe", 'ü': "ue", 'ß': "ss",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ý': "y", 'ÿ': "y",
+	'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th",
+}
+
+// FromText returns a lowercase, hyphen-separated version of the supplied text.
+// Common accented letters are transliterated to ASCII, other letters and digits are kept
+// as they are and all other characters except for whitespace, hyphens and underscores are dropped.
+func FromText(text string) string {
+	var slug []rune
+	pendingHyphen := false
+
+	appendText := func(value string) {
+		if pendingHyphen && len(slug) > 0 {
+			slug = append(slug, '-')
+		}
+
+		pendingHyphen = false
+		slug = append(slug, []rune(value)...)
+	}
+
+	for _, character := range strings.ToLower(text) {
+		switch {
+		case character < unicode.MaxASCII && (unicode.IsLetter(character) || unicode.IsDigit(character)):
+			appendText(string(character))
+
+		case transliterations[character] != "":
+			appendText(transliterations[character])
+
+		case unicode.IsLetter(character) || unicode.IsDigit(character):
+			appendText(string(character))
+
+		case unicode.IsSpace(character) || character == '-' || character == '_':
+			pendingHyphen = true
+		}
+	}
+
+	return string(slug)
+}
@@ -0,0 +1,30 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slugutil
+
+import (
+	"testing"
+)
+
+func Test_FromText(t *testing.T) {
+	inputs := map[string]string{
+		"Hello World!":     "hello-world",
+		"  Über   Größe  ": "ueber-groesse",
+		"2015 - A Review":  "2015-a-review",
+		"snake_case":       "snake-case",
+		"Ελληνικά κείμενα": "ελληνικά-κείμενα",
+		"???":              "",
+	}
+
+	for input, expected := range inputs {
+		// act
+		result := FromText(input)
+
+		// assert
+		if result != expected {
+			t.Errorf("The slug for %q should be %q but was %q.", input, expected, result)
+		}
+	}
+}
@@ -18,4 +18,8 @@ type File interface {
 	Name() string
 	Parent() route.Route
 	Route() route.Route
+
+	// ContentHash returns the SHA-1 hash of the file content.
+	// Unlike Hash it always reads the file content (unless it has been cached).
+	ContentHash() (string, error)
 }
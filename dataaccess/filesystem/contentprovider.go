@@ -122,17 +122,7 @@ func newTextContentProvider(text string, route route.Route) (*content.ContentPro
 	// hash provider
 	hashProvider := func() (string, error) {
 
-		routeHash, routeHashErr := getStringHash(route.String())
-		if routeHashErr != nil {
-			return "", fmt.Errorf("Unable to determine the hash for route %q. Error: %s", route, routeHashErr)
-		}
-
-		contentHash, contentHashErr := getStringHash(text)
-		if contentHashErr != nil {
-			return "", fmt.Errorf("Unable to determine the hash for content %q. Error: %s", text, contentHashErr)
-		}
-
-		return fmt.Sprintf("%s%s", routeHash, contentHash), nil
+		return getStringHash(route.String() + hashSeparator + text)
 	}
 
 	// last modified provider
@@ -143,72 +133,99 @@ func newTextContentProvider(text string, route route.Route) (*content.ContentPro
 	return content.NewContentProvider(mimeType, dataProvider, hashProvider, lastModifiedProvider)
 }
 
+// getHashFromFile returns the full-length SHA-1 hash of the supplied route and the content of the given file.
+// The hashes are cached until the modification time or the size of the file changes.
+// If the file cannot be read, the file path is used instead of the file content.
 func getHashFromFile(filepath string, route route.Route) (string, error) {
 
-	// fallback file hash
-	fileHash, fallbackHashErr := getStringHash(filepath)
-	if fallbackHashErr != nil {
-		return "", fallbackHashErr
-	}
-
 	// file hash
-	if isFile, _ := fsutil.IsFile(filepath); isFile {
-		if hash, err := getFileHash(filepath); err == nil {
-			fileHash = hash
-		}
+	if hash, err := getContentHash(filepath, route); err == nil {
+		return hash, nil
 	}
 
-	// route hash
-	routeHash, routeHashErr := getRouteHash(route)
-	if routeHashErr != nil {
-		return "", routeHashErr
+	// fallback file hash
+	return getStringHash(route.String() + hashSeparator + filepath)
+}
+
+// getContentHash returns the full-length SHA-1 hash of the supplied route and the content of the given file.
+// The hashes are cached until the modification time or the size of the file changes.
+// An error is returned if the file cannot be read.
+func getContentHash(filepath string, route route.Route) (string, error) {
+
+	fileInfo, err := os.Stat(filepath)
+	if err != nil {
+		return "", err
 	}
 
-	// return the combined hash
-	return fmt.Sprintf("%s%s", routeHash, fileHash), nil
-}
+	if fileInfo.IsDir() {
+		return "", fmt.Errorf("%q is not a file.", filepath)
+	}
 
-func getRouteHash(route route.Route) (string, error) {
-	return getStringHash(route.String())
-}
+	cacheKey := filepath + "|" + route.String()
+	if hash, found := fileHashes.Get(cacheKey, fileInfo.ModTime(), fileInfo.Size()); found {
+		return hash, nil
+	}
 
-func getStringHash(text string) (string, error) {
-	routeReader := strings.NewReader(text)
-	return hashutil.GetHash(routeReader)
-}
+	file, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
 
-func getFileHash(path string) (string, error) {
+	defer file.Close()
 
-	fileReader, err := os.Open(path)
+	hash, err := hashutil.GetSHA1(io.MultiReader(strings.NewReader(route.String()+hashSeparator), file))
 	if err != nil {
 		return "", err
 	}
 
-	defer fileReader.Close()
+	fileHashes.Set(cacheKey, fileInfo.ModTime(), fileInfo.Size(), hash)
+	return hash, nil
+}
+
+// hashSeparator separates the route from the content in the hash sources, so that a different split
+// of the same characters (e.g. route "a" with content "bc" and route "ab" with content "c") gives a different hash.
+// Routes are derived from file paths which cannot contain a NUL character.
+const hashSeparator = "\x00"
 
-	return hashutil.GetHash(fileReader)
+func getStringHash(text string) (string, error) {
+	return hashutil.SHA1FromString(text), nil
 }
 
+// getMimeType returns the mime type of the given file. The mime type is derived from the file extension
+// and, if the extension is unknown, from the first 512 bytes of the file content.
+// The results are cached until the modification time or the size of the file changes.
 func getMimeType(path string) (string, error) {
 
 	// content type detection
 	// derive content type from file extension
 	fileExtension := filepath.Ext(path)
-	contentType := mime.TypeByExtension(fileExtension)
-	if contentType == "" {
-		// fallback: derive content type from data
-		data, err := getData(path)
-		if err != nil {
-			return "", err
-		}
+	if contentType := mime.TypeByExtension(fileExtension); contentType != "" {
+		return contentType, nil
+	}
 
-		contentType = http.DetectContentType(data)
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return "", err
 	}
 
+	if contentType, found := fileMimeTypes.Get(path, fileInfo.ModTime(), fileInfo.Size()); found {
+		return contentType, nil
+	}
+
+	// fallback: derive content type from data
+	data, err := getHeader(path, 512)
+	if err != nil {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(data)
+	fileMimeTypes.Set(path, fileInfo.ModTime(), fileInfo.Size(), contentType)
+
 	return contentType, nil
 }
 
-func getData(path string) ([]byte, error) {
+// getHeader returns up to the given number of bytes from the beginning of the specified file.
+func getHeader(path string, size int) ([]byte, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return []byte{}, err
@@ -216,5 +233,5 @@ func getData(path string) ([]byte, error) {
 
 	defer file.Close()
 
-	return ioutil.ReadAll(file)
+	return ioutil.ReadAll(io.LimitReader(file, int64(size)))
 }
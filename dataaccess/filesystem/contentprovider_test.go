@@ -0,0 +1,87 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+)
+
+// The signature of a PNG file.
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+func Test_getMimeType(t *testing.T) {
+	// arrange
+	directory, _ := ioutil.TempDir("", "allmark-mimetype")
+	defer os.RemoveAll(directory)
+
+	inputs := []struct {
+		fileName         string
+		content          []byte
+		expectedMimeType string
+	}{
+		{"image.png", []byte("not really a png"), "image/png"},
+		{"document.pdf", []byte("%PDF-1.4"), "application/pdf"},
+		{"image", pngHeader, "image/png"},
+		{"notes", []byte("Some notes"), "text/plain"},
+	}
+
+	for _, input := range inputs {
+		filePath := filepath.Join(directory, input.fileName)
+		ioutil.WriteFile(filePath, input.content, 0600)
+
+		// act
+		result, err := getMimeType(filePath)
+
+		// assert
+		if err != nil {
+			t.Errorf("getMimeType(%q) should not return an error but returned: %s", input.fileName, err)
+		}
+
+		if !strings.HasPrefix(result, input.expectedMimeType) {
+			t.Errorf("getMimeType(%q) should return %q but returned %q.", input.fileName, input.expectedMimeType, result)
+		}
+	}
+}
+
+func Test_getMimeType_FileWithoutExtension_MimeTypeIsCached(t *testing.T) {
+	// arrange
+	directory, _ := ioutil.TempDir("", "allmark-mimetype")
+	defer os.RemoveAll(directory)
+
+	filePath := filepath.Join(directory, "image")
+	ioutil.WriteFile(filePath, pngHeader, 0600)
+
+	// act
+	getMimeType(filePath)
+
+	fileInfo, _ := os.Stat(filePath)
+	result, found := fileMimeTypes.Get(filePath, fileInfo.ModTime(), fileInfo.Size())
+
+	// assert
+	if !found || result != "image/png" {
+		t.Errorf("The sniffed mime type should have been cached but the cache returned (%q, %t).", result, found)
+	}
+}
+
+func Test_newTextContentProvider_SameCharactersDifferentRoutes_HashesDiffer(t *testing.T) {
+	// arrange
+	first, _ := newTextContentProvider("bc", route.NewFromRequest("a"))
+	second, _ := newTextContentProvider("c", route.NewFromRequest("ab"))
+
+	// act
+	firstHash, _ := first.Hash()
+	secondHash, _ := second.Hash()
+
+	// assert
+	if firstHash == secondHash {
+		t.Errorf("The hashes of route %q with content %q and route %q with content %q should differ but both were %q.", "a", "bc", "ab", "c", firstHash)
+	}
+}
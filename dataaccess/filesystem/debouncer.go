@@ -0,0 +1,82 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"sync"
+	"time"
+)
+
+// newDebouncer creates a new debouncer which passes all paths that have been added
+// within the given interval to the supplied callback in a single call.
+func newDebouncer(interval time.Duration, callback func(paths []string)) *debouncer {
+	return &debouncer{
+		interval: interval,
+		callback: callback,
+	}
+}
+
+// A debouncer combines bursts of file system changes into a single batch.
+// Every new path restarts the interval; the callback is executed
+// as soon as no new path has been added for the duration of the interval.
+// The callbacks never overlap: paths which are added while the callback is running
+// are passed to the next callback after the current one has returned.
+type debouncer struct {
+	interval time.Duration
+	callback func(paths []string)
+
+	lock  sync.Mutex
+	timer *time.Timer
+	paths []string
+
+	// flushLock serializes the callbacks of the timers
+	flushLock sync.Mutex
+}
+
+// Add adds the supplied path to the current batch.
+func (debouncer *debouncer) Add(path string) {
+	debouncer.lock.Lock()
+	defer debouncer.lock.Unlock()
+
+	// register the path only once per batch
+	if !containsPath(debouncer.paths, path) {
+		debouncer.paths = append(debouncer.paths, path)
+	}
+
+	if debouncer.timer != nil {
+		debouncer.timer.Stop()
+	}
+
+	debouncer.timer = time.AfterFunc(debouncer.interval, debouncer.flush)
+}
+
+// flush passes the current batch to the callback once the previous callback has returned.
+func (debouncer *debouncer) flush() {
+	debouncer.flushLock.Lock()
+	defer debouncer.flushLock.Unlock()
+
+	// the batch is taken after waiting for the previous callback so that a waiting flush
+	// receives all paths which have been added in the meantime
+	debouncer.lock.Lock()
+	paths := debouncer.paths
+	debouncer.paths = nil
+	debouncer.lock.Unlock()
+
+	if len(paths) == 0 {
+		return
+	}
+
+	debouncer.callback(paths)
+}
+
+func containsPath(paths []string, path string) bool {
+	for _, existingPath := range paths {
+		if existingPath == path {
+			return true
+		}
+	}
+
+	return false
+}
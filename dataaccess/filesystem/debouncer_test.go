@@ -0,0 +1,95 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_debouncer_Burst_PathsArePassedInASingleBatch(t *testing.T) {
+	// arrange
+	var lock sync.Mutex
+	var batches [][]string
+
+	debouncer := newDebouncer(10*time.Millisecond, func(paths []string) {
+		lock.Lock()
+		defer lock.Unlock()
+		batches = append(batches, paths)
+	})
+
+	// act
+	for _, path := range []string{"a", "b", "a", "c"} {
+		debouncer.Add(path)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// assert
+	lock.Lock()
+	defer lock.Unlock()
+
+	if len(batches) != 1 || strings.Join(batches[0], ",") != "a,b,c" {
+		t.Errorf("The paths should have been passed in a single batch %q but the batches were %v.", "a,b,c", batches)
+	}
+}
+
+func Test_debouncer_OverlappingBursts_CallbacksAreSerialized(t *testing.T) {
+	// arrange
+	var lock sync.Mutex
+	var batches [][]string
+	running, maxRunning := 0, 0
+
+	debouncer := newDebouncer(10*time.Millisecond, func(paths []string) {
+		lock.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		batches = append(batches, paths)
+		lock.Unlock()
+
+		// the next bursts arrive while the callback is still running
+		time.Sleep(50 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+	})
+
+	// act
+	debouncer.Add("a")
+	debouncer.Add("b")
+	time.Sleep(20 * time.Millisecond)
+
+	debouncer.Add("c")
+	time.Sleep(15 * time.Millisecond)
+
+	debouncer.Add("d")
+	time.Sleep(200 * time.Millisecond)
+
+	// assert
+	lock.Lock()
+	defer lock.Unlock()
+
+	if maxRunning != 1 {
+		t.Errorf("The callbacks should not overlap but %d callbacks were running at the same time.", maxRunning)
+	}
+
+	if len(batches) == 0 || strings.Join(batches[0], ",") != "a,b" {
+		t.Fatalf("The first batch should be %q but the batches were %v.", "a,b", batches)
+	}
+
+	var paths []string
+	for _, batch := range batches {
+		paths = append(paths, batch...)
+	}
+
+	if strings.Join(paths, ",") != "a,b,c,d" {
+		t.Errorf("Every path should have been passed once and in order but the batches were %v.", batches)
+	}
+}
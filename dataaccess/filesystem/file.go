@@ -16,6 +16,7 @@ type File struct {
 
 	parentRoute route.Route
 	fileRoute   route.Route
+	path        string
 }
 
 func (file *File) String() string {
@@ -38,3 +39,9 @@ func (file *File) Parent() route.Route {
 func (file *File) Route() route.Route {
 	return file.fileRoute
 }
+
+// ContentHash returns the SHA-1 hash of the route and the content of the file.
+// The hash is cached until the file changes.
+func (file *File) ContentHash() (string, error) {
+	return getContentHash(file.path, file.fileRoute)
+}
@@ -0,0 +1,62 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_ContentHash_DifferentFiles_HashesAreDifferent(t *testing.T) {
+	// arrange
+	ClearHashCache()
+	directory, _ := ioutil.TempDir("", "allmark-file")
+	defer os.RemoveAll(directory)
+
+	ioutil.WriteFile(filepath.Join(directory, "photo1.jpg"), []byte("photo 1"), 0600)
+	ioutil.WriteFile(filepath.Join(directory, "photo2.jpg"), []byte("photo 2"), 0600)
+
+	file1, _ := createFileFromFilesystem(directory, directory, filepath.Join(directory, "photo1.jpg"))
+	file2, _ := createFileFromFilesystem(directory, directory, filepath.Join(directory, "photo2.jpg"))
+
+	// act
+	hash1, err1 := file1.ContentHash()
+	hash2, err2 := file2.ContentHash()
+
+	// assert
+	if err1 != nil || err2 != nil {
+		t.Fatalf("ContentHash should not return an error (%v, %v).", err1, err2)
+	}
+
+	if len(hash1) != 40 {
+		t.Errorf("ContentHash should return a SHA-1 hex digest but returned %q.", hash1)
+	}
+
+	if hash1 == hash2 {
+		t.Errorf("Different files should have different hashes but both returned %q.", hash1)
+	}
+}
+
+func Test_ContentHash_DeletedFile_ErrorIsReturned(t *testing.T) {
+	// arrange
+	ClearHashCache()
+	directory, _ := ioutil.TempDir("", "allmark-file")
+	defer os.RemoveAll(directory)
+
+	filePath := filepath.Join(directory, "photo.jpg")
+	ioutil.WriteFile(filePath, []byte("photo"), 0600)
+	file, _ := createFileFromFilesystem(directory, directory, filePath)
+	os.Remove(filePath)
+
+	// act
+	_, err := file.ContentHash()
+
+	// assert
+	if err == nil {
+		t.Errorf("ContentHash should return an error if the file cannot be read.")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"sync"
+	"time"
+)
+
+// fileHashes caches the hashes of all files by path and route.
+var fileHashes = newFileCache()
+
+// fileMimeTypes caches the mime types of all files by path.
+var fileMimeTypes = newFileCache()
+
+// ClearHashCache removes all entries from the file hash cache.
+func ClearHashCache() {
+	fileHashes.Clear()
+}
+
+// newFileCache creates a new, empty file cache.
+func newFileCache() *fileCache {
+	return &fileCache{
+		entries: make(map[string]fileCacheEntry),
+	}
+}
+
+// A fileCache stores values which have been derived from a file (e.g. its hash) together with
+// the modification time and size of the file they were calculated for. It is safe for concurrent use.
+type fileCache struct {
+	lock    sync.RWMutex
+	entries map[string]fileCacheEntry
+}
+
+type fileCacheEntry struct {
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+// Get returns the cached value for the given key if the file has not been modified since the value was stored.
+func (cache *fileCache) Get(key string, modTime time.Time, size int64) (value string, found bool) {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+
+	entry, exists := cache.entries[key]
+	if !exists || !entry.modTime.Equal(modTime) || entry.size != size {
+		return "", false
+	}
+
+	return entry.value, true
+}
+
+// Set stores the value for the given key.
+func (cache *fileCache) Set(key string, modTime time.Time, size int64, value string) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	cache.entries[key] = fileCacheEntry{
+		modTime: modTime,
+		size:    size,
+		value:   value,
+	}
+}
+
+// Size returns the number of entries in the cache.
+func (cache *fileCache) Size() int {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+
+	return len(cache.entries)
+}
+
+// Clear removes all entries from the cache.
+func (cache *fileCache) Clear() {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	cache.entries = make(map[string]fileCacheEntry)
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/route"
+)
+
+func Test_getHashFromFile_UnchangedFile_HashIsCached(t *testing.T) {
+	// arrange
+	ClearHashCache()
+	directory, _ := ioutil.TempDir("", "allmark-hashcache")
+	defer os.RemoveAll(directory)
+
+	filePath := filepath.Join(directory, "document.md")
+	ioutil.WriteFile(filePath, []byte("# Document"), 0600)
+	itemRoute := route.NewFromItemPath(directory, filePath)
+
+	// act
+	hash1, _ := getHashFromFile(filePath, itemRoute)
+	hash2, _ := getHashFromFile(filePath, itemRoute)
+
+	// assert
+	if hash1 != hash2 {
+		t.Errorf("The hash of an unchanged file should not change (%q != %q).", hash1, hash2)
+	}
+
+	if fileHashes.Size() != 1 {
+		t.Errorf("The hash cache should contain one entry but contained %d.", fileHashes.Size())
+	}
+}
+
+func Test_getHashFromFile_ModifiedFile_HashIsRecalculated(t *testing.T) {
+	// arrange
+	ClearHashCache()
+	directory, _ := ioutil.TempDir("", "allmark-hashcache")
+	defer os.RemoveAll(directory)
+
+	filePath := filepath.Join(directory, "document.md")
+	ioutil.WriteFile(filePath, []byte("# Document"), 0600)
+	itemRoute := route.NewFromItemPath(directory, filePath)
+	hashBefore, _ := getHashFromFile(filePath, itemRoute)
+
+	// act
+	ioutil.WriteFile(filePath, []byte("# Modified Document"), 0600)
+	modTime := time.Now().Add(time.Minute)
+	os.Chtimes(filePath, modTime, modTime)
+	hashAfter, _ := getHashFromFile(filePath, itemRoute)
+
+	// assert
+	if hashBefore == hashAfter {
+		t.Errorf("The hash of a modified file should change (%q).", hashAfter)
+	}
+}
+
+func Test_fileCache_ConcurrentAccess(t *testing.T) {
+	// arrange
+	cache := newFileCache()
+	modTime := time.Now()
+
+	// act
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			cache.Set("key", modTime, 1, "hash")
+			cache.Get("key", modTime, 1)
+		}()
+	}
+
+	waitGroup.Wait()
+
+	// assert
+	if hash, found := cache.Get("key", modTime, 1); !found || hash != "hash" {
+		t.Errorf("The cache should return the stored hash but returned %q (found: %t).", hash, found)
+	}
+}
@@ -88,6 +88,7 @@ func createFileFromFilesystem(repositoryPath, itemDirectory, filePath string) (d
 		contentProvider,
 		parentRoute,
 		route,
+		filePath,
 	}
 
 	return file, nil
@@ -5,22 +5,24 @@
 package filesystem
 
 import (
+	"fmt"
+
 	"github.com/andreaskoch/allmark/common/content"
 	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/common/util/hashutil"
 	"github.com/andreaskoch/allmark/dataaccess"
-	"fmt"
 )
 
 // Create a new physical item.
 func newPhysicalItem(route route.Route,
+	fileName string,
 	contentProvider *content.ContentProvider,
 	files func() []dataaccess.File,
 	children func() []dataaccess.Item,
 	directory string,
 	watcherPaths []watcherPather) dataaccess.Item {
 
-	return newItem(dataaccess.TypePhysical, route, contentProvider, files, children, directory, watcherPaths)
+	return newItem(dataaccess.TypePhysical, route, fileName, contentProvider, files, children, directory, watcherPaths)
 
 }
 
@@ -32,7 +34,7 @@ func newVirtualItem(route route.Route,
 	directory string,
 	watcherPaths []watcherPather) dataaccess.Item {
 
-	return newItem(dataaccess.TypeVirtual, route, contentProvider, files, children, directory, watcherPaths)
+	return newItem(dataaccess.TypeVirtual, route, "", contentProvider, files, children, directory, watcherPaths)
 
 }
 
@@ -43,13 +45,14 @@ func newFileCollectionItem(route route.Route,
 	directory string,
 	watcherPaths []watcherPather) dataaccess.Item {
 
-	return newItem(dataaccess.TypeFileCollection, route, contentProvider, files, nil, directory, watcherPaths)
+	return newItem(dataaccess.TypeFileCollection, route, "", contentProvider, files, nil, directory, watcherPaths)
 
 }
 
 // Create a new item with the given item type.
 func newItem(itemType dataaccess.ItemType,
 	route route.Route,
+	fileName string,
 	contentProvider *content.ContentProvider,
 	files func() []dataaccess.File,
 	children func() []dataaccess.Item,
@@ -60,6 +63,7 @@ func newItem(itemType dataaccess.ItemType,
 		contentProvider,
 		itemType,
 		route,
+		fileName,
 		files,
 		children,
 
@@ -74,9 +78,10 @@ func newItem(itemType dataaccess.ItemType,
 type Item struct {
 	*content.ContentProvider
 
-	itemType   dataaccess.ItemType
-	route      route.Route
-	filesFunc  func() []dataaccess.File
+	itemType     dataaccess.ItemType
+	route        route.Route
+	fileName     string
+	filesFunc    func() []dataaccess.File
 	childrenFunc func() []dataaccess.Item
 
 	directory string
@@ -121,6 +126,12 @@ func (item *Item) Route() route.Route {
 	return item.route
 }
 
+// Get the name of the markdown file this item was created from (e.g. "document.md").
+// Virtual and file-collection items are not backed by a markdown file and return an empty string.
+func (item *Item) FileName() string {
+	return item.fileName
+}
+
 // Get the files of this item. Returns a slice of zero or more files.
 func (item *Item) Files() (files []dataaccess.File) {
 
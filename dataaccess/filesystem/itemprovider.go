@@ -81,6 +81,42 @@ func (itemProvider *itemProvider) GetItemFromDirectory(itemDirectory string) (it
 	return itemProvider.newFileCollectionItem(itemDirectory)
 }
 
+// GetLanguageVariantsFromDirectory returns an item for each localized markdown file (e.g. "document.de.md") in the given
+// directory that is not the primary markdown file of the directory. The variants are children of the directory item
+// and their route is the directory route plus the language code (e.g. "documents/sample/de").
+func (itemProvider *itemProvider) GetLanguageVariantsFromDirectory(itemDirectory string) []dataaccess.Item {
+
+	variants := make([]dataaccess.Item, 0)
+
+	found, primaryFilePath := findMarkdownFileInDirectory(itemDirectory)
+	if !found {
+		return variants
+	}
+
+	primaryLanguage := dataaccess.GetMarkdownFileLanguage(primaryFilePath)
+	languages := map[string]bool{primaryLanguage: true}
+
+	for _, markdownFilePath := range findMarkdownFilesInDirectory(itemDirectory) {
+
+		language := dataaccess.GetMarkdownFileLanguage(markdownFilePath)
+		if languages[language] {
+			continue // only one item per language
+		}
+
+		languages[language] = true
+
+		variant, err := itemProvider.newLanguageVariantItem(itemDirectory, markdownFilePath, language)
+		if err != nil {
+			itemProvider.logger.Warn("Cannot create a language variant from file %q. Error: %s", markdownFilePath, err.Error())
+			continue
+		}
+
+		variants = append(variants, variant)
+	}
+
+	return variants
+}
+
 func (itemProvider *itemProvider) getChildItemsFromDirectory(itemDirectory string) (childItems []dataaccess.Item) {
 
 	childItems = make([]dataaccess.Item, 0)
@@ -123,6 +159,7 @@ func (itemProvider *itemProvider) newItemFromFile(itemDirectory, filePath string
 	// create the item
 	item := newPhysicalItem(
 		route,
+		filepath.Base(filePath),
 		contentProvider,
 		files,
 		children,
@@ -136,6 +173,44 @@ func (itemProvider *itemProvider) newItemFromFile(itemDirectory, filePath string
 	return item, nil
 }
 
+func (itemProvider *itemProvider) newLanguageVariantItem(itemDirectory, filePath, language string) (dataaccess.Item, error) {
+
+	route := itemProvider.GetRouteFromDirectory(filepath.Join(itemDirectory, language))
+	itemProvider.logger.Debug("Creating a language variant item from route %q", route)
+
+	// content
+	contentProvider, contentProviderError := newFileContentProvider(filePath, route)
+	if contentProviderError != nil {
+		return nil, contentProviderError
+	}
+
+	// files
+	filesDirectory := filepath.Join(itemDirectory, config.FilesDirectoryName)
+	files := func() []dataaccess.File {
+		return itemProvider.fileProvider.GetFilesFromDirectory(itemDirectory, filesDirectory)
+	}
+
+	// children: language variants cannot have children of their own
+	children := func() []dataaccess.Item {
+		return []dataaccess.Item{}
+	}
+
+	// create the item
+	item := newPhysicalItem(
+		route,
+		filepath.Base(filePath),
+		contentProvider,
+		files,
+		children,
+		itemDirectory,
+		[]watcherPather{
+			watcherFilePath{filePath},
+			watcherDirectoryPath{filesDirectory, true},
+		},
+	)
+	return item, nil
+}
+
 func (itemProvider *itemProvider) newVirtualItem(itemDirectory string) (dataaccess.Item, error) {
 
 	route := itemProvider.GetRouteFromDirectory(itemDirectory)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/andreaskoch/allmark/common/config"
@@ -27,6 +28,7 @@ type Repository struct {
 
 	// Update Subscription
 	watcher           *filesystemWatcher
+	changes           *debouncer
 	updateSubscribers []chan dataaccess.Update
 
 	// live reload
@@ -75,6 +77,9 @@ func NewRepository(logger logger.Logger, directory string, config config.Config)
 		livereloadIsEnabled: config.LiveReload.Enabled,
 	}
 
+	// combine bursts of file system changes into a single update
+	repository.changes = newDebouncer(config.LiveReloadDebounceInterval(), repository.updateIndexForPaths)
+
 	// index the repository
 	repository.init()
 
@@ -150,20 +155,15 @@ func (repository *Repository) StartWatching(route route.Route) {
 		return
 	}
 
-	itemDirectory := fileSystemItem.Directory()
-
 	go func() {
 		for repository.watcher.IsRunning(route) {
 			select {
-			case <-updates:
+			case changedPath := <-updates:
 
-				repository.logger.Info("Received an update for route %q. Rescanning directory %q.", itemRoute, itemDirectory)
+				repository.logger.Info("Received an update for route %q (%q changed).", itemRoute, changedPath)
 
-				// update the index
-				oldIndex := repository.index
-				limitDepth := true
-				maxDepth := 2
-				repository.updateIndex(oldIndex, itemRoute, itemDirectory, limitDepth, maxDepth)
+				// update the index as soon as the changes have settled
+				repository.changes.Add(changedPath)
 
 			}
 		}
@@ -234,6 +234,9 @@ func (repository *Repository) getItemsFromDirectory(itemDirectory string, limitD
 		return
 	}
 
+	// append the language variants of the item
+	items = append(items, repository.itemProvider.GetLanguageVariantsFromDirectory(itemDirectory)...)
+
 	if limitDepth {
 
 		// abort if the max depth level has been reached
@@ -305,6 +308,133 @@ func (repository *Repository) updateIndex(oldIndex *Index, itemRoute route.Route
 	subIndexNew := repository.createIndexFromDirectory(itemDirectory, limitDepth, maxDepth)
 
 	repository.logger.Debug("------- Sub Indexes for %q ---------------", itemDirectory)
+
+	// update the index and send out the changes
+	repository.sendUpdate(repository.applySubIndexChanges(oldIndex, subIndexOld, subIndexNew))
+}
+
+// updateIndexForPaths updates the index for the supplied changed file or directory paths
+// and sends the combined changes as a single update to all subscribers.
+func (repository *Repository) updateIndexForPaths(changedPaths []string) {
+
+	updates := make([]dataaccess.Update, 0, len(changedPaths))
+	for _, changedPath := range changedPaths {
+		updates = append(updates, repository.updateIndexForPath(changedPath))
+	}
+
+	repository.sendUpdate(mergeUpdates(updates))
+}
+
+// updateIndexForPath updates the index for a single changed file or directory path.
+// Only the item which contains the path is re-read: if the path belongs to a new child directory
+// the new sub tree is scanned and if the item directory has been removed the item and all of its
+// children are removed from the index.
+func (repository *Repository) updateIndexForPath(changedPath string) dataaccess.Update {
+
+	oldIndex := repository.index
+	changedPath = filepath.Clean(changedPath)
+
+	item, found := repository.getItemByPath(oldIndex, changedPath)
+	if !found {
+		repository.logger.Warn("Cannot update the index. No item was found for path %q.", changedPath)
+		return dataaccess.Update{}
+	}
+
+	itemRoute := item.Route()
+	itemDirectory := item.Directory()
+
+	subIndexOld := newIndex()
+	subIndexNew := newIndex()
+
+	if !fsutil.PathExists(itemDirectory) {
+
+		// the item has been deleted: remove it and all of its children
+		repository.logger.Debug("The directory %q of item %q has been removed.", itemDirectory, itemRoute)
+		subIndexOld = oldIndex.GetSubIndex(itemRoute, false, 0)
+
+	} else {
+
+		// re-read only the item itself and its language variants
+		addItemsToIndex(subIndexOld, getItemWithLanguageVariants(oldIndex, item))
+		subIndexNew = repository.createIndexFromDirectory(itemDirectory, true, 0)
+
+		newItem, _ := subIndexNew.IsMatch(itemRoute)
+		if newItem == nil || newItem.Type() != item.Type() {
+
+			// the item type changed (e.g. a file collection became a document): rescan all children
+			repository.logger.Debug("The type of item %q changed. Rescanning directory %q.", itemRoute, itemDirectory)
+			subIndexOld = oldIndex.GetSubIndex(itemRoute, false, 0)
+			subIndexNew = repository.createIndexFromDirectory(itemDirectory, false, 0)
+
+		} else if childDirectory, isNewChild := repository.getNewChildDirectory(oldIndex, newItem, changedPath); isNewChild {
+
+			// a child item has been added: scan the new sub tree
+			repository.logger.Debug("Found the new child directory %q for item %q.", childDirectory, itemRoute)
+			addItemsToIndex(subIndexNew, repository.getItemsFromDirectory(childDirectory, false, 0))
+
+		}
+	}
+
+	return repository.applySubIndexChanges(oldIndex, subIndexOld, subIndexNew)
+}
+
+// getItemByPath returns the item whose directory is closest to the supplied file or directory path.
+func (repository *Repository) getItemByPath(index *Index, path string) (item *Item, found bool) {
+
+	repositoryDirectory := filepath.Clean(repository.directory)
+
+	directory := path
+	for {
+
+		itemRoute := repository.itemProvider.GetRouteFromDirectory(directory)
+		if indexItem, isMatch := index.IsMatch(itemRoute); isMatch {
+			if item, ok := indexItem.(*Item); ok {
+				return item, true
+			}
+		}
+
+		// stop at the repository root
+		parentDirectory := filepath.Dir(directory)
+		if directory == repositoryDirectory || parentDirectory == directory {
+			return nil, false
+		}
+
+		directory = parentDirectory
+	}
+}
+
+// getNewChildDirectory checks if the supplied path is located in a direct child directory
+// of the given item which is not yet part of the index.
+func (repository *Repository) getNewChildDirectory(index *Index, item dataaccess.Item, path string) (childDirectory string, isNewChild bool) {
+
+	// abort if the item cannot have children
+	if !item.CanHaveChildren() {
+		return "", false
+	}
+
+	itemDirectory := item.(*Item).Directory()
+	relativePath, err := filepath.Rel(itemDirectory, path)
+	if err != nil || relativePath == "." || strings.HasPrefix(relativePath, "..") {
+		return "", false
+	}
+
+	childDirectory = filepath.Join(itemDirectory, strings.Split(relativePath, string(filepath.Separator))[0])
+	if isDirectory, _ := fsutil.IsDirectory(childDirectory); !isDirectory || isReservedDirectory(childDirectory) {
+		return "", false
+	}
+
+	if _, exists := index.IsMatch(repository.itemProvider.GetRouteFromDirectory(childDirectory)); exists {
+		return "", false
+	}
+
+	return childDirectory, true
+}
+
+// applySubIndexChanges determines the differences between the supplied old and new sub indexes,
+// assigns a copy of the old index with these changes applied as the new repository index
+// and returns the changes.
+func (repository *Repository) applySubIndexChanges(oldIndex, subIndexOld, subIndexNew *Index) dataaccess.Update {
+
 	repository.logger.Debug("Sub index (old):\n%s", subIndexOld.String())
 	repository.logger.Debug("Sub index (new):\n%s", subIndexNew.String())
 
@@ -324,8 +454,8 @@ func (repository *Repository) updateIndex(oldIndex *Index, itemRoute route.Route
 		newIndex.Remove(deletedItem.Route())
 	}
 
-	// add new items
-	for _, newItem := range newItems {
+	// add new items and replace the modified ones
+	for _, newItem := range append(newItems, modifiedItems...) {
 		newIndex.Add(newItem)
 	}
 
@@ -336,9 +466,7 @@ func (repository *Repository) updateIndex(oldIndex *Index, itemRoute route.Route
 	// assign the new index
 	repository.index = newIndex
 
-	// send out updates
-	changedItems := dataaccess.NewUpdate(itemsToRoutes(newItems), itemsToRoutes(modifiedItems), itemsToRoutes(deletedItems))
-	repository.sendUpdate(changedItems)
+	return dataaccess.NewUpdate(itemsToRoutes(newItems), itemsToRoutes(modifiedItems), itemsToRoutes(deletedItems))
 }
 
 // diffIndexes calculates the differences between the specified old and new indexes.
@@ -400,3 +528,83 @@ func itemsToRoutes(items []dataaccess.Item) []route.Route {
 	}
 	return routes
 }
+
+// mergeUpdates combines the supplied consecutive updates into a single update.
+func mergeUpdates(updates []dataaccess.Update) dataaccess.Update {
+
+	var routes []route.Route
+	states := make(map[string]string)
+
+	setState := func(itemRoute route.Route, state string) {
+		key := route.ToKey(itemRoute)
+		if _, exists := states[key]; !exists {
+			routes = append(routes, itemRoute)
+		}
+
+		states[key] = state
+	}
+
+	for _, update := range updates {
+
+		for _, itemRoute := range update.New() {
+			if states[route.ToKey(itemRoute)] == "deleted" {
+				// deleted and re-created
+				setState(itemRoute, "modified")
+				continue
+			}
+
+			setState(itemRoute, "new")
+		}
+
+		for _, itemRoute := range update.Modified() {
+			if states[route.ToKey(itemRoute)] == "new" {
+				continue // still new
+			}
+
+			setState(itemRoute, "modified")
+		}
+
+		for _, itemRoute := range update.Deleted() {
+			if states[route.ToKey(itemRoute)] == "new" {
+				// created and deleted again
+				setState(itemRoute, "")
+				continue
+			}
+
+			setState(itemRoute, "deleted")
+		}
+	}
+
+	var newItemRoutes, modifiedItemRoutes, deletedItemRoutes []route.Route
+	for _, itemRoute := range routes {
+		switch states[route.ToKey(itemRoute)] {
+		case "new":
+			newItemRoutes = append(newItemRoutes, itemRoute)
+		case "modified":
+			modifiedItemRoutes = append(modifiedItemRoutes, itemRoute)
+		case "deleted":
+			deletedItemRoutes = append(deletedItemRoutes, itemRoute)
+		}
+	}
+
+	return dataaccess.NewUpdate(newItemRoutes, modifiedItemRoutes, deletedItemRoutes)
+}
+
+// getItemWithLanguageVariants returns the supplied item and the language variants of it from the given index.
+func getItemWithLanguageVariants(index *Index, item *Item) []dataaccess.Item {
+	items := []dataaccess.Item{item}
+	for _, child := range index.GetDirectChildren(item.Route()) {
+		if childItem, ok := child.(*Item); ok && childItem.Directory() == item.Directory() {
+			items = append(items, child)
+		}
+	}
+
+	return items
+}
+
+// addItemsToIndex adds the supplied items to the given index.
+func addItemsToIndex(index *Index, items []dataaccess.Item) {
+	for _, item := range items {
+		index.Add(item)
+	}
+}
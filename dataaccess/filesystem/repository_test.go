@@ -0,0 +1,240 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+)
+
+// newTestRepository creates a repository with the supplied markdown files (relative path -> content).
+func newTestRepository(t *testing.T, files map[string]string) (*Repository, string) {
+	ClearHashCache()
+	directory, _ := ioutil.TempDir("", "allmark-repository")
+
+	for relativePath, content := range files {
+		writeTestFile(t, filepath.Join(directory, relativePath), content)
+	}
+
+	repository, err := NewRepository(console.New(loglevel.Fatal), directory, *config.New(directory))
+	if err != nil {
+		os.RemoveAll(directory)
+		t.Fatalf("The repository could not be created. Error: %s", err)
+	}
+
+	return repository, directory
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	os.MkdirAll(filepath.Dir(path), 0700)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("The file %q could not be written. Error: %s", path, err)
+	}
+}
+
+func routesToStrings(routes []route.Route) []string {
+	values := make([]string, 0, len(routes))
+	for _, itemRoute := range routes {
+		values = append(values, itemRoute.Value())
+	}
+
+	return values
+}
+
+func assertRoutes(t *testing.T, name string, routes []route.Route, expected ...string) {
+	values := routesToStrings(routes)
+	if len(values) != len(expected) {
+		t.Errorf("The %s routes should be %v but were %v.", name, expected, values)
+		return
+	}
+
+	for index, value := range values {
+		if value != expected[index] {
+			t.Errorf("The %s routes should be %v but were %v.", name, expected, values)
+			return
+		}
+	}
+}
+
+var testRepositoryFiles = map[string]string{
+	"readme.md":                  "# Root",
+	"documents/readme.md":        "# Documents",
+	"documents/first/readme.md":  "# First",
+	"documents/second/readme.md": "# Second",
+	"other/readme.md":            "# Other",
+}
+
+func Test_updateIndexForPath_FileModified_OnlyTheItemIsUpdated(t *testing.T) {
+	// arrange
+	repository, directory := newTestRepository(t, testRepositoryFiles)
+	defer os.RemoveAll(directory)
+
+	unchangedItem := repository.Item(route.NewFromRequest("documents/second"))
+	parentItem := repository.Item(route.NewFromRequest("documents"))
+
+	changedFile := filepath.Join(directory, "documents", "first", "readme.md")
+	writeTestFile(t, changedFile, "# First (changed)")
+
+	// act
+	update := repository.updateIndexForPath(changedFile)
+
+	// assert
+	assertRoutes(t, "new", update.New())
+	assertRoutes(t, "modified", update.Modified(), "documents/first")
+	assertRoutes(t, "deleted", update.Deleted())
+
+	if repository.Item(route.NewFromRequest("documents/second")) != unchangedItem {
+		t.Errorf("The sibling item should not have been re-read.")
+	}
+
+	if repository.Item(route.NewFromRequest("documents")) != parentItem {
+		t.Errorf("The parent item should not have been re-read.")
+	}
+
+	if len(repository.Items()) != 5 {
+		t.Errorf("The index should contain %d items but contained %d.", 5, len(repository.Items()))
+	}
+}
+
+func Test_updateIndexForPath_ItemAdded_NewItemIsInsertedBelowItsParent(t *testing.T) {
+	// arrange
+	repository, directory := newTestRepository(t, testRepositoryFiles)
+	defer os.RemoveAll(directory)
+
+	unchangedItem := repository.Item(route.NewFromRequest("documents/first"))
+
+	writeTestFile(t, filepath.Join(directory, "documents", "third", "readme.md"), "# Third")
+	writeTestFile(t, filepath.Join(directory, "documents", "third", "child", "readme.md"), "# Child")
+
+	// act
+	update := repository.updateIndexForPath(filepath.Join(directory, "documents", "third"))
+
+	// assert
+	assertRoutes(t, "new", update.New(), "documents/third", "documents/third/child")
+	assertRoutes(t, "modified", update.Modified())
+	assertRoutes(t, "deleted", update.Deleted())
+
+	if repository.Item(route.NewFromRequest("documents/first")) != unchangedItem {
+		t.Errorf("The sibling item should not have been re-read.")
+	}
+
+	parent := repository.index.GetParent(route.NewFromRequest("documents/third"))
+	if parent == nil || parent.Route().Value() != "documents" {
+		t.Errorf("The parent of the new item should be %q but was %v.", "documents", parent)
+	}
+}
+
+func Test_updateIndexForPath_ItemDeleted_ItemAndChildrenAreRemoved(t *testing.T) {
+	// arrange
+	files := map[string]string{
+		"readme.md":                       "# Root",
+		"documents/readme.md":             "# Documents",
+		"documents/first/readme.md":       "# First",
+		"documents/first/child/readme.md": "# Child",
+		"documents/second/readme.md":      "# Second",
+	}
+
+	repository, directory := newTestRepository(t, files)
+	defer os.RemoveAll(directory)
+
+	unchangedItem := repository.Item(route.NewFromRequest("documents/second"))
+
+	deletedDirectory := filepath.Join(directory, "documents", "first")
+	os.RemoveAll(deletedDirectory)
+
+	// act
+	update := repository.updateIndexForPath(deletedDirectory)
+
+	// assert
+	assertRoutes(t, "new", update.New())
+	assertRoutes(t, "modified", update.Modified())
+	assertRoutes(t, "deleted", update.Deleted(), "documents/first", "documents/first/child")
+
+	for _, deletedRoute := range []string{"documents/first", "documents/first/child"} {
+		if repository.Item(route.NewFromRequest(deletedRoute)) != nil {
+			t.Errorf("The item %q should have been removed from the index.", deletedRoute)
+		}
+	}
+
+	if repository.Item(route.NewFromRequest("documents/second")) != unchangedItem {
+		t.Errorf("The sibling item should not have been re-read.")
+	}
+}
+
+func Test_updateIndexForPath_UnchangedFile_UpdateIsEmpty(t *testing.T) {
+	// arrange
+	repository, directory := newTestRepository(t, testRepositoryFiles)
+	defer os.RemoveAll(directory)
+
+	// act
+	update := repository.updateIndexForPath(filepath.Join(directory, "other", "readme.md"))
+
+	// assert
+	if !update.IsEmpty() {
+		t.Errorf("The update should be empty but was %s.", update.String())
+	}
+}
+
+func Test_updateIndexForPaths_ThreeChangesWithinTheDebounceInterval_OneUpdateIsSent(t *testing.T) {
+	// arrange
+	repository, directory := newTestRepository(t, testRepositoryFiles)
+	defer os.RemoveAll(directory)
+
+	updates := make(chan dataaccess.Update, 10)
+	repository.Subscribe(updates)
+	repository.changes = newDebouncer(50*time.Millisecond, repository.updateIndexForPaths)
+
+	firstFile := filepath.Join(directory, "documents", "first", "readme.md")
+	secondFile := filepath.Join(directory, "documents", "second", "readme.md")
+
+	// act
+	writeTestFile(t, firstFile, "# First (1)")
+	repository.changes.Add(firstFile)
+
+	writeTestFile(t, firstFile, "# First (two)")
+	repository.changes.Add(firstFile)
+
+	writeTestFile(t, secondFile, "# Second (changed)")
+	repository.changes.Add(secondFile)
+
+	time.Sleep(300 * time.Millisecond)
+
+	// assert
+	if len(updates) != 1 {
+		t.Fatalf("Three changes within the debounce interval should produce %d update but produced %d.", 1, len(updates))
+	}
+
+	update := <-updates
+	assertRoutes(t, "modified", update.Modified(), "documents/first", "documents/second")
+}
+
+func Test_mergeUpdates(t *testing.T) {
+	// arrange
+	first := route.NewFromRequest("first")
+	second := route.NewFromRequest("second")
+	third := route.NewFromRequest("third")
+
+	updates := []dataaccess.Update{
+		dataaccess.NewUpdate([]route.Route{first, second}, nil, []route.Route{third}),
+		dataaccess.NewUpdate([]route.Route{third}, []route.Route{first}, []route.Route{second}),
+	}
+
+	// act
+	result := mergeUpdates(updates)
+
+	// assert
+	assertRoutes(t, "new", result.New(), "first")
+	assertRoutes(t, "modified", result.Modified(), "third")
+	assertRoutes(t, "deleted", result.Deleted())
+}
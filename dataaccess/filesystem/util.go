@@ -7,6 +7,7 @@ package filesystem
 import (
 	"github.com/andreaskoch/allmark/common/config"
 	"github.com/andreaskoch/allmark/common/util/fsutil"
+	"github.com/andreaskoch/allmark/dataaccess"
 	"io/ioutil"
 	"path/filepath"
 	"strings"
@@ -40,7 +41,7 @@ func directoryContainsItems(directory string, maxdepth int) bool {
 			continue
 		}
 
-		if isMarkdownFile(childDirectory) {
+		if dataaccess.IsMarkdownFile(childDirectory) {
 			return true
 		}
 
@@ -74,10 +75,30 @@ func isReservedDirectory(path string) bool {
 	return false
 }
 
+// findMarkdownFileInDirectory returns the path of the primary markdown file of the given directory.
+// Markdown files without a language suffix (e.g. "document.md") take precedence over localized ones (e.g. "document.de.md").
 func findMarkdownFileInDirectory(directory string) (found bool, file string) {
+	markdownFiles := findMarkdownFilesInDirectory(directory)
+	if len(markdownFiles) == 0 {
+		return false, ""
+	}
+
+	for _, markdownFile := range markdownFiles {
+		if dataaccess.GetMarkdownFileLanguage(markdownFile) == "" {
+			return true, markdownFile
+		}
+	}
+
+	return true, markdownFiles[0]
+}
+
+// findMarkdownFilesInDirectory returns the paths of all markdown files in the given directory sorted by name.
+func findMarkdownFilesInDirectory(directory string) []string {
+	markdownFiles := make([]string, 0)
+
 	entries, err := ioutil.ReadDir(directory)
 	if err != nil {
-		return false, ""
+		return markdownFiles
 	}
 
 	for _, element := range entries {
@@ -87,12 +108,12 @@ func findMarkdownFileInDirectory(directory string) (found bool, file string) {
 		}
 
 		absoluteFilePath := filepath.Join(directory, element.Name())
-		if isMarkdown := isMarkdownFile(absoluteFilePath); isMarkdown {
-			return true, absoluteFilePath
+		if isMarkdown := dataaccess.IsMarkdownFile(absoluteFilePath); isMarkdown {
+			markdownFiles = append(markdownFiles, absoluteFilePath)
 		}
 	}
 
-	return false, ""
+	return markdownFiles
 }
 
 func getChildDirectories(directory string) []string {
@@ -116,15 +137,3 @@ func getChildDirectories(directory string) []string {
 
 	return directories
 }
-
-func isMarkdownFile(fileNameOrPath string) bool {
-	fileExtension := strings.ToLower(filepath.Ext(fileNameOrPath))
-	switch fileExtension {
-	case ".md", ".markdown", ".mdown":
-		return true
-	default:
-		return false
-	}
-
-	panic("Unreachable")
-}
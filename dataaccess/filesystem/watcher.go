@@ -64,7 +64,9 @@ type filesystemWatcher struct {
 	watchers map[string][]fswatch.Watcher
 }
 
-func (watcher *filesystemWatcher) Start(route route.Route, watcherPaths []watcherPather) (chan bool, error) {
+// Start starts watching the supplied paths of the item with the given route.
+// The paths of all changed files and directories are passed down the returned channel.
+func (watcher *filesystemWatcher) Start(route route.Route, watcherPaths []watcherPather) (chan string, error) {
 
 	// check if there are already watchers
 	if _, exists := watcher.watchers[routeToString(route)]; exists {
@@ -73,7 +75,7 @@ func (watcher *filesystemWatcher) Start(route route.Route, watcherPaths []watche
 
 	watcher.logger.Debug("Starting to watch %q", route.String())
 
-	backChannel := make(chan bool, 1)
+	backChannel := make(chan string, 10)
 
 	// create a watcher for every path
 	watchers := make([]fswatch.Watcher, 0)
@@ -120,7 +122,7 @@ func (watcher *filesystemWatcher) IsRunning(route route.Route) bool {
 	return exists
 }
 
-func (watcher *filesystemWatcher) createFileWatcher(filePath string, backChannel chan bool) fswatch.Watcher {
+func (watcher *filesystemWatcher) createFileWatcher(filePath string, backChannel chan string) fswatch.Watcher {
 
 	checkIntervalInSeconds := 1
 	filewatcher := fswatch.NewFileWatcher(filePath, checkIntervalInSeconds)
@@ -133,9 +135,12 @@ func (watcher *filesystemWatcher) createFileWatcher(filePath string, backChannel
 
 			select {
 			case <-filewatcher.Modified():
-				backChannel <- true
+				backChannel <- filePath
 
 			case <-filewatcher.Moved():
+				// editors which save atomically (write a temp file, rename it) replace the file:
+				// treat this as a modification. Later changes are reported by the directory watcher.
+				backChannel <- filePath
 				running = false
 
 			case <-filewatcher.Stopped():
@@ -147,7 +152,7 @@ func (watcher *filesystemWatcher) createFileWatcher(filePath string, backChannel
 	return filewatcher
 }
 
-func (watcher *filesystemWatcher) createDirectoryWatcher(directoryPath string, recurse bool, backChannel chan bool) fswatch.Watcher {
+func (watcher *filesystemWatcher) createDirectoryWatcher(directoryPath string, recurse bool, backChannel chan string) fswatch.Watcher {
 	checkIntervalInSeconds := 1
 
 	skipNoFiles := func(path string) bool {
@@ -164,7 +169,14 @@ func (watcher *filesystemWatcher) createDirectoryWatcher(directoryPath string, r
 
 			select {
 			case <-folderWatcher.Modified():
-				backChannel <- true
+				// the changed paths are sent via the change details
+
+			case change := <-folderWatcher.ChangeDetails():
+				for _, changedPaths := range [][]string{change.New(), change.Modified(), change.Moved()} {
+					for _, changedPath := range changedPaths {
+						backChannel <- changedPath
+					}
+				}
 
 			case <-folderWatcher.Moved():
 				running = false
@@ -55,6 +55,7 @@ type Item interface {
 	Type() ItemType
 	CanHaveChildren() bool
 	Route() route.Route
+	FileName() string
 	Files() []File
 	LastHash() string
 }
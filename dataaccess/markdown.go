@@ -0,0 +1,69 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataaccess
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// MarkdownFileExtensions contains all (lower-case) file extensions that identify a markdown file.
+	MarkdownFileExtensions = []string{".md", ".markdown", ".mdown"}
+
+	// languageSuffixPattern matches language codes in file names (e.g. "de" or "de-CH" in "document.de-CH.md").
+	languageSuffixPattern = regexp.MustCompile(`^[a-zA-Z]{2}(-[a-zA-Z]{2})?$`)
+)
+
+// IsMarkdownFile checks if the supplied file name or path has one of the accepted markdown file extensions.
+// The comparison is case-insensitive but the extension must match exactly (e.g. "readme.markdownx" is not a markdown file).
+func IsMarkdownFile(fileNameOrPath string) bool {
+	fileExtension := strings.ToLower(filepath.Ext(strings.TrimSpace(fileNameOrPath)))
+	for _, markdownFileExtension := range MarkdownFileExtensions {
+		if fileExtension == markdownFileExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMarkdownFileBaseName returns the lower-case name of the supplied markdown file without the markdown extension
+// and without a language suffix (e.g. "Document.markdown" -> "document", "document.de.md" -> "document").
+// If the file is not a markdown file an empty string is returned.
+func GetMarkdownFileBaseName(fileNameOrPath string) string {
+	baseName, _ := splitMarkdownFileName(fileNameOrPath)
+	return strings.ToLower(baseName)
+}
+
+// GetMarkdownFileLanguage returns the language code of localized markdown files (e.g. "de" for "document.de.md").
+// If the file name has no language suffix or if it is not a markdown file an empty string is returned.
+func GetMarkdownFileLanguage(fileNameOrPath string) string {
+	_, language := splitMarkdownFileName(fileNameOrPath)
+	return language
+}
+
+// splitMarkdownFileName splits the supplied markdown file name into the base name and the (optional) language code.
+func splitMarkdownFileName(fileNameOrPath string) (baseName, language string) {
+	if !IsMarkdownFile(fileNameOrPath) {
+		return "", ""
+	}
+
+	fileName := filepath.Base(strings.TrimSpace(fileNameOrPath))
+	baseName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+
+	languageSuffix := filepath.Ext(baseName)
+	if languageSuffix == "" || languageSuffix == baseName {
+		return baseName, ""
+	}
+
+	language = strings.TrimPrefix(languageSuffix, ".")
+	if !languageSuffixPattern.MatchString(language) {
+		return baseName, ""
+	}
+
+	return strings.TrimSuffix(baseName, languageSuffix), language
+}
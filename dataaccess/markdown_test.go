@@ -0,0 +1,135 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataaccess
+
+import (
+	"testing"
+)
+
+func Test_IsMarkdownFile_AcceptedExtensions_ResultIsTrue(t *testing.T) {
+	// arrange
+	inputs := []string{
+		"document.md",
+		"document.markdown",
+		"presentation.mdown",
+		"README.MD",
+		"/some/folder/readme.Markdown",
+	}
+
+	for _, input := range inputs {
+
+		// act
+		result := IsMarkdownFile(input)
+
+		// assert
+		if !result {
+			t.Errorf("%q should be detected as a markdown file.", input)
+		}
+	}
+}
+
+func Test_IsMarkdownFile_OtherExtensions_ResultIsFalse(t *testing.T) {
+	// arrange
+	inputs := []string{
+		"foo.markdownx",
+		"foo.mdx",
+		"foo.md.txt",
+		"markdown",
+		"md",
+		"",
+	}
+
+	for _, input := range inputs {
+
+		// act
+		result := IsMarkdownFile(input)
+
+		// assert
+		if result {
+			t.Errorf("%q should not be detected as a markdown file.", input)
+		}
+	}
+}
+
+func Test_GetMarkdownFileBaseName_AllExtensionsMapToTheSameBaseName(t *testing.T) {
+	// arrange
+	inputs := []string{
+		"document.md",
+		"document.markdown",
+		"Document.mdown",
+		"/some/folder/document.MD",
+	}
+	expected := "document"
+
+	for _, input := range inputs {
+
+		// act
+		result := GetMarkdownFileBaseName(input)
+
+		// assert
+		if result != expected {
+			t.Errorf("The base name of %q should be %q but was %q.", input, expected, result)
+		}
+	}
+}
+
+func Test_GetMarkdownFileBaseName_NoMarkdownFile_ResultIsEmpty(t *testing.T) {
+	// arrange
+	input := "document.markdownx"
+
+	// act
+	result := GetMarkdownFileBaseName(input)
+
+	// assert
+	if result != "" {
+		t.Errorf("The base name of %q should be empty because it is not a markdown file but was %q.", input, result)
+	}
+}
+
+func Test_GetMarkdownFileBaseName_LocalizedFile_LanguageIsStripped(t *testing.T) {
+	// arrange
+	inputs := []string{
+		"document.de.md",
+		"document.en.markdown",
+		"document.de-CH.mdown",
+	}
+	expected := "document"
+
+	for _, input := range inputs {
+
+		// act
+		result := GetMarkdownFileBaseName(input)
+
+		// assert
+		if result != expected {
+			t.Errorf("The base name of %q should be %q but was %q.", input, expected, result)
+		}
+	}
+}
+
+func Test_GetMarkdownFileLanguage(t *testing.T) {
+	// arrange
+	inputs := map[string]string{
+		"document.md":          "",
+		"document.de.md":       "de",
+		"document.fr.markdown": "fr",
+		"document.de-CH.md":    "de-CH",
+		"document.v2.md":       "",
+		"document.draft.md":    "",
+		".de.md":               "",
+		"document.de.txt":      "",
+	}
+
+	for input, expected := range inputs {
+
+		// act
+		result := GetMarkdownFileLanguage(input)
+
+		// assert
+		if result != expected {
+			t.Errorf("The language of %q should be %q but was %q.", input, expected, result)
+		}
+	}
+}
@@ -13,6 +13,11 @@ type PathProvider interface {
 	Path() string
 }
 
+// A DirectoryProvider is an item which is stored in a directory of the file system.
+type DirectoryProvider interface {
+	Directory() string
+}
+
 type RoutesProvider interface {
 	Routes() []route.Route
 }
@@ -0,0 +1,151 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The layouts which are accepted for date values of blocks (see GetBlockDate).
+var blockDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// A Block is a single name-value pair from the meta data section of an item (e.g. "author: John Doe").
+type Block struct {
+	Name  string
+	Value string
+}
+
+// NewBlock creates a new block with the given name and value.
+// The name and the value are trimmed; an empty name is not allowed.
+func NewBlock(name, value string) (Block, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Block{}, fmt.Errorf("Cannot create a block without a name (value: %q).", value)
+	}
+
+	return Block{
+		Name:  name,
+		Value: strings.TrimSpace(value),
+	}, nil
+}
+
+// String returns the "name: value" representation of the block.
+func (block Block) String() string {
+	return fmt.Sprintf("%s: %s", block.Name, block.Value)
+}
+
+// AddBlock appends a new block with the given name and value to the meta data.
+// An error is returned if no block can be created from the supplied name and value.
+func (metaData *MetaData) AddBlock(name, value string) error {
+	block, err := NewBlock(name, value)
+	if err != nil {
+		return err
+	}
+
+	metaData.Blocks = append(metaData.Blocks, block)
+	return nil
+}
+
+// GetBlockValue returns the value of the first block with the given (case-insensitive) name.
+// If there is no such block an empty string is returned.
+func (metaData *MetaData) GetBlockValue(name string) string {
+	value, _ := metaData.GetBlockValueOk(name)
+	return value
+}
+
+// GetBlockValueOk returns the value of the first block with the given (case-insensitive) name
+// and a flag indicating whether such a block exists. This allows to distinguish missing blocks from empty values.
+func (metaData *MetaData) GetBlockValueOk(name string) (value string, found bool) {
+	for _, block := range metaData.Blocks {
+		if strings.EqualFold(block.Name, strings.TrimSpace(name)) {
+			return block.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// GetBlockValueOrDefault returns the value of the first block with the given (case-insensitive) name
+// or the supplied default value if there is no such block.
+func (metaData *MetaData) GetBlockValueOrDefault(name, defaultValue string) string {
+	if value, found := metaData.GetBlockValueOk(name); found {
+		return value
+	}
+
+	return defaultValue
+}
+
+// GetBlockValues returns the values of all blocks with the given (case-insensitive) name in the order of their definition.
+func (metaData *MetaData) GetBlockValues(name string) []string {
+	values := make([]string, 0)
+	for _, block := range metaData.Blocks {
+		if strings.EqualFold(block.Name, strings.TrimSpace(name)) {
+			values = append(values, block.Value)
+		}
+	}
+
+	return values
+}
+
+// RemoveBlock deletes all blocks with the given (case-insensitive) name
+// and reports whether any block has been removed.
+func (metaData *MetaData) RemoveBlock(name string) bool {
+	remainingBlocks := make([]Block, 0, len(metaData.Blocks))
+	for _, block := range metaData.Blocks {
+		if strings.EqualFold(block.Name, strings.TrimSpace(name)) {
+			continue
+		}
+
+		remainingBlocks = append(remainingBlocks, block)
+	}
+
+	removed := len(remainingBlocks) != len(metaData.Blocks)
+	metaData.Blocks = remainingBlocks
+	return removed
+}
+
+// UpdateBlock replaces the value of the first block with the given (case-insensitive) name.
+// If there is no such block a new block is appended.
+// An error is returned if no block can be created from the supplied name and value.
+func (metaData *MetaData) UpdateBlock(name, value string) error {
+	block, err := NewBlock(name, value)
+	if err != nil {
+		return err
+	}
+
+	for index := range metaData.Blocks {
+		if strings.EqualFold(metaData.Blocks[index].Name, block.Name) {
+			metaData.Blocks[index].Value = block.Value
+			return nil
+		}
+	}
+
+	metaData.Blocks = append(metaData.Blocks, block)
+	return nil
+}
+
+// GetBlockDate parses the value of the first block with the given (case-insensitive) name as a date.
+// Accepted layouts are RFC3339 (e.g. "2015-03-01T21:13:00+01:00"), "2006-01-02 15:04" and "2006-01-02";
+// values without a time zone are interpreted as UTC.
+func (metaData *MetaData) GetBlockDate(name string) (time.Time, error) {
+	value, found := metaData.GetBlockValueOk(name)
+	if !found {
+		return time.Time{}, fmt.Errorf("The meta data does not contain a %q block.", name)
+	}
+
+	for _, layout := range blockDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("The value %q of the %q block is not a valid date. Accepted formats are %q.", value, name, blockDateLayouts)
+}
@@ -0,0 +1,257 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_AddBlock_EmptyName_ErrorIsReturned(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+
+	// act
+	err := metaData.AddBlock(" ", "value")
+
+	// assert
+	if err == nil {
+		t.Errorf("AddBlock should return an error if the name is empty.")
+	}
+
+	if len(metaData.Blocks) != 0 {
+		t.Errorf("AddBlock should not add a block if the name is empty.")
+	}
+}
+
+func Test_AddBlock_ValidName_BlockIsAdded(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+
+	// act
+	err := metaData.AddBlock("Author", " John Doe ")
+
+	// assert
+	if err != nil {
+		t.Errorf("AddBlock should not return an error but returned %q.", err)
+	}
+
+	if value := metaData.GetBlockValue("author"); value != "John Doe" {
+		t.Errorf("The value of the block should be %q but was %q.", "John Doe", value)
+	}
+}
+
+func Test_GetBlockValueOk_AbsentEmptyAndPresentBlocks(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+	metaData.AddBlock("draft", "")
+	metaData.AddBlock("author", "John Doe")
+
+	inputs := []struct {
+		name          string
+		expectedValue string
+		expectedFound bool
+	}{
+		{"missing", "", false},
+		{"draft", "", true},
+		{"Author", "John Doe", true},
+	}
+
+	for _, input := range inputs {
+
+		// act
+		value, found := metaData.GetBlockValueOk(input.name)
+
+		// assert
+		if value != input.expectedValue || found != input.expectedFound {
+			t.Errorf("GetBlockValueOk(%q) should return (%q, %t) but returned (%q, %t).", input.name, input.expectedValue, input.expectedFound, value, found)
+		}
+	}
+}
+
+func Test_GetBlockValueOrDefault_AbsentBlock_DefaultIsReturned(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+	metaData.AddBlock("draft", "")
+
+	// act
+	absentResult := metaData.GetBlockValueOrDefault("missing", "default")
+	emptyResult := metaData.GetBlockValueOrDefault("draft", "default")
+
+	// assert
+	if absentResult != "default" {
+		t.Errorf("GetBlockValueOrDefault should return the default value for a missing block but returned %q.", absentResult)
+	}
+
+	if emptyResult != "" {
+		t.Errorf("GetBlockValueOrDefault should return the empty value of an existing block but returned %q.", emptyResult)
+	}
+}
+
+func Test_GetBlockValues_RepeatedBlocks_AllValuesAreReturned(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+	metaData.AddBlock("author", "John Doe")
+	metaData.AddBlock("language", "en")
+	metaData.AddBlock("Author", "Jane Doe")
+
+	// act
+	result := metaData.GetBlockValues("author")
+
+	// assert
+	if len(result) != 2 || result[0] != "John Doe" || result[1] != "Jane Doe" {
+		t.Errorf("GetBlockValues should return both authors in order but returned %v.", result)
+	}
+
+	if missing := metaData.GetBlockValues("missing"); len(missing) != 0 {
+		t.Errorf("GetBlockValues should return an empty list for a missing block but returned %v.", missing)
+	}
+}
+
+func Test_RemoveBlock_AllMatchingBlocksAreRemoved(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+	metaData.AddBlock("draft", "true")
+	metaData.AddBlock("author", "John Doe")
+	metaData.AddBlock("Draft", "false")
+
+	// act
+	removed := metaData.RemoveBlock("DRAFT")
+
+	// assert
+	if !removed {
+		t.Errorf("RemoveBlock should report that blocks have been removed.")
+	}
+
+	if len(metaData.Blocks) != 1 || metaData.Blocks[0].Name != "author" {
+		t.Errorf("Only the author block should remain but the blocks are %v.", metaData.Blocks)
+	}
+}
+
+func Test_RemoveBlock_MissingBlock_FalseIsReturned(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+	metaData.AddBlock("author", "John Doe")
+
+	// act
+	removed := metaData.RemoveBlock("draft")
+
+	// assert
+	if removed {
+		t.Errorf("RemoveBlock should return false if there is no matching block.")
+	}
+
+	if len(metaData.Blocks) != 1 {
+		t.Errorf("The existing blocks should not be modified but the blocks are %v.", metaData.Blocks)
+	}
+}
+
+func Test_UpdateBlock_ExistingBlock_FirstMatchIsReplaced(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+	metaData.AddBlock("author", "John Doe")
+	metaData.AddBlock("Author", "Jane Doe")
+
+	// act
+	err := metaData.UpdateBlock("AUTHOR", "Max Mustermann")
+
+	// assert
+	if err != nil {
+		t.Errorf("UpdateBlock should not return an error but returned %s.", err)
+	}
+
+	values := metaData.GetBlockValues("author")
+	if len(values) != 2 || values[0] != "Max Mustermann" || values[1] != "Jane Doe" {
+		t.Errorf("Only the first author should have been replaced but the values are %v.", values)
+	}
+}
+
+func Test_UpdateBlock_MissingBlock_BlockIsAppended(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+	metaData.AddBlock("author", "John Doe")
+
+	// act
+	err := metaData.UpdateBlock("language", "de")
+
+	// assert
+	if err != nil {
+		t.Errorf("UpdateBlock should not return an error but returned %s.", err)
+	}
+
+	if len(metaData.Blocks) != 2 || metaData.Blocks[1].String() != "language: de" {
+		t.Errorf("The language block should have been appended but the blocks are %v.", metaData.Blocks)
+	}
+}
+
+func Test_UpdateBlock_EmptyName_ErrorIsReturned(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+
+	// act
+	err := metaData.UpdateBlock(" ", "value")
+
+	// assert
+	if err == nil {
+		t.Errorf("UpdateBlock should return an error for an empty name.")
+	}
+
+	if len(metaData.Blocks) != 0 {
+		t.Errorf("No block should have been added but the blocks are %v.", metaData.Blocks)
+	}
+}
+
+func Test_GetBlockDate_AcceptedLayouts_DateIsReturned(t *testing.T) {
+	// arrange
+	inputs := map[string]time.Time{
+		"2015-03-01T21:13:00+01:00": time.Date(2015, time.March, 1, 20, 13, 0, 0, time.UTC),
+		"2015-03-01 21:13":          time.Date(2015, time.March, 1, 21, 13, 0, 0, time.UTC),
+		"2015-03-01":                time.Date(2015, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for value, expectedResult := range inputs {
+		metaData := NewMetaData()
+		metaData.AddBlock("date", value)
+
+		// act
+		result, err := metaData.GetBlockDate("Date")
+
+		// assert
+		if err != nil {
+			t.Errorf("GetBlockDate should not return an error for %q but returned: %s", value, err)
+		}
+
+		if !result.Equal(expectedResult) {
+			t.Errorf("GetBlockDate should return %s for %q but returned %s.", expectedResult, value, result)
+		}
+	}
+}
+
+func Test_GetBlockDate_MalformedValue_ErrorIsReturned(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+	metaData.AddBlock("date", "01.03.2015")
+
+	// act
+	_, err := metaData.GetBlockDate("date")
+
+	// assert
+	if err == nil {
+		t.Errorf("GetBlockDate should return an error for a malformed date.")
+	}
+}
+
+func Test_GetBlockDate_MissingBlock_ErrorIsReturned(t *testing.T) {
+	// arrange
+	metaData := NewMetaData()
+
+	// act
+	_, err := metaData.GetBlockDate("date")
+
+	// assert
+	if err == nil {
+		t.Errorf("GetBlockDate should return an error if there is no date block.")
+	}
+}
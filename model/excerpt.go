@@ -0,0 +1,110 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// The suffix which is appended to truncated excerpts.
+const excerptEllipsis = "…"
+
+var (
+	// excerptOmittedElementPattern matches the elements which are no part of an excerpt (headlines, code blocks, scripts and styles).
+	excerptOmittedElementPattern = regexp.MustCompile(`(?is)<h[1-6]\b.*?</h[1-6]\s*>|<pre\b.*?</pre\s*>|<script\b.*?</script\s*>|<style\b.*?</style\s*>`)
+
+	// blockElementEndPattern matches the end of the block elements which separate the paragraphs of an excerpt (e.g. "</p>", "</li>").
+	blockElementEndPattern = regexp.MustCompile(`(?i)</(?:p|div|li|dt|dd|blockquote|table|tr|section|article|figure|figcaption)\s*>`)
+
+	// lineBreakPattern matches line breaks (e.g. "<br>", "<br/>").
+	lineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+	// htmlTagPattern matches opening, closing and self-closing HTML tags (e.g. "<b>", "</b>", "<br/>").
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+	// paragraphSeparatorPattern matches one or more empty lines.
+	paragraphSeparatorPattern = regexp.MustCompile(`\n\s*\n`)
+)
+
+// Excerpt returns a short plain-text summary of the item. The summary is the value of the "summary" or
+// "description" block, the description line or, if the item has none of them, the text of the first
+// paragraphs of the supplied rendered HTML content of the item (see GetExcerpt).
+// The summary is truncated at a word boundary to maxChars characters (including the ellipsis).
+func (item *Item) Excerpt(htmlContent string, maxChars int) string {
+	for _, blockName := range []string{"summary", "description"} {
+		if value := item.MetaData.GetBlockValue(blockName); strings.TrimSpace(value) != "" {
+			return truncateAtWordBoundary(getExcerptText(value), maxChars)
+		}
+	}
+
+	if description := strings.TrimSpace(item.Description); description != "" {
+		return truncateAtWordBoundary(getExcerptText(description), maxChars)
+	}
+
+	return GetExcerpt(htmlContent, maxChars)
+}
+
+// GetExcerpt returns the plain text of the first paragraphs of the supplied HTML code truncated at a word boundary
+// to maxChars characters (including the ellipsis). Headlines and code blocks are no part of the excerpt, HTML tags
+// are stripped and entities are unescaped. If maxChars is zero or negative only the first paragraph is returned
+// without truncation.
+func GetExcerpt(htmlCode string, maxChars int) string {
+	htmlCode = excerptOmittedElementPattern.ReplaceAllString(htmlCode, "\n\n")
+	htmlCode = blockElementEndPattern.ReplaceAllString(htmlCode, "\n\n")
+	htmlCode = lineBreakPattern.ReplaceAllString(htmlCode, " ")
+
+	text := ""
+	for _, paragraph := range paragraphSeparatorPattern.Split(strings.Replace(htmlCode, "\r\n", "\n", -1), -1) {
+		paragraph = getExcerptText(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		if text == "" {
+			text = paragraph
+		} else {
+			text += " " + paragraph
+		}
+
+		if maxChars <= 0 || utf8.RuneCountInString(text) >= maxChars {
+			break
+		}
+	}
+
+	return truncateAtWordBoundary(text, maxChars)
+}
+
+// getExcerptText returns the supplied HTML code without tags, with unescaped entities and normalized whitespace.
+func getExcerptText(htmlCode string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(htmlTagPattern.ReplaceAllString(htmlCode, ""))), " ")
+}
+
+// truncateAtWordBoundary shortens the supplied text to at most maxChars characters (including the ellipsis)
+// without cutting words in half. Texts which are short enough are returned unchanged.
+func truncateAtWordBoundary(text string, maxChars int) string {
+	runes := []rune(text)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return text
+	}
+
+	ellipsisLength := utf8.RuneCountInString(excerptEllipsis)
+	if maxChars <= ellipsisLength {
+		return excerptEllipsis
+	}
+
+	truncated := string(runes[:maxChars-ellipsisLength])
+
+	// cut off the partial word (unless the text was truncated right before a space)
+	if runes[maxChars-ellipsisLength] != ' ' {
+		if lastSpace := strings.LastIndex(truncated, " "); lastSpace > 0 {
+			truncated = truncated[:lastSpace]
+		}
+	}
+
+	return strings.TrimRight(truncated, " .,;:") + excerptEllipsis
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func Test_Excerpt_SummaryBlock_BlockValueIsReturned(t *testing.T) {
+	// arrange
+	item := &Item{Description: "The description line"}
+	item.MetaData.AddBlock("summary", "  An explicit summary  ")
+
+	// act
+	result := item.Excerpt("<p>The first paragraph of the content.</p>", 100)
+
+	// assert
+	if result != "An explicit summary" {
+		t.Errorf("The excerpt should be %q but was %q.", "An explicit summary", result)
+	}
+}
+
+func Test_Excerpt_DescriptionBlock_BlockValueIsReturned(t *testing.T) {
+	// arrange
+	item := &Item{}
+	item.MetaData.AddBlock("description", "An explicit description")
+
+	// act
+	result := item.Excerpt("<p>The first paragraph of the content.</p>", 100)
+
+	// assert
+	if result != "An explicit description" {
+		t.Errorf("The excerpt should be %q but was %q.", "An explicit description", result)
+	}
+}
+
+func Test_Excerpt_LongSummaryAndDescription_AreTruncated(t *testing.T) {
+	// arrange
+	withSummary := &Item{}
+	withSummary.MetaData.AddBlock("summary", "The quick brown fox jumps over the lazy dog.")
+	withDescription := &Item{Description: "The quick brown fox jumps over the lazy dog."}
+
+	for _, item := range []*Item{withSummary, withDescription} {
+
+		// act
+		result := item.Excerpt("", 20)
+
+		// assert
+		if result != "The quick brown fox…" {
+			t.Errorf("The excerpt should be %q but was %q.", "The quick brown fox…", result)
+		}
+	}
+}
+
+func Test_Excerpt_LongContent_IsTruncatedAtAWordBoundary(t *testing.T) {
+	// arrange
+	item := &Item{}
+
+	// act
+	result := item.Excerpt("<h2>Introduction</h2>\n<p>The quick brown fox jumps over the lazy dog.</p>\n<p>Second paragraph.</p>", 20)
+
+	// assert
+	if result != "The quick brown fox…" {
+		t.Errorf("The excerpt should be %q but was %q.", "The quick brown fox…", result)
+	}
+
+	if utf8.RuneCountInString(result) > 20 {
+		t.Errorf("The excerpt should not be longer than %d characters but was %d.", 20, utf8.RuneCountInString(result))
+	}
+}
+
+func Test_Excerpt_ShortParagraphs_AreCombined(t *testing.T) {
+	// arrange
+	item := &Item{}
+
+	// act
+	result := item.Excerpt("<p>First paragraph.</p><p>Second paragraph.</p><p>Third paragraph which is not needed.</p>", 30)
+
+	// assert
+	if result != "First paragraph. Second…" {
+		t.Errorf("The excerpt should be %q but was %q.", "First paragraph. Second…", result)
+	}
+}
+
+func Test_Excerpt_ShortContent_IsNotTruncated(t *testing.T) {
+	// arrange
+	item := &Item{}
+
+	// act
+	result := item.Excerpt("<p>A short text.</p>", 100)
+
+	// assert
+	if result != "A short text." {
+		t.Errorf("The excerpt should be %q but was %q.", "A short text.", result)
+	}
+}
+
+func Test_Excerpt_HTMLContent_TagsAreStripped(t *testing.T) {
+	// arrange
+	item := &Item{}
+
+	// act
+	result := item.Excerpt("<p>Some <strong>bold</strong> and<br/> <a href=\"/x\">linked</a>\ntext.</p>", 100)
+
+	// assert
+	if result != "Some bold and linked text." {
+		t.Errorf("The excerpt should be %q but was %q.", "Some bold and linked text.", result)
+	}
+}
+
+func Test_GetExcerpt_RenderedMarkdown_HeadlinesAndCodeAreOmittedAndEntitiesAreUnescaped(t *testing.T) {
+	// arrange
+	html := "<h1>Title</h1>\n<pre><code>go build ./...\n</code></pre>\n<ul>\n<li>Fish &amp; Chips</li>\n<li>Use <code>&lt;b&gt;</code></li>\n</ul>"
+
+	// act
+	result := GetExcerpt(html, 100)
+
+	// assert
+	if result != "Fish & Chips Use <b>" {
+		t.Errorf("The excerpt should be %q but was %q.", "Fish & Chips Use <b>", result)
+	}
+}
@@ -5,15 +5,74 @@
 package model
 
 import (
-	"github.com/andreaskoch/allmark/dataaccess"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
 	"strings"
+
+	"github.com/andreaskoch/allmark/common/util/exifutil"
+	"github.com/andreaskoch/allmark/dataaccess"
 )
 
+// The extensions of the image files returned by Item.ImageFiles.
+var imageFileExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".svg", ".tif", ".tiff"}
+
 // A File represents a file ressource that is associated with an Item.
 type File struct {
 	dataaccess.File
 }
 
+// GetHash returns the SHA-1 hash of the file content (e.g. for fingerprinted asset URLs like "photo.jpg?v=<hash>").
+// An empty string is returned if the file cannot be read.
+func (file *File) GetHash() string {
+	hash, err := file.ContentHash()
+	if err != nil {
+		return ""
+	}
+
+	return hash
+}
+
+// GetImageDimensions returns the width and height of image files. Only the image header is decoded
+// so the full pixel data is never loaded into memory.
+// An error is returned if the file is not an image in a supported format (gif, jpeg or png).
+func (file *File) GetImageDimensions() (width, height int, err error) {
+	err = file.Data(func(content io.ReadSeeker) error {
+		config, _, decodeErr := image.DecodeConfig(content)
+		if decodeErr != nil {
+			return fmt.Errorf("Unable to determine the dimensions of file %q. Error: %s", file, decodeErr)
+		}
+
+		width, height = config.Width, config.Height
+		return nil
+	})
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return width, height, nil
+}
+
+// GetExifTags returns the capture date, camera model and GPS position (in decimal degrees)
+// from the EXIF data of JPEG images (see the tag names in the exifutil package).
+// Files without EXIF data result in an empty map.
+func (file *File) GetExifTags() map[string]string {
+	tags := make(map[string]string)
+	file.Data(func(content io.ReadSeeker) error {
+		if exifTags, err := exifutil.Parse(content); err == nil {
+			tags = exifTags
+		}
+
+		return nil
+	})
+
+	return tags
+}
+
 // IsImageFile returns true if the supplied file model is an image.
 func IsImageFile(file *File) bool {
 	mimetype, err := GetMimeType(file)
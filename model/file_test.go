@@ -0,0 +1,85 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/andreaskoch/allmark/dataaccess"
+)
+
+// A testDataFile is a dataaccess.File with in-memory content.
+type testDataFile struct {
+	dataaccess.File
+	data []byte
+}
+
+func (file testDataFile) Data(contentReader func(content io.ReadSeeker) error) error {
+	return contentReader(bytes.NewReader(file.data))
+}
+
+func (file testDataFile) String() string {
+	return "test file"
+}
+
+func Test_GetImageDimensions_PNG_DimensionsAreReturned(t *testing.T) {
+	// arrange
+	buffer := new(bytes.Buffer)
+	png.Encode(buffer, image.NewRGBA(image.Rect(0, 0, 3, 2)))
+	file := &File{testDataFile{data: buffer.Bytes()}}
+
+	// act
+	width, height, err := file.GetImageDimensions()
+
+	// assert
+	if err != nil {
+		t.Errorf("GetImageDimensions should not return an error but returned: %s", err)
+	}
+
+	if width != 3 || height != 2 {
+		t.Errorf("GetImageDimensions should return 3x2 but returned %dx%d.", width, height)
+	}
+}
+
+func Test_GetImageDimensions_JPEG_DimensionsAreReturned(t *testing.T) {
+	// arrange
+	buffer := new(bytes.Buffer)
+	jpeg.Encode(buffer, image.NewRGBA(image.Rect(0, 0, 16, 9)), nil)
+	file := &File{testDataFile{data: buffer.Bytes()}}
+
+	// act
+	width, height, err := file.GetImageDimensions()
+
+	// assert
+	if err != nil {
+		t.Errorf("GetImageDimensions should not return an error but returned: %s", err)
+	}
+
+	if width != 16 || height != 9 {
+		t.Errorf("GetImageDimensions should return 16x9 but returned %dx%d.", width, height)
+	}
+}
+
+func Test_GetImageDimensions_NoImage_ErrorIsReturned(t *testing.T) {
+	// arrange
+	file := &File{testDataFile{data: []byte("%PDF-1.4")}}
+
+	// act
+	width, height, err := file.GetImageDimensions()
+
+	// assert
+	if err == nil {
+		t.Errorf("GetImageDimensions should return an error for files which are no images.")
+	}
+
+	if width != 0 || height != 0 {
+		t.Errorf("GetImageDimensions should return 0x0 for files which are no images but returned %dx%d.", width, height)
+	}
+}
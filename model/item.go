@@ -6,10 +6,15 @@ package model
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/common/util/hashutil"
 	"github.com/andreaskoch/allmark/dataaccess"
 )
 
@@ -26,7 +31,23 @@ func (itemType ItemType) String() string {
 	case TypeRepository:
 		return "repository"
 
+	case TypeRedirect:
+		return "redirect"
+
+	case TypeCollection:
+		return "collection"
+
+	case TypeComment:
+		return "comment"
+
+	case TypeMessage:
+		return "message"
+
 	default:
+		if typeName, isCustomType := customItemTypes[itemType]; isCustomType {
+			return typeName
+		}
+
 		return "unknown"
 
 	}
@@ -34,13 +55,67 @@ func (itemType ItemType) String() string {
 	panic("Unreachable")
 }
 
+// IsCustom returns true if the item type is not one of the built-in types but was registered with NewCustomItemType.
+func (itemType ItemType) IsCustom() bool {
+	_, isCustomType := customItemTypes[itemType]
+	return isCustomType
+}
+
 const (
 	TypeDocument ItemType = iota
 	TypePresentation
 	TypeRepository
+	TypeRedirect
+	TypeCollection
+	TypeComment
+	TypeMessage
 	TypeUnknown
 )
 
+// customItemTypes contains the names of all registered custom item types.
+var customItemTypes = make(map[ItemType]string)
+
+// GetItemTypeByName returns the built-in or custom item type with the given (case-insensitive) name.
+func GetItemTypeByName(typeName string) (itemType ItemType, found bool) {
+	typeName = strings.ToLower(strings.TrimSpace(typeName))
+
+	for _, builtInType := range []ItemType{TypeDocument, TypePresentation, TypeRepository, TypeRedirect, TypeCollection, TypeComment, TypeMessage} {
+		if builtInType.String() == typeName {
+			return builtInType, true
+		}
+	}
+
+	for customType, customTypeName := range customItemTypes {
+		if customTypeName == typeName {
+			return customType, true
+		}
+	}
+
+	return TypeUnknown, false
+}
+
+// NewCustomItemType registers a new item type with the given name (e.g. "recipe") and returns it.
+// If a built-in or custom type with the same name already exists, the existing type is returned.
+// Custom item types are parsed like documents.
+func NewCustomItemType(typeName string) (ItemType, error) {
+	typeName = strings.ToLower(strings.TrimSpace(typeName))
+	if typeName == "" {
+		return TypeUnknown, fmt.Errorf("The name of an item type cannot be empty.")
+	}
+
+	if typeName == TypeUnknown.String() {
+		return TypeUnknown, fmt.Errorf("%q is not a valid name for an item type.", typeName)
+	}
+
+	if existingType, exists := GetItemTypeByName(typeName); exists {
+		return existingType, nil
+	}
+
+	customType := TypeUnknown + ItemType(len(customItemTypes)+1)
+	customItemTypes[customType] = typeName
+	return customType, nil
+}
+
 // An Item represents a single document.
 type Item struct {
 	route      route.Route
@@ -57,6 +132,14 @@ type Item struct {
 	Hash string
 
 	MetaData MetaData
+
+	// the parent item in the item tree (not serialized)
+	parent *Item
+
+	// the cached content hashes with and without the children (see GetContentHash)
+	contentHashLock            sync.Mutex
+	contentHash                string
+	contentHashWithoutChildren string
 }
 
 func NewItem(route route.Route, files []*File, sourceType dataaccess.ItemType) *Item {
@@ -73,6 +156,161 @@ func (item *Item) String() string {
 	return fmt.Sprintf("%s", item.route)
 }
 
+// GetParent returns the parent of this item and a flag indicating whether the item has a parent.
+// The parent is assigned when the item is added to an index; the root item has no parent.
+func (item *Item) GetParent() (parent *Item, exists bool) {
+	return item.parent, item.parent != nil
+}
+
+// SetParent assigns the supplied parent to this item. Use nil to remove the parent reference.
+func (item *Item) SetParent(parent *Item) {
+	item.parent = parent
+}
+
+// GetBreadcrumbs returns the ancestors of this item ordered from the root down to the parent of this item.
+// The list does not contain the item itself and is empty for the root.
+func (item *Item) GetBreadcrumbs() []*Item {
+	ancestors := make([]*Item, 0)
+	for parent, exists := item.GetParent(); exists; parent, exists = parent.GetParent() {
+		ancestors = append([]*Item{parent}, ancestors...)
+	}
+
+	return ancestors
+}
+
+// ShortHash returns the short form of the item hash for display purposes.
+// Use the full Hash for cache keys and comparisons.
+func (item *Item) ShortHash() string {
+	return hashutil.Short(item.Hash)
+}
+
+// GetContentHash returns a hash over the item itself, the hashes of all its files and the content hashes
+// of all child items returned by the supplied getChildren function (recursively).
+// Unlike the Hash this changes whenever anything in the rendered subtree of the item changes.
+// Files and children are sorted by route so the hash is stable across runs.
+// The hash is calculated only once and then cached on the item; changed items and their parents
+// are parsed again into new items, so the cached hash never becomes stale.
+func (item *Item) GetContentHash(getChildren func(parent *Item) []*Item) string {
+	item.contentHashLock.Lock()
+	defer item.contentHashLock.Unlock()
+
+	if getChildren == nil {
+		if item.contentHashWithoutChildren == "" {
+			item.contentHashWithoutChildren = item.calculateContentHash(nil)
+		}
+
+		return item.contentHashWithoutChildren
+	}
+
+	if item.contentHash == "" {
+		item.contentHash = item.calculateContentHash(getChildren)
+	}
+
+	return item.contentHash
+}
+
+// calculateContentHash calculates the content hash of the item (see GetContentHash).
+func (item *Item) calculateContentHash(getChildren func(parent *Item) []*Item) string {
+	hashes := []string{item.Hash}
+
+	// files
+	files := make([]*File, len(item.files))
+	copy(files, item.files)
+	sort.Sort(filesByRoute(files))
+
+	for _, file := range files {
+		fileHash, err := file.Hash()
+		if err != nil {
+			fileHash = file.Route().Value()
+		}
+
+		hashes = append(hashes, fileHash)
+	}
+
+	// children
+	if getChildren != nil {
+		children := getChildren(item)
+		sort.Sort(itemsByRoute(children))
+
+		for _, child := range children {
+			hashes = append(hashes, child.GetContentHash(getChildren))
+		}
+	}
+
+	return hashutil.SHA1FromString(strings.Join(hashes, "\n"))
+}
+
+// Date returns the value of the "date" block of the item
+// or the creation date if the item has no valid date block.
+func (item *Item) Date() time.Time {
+	if date, err := item.MetaData.GetBlockDate("date"); err == nil {
+		return date
+	}
+
+	return item.MetaData.CreationDate
+}
+
+// IsDraft returns true if the item has a "draft" block with a true value (e.g. "draft: true" or "draft: yes")
+// or a "published" block with a false value (e.g. "published: no"). Items are published by default.
+// Drafts are not published unless the preview is enabled.
+func (item *Item) IsDraft() bool {
+	if isDraft, ok := parseBlockBool(item.MetaData.GetBlockValue("draft")); ok && isDraft {
+		return true
+	}
+
+	if isPublished, ok := parseBlockBool(item.MetaData.GetBlockValue("published")); ok && !isPublished {
+		return true
+	}
+
+	return false
+}
+
+// IsScheduled returns true if the "date" block of the item is after the supplied time.
+// Scheduled items are treated like drafts until their date has passed.
+func (item *Item) IsScheduled(now time.Time) bool {
+	date, err := item.MetaData.GetBlockDate("date")
+	return err == nil && date.After(now)
+}
+
+// parseBlockBool parses boolean block values (true/false, yes/no, on/off, 1/0).
+// The flag indicates whether the value could be parsed.
+func parseBlockBool(value string) (result bool, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "on":
+		return true, true
+
+	case "no", "off":
+		return false, true
+	}
+
+	result, err := strconv.ParseBool(strings.TrimSpace(value))
+	return result, err == nil
+}
+
+// Tags returns the lowercased tags of the item from the meta data and from its "tags" blocks.
+// Comma-separated values (e.g. "tags: go, markdown") are split up; empty and duplicate tags are dropped.
+func (item *Item) Tags() []string {
+	tags := make([]string, 0)
+	uniqueTags := make(map[string]bool)
+
+	values := append([]string{}, item.MetaData.Tags...)
+	values = append(values, item.MetaData.GetBlockValues("tags")...)
+
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || uniqueTags[tag] {
+				continue
+			}
+
+			uniqueTags[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
 func (item *Item) FolderName() string {
 	return item.route.LastComponentName()
 }
@@ -85,6 +323,34 @@ func (item *Item) Files() []*File {
 	return item.files
 }
 
+// FilesByExtension returns all files of the item whose extension matches any of the
+// supplied extensions (case-insensitive, with or without the leading dot, e.g. ".pdf" or "PDF").
+// If no extensions are supplied all files are returned.
+func (item *Item) FilesByExtension(extensions ...string) []*File {
+	if len(extensions) == 0 {
+		return item.files
+	}
+
+	normalizedExtensions := make(map[string]bool)
+	for _, extension := range extensions {
+		normalizedExtensions["."+strings.ToLower(strings.TrimPrefix(extension, "."))] = true
+	}
+
+	files := make([]*File, 0)
+	for _, file := range item.files {
+		if normalizedExtensions[strings.ToLower(filepath.Ext(file.Name()))] {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// ImageFiles returns all image files (e.g. jpg, png or gif) of the item.
+func (item *Item) ImageFiles() []*File {
+	return item.FilesByExtension(imageFileExtensions...)
+}
+
 // Get the file which matches the supplied route. Returns nil if there is no matching file.
 func (item *Item) GetFile(fileRoute route.Route) *File {
 	for _, file := range item.Files() {
@@ -137,3 +403,21 @@ func (sorter *modelSorter) Swap(i, j int) {
 func (sorter *modelSorter) Less(i, j int) bool {
 	return sorter.by(sorter.items[i], sorter.items[j])
 }
+
+// itemsByRoute sorts items by their route.
+type itemsByRoute []*Item
+
+func (items itemsByRoute) Len() int      { return len(items) }
+func (items itemsByRoute) Swap(i, j int) { items[i], items[j] = items[j], items[i] }
+func (items itemsByRoute) Less(i, j int) bool {
+	return items[i].Route().Value() < items[j].Route().Value()
+}
+
+// filesByRoute sorts files by their route.
+type filesByRoute []*File
+
+func (files filesByRoute) Len() int      { return len(files) }
+func (files filesByRoute) Swap(i, j int) { files[i], files[j] = files[j], files[i] }
+func (files filesByRoute) Less(i, j int) bool {
+	return files[i].Route().Value() < files[j].Route().Value()
+}
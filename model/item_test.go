@@ -0,0 +1,264 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/dataaccess"
+)
+
+// A testFile is a dataaccess.File which only has a name.
+type testFile struct {
+	dataaccess.File
+	name string
+}
+
+func (file testFile) Name() string {
+	return file.name
+}
+
+func newTestItemWithFiles(fileNames ...string) *Item {
+	files := make([]*File, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		files = append(files, &File{testFile{name: fileName}})
+	}
+
+	return &Item{files: files}
+}
+
+func getFileNames(files []*File) string {
+	fileNames := make([]string, 0, len(files))
+	for _, file := range files {
+		fileNames = append(fileNames, file.Name())
+	}
+
+	return strings.Join(fileNames, "|")
+}
+
+func Test_Tags(t *testing.T) {
+	// arrange
+	inputs := []struct {
+		blocks       []string
+		expectedTags string
+	}{
+		{[]string{"go,markdown,web"}, "go|markdown|web"},
+		{[]string{"  go ,  markdown  , , web  "}, "go|markdown|web"},
+		{[]string{"Go, markdown, go", "MARKDOWN", "web"}, "go|markdown|web"},
+		{[]string{}, ""},
+	}
+
+	for _, input := range inputs {
+		item := &Item{}
+		for _, value := range input.blocks {
+			item.MetaData.AddBlock("tags", value)
+		}
+
+		// act
+		result := strings.Join(item.Tags(), "|")
+
+		// assert
+		if result != input.expectedTags {
+			t.Errorf("The tags of %q should be %q but were %q.", input.blocks, input.expectedTags, result)
+		}
+	}
+}
+
+func Test_Tags_MetaDataTagsAreMergedWithTagBlocks(t *testing.T) {
+	// arrange
+	item := &Item{}
+	item.MetaData.Tags = []string{"Go", "Web"}
+	item.MetaData.AddBlock("tags", "markdown, go")
+
+	// act
+	result := strings.Join(item.Tags(), "|")
+
+	// assert
+	if result != "go|web|markdown" {
+		t.Errorf("The tags should be %q but were %q.", "go|web|markdown", result)
+	}
+}
+
+func Test_FilesByExtension_NoExtensions_AllFilesAreReturned(t *testing.T) {
+	// arrange
+	item := newTestItemWithFiles("photo.jpg", "document.pdf", "notes")
+
+	// act
+	result := getFileNames(item.FilesByExtension())
+
+	// assert
+	if result != "photo.jpg|document.pdf|notes" {
+		t.Errorf("FilesByExtension without extensions should return all files but returned %q.", result)
+	}
+}
+
+func Test_FilesByExtension_MixedCase_MatchingFilesAreReturned(t *testing.T) {
+	// arrange
+	item := newTestItemWithFiles("photo.JPG", "document.pdf", "Manual.PDF", "notes.txt", "pdf")
+
+	// act
+	result := getFileNames(item.FilesByExtension("Pdf", ".jpg"))
+
+	// assert
+	if result != "photo.JPG|document.pdf|Manual.PDF" {
+		t.Errorf("FilesByExtension should return the jpg and pdf files but returned %q.", result)
+	}
+}
+
+func Test_ImageFiles_OnlyImagesAreReturned(t *testing.T) {
+	// arrange
+	item := newTestItemWithFiles("photo.jpg", "document.pdf", "diagram.PNG", "animation.gif")
+
+	// act
+	result := getFileNames(item.ImageFiles())
+
+	// assert
+	if result != "photo.jpg|diagram.PNG|animation.gif" {
+		t.Errorf("ImageFiles should return the image files but returned %q.", result)
+	}
+}
+
+func Test_IsDraft(t *testing.T) {
+	// arrange
+	inputs := map[string]bool{
+		"true":  true,
+		"True":  true,
+		"yes":   true,
+		"1":     true,
+		"false": false,
+		"no":    false,
+		"":      false,
+		"maybe": false,
+	}
+
+	for value, expectedResult := range inputs {
+		item := &Item{}
+		item.MetaData.AddBlock("draft", value)
+
+		// act
+		result := item.IsDraft()
+
+		// assert
+		if result != expectedResult {
+			t.Errorf("IsDraft() should return %t for %q but returned %t.", expectedResult, value, result)
+		}
+	}
+}
+
+func Test_IsDraft_PublishedBlock(t *testing.T) {
+	// arrange
+	inputs := map[string]bool{
+		"true":  false,
+		"yes":   false,
+		"1":     false,
+		"false": true,
+		"No":    true,
+		"0":     true,
+		"":      false,
+	}
+
+	for value, expectedResult := range inputs {
+		item := &Item{}
+		item.MetaData.AddBlock("published", value)
+
+		// act
+		result := item.IsDraft()
+
+		// assert
+		if result != expectedResult {
+			t.Errorf("IsDraft() should return %t for %q but returned %t.", expectedResult, "published: "+value, result)
+		}
+	}
+}
+
+func Test_IsDraft_NoBlocks_ItemIsPublished(t *testing.T) {
+	// arrange
+	item := &Item{}
+
+	// act
+	result := item.IsDraft()
+
+	// assert
+	if result {
+		t.Errorf("Items without a draft or published block should be published.")
+	}
+}
+
+func Test_IsScheduled(t *testing.T) {
+	// arrange
+	now := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	inputs := map[string]bool{
+		"2015-05-31": false,
+		"2015-06-02": true,
+		"":           false,
+		"someday":    false,
+	}
+
+	for value, expectedResult := range inputs {
+		item := &Item{}
+		item.MetaData.AddBlock("date", value)
+
+		// act
+		result := item.IsScheduled(now)
+
+		// assert
+		if result != expectedResult {
+			t.Errorf("IsScheduled() should return %t for %q but returned %t.", expectedResult, value, result)
+		}
+	}
+}
+
+func Test_GetContentHash_CalledTwice_ChildrenAreHashedOnlyOnce(t *testing.T) {
+	// arrange
+	parent := &Item{Hash: "parent"}
+	child := &Item{Hash: "child"}
+
+	calls := 0
+	getChildren := func(item *Item) []*Item {
+		calls++
+		if item == parent {
+			return []*Item{child}
+		}
+
+		return []*Item{}
+	}
+
+	// act
+	first := parent.GetContentHash(getChildren)
+	second := parent.GetContentHash(getChildren)
+
+	// assert
+	if first != second {
+		t.Errorf("The content hash should be %q but was %q.", first, second)
+	}
+
+	if calls != 2 {
+		t.Errorf("The children should have been fetched %d times but were fetched %d times.", 2, calls)
+	}
+}
+
+func Test_GetContentHash_WithoutChildren_HashDiffersFromTheHashWithChildren(t *testing.T) {
+	// arrange
+	parent := &Item{Hash: "parent"}
+	child := &Item{Hash: "child"}
+	getChildren := func(item *Item) []*Item {
+		if item == parent {
+			return []*Item{child}
+		}
+
+		return []*Item{}
+	}
+
+	// act
+	withChildren := parent.GetContentHash(getChildren)
+	withoutChildren := parent.GetContentHash(nil)
+
+	// assert
+	if withChildren == withoutChildren {
+		t.Errorf("The content hash without children should differ from the content hash with children (%q).", withChildren)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+)
+
+// itemJSON is the public JSON representation of an item.
+// It contains no filesystem paths and no reference to the parent item.
+type itemJSON struct {
+	Type     string     `json:"type"`
+	Title    string     `json:"title"`
+	URL      string     `json:"url"`
+	Hash     string     `json:"hash"`
+	Children []itemJSON `json:"children,omitempty"`
+}
+
+// MarshalJSON returns the public JSON representation of the item (type, title, URL and hash) without any children.
+func (item *Item) MarshalJSON() ([]byte, error) {
+	return json.Marshal(item.toJSON(nil, 0))
+}
+
+// MarshalJSONWithChildren returns the public JSON representation of the item including the children returned
+// by the supplied getChildren function down to the given depth (1 = direct children only, negative = unlimited).
+func (item *Item) MarshalJSONWithChildren(getChildren func(parent *Item) []*Item, depth int) ([]byte, error) {
+	return json.Marshal(item.toJSON(getChildren, depth))
+}
+
+func (item *Item) toJSON(getChildren func(parent *Item) []*Item, depth int) itemJSON {
+	model := itemJSON{
+		Type:  item.Type.String(),
+		Title: item.MetaData.GetBlockValueOrDefault("title", item.Title),
+		URL:   "/" + item.Route().Value(),
+		Hash:  item.Hash,
+	}
+
+	if getChildren == nil || depth == 0 {
+		return model
+	}
+
+	for _, child := range getChildren(item) {
+		model.Children = append(model.Children, child.toJSON(getChildren, depth-1))
+	}
+
+	return model
+}
+
+// itemBlocksJSON is the public JSON representation of the meta data of an item.
+type itemBlocksJSON struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Excerpt  string         `json:"excerpt"`
+	URL      string         `json:"url"`
+	Hash     string         `json:"hash"`
+	Blocks   []blockJSON    `json:"blocks"`
+	Children []itemLinkJSON `json:"children"`
+}
+
+// blockJSON is a name-value pair of the meta data section of an item.
+type blockJSON struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// itemLinkJSON is a link to an item.
+type itemLinkJSON struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// MarshalBlocksJSON returns the public JSON representation of the meta data of the item: the type, title, the supplied
+// excerpt (see Excerpt), URL and hash, all blocks of the meta data section in their original order
+// and the links to the supplied children. Items without blocks or children have empty lists.
+func (item *Item) MarshalBlocksJSON(children []*Item, excerpt string) ([]byte, error) {
+	model := itemBlocksJSON{
+		Type:     item.Type.String(),
+		Title:    item.GetTitle(),
+		Excerpt:  excerpt,
+		URL:      "/" + item.Route().Value(),
+		Hash:     item.Hash,
+		Blocks:   []blockJSON{},
+		Children: []itemLinkJSON{},
+	}
+
+	for _, block := range item.MetaData.Blocks {
+		model.Blocks = append(model.Blocks, blockJSON{block.Name, block.Value})
+	}
+
+	for _, child := range children {
+		model.Children = append(model.Children, itemLinkJSON{child.GetTitle(), "/" + child.Route().Value()})
+	}
+
+	return json.Marshal(model)
+}
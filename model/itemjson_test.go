@@ -0,0 +1,102 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+)
+
+// A testPathFile is a dataaccess.File which is located at an absolute filesystem path.
+type testPathFile struct {
+	dataaccess.File
+	path string
+}
+
+func (file testPathFile) String() string {
+	return file.path
+}
+
+func newTestJSONItem(itemRoute, title string) *Item {
+	files := []*File{&File{testPathFile{path: "/home/user/repository/" + itemRoute + "/files/photo.jpg"}}}
+	item := NewItem(route.NewFromRequest(itemRoute), files, dataaccess.TypePhysical)
+	item.Title = title
+	item.Hash = "hash-of-" + title
+	item.Content = "<p>/home/user/repository/" + itemRoute + "</p>"
+	return item
+}
+
+func Test_MarshalJSON_PublicKeysAreSerializedWithoutAbsolutePaths(t *testing.T) {
+	// arrange
+	item := newTestJSONItem("guides/install", "Install")
+	item.MetaData.AddBlock("title", "Installation")
+	item.SetParent(newTestJSONItem("guides", "Guides"))
+
+	// act
+	bytes, err := json.Marshal(item)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The item could not be serialized. Error: %s", err)
+	}
+
+	var result map[string]interface{}
+	json.Unmarshal(bytes, &result)
+
+	expected := map[string]string{"type": "document", "title": "Installation", "url": "/guides/install", "hash": "hash-of-Install"}
+	if len(result) != len(expected) {
+		t.Errorf("The JSON should contain exactly the keys %v but was %s.", expected, bytes)
+	}
+
+	for key, value := range expected {
+		if result[key] != value {
+			t.Errorf("The value of %q should be %q but was %v.", key, value, result[key])
+		}
+	}
+
+	if strings.Contains(string(bytes), "/home/user") {
+		t.Errorf("The JSON must not contain absolute paths: %s", bytes)
+	}
+}
+
+func Test_MarshalJSONWithChildren_ChildrenAreIncludedDownToTheGivenDepth(t *testing.T) {
+	// arrange
+	root := newTestJSONItem("", "Home")
+	guides := newTestJSONItem("guides", "Guides")
+	install := newTestJSONItem("guides/install", "Install")
+
+	children := map[*Item][]*Item{root: {guides}, guides: {install}}
+	getChildren := func(parent *Item) []*Item {
+		return children[parent]
+	}
+
+	inputs := []struct {
+		depth    int
+		expected string
+	}{
+		{0, `{"type":"document","title":"Home","url":"/","hash":"hash-of-Home"}`},
+		{1, `{"type":"document","title":"Home","url":"/","hash":"hash-of-Home","children":[{"type":"document","title":"Guides","url":"/guides","hash":"hash-of-Guides"}]}`},
+		{-1, `{"type":"document","title":"Home","url":"/","hash":"hash-of-Home","children":[{"type":"document","title":"Guides","url":"/guides","hash":"hash-of-Guides","children":[{"type":"document","title":"Install","url":"/guides/install","hash":"hash-of-Install"}]}]}`},
+	}
+
+	for _, input := range inputs {
+
+		// act
+		bytes, err := root.MarshalJSONWithChildren(getChildren, input.depth)
+
+		// assert
+		if err != nil {
+			t.Fatalf("The item could not be serialized. Error: %s", err)
+		}
+
+		if string(bytes) != input.expected {
+			t.Errorf("The JSON with depth %d should be %s but was %s.", input.depth, input.expected, bytes)
+		}
+	}
+}
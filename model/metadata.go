@@ -17,6 +17,9 @@ type MetaData struct {
 	Aliases          []string
 	Author           string
 	GeoInformation   GeoInformation
+
+	// all name-value pairs of the meta data section (including the ones above)
+	Blocks []Block
 }
 
 // NewMetaData creates a new instance of the the MetaData struct.
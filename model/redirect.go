@@ -0,0 +1,47 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultRedirectStatusCode is the status code of redirects which do not define a status.
+const DefaultRedirectStatusCode = http.StatusMovedPermanently
+
+// RedirectTarget returns the target URL and the status code (301 or 302) of a redirect item
+// which are defined by the "target" and the optional "status" block (e.g. "target: /new/location", "status: 302").
+// The target must either be an absolute path or an absolute http(s) URL.
+func (item *Item) RedirectTarget() (target string, statusCode int, err error) {
+	target = strings.TrimSpace(item.MetaData.GetBlockValue("target"))
+	if target == "" {
+		return "", 0, fmt.Errorf("The redirect %q does not define a target.", item)
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return "", 0, fmt.Errorf("The target %q of the redirect %q is not a valid URL. Error: %s", target, item, err.Error())
+	}
+
+	isAbsoluteURL := (targetURL.Scheme == "http" || targetURL.Scheme == "https") && targetURL.Host != ""
+	isAbsolutePath := targetURL.Scheme == "" && targetURL.Host == "" && strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "//")
+	if !isAbsoluteURL && !isAbsolutePath {
+		return "", 0, fmt.Errorf("The target %q of the redirect %q must be an absolute path or an http(s) URL.", target, item)
+	}
+
+	statusCode = DefaultRedirectStatusCode
+	if status := strings.TrimSpace(item.MetaData.GetBlockValue("status")); status != "" {
+		statusCode, err = strconv.Atoi(status)
+		if err != nil || (statusCode != http.StatusMovedPermanently && statusCode != http.StatusFound) {
+			return "", 0, fmt.Errorf("The status %q of the redirect %q is not supported. Use 301 or 302.", status, item)
+		}
+	}
+
+	return target, statusCode, nil
+}
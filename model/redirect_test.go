@@ -0,0 +1,86 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"net/http"
+	"testing"
+)
+
+func newTestRedirect(target, status string) *Item {
+	item := &Item{Type: TypeRedirect}
+	if target != "" {
+		item.MetaData.AddBlock("target", target)
+	}
+
+	if status != "" {
+		item.MetaData.AddBlock("status", status)
+	}
+
+	return item
+}
+
+func Test_RedirectTarget_ValidTargets(t *testing.T) {
+	// arrange
+	inputs := []struct {
+		target             string
+		status             string
+		expectedStatusCode int
+	}{
+		{"/new/location", "", http.StatusMovedPermanently},
+		{"/new/location?page=2#top", "301", http.StatusMovedPermanently},
+		{"https://example.com/docs", "302", http.StatusFound},
+		{"http://example.com", " 302 ", http.StatusFound},
+	}
+
+	for _, input := range inputs {
+		item := newTestRedirect(input.target, input.status)
+
+		// act
+		target, statusCode, err := item.RedirectTarget()
+
+		// assert
+		if err != nil {
+			t.Errorf("The redirect to %q (status %q) should be valid but returned an error: %s", input.target, input.status, err)
+			continue
+		}
+
+		if target != input.target {
+			t.Errorf("The target should be %q but was %q.", input.target, target)
+		}
+
+		if statusCode != input.expectedStatusCode {
+			t.Errorf("The status code for %q should be %d but was %d.", input.status, input.expectedStatusCode, statusCode)
+		}
+	}
+}
+
+func Test_RedirectTarget_InvalidTargets_ErrorIsReturned(t *testing.T) {
+	// arrange
+	inputs := []struct {
+		target string
+		status string
+	}{
+		{"", ""},
+		{"new/location", ""},
+		{"//example.com/docs", ""},
+		{"ftp://example.com/docs", ""},
+		{"javascript:alert(1)", ""},
+		{"/new/location", "307"},
+		{"/new/location", "permanent"},
+	}
+
+	for _, input := range inputs {
+		item := newTestRedirect(input.target, input.status)
+
+		// act
+		_, _, err := item.RedirectTarget()
+
+		// assert
+		if err == nil {
+			t.Errorf("The redirect to %q (status %q) should be invalid.", input.target, input.status)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// headingLevelOnePattern matches markdown headlines of the first level (e.g. "# Title" or "#Title #").
+	headingLevelOnePattern = regexp.MustCompile(`^#\s*([^#\s].*?)\s*#*\s*$`)
+
+	// titleSeparatorPattern matches the characters which separate words in directory names (e.g. "my-first_post").
+	titleSeparatorPattern = regexp.MustCompile(`[-_\s]+`)
+)
+
+// GetTitle returns the title of the item: the value of the "title" block if there is one,
+// else the first level-one headline of the markdown and else a humanized form of the
+// directory name (e.g. "my-first-post" → "My First Post").
+func (item *Item) GetTitle() string {
+	if title := strings.TrimSpace(item.MetaData.GetBlockValue("title")); title != "" {
+		return title
+	}
+
+	if title := getFirstHeadingLevelOne(item.Markdown); title != "" {
+		return title
+	}
+
+	return humanize(item.FolderName())
+}
+
+// getFirstHeadingLevelOne returns the text of the first level-one headline outside of code blocks
+// in the supplied markdown or an empty string if there is none.
+func getFirstHeadingLevelOne(markdown string) string {
+	isCodeBlock := false
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			isCodeBlock = !isCodeBlock
+			continue
+		}
+
+		if isCodeBlock {
+			continue
+		}
+
+		if matches := headingLevelOnePattern.FindStringSubmatch(line); len(matches) > 1 {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
+
+// humanize converts the supplied directory name into a title by replacing
+// dashes and underscores with spaces and capitalizing every word.
+func humanize(name string) string {
+	words := titleSeparatorPattern.Split(strings.TrimSpace(name), -1)
+
+	titleWords := make([]string, 0, len(words))
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		titleWords = append(titleWords, string(runes))
+	}
+
+	return strings.Join(titleWords, " ")
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+)
+
+func Test_GetTitle_TitleBlock_BlockValueIsReturned(t *testing.T) {
+	// arrange
+	item := NewItem(route.NewFromRequest("documents/my-post"), nil, 0)
+	item.Markdown = "# The Headline\n\nSome content"
+	item.MetaData.AddBlock("title", "The Title Block")
+
+	// act
+	result := item.GetTitle()
+
+	// assert
+	if result != "The Title Block" {
+		t.Errorf("The title should be %q but was %q.", "The Title Block", result)
+	}
+}
+
+func Test_GetTitle_NoTitleBlock_FirstHeadingLevelOneIsReturned(t *testing.T) {
+	// arrange
+	item := NewItem(route.NewFromRequest("documents/my-post"), nil, 0)
+	item.Markdown = "Some text\n\n```\n# not a headline\n```\n\n## Subheadline\n\n# The Headline #\n\n# Another Headline"
+
+	// act
+	result := item.GetTitle()
+
+	// assert
+	if result != "The Headline" {
+		t.Errorf("The title should be %q but was %q.", "The Headline", result)
+	}
+}
+
+func Test_GetTitle_NoTitleBlockAndNoHeadline_HumanizedDirectoryNameIsReturned(t *testing.T) {
+	// arrange
+	item := NewItem(route.NewFromRequest("documents/my-first_post"), nil, 0)
+	item.Markdown = "Just some text.\n\n## Only a subheadline"
+
+	// act
+	result := item.GetTitle()
+
+	// assert
+	if result != "My First Post" {
+		t.Errorf("The title should be %q but was %q.", "My First Post", result)
+	}
+}
@@ -6,10 +6,13 @@ package converter
 
 import (
 	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/model"
 )
 
 type Converter interface {
-	// Convert the supplied item with all paths relative to the supplied base route
-	Convert(aliasResolver func(alias string) *model.Item, pathProvider paths.Pather, item *model.Item) (convertedContent string, converterError error)
+	// Convert the supplied item with all paths relative to the supplied base route.
+	// The alias resolver returns the item with a given alias (or nil), the title resolver all items with a given title
+	// and the item resolver the item with a given route (or nil).
+	Convert(aliasResolver func(alias string) *model.Item, titleResolver func(title string) []*model.Item, itemResolver func(itemRoute route.Route) *model.Item, pathProvider paths.Pather, item *model.Item) (convertedContent string, converterError error)
 }
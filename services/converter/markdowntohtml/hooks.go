@@ -0,0 +1,73 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markdowntohtml
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+// A Hook transforms the content of an item during its conversion. Pre-render hooks receive the markdown
+// of the item before it is rendered, post-render hooks receive the HTML code after it has been rendered.
+// An error aborts the conversion of the item.
+type Hook func(item *model.Item, content string) (string, error)
+
+// A namedHook is a hook of the conversion pipeline. The name identifies the hook in error messages.
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+var (
+	hooksLock sync.RWMutex
+
+	// the registered hooks in the order of their registration
+	registeredPreRenderHooks  []namedHook
+	registeredPostRenderHooks []namedHook
+)
+
+// RegisterPreRenderHook adds a hook which is applied to the markdown of every item before the built-in steps
+// (includes, shortcodes and the preprocessor) and before the markdown is rendered.
+// The hooks are applied in the order of their registration.
+func RegisterPreRenderHook(name string, hook Hook) {
+	hooksLock.Lock()
+	defer hooksLock.Unlock()
+
+	registeredPreRenderHooks = append(registeredPreRenderHooks, namedHook{name, hook})
+}
+
+// RegisterPostRenderHook adds a hook which is applied to the HTML code of every item after the built-in steps
+// (shortcodes and the postprocessor). The content of included items is inserted after the hooks have been applied
+// (the included items pass through the hooks themselves). The hooks are applied in the order of their registration.
+func RegisterPostRenderHook(name string, hook Hook) {
+	hooksLock.Lock()
+	defer hooksLock.Unlock()
+
+	registeredPostRenderHooks = append(registeredPostRenderHooks, namedHook{name, hook})
+}
+
+// getRegisteredHooks returns a copy of the supplied registered hooks.
+func getRegisteredHooks(hooks *[]namedHook) []namedHook {
+	hooksLock.RLock()
+	defer hooksLock.RUnlock()
+
+	return append([]namedHook(nil), (*hooks)...)
+}
+
+// runHooks applies the supplied hooks in order to the content of the given item.
+// The stage (e.g. "pre-render") is used in the error message if one of the hooks fails.
+func runHooks(stage string, hooks []namedHook, item *model.Item, content string) (string, error) {
+	for _, namedHook := range hooks {
+		var err error
+		content, err = namedHook.hook(item, content)
+		if err != nil {
+			return "", fmt.Errorf("The %s hook %q failed for item %q. Error: %s", stage, namedHook.name, item, err.Error())
+		}
+	}
+
+	return content, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markdowntohtml
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// the registered hooks only change the items below this route so they don't affect the other tests
+const testHookRoute = "hooktests"
+
+func init() {
+	onlyTestItems := func(hook Hook) Hook {
+		return func(item *model.Item, content string) (string, error) {
+			if !strings.HasPrefix(item.Route().Value(), testHookRoute+"/") {
+				return content, nil
+			}
+
+			return hook(item, content)
+		}
+	}
+
+	RegisterPreRenderHook("first", onlyTestItems(func(item *model.Item, markdown string) (string, error) {
+		return markdown + "\n\nfirst", nil
+	}))
+
+	RegisterPreRenderHook("second", onlyTestItems(func(item *model.Item, markdown string) (string, error) {
+		return strings.Replace(markdown, "first", "first, second", 1) + "\n\n{{figure src=\"http://example.com/banner.png\"}}", nil
+	}))
+
+	RegisterPostRenderHook("classes", onlyTestItems(func(item *model.Item, html string) (string, error) {
+		return strings.Replace(html, "<figure class=\"figure\">", "<figure class=\"figure banner\">", -1), nil
+	}))
+
+	RegisterPostRenderHook("failure", onlyTestItems(func(item *model.Item, html string) (string, error) {
+		if strings.Contains(html, "fail") {
+			return "", fmt.Errorf("The item contains %q", "fail")
+		}
+
+		return "<div class=\"wrapper\">" + html + "</div>", nil
+	}))
+}
+
+func convertHookTestItem(content string) (string, error) {
+	item := model.NewItem(route.NewFromRequest(testHookRoute+"/sample"), nil, dataaccess.TypePhysical)
+	item.Content = content
+
+	aliasResolver := func(alias string) *model.Item { return nil }
+	titleResolver := func(title string) []*model.Item { return nil }
+	itemResolver := func(itemRoute route.Route) *model.Item { return nil }
+
+	converter := New(console.New(loglevel.Off), config.Markdown{}, nil)
+	return converter.Convert(aliasResolver, titleResolver, itemResolver, testIncludePather{}, item)
+}
+
+func Test_Convert_Hooks_AreAppliedInOrder(t *testing.T) {
+	// arrange
+	content := "Content"
+
+	// act
+	html, err := convertHookTestItem(content)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The conversion failed: %s", err)
+	}
+
+	if !strings.HasPrefix(html, "<div class=\"wrapper\"><p>Content</p>") || !strings.HasSuffix(html, "</div>") {
+		t.Errorf("The post-render hook should have wrapped the rendered content: %s", html)
+	}
+
+	if !strings.Contains(html, "<p>first, second</p>") {
+		t.Errorf("The pre-render hooks should have been applied in the order of their registration: %s", html)
+	}
+}
+
+func Test_Convert_Hooks_BuiltInStepsAreAppliedBetweenTheRegisteredHooks(t *testing.T) {
+	// arrange
+	content := "Content"
+
+	// act
+	html, err := convertHookTestItem(content)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The conversion failed: %s", err)
+	}
+
+	// the shortcode of the pre-render hook has been expanded and post-processed (lazy loading) before the post-render hook
+	expected := `<figure class="figure banner"><img loading="lazy" src="http://example.com/banner.png"`
+	if !strings.Contains(html, expected) {
+		t.Errorf("The result should contain %q but was: %s", expected, html)
+	}
+}
+
+func Test_Convert_HookFails_ConversionIsAborted(t *testing.T) {
+	// arrange
+	content := "This will fail"
+
+	// act
+	html, err := convertHookTestItem(content)
+
+	// assert
+	if err == nil {
+		t.Fatalf("The conversion should have failed but returned: %s", html)
+	}
+
+	expected := `The post-render hook "failure" failed for item "hooktests > sample". Error: The item contains "fail"`
+	if err.Error() != expected {
+		t.Errorf("The error should be %q but was %q.", expected, err.Error())
+	}
+}
+
+func Test_runHooks(t *testing.T) {
+	// arrange
+	var calls []string
+	hooks := []namedHook{
+		{"a", func(item *model.Item, content string) (string, error) {
+			calls = append(calls, "a")
+			return content + "a", nil
+		}},
+		{"b", func(item *model.Item, content string) (string, error) {
+			calls = append(calls, "b")
+			return content + "b", nil
+		}},
+	}
+
+	// act
+	result, err := runHooks("test", hooks, nil, ">")
+
+	// assert
+	if err != nil || result != ">ab" {
+		t.Errorf("runHooks returned %q (%v) but expected %q.", result, err, ">ab")
+	}
+
+	if strings.Join(calls, ",") != "a,b" {
+		t.Errorf("The hooks should have been called in order but the calls were %v.", calls)
+	}
+}
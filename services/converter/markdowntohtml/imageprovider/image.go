@@ -25,7 +25,8 @@ type ImageProvider struct {
 }
 
 // GetImagePath returns the image path for the given file route.
-// If one or more thumbnais exist it will return the thumbnail path (e.g. srcset="/thumbnails/105-D6134C1B-320-240.png 320w, /thumbnails/105-D6134C1B-640-480.png 640w, /thumbnails/105-D6134C1B-1024-768.png 1024w").
+// If one or more thumbnais exist it will return the thumbnail path (e.g. srcset="/thumbnails/105-D6134C1B-320-240.png 320w, /thumbnails/105-D6134C1B-640-480.png 640w, /thumbnails/105-D6134C1B-1024-768.png 1024w")
+// and the matching sizes attribute (e.g. sizes="(max-width: 1024px) 100vw, 1024px").
 // If there is no thumbnail is will just return the canonical image path (e.g. src="document/files/sample.png")
 func (provider *ImageProvider) GetImagePath(imagePathProvider paths.Pather, fileRoute route.Route) string {
 
@@ -42,21 +43,28 @@ func (provider *ImageProvider) GetImagePath(imagePathProvider paths.Pather, file
 	// assemble the src sets
 	if smallExists || mediumExists || largeExists {
 
+		var largestWidth uint
 		srcSets := make([]string, 0)
 		if smallExists {
 			srcSets = append(srcSets, small+fmt.Sprintf(" %vw", thumbnail.SizeSmall.MaxWidth))
+			largestWidth = thumbnail.SizeSmall.MaxWidth
 		}
 
 		if mediumExists {
 			srcSets = append(srcSets, medium+fmt.Sprintf(" %vw", thumbnail.SizeMedium.MaxWidth))
+			largestWidth = thumbnail.SizeMedium.MaxWidth
 		}
 
 		if largeExists {
 			srcSets = append(srcSets, large+fmt.Sprintf(" %vw", thumbnail.SizeLarge.MaxWidth))
+			largestWidth = thumbnail.SizeLarge.MaxWidth
 		}
 
 		if len(srcSets) > 0 {
 			imagePath += fmt.Sprintf(` srcset="%s"`, strings.Join(srcSets, `, `))
+
+			// the image is displayed with the full viewport width up to the width of the largest thumbnail
+			imagePath += fmt.Sprintf(` sizes="(max-width: %vpx) 100vw, %vpx"`, largestWidth, largestWidth)
 		}
 	}
 
@@ -0,0 +1,59 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imageprovider
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/services/thumbnail"
+)
+
+type testPather struct{}
+
+func (p testPather) Path(itemPath string) string {
+	return "/" + itemPath
+}
+
+func (p testPather) Base() route.Route {
+	return route.New()
+}
+
+func Test_GetImagePath_ThumbnailsExist_SrcSetAndSizesAreReturned(t *testing.T) {
+	// arrange
+	fileRoute := route.NewFromRequest("document/files/sample.png")
+
+	thumbnailIndex := thumbnail.EmptyIndex()
+	thumbnailIndex.SetThumbs(fileRoute.Value(), thumbnail.Thumbs{
+		thumbnail.SizeSmall.String():  thumbnail.Thumb{Path: "1-320-240.png", Dimensions: thumbnail.SizeSmall},
+		thumbnail.SizeMedium.String(): thumbnail.Thumb{Path: "1-640-480.png", Dimensions: thumbnail.SizeMedium},
+	})
+
+	imageProvider := NewImageProvider(testPather{}, thumbnailIndex)
+
+	// act
+	result := imageProvider.GetImagePath(testPather{}, fileRoute)
+
+	// assert
+	expected := ` srcset="/thumbnails/1-320-240.png 320w, /thumbnails/1-640-480.png 640w" sizes="(max-width: 640px) 100vw, 640px" src="/document/files/sample.png"`
+	if result != expected {
+		t.Errorf("The image path should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_GetImagePath_NoThumbnails_OnlySrcIsReturned(t *testing.T) {
+	// arrange
+	fileRoute := route.NewFromRequest("document/files/sample.png")
+	imageProvider := NewImageProvider(testPather{}, thumbnail.EmptyIndex())
+
+	// act
+	result := imageProvider.GetImagePath(testPather{}, fileRoute)
+
+	// assert
+	expected := ` src="/document/files/sample.png"`
+	if result != expected {
+		t.Errorf("The image path should be %q but was %q.", expected, result)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markdowntohtml
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+)
+
+var (
+	// {{include: /path/of/the/included/item}}
+	includePattern = regexp.MustCompile(`\{\{\s*include:\s*([^}]*?)\s*\}\}`)
+
+	// the placeholders which replace the include directives until the markdown has been rendered
+	includePlaceholderPattern = getPlaceholderPattern("include")
+)
+
+// insertIncludePlaceholders replaces all include directives of the supplied markdown which are not part of code
+// with placeholders and returns the targets of the directives.
+func insertIncludePlaceholders(markdown string) (string, []string) {
+	var targets []string
+	codeRanges := getMarkdownCodeRanges(markdown)
+
+	var result bytes.Buffer
+	position := 0
+	for _, location := range includePattern.FindAllStringSubmatchIndex(markdown, -1) {
+		if isInRanges(codeRanges, location[0]) {
+			continue
+		}
+
+		targets = append(targets, markdown[location[2]:location[3]])
+
+		result.WriteString(markdown[position:location[0]])
+		result.WriteString(getPlaceholder("include", len(targets)-1))
+		position = location[1]
+	}
+
+	result.WriteString(markdown[position:])
+	return result.String(), targets
+}
+
+// replaceIncludePlaceholders replaces the include placeholders of the supplied HTML code with the result of the render function
+// for the respective target.
+func replaceIncludePlaceholders(htmlCode string, targets []string, render func(target string) string) string {
+	return replacePlaceholders(htmlCode, includePlaceholderPattern, len(targets), func(index int) string {
+		return render(targets[index])
+	})
+}
+
+// getPlaceholder returns the placeholder of the given kind and index (e.g. `<span data-placeholder="include-0"></span>`).
+// The placeholders are raw HTML elements because they are passed through unchanged by the markdown renderer,
+// no matter if they are part of a paragraph, a heading or a list (HTML comments are only kept on their own line).
+func getPlaceholder(kind string, index int) string {
+	return fmt.Sprintf(`<span data-placeholder="%s-%d"></span>`, kind, index)
+}
+
+// getPlaceholderPattern returns the pattern which matches the placeholders of the given kind
+// including the paragraph tags around them.
+func getPlaceholderPattern(kind string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:<p>)?<span data-placeholder="` + regexp.QuoteMeta(kind) + `-([0-9]+)"></span>(?:</p>)?`)
+}
+
+// replacePlaceholders replaces the placeholders which are matched by the supplied pattern (see getPlaceholderPattern)
+// with the result of the render function for their index. A placeholder which is the only content of a paragraph
+// replaces the whole paragraph. Placeholders with an index outside of the given count are not changed.
+func replacePlaceholders(htmlCode string, placeholderPattern *regexp.Regexp, count int, render func(index int) string) string {
+	return placeholderPattern.ReplaceAllStringFunc(htmlCode, func(placeholder string) string {
+		match := placeholderPattern.FindStringSubmatch(placeholder)
+
+		var index int
+		fmt.Sscanf(match[1], "%d", &index)
+		if index >= count {
+			return placeholder
+		}
+
+		// keep unbalanced paragraph tags (e.g. "<p>Text <span data-placeholder="include-0"></span></p>")
+		prefix, suffix := "", ""
+		if strings.HasPrefix(placeholder, "<p>") && !strings.HasSuffix(placeholder, "</p>") {
+			prefix = "<p>"
+		}
+
+		if strings.HasSuffix(placeholder, "</p>") && !strings.HasPrefix(placeholder, "<p>") {
+			suffix = "</p>"
+		}
+
+		return prefix + render(index) + suffix
+	})
+}
+
+// GetIncludeRoutes returns the routes of the items which are directly included by the supplied item
+// (the include directives of its markdown which are not part of code). Comments cannot include other items.
+func GetIncludeRoutes(item *model.Item) []route.Route {
+	if item.Type == model.TypeComment {
+		return nil
+	}
+
+	_, targets := insertIncludePlaceholders(item.Content)
+
+	routes := make([]route.Route, 0, len(targets))
+	for _, target := range targets {
+		routes = append(routes, getIncludeRoute(item.Route(), target))
+	}
+
+	return routes
+}
+
+// getIncludeRoute returns the route of the include target. Targets which start with a slash are relative to the repository,
+// all others are relative to the including item (e.g. "../shared/notice").
+func getIncludeRoute(itemRoute route.Route, target string) route.Route {
+	if !strings.HasPrefix(target, "/") {
+		target = path.Join("/"+itemRoute.Value(), target)
+	}
+
+	return route.NewFromRequest(target)
+}
+
+// getIncludeError returns a visible error marker with the supplied message.
+func getIncludeError(format string, arguments ...interface{}) string {
+	return fmt.Sprintf(`<div class="include-error">%s</div>`, html.EscapeString(fmt.Sprintf(format, arguments...)))
+}
+
+// getIncludeCycle returns the routes of the include cycle if the include route is already on the include stack.
+func getIncludeCycle(includeStack []route.Route, includeRoute route.Route) ([]string, bool) {
+	for index, includingRoute := range includeStack {
+		if !includingRoute.Equals(includeRoute) {
+			continue
+		}
+
+		var cycle []string
+		for _, cycleRoute := range includeStack[index:] {
+			cycle = append(cycle, "/"+cycleRoute.Value())
+		}
+
+		return append(cycle, "/"+includeRoute.Value()), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,175 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markdowntohtml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+type testIncludePather struct{}
+
+func (testIncludePather) Path(itemPath string) string {
+	return "/" + itemPath
+}
+
+func (testIncludePather) Base() route.Route {
+	return route.New()
+}
+
+// convertTestItem converts the item with the given route of a repository with the supplied items (route -> markdown).
+func convertTestItem(t *testing.T, items map[string]string, itemRoute string) string {
+	itemsByRoute := make(map[string]*model.Item)
+	for itemPath, content := range items {
+		item := model.NewItem(route.NewFromRequest(itemPath), nil, dataaccess.TypePhysical)
+		item.Content = content
+		itemsByRoute[item.Route().Value()] = item
+	}
+
+	itemResolver := func(itemRoute route.Route) *model.Item {
+		return itemsByRoute[itemRoute.Value()]
+	}
+
+	aliasResolver := func(alias string) *model.Item { return nil }
+	titleResolver := func(title string) []*model.Item { return nil }
+
+	converter := New(console.New(loglevel.Off), config.Markdown{}, nil)
+	html, err := converter.Convert(aliasResolver, titleResolver, itemResolver, testIncludePather{}, itemsByRoute[route.NewFromRequest(itemRoute).Value()])
+	if err != nil {
+		t.Fatalf("The item %q could not be converted. Error: %s", itemRoute, err)
+	}
+
+	return html
+}
+
+func Test_Convert_Include_ContentOfTheIncludedItemIsInlined(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/sample":  "Before\n\n{{include: /shared/notice}}\n\nBetween\n\n{{include: ../license}}\n\nAfter",
+		"documents/license": "Licensed under the *BSD* license.",
+		"shared/notice":     "This is a **shared** notice.",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/sample")
+
+	// assert
+	expected := "<p>Before</p>\n\n" +
+		"<p>This is a <strong>shared</strong> notice.</p>\n\n\n" +
+		"<p>Between</p>\n\n" +
+		"<p>Licensed under the <em>BSD</em> license.</p>\n\n\n" +
+		"<p>After</p>\n"
+
+	if html != expected {
+		t.Errorf("The result should be %q but was %q.", expected, html)
+	}
+}
+
+func Test_Convert_NestedIncludes_AreResolvedRelativeToTheIncludedItem(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/sample":     "{{include: /shared/notice}}",
+		"shared/notice":        "Notice\n\n{{include: footer}}",
+		"shared/notice/footer": "Footer",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/sample")
+
+	// assert
+	if !strings.Contains(html, "<p>Notice</p>") || !strings.Contains(html, "<p>Footer</p>") {
+		t.Errorf("The nested include should be resolved: %q", html)
+	}
+}
+
+func Test_Convert_IncludeTargetIsMissing_ErrorMarkerIsRendered(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/sample": "Before\n\n{{include: /shared/missing}}\n\nAfter",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/sample")
+
+	// assert
+	expected := `<div class="include-error">Include &#34;/shared/missing&#34; not found</div>`
+	if !strings.Contains(html, expected) {
+		t.Errorf("The result should contain %q but was %q.", expected, html)
+	}
+
+	if !strings.Contains(html, "<p>Before</p>") || !strings.Contains(html, "<p>After</p>") {
+		t.Errorf("The rest of the content should still be rendered: %q", html)
+	}
+}
+
+func Test_Convert_CyclicInclude_ErrorMarkerContainsTheCycle(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/a": "A\n\n{{include: /documents/b}}",
+		"documents/b": "B\n\n{{include: /documents/c}}",
+		"documents/c": "C\n\n{{include: /documents/a}}",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/a")
+
+	// assert
+	expected := `<div class="include-error">Circular include: /documents/a -&gt; /documents/b -&gt; /documents/c -&gt; /documents/a</div>`
+	if !strings.Contains(html, expected) {
+		t.Errorf("The result should contain %q but was %q.", expected, html)
+	}
+
+	for _, paragraph := range []string{"<p>A</p>", "<p>B</p>", "<p>C</p>"} {
+		if strings.Count(html, paragraph) != 1 {
+			t.Errorf("The content %q should be included exactly once: %q", paragraph, html)
+		}
+	}
+}
+
+func Test_Convert_SelfInclude_ErrorMarkerIsRendered(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/a": "A\n\n{{include: /documents/a}}",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/a")
+
+	// assert
+	expected := `<div class="include-error">Circular include: /documents/a -&gt; /documents/a</div>`
+	if !strings.Contains(html, expected) {
+		t.Errorf("The result should contain %q but was %q.", expected, html)
+	}
+}
+
+func Test_Convert_IncludeInCode_DirectiveIsNotReplaced(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/sample": "Use `{{include: /shared/notice}}` to include a notice:\n\n" +
+			"```\n{{include: /shared/notice}}\n```\n\n" +
+			"    {{include: /shared/notice}}\n\n" +
+			"{{include: /shared/notice}}",
+		"shared/notice": "This is a **shared** notice.",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/sample")
+
+	// assert
+	if count := strings.Count(html, "<strong>shared</strong>"); count != 1 {
+		t.Errorf("Only the include outside of code should have been replaced but the notice was included %d times: %q", count, html)
+	}
+
+	if count := strings.Count(html, "{{include: /shared/notice}}"); count != 3 {
+		t.Errorf("The three includes in code should have been rendered as code but %d were found: %q", count, html)
+	}
+}
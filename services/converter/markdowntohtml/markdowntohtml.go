@@ -5,8 +5,12 @@
 package markdowntohtml
 
 import (
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/config"
 	"github.com/andreaskoch/allmark/common/logger"
 	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/model"
 	"github.com/andreaskoch/allmark/services/converter/markdowntohtml/imageprovider"
 	"github.com/andreaskoch/allmark/services/converter/markdowntohtml/postprocessor"
@@ -17,61 +21,172 @@ import (
 // Converter converts markdown to HTML
 type Converter struct {
 	logger        logger.Logger
+	extensions    config.Markdown
 	preprocessor  *preprocessor.Preprocessor
 	postprocessor *postprocessor.Postprocessor
 }
 
-// New creates a new Markdown-to-HTML converter instance.
-func New(logger logger.Logger, imageProvider *imageprovider.ImageProvider) *Converter {
+// New creates a new Markdown-to-HTML converter instance which uses the supplied markdown extensions.
+func New(logger logger.Logger, extensions config.Markdown, imageProvider *imageprovider.ImageProvider) *Converter {
 	return &Converter{
 		logger:        logger,
+		extensions:    extensions,
 		preprocessor:  preprocessor.New(logger, imageProvider),
 		postprocessor: postprocessor.New(logger, imageProvider),
 	}
 }
 
 // Convert the supplied item with all paths relative to the supplied base route
-func (converter *Converter) Convert(aliasResolver func(alias string) *model.Item, pathProvider paths.Pather, item *model.Item) (convertedContent string, converterError error) {
+func (converter *Converter) Convert(aliasResolver func(alias string) *model.Item, titleResolver func(title string) []*model.Item, itemResolver func(itemRoute route.Route) *model.Item, pathProvider paths.Pather, item *model.Item) (convertedContent string, converterError error) {
+	return converter.convert(aliasResolver, titleResolver, itemResolver, pathProvider, item, nil)
+}
+
+// convert converts the supplied item and all items it includes. The include stack contains the routes
+// of the items which (directly or indirectly) include the supplied item.
+// The conversion is a pipeline of hooks (see RegisterPreRenderHook and RegisterPostRenderHook) around the markdown renderer
+// in which the built-in steps are hooks too.
+func (converter *Converter) convert(aliasResolver func(alias string) *model.Item, titleResolver func(title string) []*model.Item, itemResolver func(itemRoute route.Route) *model.Item, pathProvider paths.Pather, item *model.Item, includeStack []route.Route) (convertedContent string, converterError error) {
 
 	converter.logger.Debug("Converting markdown for item %q.", item)
 
-	// preprocessor
-	rawMarkdownContent := item.Content
-	preprocessedMarkdownContent, err := converter.preprocessor.Convert(aliasResolver, pathProvider, item.Route(), item.Files(), rawMarkdownContent)
-	if err != nil {
-		return "", err
+	var includeTargets []string
+	var shortcodeInvocations []shortcode
+
+	// the built-in steps before the markdown is rendered
+	preRenderHooks := append(getRegisteredHooks(&registeredPreRenderHooks),
+
+		// includes
+		namedHook{"includes", func(item *model.Item, markdown string) (string, error) {
+			markdown, includeTargets = insertIncludePlaceholders(markdown)
+			return markdown, nil
+		}},
+
+		// shortcodes
+		namedHook{"shortcodes", func(item *model.Item, markdown string) (string, error) {
+			markdown, shortcodeInvocations = insertShortcodePlaceholders(markdown)
+			return markdown, nil
+		}},
+
+		// preprocessor
+		namedHook{"preprocessor", func(item *model.Item, markdown string) (string, error) {
+			return converter.preprocessor.Convert(aliasResolver, titleResolver, pathProvider, item.Route(), item.Files(), markdown)
+		}},
+	)
+
+	// the built-in steps after the markdown has been rendered
+	postRenderHooks := []namedHook{
+
+		// expand the shortcodes before the postprocessing so their links and images are processed too
+		namedHook{"shortcodes", func(item *model.Item, html string) (string, error) {
+			return replaceShortcodePlaceholders(html, shortcodeInvocations), nil
+		}},
+
+		// postprocessing
+		namedHook{"postprocessor", func(item *model.Item, html string) (string, error) {
+			return converter.postprocessor.Convert(pathProvider, item.Route(), item.Files(), html)
+		}},
 	}
 
-	// markdown to html
-	htmlContent := markdownToHTML(preprocessedMarkdownContent)
+	postRenderHooks = append(postRenderHooks, getRegisteredHooks(&registeredPostRenderHooks)...)
+
+	// insert the content of the included items
+	includeStack = append(includeStack[:len(includeStack):len(includeStack)], item.Route())
+	postRenderHooks = append(postRenderHooks, namedHook{"includes", func(item *model.Item, html string) (string, error) {
+		return replaceIncludePlaceholders(html, includeTargets, func(target string) string {
+
+			includeRoute := getIncludeRoute(item.Route(), target)
+			if cycle, isCycle := getIncludeCycle(includeStack, includeRoute); isCycle {
+				converter.logger.Warn("Circular include in item %q: %s", item, strings.Join(cycle, " -> "))
+				return getIncludeError("Circular include: %s", strings.Join(cycle, " -> "))
+			}
+
+			includedItem := itemResolver(includeRoute)
+			if includedItem == nil {
+				converter.logger.Warn("The item %q included by %q was not found.", target, item)
+				return getIncludeError("Include %q not found", target)
+			}
+
+			includedContent, err := converter.convert(aliasResolver, titleResolver, itemResolver, pathProvider, includedItem, includeStack)
+			if err != nil {
+				converter.logger.Warn("Cannot convert the item %q included by %q. Error: %s", target, item, err.Error())
+				return getIncludeError("Include %q cannot be converted", target)
+			}
+
+			return includedContent
+		}), nil
+	}})
+
+	// comments are written by visitors: they cannot include other items, use shortcodes or the markdown extensions of the preprocessor
+	// (e.g. wiki links) and raw HTML is removed
+	render := markdownToHTML
+	if item.Type == model.TypeComment {
+		preRenderHooks = getRegisteredHooks(&registeredPreRenderHooks)
+		render = untrustedMarkdownToHTML
+	}
 
-	// postprocessing
-	postProcessedHTMLContent, err := converter.postprocessor.Convert(pathProvider, item.Route(), item.Files(), htmlContent)
+	markdown, err := runHooks("pre-render", preRenderHooks, item, item.Content)
 	if err != nil {
 		return "", err
 	}
 
-	return postProcessedHTMLContent, nil
+	// markdown to html
+	htmlContent := render(markdown, converter.extensions)
+
+	return runHooks("post-render", postRenderHooks, item, htmlContent)
+}
+
+// markdownToHTML renders the supplied markdown with the given extensions.
+func markdownToHTML(markdown string, markdownExtensions config.Markdown) (html string) {
+	return renderMarkdown(markdown, markdownExtensions, 0)
 }
 
-func markdownToHTML(markdown string) (html string) {
+// untrustedMarkdownToHTML renders the supplied markdown of an untrusted author (e.g. a visitor comment) with the given extensions.
+// Raw HTML is removed and only links with safe protocols (e.g. "http", "mailto") are rendered.
+func untrustedMarkdownToHTML(markdown string, markdownExtensions config.Markdown) (html string) {
+	return renderMarkdown(markdown, markdownExtensions, blackfriday.HTML_SKIP_HTML|blackfriday.HTML_SAFELINK)
+}
+
+// renderMarkdown renders the supplied markdown with the given extensions and additional HTML renderer flags.
+func renderMarkdown(markdown string, markdownExtensions config.Markdown, additionalHTMLFlags int) (html string) {
 	// set up the HTML renderer
-	htmlFlags := 0
+	htmlFlags := additionalHTMLFlags
 	htmlFlags |= blackfriday.HTML_USE_XHTML
-	htmlFlags |= blackfriday.HTML_USE_SMARTYPANTS
-	htmlFlags |= blackfriday.HTML_SMARTYPANTS_FRACTIONS
-	htmlFlags |= blackfriday.HTML_SMARTYPANTS_LATEX_DASHES
+
+	// the typographer only changes text; code spans, code blocks, URLs and raw HTML are left as they are
+	if markdownExtensions.Typographer {
+		htmlFlags |= blackfriday.HTML_USE_SMARTYPANTS
+		htmlFlags |= blackfriday.HTML_SMARTYPANTS_FRACTIONS
+		htmlFlags |= blackfriday.HTML_SMARTYPANTS_LATEX_DASHES
+	}
+
 	renderer := blackfriday.HtmlRenderer(htmlFlags, "", "")
 
 	// set up the parser
 	extensions := 0
 	extensions |= blackfriday.EXTENSION_NO_INTRA_EMPHASIS
-	extensions |= blackfriday.EXTENSION_TABLES
 	extensions |= blackfriday.EXTENSION_FENCED_CODE
 	extensions |= blackfriday.EXTENSION_AUTOLINK
-	extensions |= blackfriday.EXTENSION_STRIKETHROUGH
 	extensions |= blackfriday.EXTENSION_SPACE_HEADERS
 	extensions |= blackfriday.EXTENSION_HARD_LINE_BREAK
 
-	return string(blackfriday.Markdown([]byte(markdown), renderer, extensions))
+	// optional extensions
+	if !markdownExtensions.DisableTables {
+		extensions |= blackfriday.EXTENSION_TABLES
+	}
+
+	if !markdownExtensions.DisableFootnotes {
+		extensions |= blackfriday.EXTENSION_FOOTNOTES
+	}
+
+	if !markdownExtensions.DisableStrikethrough {
+		extensions |= blackfriday.EXTENSION_STRIKETHROUGH
+	}
+
+	html = string(blackfriday.Markdown([]byte(markdown), renderer, extensions))
+
+	if !markdownExtensions.DisableTaskLists {
+		html = renderTaskLists(html)
+	}
+
+	return html
 }
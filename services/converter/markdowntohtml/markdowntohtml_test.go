@@ -0,0 +1,183 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markdowntohtml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+const (
+	testTableMarkdown = "| Name | Value |\n|------|-------|\n| a    | 1     |\n"
+
+	testFootnoteMarkdown = "A statement[^1].\n\n[^1]: The source.\n"
+
+	testStrikethroughMarkdown = "This is ~~wrong~~ right.\n"
+
+	testTaskListMarkdown = "- [ ] Open task\n- [x] Done task\n- A normal item\n"
+)
+
+func Test_markdownToHTML_DefaultExtensions_AllExtensionsAreRendered(t *testing.T) {
+	// arrange
+	markdown := strings.Join([]string{testTableMarkdown, testFootnoteMarkdown, testStrikethroughMarkdown, testTaskListMarkdown}, "\n")
+
+	// act
+	html := markdownToHTML(markdown, config.Markdown{})
+
+	// assert
+	expectedFragments := []string{
+		"<table>",
+		"<th>Name</th>",
+		`<sup class="footnote-ref" id="fnref:1"><a rel="footnote" href="#fn:1">1</a></sup>`,
+		`<div class="footnotes">`,
+		"<del>wrong</del>",
+		`<li class="task-list-item"><input type="checkbox" class="task-list-item-checkbox" disabled="disabled" /> Open task`,
+		`<li class="task-list-item"><input type="checkbox" class="task-list-item-checkbox" disabled="disabled" checked="checked" /> Done task`,
+		"<li>A normal item",
+	}
+
+	for _, fragment := range expectedFragments {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("The HTML should contain %q but it does not:\n%s", fragment, html)
+		}
+	}
+}
+
+func Test_markdownToHTML_TablesDisabled_TableIsLeftLiteral(t *testing.T) {
+	// act
+	html := markdownToHTML(testTableMarkdown, config.Markdown{DisableTables: true})
+
+	// assert
+	if strings.Contains(html, "<table>") || !strings.Contains(html, "| Name | Value |") {
+		t.Errorf("The table should not be rendered:\n%s", html)
+	}
+}
+
+func Test_markdownToHTML_FootnotesDisabled_FootnoteIsLeftLiteral(t *testing.T) {
+	// act
+	html := markdownToHTML(testFootnoteMarkdown, config.Markdown{DisableFootnotes: true})
+
+	// assert
+	if strings.Contains(html, "<sup") || !strings.Contains(html, "[^1]") {
+		t.Errorf("The footnote should not be rendered:\n%s", html)
+	}
+}
+
+func Test_markdownToHTML_StrikethroughDisabled_TextIsLeftLiteral(t *testing.T) {
+	// act
+	html := markdownToHTML(testStrikethroughMarkdown, config.Markdown{DisableStrikethrough: true})
+
+	// assert
+	if strings.Contains(html, "<del>") || !strings.Contains(html, "~~wrong~~") {
+		t.Errorf("The strikethrough text should not be rendered:\n%s", html)
+	}
+}
+
+func Test_markdownToHTML_TaskListsDisabled_MarkersAreLeftLiteral(t *testing.T) {
+	// act
+	html := markdownToHTML(testTaskListMarkdown, config.Markdown{DisableTaskLists: true})
+
+	// assert
+	if strings.Contains(html, "checkbox") || !strings.Contains(html, "<li>[ ] Open task") || !strings.Contains(html, "<li>[x] Done task") {
+		t.Errorf("The task list markers should not be rendered:\n%s", html)
+	}
+}
+
+func Test_renderTaskLists_LooseList_CheckboxIsPlacedInsideTheParagraph(t *testing.T) {
+	// arrange
+	html := "<ul>\n<li><p>[X] Done</p></li>\n\n<li><p>Not a [ ] task</p></li>\n</ul>"
+	expected := "<ul>\n<li class=\"task-list-item\"><p><input type=\"checkbox\" class=\"task-list-item-checkbox\" disabled=\"disabled\" checked=\"checked\" /> Done</p></li>\n\n<li><p>Not a [ ] task</p></li>\n</ul>"
+
+	// act
+	result := renderTaskLists(html)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+const testTypographerMarkdown = "He said \"hello\" -- it's a 'test' --- really...\n\n" +
+	"Use `\"quoted\" -- code...` inline.\n\n" +
+	"```\nprint(\"block\") -- 'code'...\n```\n\n" +
+	"See [the docs](http://example.com/a--b...c) and http://example.com/x--y.\n"
+
+func Test_markdownToHTML_TypographerEnabled_ProseIsConverted(t *testing.T) {
+	// act
+	html := markdownToHTML(testTypographerMarkdown, config.Markdown{Typographer: true})
+
+	// assert
+	expectedProse := "<p>He said &ldquo;hello&rdquo; &ndash; it&rsquo;s a &lsquo;test&rsquo; &mdash; really&hellip;</p>"
+	if !strings.Contains(html, expectedProse) {
+		t.Errorf("The HTML should contain %q but it does not:\n%s", expectedProse, html)
+	}
+}
+
+func Test_markdownToHTML_TypographerEnabled_CodeAndURLsAreNotChanged(t *testing.T) {
+	// act
+	html := markdownToHTML(testTypographerMarkdown, config.Markdown{Typographer: true})
+
+	// assert
+	expectedFragments := []string{
+		"<code>&quot;quoted&quot; -- code...</code>",
+		"<pre><code>print(&quot;block&quot;) -- 'code'...\n</code></pre>",
+		`<a href="http://example.com/a--b...c">the docs</a>`,
+		`<a href="http://example.com/x--y">http://example.com/x--y</a>`,
+	}
+
+	for _, fragment := range expectedFragments {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("The HTML should contain %q but it does not:\n%s", fragment, html)
+		}
+	}
+}
+
+func Test_markdownToHTML_TypographerDisabled_QuotesAndDashesAreNotChanged(t *testing.T) {
+	// act
+	html := markdownToHTML(testTypographerMarkdown, config.Markdown{})
+
+	// assert
+	expectedProse := "<p>He said &quot;hello&quot; -- it's a 'test' --- really...</p>"
+	if !strings.Contains(html, expectedProse) {
+		t.Errorf("The HTML should contain %q but it does not:\n%s", expectedProse, html)
+	}
+}
+
+func Test_Convert_CommentItem_HTMLAndDirectivesAreNotRendered(t *testing.T) {
+	// arrange
+	item := model.NewItem(route.NewFromRequest("documents/sample/comment-1"), nil, dataaccess.TypePhysical)
+	item.Type = model.TypeComment
+	item.Content = "<script>alert(1)</script>\n\n{{include: ../secret}}\n\n{{youtube id=\"dQw4w9WgXcQ\"}}\n\n[Click](javascript:alert(1))"
+
+	aliasResolver := func(alias string) *model.Item { return nil }
+	titleResolver := func(title string) []*model.Item { return nil }
+	itemResolver := func(itemRoute route.Route) *model.Item {
+		t.Errorf("The comment should not include the item %q.", itemRoute)
+		return nil
+	}
+
+	converter := New(console.New(loglevel.Off), config.Markdown{}, nil)
+
+	// act
+	html, err := converter.Convert(aliasResolver, titleResolver, itemResolver, testIncludePather{}, item)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The conversion failed: %s", err)
+	}
+
+	for _, unexpected := range []string{"<script", "javascript:", "youtube.com", "data-placeholder", "include-error"} {
+		if strings.Contains(html, unexpected) {
+			t.Errorf("The converted comment should not contain %q: %s", unexpected, html)
+		}
+	}
+}
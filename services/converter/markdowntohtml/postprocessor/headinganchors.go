@@ -0,0 +1,76 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postprocessor
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/andreaskoch/allmark/common/util/slugutil"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`(?s)<h([1-6])>(.*?)</h([1-6])>`)
+	idAttributePattern = regexp.MustCompile(`\sid\s*=\s*"([^"]*)"`)
+	htmlTagPattern     = regexp.MustCompile(`<[^>]*>`)
+)
+
+// defaultHeadingSlug is used for headings whose text does not contain any letters or digits.
+const defaultHeadingSlug = "section"
+
+// addHeadingAnchors assigns an id to every heading of the supplied HTML code
+// and appends a link to the heading itself so sections can be linked to.
+// Headings which already have attributes are left unchanged, but their ids are reserved.
+func addHeadingAnchors(htmlCode string) string {
+
+	usedSlugs := make(map[string]bool)
+	for _, match := range idAttributePattern.FindAllStringSubmatch(htmlCode, -1) {
+		usedSlugs[match[1]] = true
+	}
+
+	return headingPattern.ReplaceAllStringFunc(htmlCode, func(heading string) string {
+		match := headingPattern.FindStringSubmatch(heading)
+		level, content, closingLevel := match[1], match[2], match[3]
+		if level != closingLevel {
+			return heading
+		}
+
+		text := html.UnescapeString(htmlTagPattern.ReplaceAllString(content, ""))
+		slug := getUniqueSlug(getSlug(text), usedSlugs)
+		href := (&url.URL{Fragment: slug}).String()
+
+		return fmt.Sprintf(`<h%s id="%s">%s <a class="heading-anchor" href="%s" aria-label="Link to this section">#</a></h%s>`,
+			level,
+			html.EscapeString(slug),
+			content,
+			html.EscapeString(href),
+			level)
+	})
+}
+
+// getSlug returns the slug for the supplied heading text.
+func getSlug(text string) string {
+	slug := slugutil.FromText(text)
+	if slug == "" {
+		return defaultHeadingSlug
+	}
+
+	return slug
+}
+
+// getUniqueSlug returns the supplied slug or, if it has already been used,
+// the slug with the first free numeric suffix ("slug-2", "slug-3", ...).
+func getUniqueSlug(slug string, usedSlugs map[string]bool) string {
+	uniqueSlug := slug
+	for suffix := 2; usedSlugs[uniqueSlug]; suffix++ {
+		uniqueSlug = slug + "-" + strconv.Itoa(suffix)
+	}
+
+	usedSlugs[uniqueSlug] = true
+	return uniqueSlug
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postprocessor
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_getSlug(t *testing.T) {
+	inputs := map[string]string{
+		"Getting Started":            "getting-started",
+		"  Leading and trailing  ":   "leading-and-trailing",
+		"What's new in v1.2?":        "whats-new-in-v12",
+		"Foo & Bar -- Baz":           "foo-bar-baz",
+		"snake_case_heading":         "snake-case-heading",
+		"Über Größe":                 "ueber-groesse",
+		"Café Crème":                 "cafe-creme",
+		"日本語 見出し":                    "日本語-見出し",
+		"!!!":                        "section",
+		"Heading with <code> & more": "heading-with-code-more",
+	}
+
+	for input, expected := range inputs {
+		// act
+		result := getSlug(input)
+
+		// assert
+		if result != expected {
+			t.Errorf("The slug for %q should be %q but was %q.", input, expected, result)
+		}
+	}
+}
+
+func Test_addHeadingAnchors_Headings_IdsAndAnchorsAreAdded(t *testing.T) {
+	// arrange
+	input := `<h2>Getting Started</h2><p>Text</p><h3>Install <code>allmark</code></h3>`
+	expected := `<h2 id="getting-started">Getting Started <a class="heading-anchor" href="#getting-started" aria-label="Link to this section">#</a></h2>` +
+		`<p>Text</p>` +
+		`<h3 id="install-allmark">Install <code>allmark</code> <a class="heading-anchor" href="#install-allmark" aria-label="Link to this section">#</a></h3>`
+
+	// act
+	result := addHeadingAnchors(input)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_addHeadingAnchors_DuplicateHeadings_SlugsGetNumericSuffixes(t *testing.T) {
+	// arrange
+	input := `<h2>Example</h2><h2>Example</h2><h2>Example 2</h2><h2 id="example-3">Existing</h2><h2>Example</h2>`
+
+	// act
+	result := addHeadingAnchors(input)
+
+	// assert
+	for _, expectedID := range []string{`id="example"`, `id="example-2"`, `id="example-2-2"`, `id="example-3"`, `id="example-4"`} {
+		if strings.Count(result, expectedID) != 1 {
+			t.Errorf("The result should contain %s exactly once but was %q.", expectedID, result)
+		}
+	}
+}
+
+func Test_addHeadingAnchors_SameInput_SameSlugs(t *testing.T) {
+	// arrange
+	input := `<h2>One</h2><h2>Two</h2><h2>One</h2>`
+
+	// act
+	first := addHeadingAnchors(input)
+	second := addHeadingAnchors(input)
+
+	// assert
+	if first != second {
+		t.Errorf("The slugs should be identical across conversions but were %q and %q.", first, second)
+	}
+}
+
+func Test_addHeadingAnchors_NonASCIIHeading_HrefIsURLEncoded(t *testing.T) {
+	// arrange
+	input := `<h2>日本語</h2>`
+	expected := `<h2 id="日本語">日本語 <a class="heading-anchor" href="#%E6%97%A5%E6%9C%AC%E8%AA%9E" aria-label="Link to this section">#</a></h2>`
+
+	// act
+	result := addHeadingAnchors(input)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postprocessor
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	imageTagPattern         = regexp.MustCompile(`<img\b[^>]*>`)
+	loadingAttributePattern = regexp.MustCompile(`\sloading\s*=`)
+)
+
+// addLazyLoading adds the loading="lazy" attribute to all image tags of the supplied HTML code.
+// Image tags which already have a loading attribute (e.g. loading="eager" for images above the fold) are left unchanged.
+func addLazyLoading(html string) string {
+	return imageTagPattern.ReplaceAllStringFunc(html, func(imageTag string) string {
+		if loadingAttributePattern.MatchString(imageTag) {
+			return imageTag
+		}
+
+		return strings.Replace(imageTag, "<img", `<img loading="lazy"`, 1)
+	})
+}
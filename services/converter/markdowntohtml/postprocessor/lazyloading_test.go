@@ -0,0 +1,49 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postprocessor
+
+import (
+	"testing"
+)
+
+func Test_addLazyLoading_ImageTags_LoadingAttributeIsAdded(t *testing.T) {
+	// arrange
+	input := `<p><img srcset="/thumbnails/1-320-240.png 320w" sizes="(max-width: 320px) 100vw, 320px" src="/files/1.png" alt="One"/> <img src="/files/2.png"></p>`
+	expected := `<p><img loading="lazy" srcset="/thumbnails/1-320-240.png 320w" sizes="(max-width: 320px) 100vw, 320px" src="/files/1.png" alt="One"/> <img loading="lazy" src="/files/2.png"></p>`
+
+	// act
+	result := addLazyLoading(input)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_addLazyLoading_EagerImage_ImageTagIsNotChanged(t *testing.T) {
+	// arrange
+	input := `<img loading="eager" src="/files/header.png" alt="Header"/>`
+
+	// act
+	result := addLazyLoading(input)
+
+	// assert
+	if result != input {
+		t.Errorf("The result should be %q but was %q.", input, result)
+	}
+}
+
+func Test_addLazyLoading_NoImages_HTMLIsNotChanged(t *testing.T) {
+	// arrange
+	input := `<p>An <a href="/imagery">imagery</a> link.</p>`
+
+	// act
+	result := addLazyLoading(input)
+
+	// assert
+	if result != input {
+		t.Errorf("The result should be %q but was %q.", input, result)
+	}
+}
@@ -40,9 +40,15 @@ func (postprocessor *Postprocessor) Convert(
 		postprocessor.logger.Warn("Error while converting images/thumbnails. Error: %s", imageConversionError)
 	}
 
+	// Lazy-load images
+	html = addLazyLoading(html)
+
 	// Rewrite Links
 	html = rewireLinks(pathProvider, itemRoute, files, html)
 
+	// Heading anchors
+	html = addHeadingAnchors(html)
+
 	// Add Emojis
 	html = addEmojis(html)
 
@@ -103,7 +103,8 @@ func (converter *imageGalleryExtension) getImageLinksByPath(path string) []strin
 
 		// link the image to the original
 		fullSizeImagePath := converter.pathProvider.Path(file.Route().Value())
-		imageWithLink := fmt.Sprintf(`<a href="%s" title="%s">%s</a>`, fullSizeImagePath, imageTitle, imageCode)
+		// (the gallery attributes are used by the lightbox of the theme)
+		imageWithLink := fmt.Sprintf(`<a href="%s" title="%s" class="imagegallery-image" data-gallery="%s">%s</a>`, fullSizeImagePath, imageTitle, fullGalleryRoute.Value(), imageCode)
 
 		imagelinks = append(imagelinks, imageWithLink)
 	}
@@ -29,6 +29,7 @@ func New(logger logger.Logger, imageProvider *imageprovider.ImageProvider) *Prep
 // Convert converts all markdown extensions in the supplied markdown to normal markdown code or HTML.
 func (preprocessor *Preprocessor) Convert(
 	aliasResolver func(alias string) *model.Item,
+	titleResolver func(title string) []*model.Item,
 	pathProvider paths.Pather,
 	itemRoute route.Route,
 	files []*model.File,
@@ -83,6 +84,13 @@ func (preprocessor *Preprocessor) Convert(
 		preprocessor.logger.Warn("Error while converting reference extensions. Error: %s", referenceConversionError)
 	}
 
+	// markdown extension: wiki links
+	wikiLinkConverter := newWikiLinkExtension(preprocessor.logger, pathProvider, itemRoute, titleResolver)
+	markdown, wikiLinkConversionError := wikiLinkConverter.Convert(markdown)
+	if wikiLinkConversionError != nil {
+		preprocessor.logger.Warn("Error while converting wiki links. Error: %s", wikiLinkConversionError)
+	}
+
 	return markdown, nil
 
 }
@@ -0,0 +1,136 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package preprocessor
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+)
+
+var (
+	// [[*title of the linked item*]] or [[*title of the linked item*|*display text*]]
+	wikiLinkPattern = regexp.MustCompile(`\[\[([^\[\]|\n]+)(?:\|([^\[\]\n]+))?\]\]`)
+)
+
+func newWikiLinkExtension(logger logger.Logger, pathProvider paths.Pather, itemRoute route.Route, titleResolver func(title string) []*model.Item) *wikiLinkExtension {
+	return &wikiLinkExtension{
+		logger:        logger,
+		pathProvider:  pathProvider,
+		itemRoute:     itemRoute,
+		titleResolver: titleResolver,
+	}
+}
+
+// wikiLinkExtension replaces wiki-style links to the titles of other items with HTML links.
+// Links inside of code blocks and code spans are not changed.
+type wikiLinkExtension struct {
+	logger        logger.Logger
+	pathProvider  paths.Pather
+	itemRoute     route.Route
+	titleResolver func(title string) []*model.Item
+}
+
+func (converter *wikiLinkExtension) Convert(markdown string) (convertedContent string, converterError error) {
+	return replaceOutsideOfCode(markdown, func(text string) string {
+		return wikiLinkPattern.ReplaceAllStringFunc(text, converter.getLinkCode)
+	}), nil
+}
+
+// getLinkCode returns the HTML code for the supplied wiki link (e.g. "[[Installation Guide|Install]]").
+func (converter *wikiLinkExtension) getLinkCode(wikiLink string) string {
+	match := wikiLinkPattern.FindStringSubmatch(wikiLink)
+	title := strings.TrimSpace(match[1])
+	text := strings.TrimSpace(match[2])
+	if text == "" {
+		text = title
+	}
+
+	candidates := converter.titleResolver(title)
+	if len(candidates) == 0 {
+		return fmt.Sprintf(`<span class="wikilink wikilink-broken" title="%s">%s</span>`, html.EscapeString(fmt.Sprintf("No item with the title %q found", title)), html.EscapeString(text))
+	}
+
+	item := getNearestItem(converter.itemRoute, candidates)
+	if len(candidates) > 1 {
+		converter.logger.Warn("The title %q of the wiki link in %q is ambiguous (%d items). Linking to the nearest item %q.", title, converter.itemRoute, len(candidates), item.Route())
+	}
+
+	path := converter.pathProvider.Path(item.Route().Value())
+	return fmt.Sprintf(`<a href="%s" class="wikilink">%s</a>`, html.EscapeString(path), html.EscapeString(text))
+}
+
+// getNearestItem returns the item whose route is the least number of steps away from the supplied route
+// in the item tree. If multiple items have the same distance the one with the lowest route wins.
+func getNearestItem(origin route.Route, items []*model.Item) *model.Item {
+	var nearest *model.Item
+	nearestDistance := 0
+	for _, item := range items {
+		distance := getRouteDistance(origin, item.Route())
+		if nearest == nil || distance < nearestDistance || (distance == nearestDistance && item.Route().Value() < nearest.Route().Value()) {
+			nearest = item
+			nearestDistance = distance
+		}
+	}
+
+	return nearest
+}
+
+// getRouteDistance returns the number of steps between the supplied routes
+// (up to their closest common ancestor and down to the other route).
+func getRouteDistance(route1, route2 route.Route) int {
+	components1 := getRouteComponents(route1)
+	components2 := getRouteComponents(route2)
+
+	common := 0
+	for common < len(components1) && common < len(components2) && components1[common] == components2[common] {
+		common++
+	}
+
+	return (len(components1) - common) + (len(components2) - common)
+}
+
+func getRouteComponents(itemRoute route.Route) []string {
+	if itemRoute.Value() == "" {
+		return []string{}
+	}
+
+	return strings.Split(itemRoute.Value(), "/")
+}
+
+// replaceOutsideOfCode applies the supplied replace function to all parts of the markdown
+// which are neither fenced code blocks nor inline code spans.
+func replaceOutsideOfCode(markdown string, replace func(text string) string) string {
+	lines := strings.Split(markdown, "\n")
+
+	isInFencedCodeBlock := false
+	for index, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmedLine, "```") || strings.HasPrefix(trimmedLine, "~~~") {
+			isInFencedCodeBlock = !isInFencedCodeBlock
+			continue
+		}
+
+		if isInFencedCodeBlock {
+			continue
+		}
+
+		// every second segment between backticks is a code span
+		segments := strings.Split(line, "`")
+		for segmentIndex := 0; segmentIndex < len(segments); segmentIndex += 2 {
+			segments[segmentIndex] = replace(segments[segmentIndex])
+		}
+
+		lines[index] = strings.Join(segments, "`")
+	}
+
+	return strings.Join(lines, "\n")
+}
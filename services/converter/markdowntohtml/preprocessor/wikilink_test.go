@@ -0,0 +1,133 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package preprocessor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// warningRecorder is a logger which records all warnings.
+type warningRecorder struct {
+	logger.Logger
+	warnings []string
+}
+
+func (recorder *warningRecorder) Warn(format string, v ...interface{}) {
+	recorder.warnings = append(recorder.warnings, fmt.Sprintf(format, v...))
+}
+
+type testPather struct{}
+
+func (testPather) Path(itemPath string) string {
+	return "/" + itemPath
+}
+
+func (testPather) Base() route.Route {
+	return route.New()
+}
+
+// newTestTitleResolver returns a title resolver for items with the supplied routes and titles.
+func newTestTitleResolver(titlesByRoute map[string]string) func(title string) []*model.Item {
+	return func(title string) []*model.Item {
+		items := make([]*model.Item, 0)
+		for itemRoute, itemTitle := range titlesByRoute {
+			if strings.EqualFold(itemTitle, title) {
+				item := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+				item.Title = itemTitle
+				items = append(items, item)
+			}
+		}
+
+		return items
+	}
+}
+
+var testWikiTitles = map[string]string{
+	"documentation/installation":    "Installation Guide",
+	"documentation/configuration":   "Configuration",
+	"projects/alpha/docs/notes":     "Notes",
+	"projects/beta/docs/notes":      "Notes",
+	"projects/beta/docs/more/notes": "Notes",
+}
+
+func convertTestWikiLinks(recorder *warningRecorder, itemRoute, markdown string) string {
+	extension := newWikiLinkExtension(recorder, testPather{}, route.NewFromRequest(itemRoute), newTestTitleResolver(testWikiTitles))
+	result, _ := extension.Convert(markdown)
+	return result
+}
+
+func Test_wikiLinkExtension_KnownTitle_LinkIsResolved(t *testing.T) {
+	// arrange
+	recorder := &warningRecorder{Logger: console.New(loglevel.Fatal)}
+
+	// act
+	result := convertTestWikiLinks(recorder, "start", "See the [[installation guide]] and [[Configuration|the settings]].")
+
+	// assert
+	expected := `See the <a href="/documentation/installation" class="wikilink">installation guide</a> and <a href="/documentation/configuration" class="wikilink">the settings</a>.`
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+
+	if len(recorder.warnings) != 0 {
+		t.Errorf("Resolving unique titles should not log warnings but logged %v.", recorder.warnings)
+	}
+}
+
+func Test_wikiLinkExtension_AmbiguousTitle_NearestItemIsLinkedAndAWarningIsLogged(t *testing.T) {
+	// arrange
+	recorder := &warningRecorder{Logger: console.New(loglevel.Fatal)}
+
+	// act
+	result := convertTestWikiLinks(recorder, "projects/beta/readme", "[[Notes]]")
+
+	// assert
+	expected := `<a href="/projects/beta/docs/notes" class="wikilink">Notes</a>`
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+
+	if len(recorder.warnings) != 1 {
+		t.Errorf("An ambiguous title should log %d warning but logged %v.", 1, recorder.warnings)
+	}
+}
+
+func Test_wikiLinkExtension_UnknownTitle_BrokenLinkIsRendered(t *testing.T) {
+	// arrange
+	recorder := &warningRecorder{Logger: console.New(loglevel.Fatal)}
+
+	// act
+	result := convertTestWikiLinks(recorder, "start", "Read the [[Missing Page|<missing> page]].")
+
+	// assert
+	expected := `Read the <span class="wikilink wikilink-broken" title="No item with the title &#34;Missing Page&#34; found">&lt;missing&gt; page</span>.`
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_wikiLinkExtension_LinksInCode_AreNotChanged(t *testing.T) {
+	// arrange
+	recorder := &warningRecorder{Logger: console.New(loglevel.Fatal)}
+	markdown := "Use `[[Configuration]]` for links.\n\n```bash\nif [[ -f config ]]; then echo ok; fi\n```\n\n[[Configuration]]"
+
+	// act
+	result := convertTestWikiLinks(recorder, "start", markdown)
+
+	// assert
+	expected := "Use `[[Configuration]]` for links.\n\n```bash\nif [[ -f config ]]; then echo ok; fi\n```\n\n<a href=\"/documentation/configuration\" class=\"wikilink\">Configuration</a>"
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markdowntohtml
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	// {{name key="value" key='value' key=value}}
+	shortcodePattern = regexp.MustCompile(`\{\{\s*([A-Za-z][A-Za-z0-9_-]*)((?:\s+[A-Za-z][A-Za-z0-9_-]*\s*=\s*(?:"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|[^\s"'}]+))*)\s*\}\}`)
+
+	// a single argument of a shortcode (key="value", key='value' or key=value)
+	shortcodeArgumentPattern = regexp.MustCompile(`([A-Za-z][A-Za-z0-9_-]*)\s*=\s*(?:"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)'|([^\s"'}]+))`)
+
+	// the placeholders which replace the shortcodes until the markdown has been rendered
+	shortcodePlaceholderPattern = getPlaceholderPattern("shortcode")
+
+	// fenced code blocks and code spans (shortcodes and includes in code are not expanded)
+	markdownCodePattern = regexp.MustCompile("(?ms)^[ \t]*```.*?^[ \t]*```|^[ \t]*~~~.*?^[ \t]*~~~|`[^`\n]+`")
+
+	// the lines of indented code blocks (four spaces or a tab) and the first lines of list items
+	indentedCodeLinePattern = regexp.MustCompile(`^(?: {4}|\t)`)
+	listItemLinePattern     = regexp.MustCompile(`^ {0,3}(?:[*+-]|[0-9]+[.)])[ \t]`)
+
+	// the YouTube video ids
+	youTubeVideoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+var (
+	shortcodesLock sync.RWMutex
+
+	// the shortcode handlers by their (lower-case) name
+	shortcodes = map[string]func(args map[string]string) string{
+		"figure":  renderFigureShortcode,
+		"youtube": renderYouTubeShortcode,
+	}
+)
+
+// RegisterShortcode registers the handler for the shortcode with the given name (e.g. "figure").
+// The handler receives the arguments of the shortcode (e.g. {{figure src="a.png"}} -> "src": "a.png")
+// and returns the HTML code which replaces it. Registering a name again replaces the existing handler.
+func RegisterShortcode(name string, fn func(args map[string]string) string) {
+	shortcodesLock.Lock()
+	defer shortcodesLock.Unlock()
+
+	shortcodes[strings.ToLower(name)] = fn
+}
+
+// getShortcode returns the handler of the shortcode with the given name.
+func getShortcode(name string) (func(args map[string]string) string, bool) {
+	shortcodesLock.RLock()
+	defer shortcodesLock.RUnlock()
+
+	fn, exists := shortcodes[strings.ToLower(name)]
+	return fn, exists
+}
+
+// A shortcode is a single shortcode invocation of an item.
+type shortcode struct {
+	name      string
+	arguments map[string]string
+}
+
+// insertShortcodePlaceholders replaces all shortcodes of the supplied markdown which are not part of code
+// with placeholders and returns the shortcodes in the order of their placeholders.
+func insertShortcodePlaceholders(markdown string) (string, []shortcode) {
+	var invocations []shortcode
+	codeRanges := getMarkdownCodeRanges(markdown)
+
+	var result bytes.Buffer
+	position := 0
+	for _, location := range shortcodePattern.FindAllStringSubmatchIndex(markdown, -1) {
+		if isInRanges(codeRanges, location[0]) {
+			continue
+		}
+
+		invocations = append(invocations, shortcode{
+			name:      markdown[location[2]:location[3]],
+			arguments: parseShortcodeArguments(markdown[location[4]:location[5]]),
+		})
+
+		result.WriteString(markdown[position:location[0]])
+		result.WriteString(getPlaceholder("shortcode", len(invocations)-1))
+		position = location[1]
+	}
+
+	result.WriteString(markdown[position:])
+	return result.String(), invocations
+}
+
+// replaceShortcodePlaceholders replaces the shortcode placeholders of the supplied HTML code with the output of the
+// respective shortcode handlers. Unknown shortcodes are replaced with a visible error marker.
+func replaceShortcodePlaceholders(htmlCode string, invocations []shortcode) string {
+	return replacePlaceholders(htmlCode, shortcodePlaceholderPattern, len(invocations), func(index int) string {
+		invocation := invocations[index]
+
+		fn, exists := getShortcode(invocation.name)
+		if !exists {
+			return getShortcodeError("Unknown shortcode %q", invocation.name)
+		}
+
+		return fn(invocation.arguments)
+	})
+}
+
+// parseShortcodeArguments returns the arguments of a shortcode (e.g. ` src="a.png" width=200` -> "src": "a.png", "width": "200").
+// Quotes and backslashes in quoted values can be escaped with a backslash (e.g. caption="A \"quoted\" caption").
+func parseShortcodeArguments(code string) map[string]string {
+	arguments := make(map[string]string)
+
+	for _, match := range shortcodeArgumentPattern.FindAllStringSubmatch(code, -1) {
+		key := strings.ToLower(match[1])
+
+		// only one of the value groups (double-quoted, single-quoted, unquoted) matches
+		if unquotedValue := match[4]; unquotedValue != "" {
+			arguments[key] = unquotedValue
+			continue
+		}
+
+		arguments[key] = unescapeShortcodeValue(match[2] + match[3])
+	}
+
+	return arguments
+}
+
+// unescapeShortcodeValue removes the backslashes which escape the following character.
+func unescapeShortcodeValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var result bytes.Buffer
+	for index := 0; index < len(value); index++ {
+		if value[index] == '\\' && index+1 < len(value) {
+			index++
+		}
+
+		result.WriteByte(value[index])
+	}
+
+	return result.String()
+}
+
+// getMarkdownCodeRanges returns the ranges ([start, end)) of the code blocks (fenced and indented) and code spans of the supplied markdown.
+func getMarkdownCodeRanges(markdown string) [][]int {
+	return append(markdownCodePattern.FindAllStringIndex(markdown, -1), getIndentedCodeRanges(markdown)...)
+}
+
+// getIndentedCodeRanges returns the ranges ([start, end)) of the indented code blocks of the supplied markdown.
+// An indented code block starts after a blank line and ends with the first line that is not indented.
+// Indented lines inside of lists belong to the list items and are not treated as code.
+func getIndentedCodeRanges(markdown string) [][]int {
+	var ranges [][]int
+
+	previousLineIsBlank, isInList, isInCode := true, false, false
+	position := 0
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		lineStart := position
+		position += len(line)
+
+		isBlank := strings.TrimSpace(line) == ""
+		isIndented := indentedCodeLinePattern.MatchString(line)
+
+		switch {
+		case isInCode && (isIndented || isBlank):
+			ranges[len(ranges)-1][1] = position
+
+		case isIndented && !isBlank && previousLineIsBlank && !isInList:
+			ranges = append(ranges, []int{lineStart, position})
+			isInCode = true
+
+		case !isIndented && !isBlank:
+			isInCode = false
+			isInList = listItemLinePattern.MatchString(line) || (isInList && !previousLineIsBlank)
+		}
+
+		previousLineIsBlank = isBlank
+	}
+
+	return ranges
+}
+
+// isInRanges returns true if the supplied position is inside one of the given ranges ([start, end)).
+func isInRanges(ranges [][]int, position int) bool {
+	for _, positions := range ranges {
+		if position >= positions[0] && position < positions[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getShortcodeError returns a visible error marker with the supplied message (a span, because shortcodes can be part of a paragraph).
+func getShortcodeError(format string, arguments ...interface{}) string {
+	return fmt.Sprintf(`<span class="shortcode-error">%s</span>`, html.EscapeString(fmt.Sprintf(format, arguments...)))
+}
+
+// renderFigureShortcode renders an image with an optional caption
+// (e.g. {{figure src="files/photo.jpg" caption="A photo" alt="..." link="..."}}).
+func renderFigureShortcode(args map[string]string) string {
+	source := args["src"]
+	if source == "" {
+		return getShortcodeError("The figure shortcode requires a src argument")
+	}
+
+	caption := args["caption"]
+	alternativeText := args["alt"]
+	if alternativeText == "" {
+		alternativeText = caption
+	}
+
+	image := fmt.Sprintf(`<img src="%s" alt="%s" />`, html.EscapeString(source), html.EscapeString(alternativeText))
+	if link := args["link"]; link != "" {
+		image = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link), image)
+	}
+
+	if caption == "" {
+		return fmt.Sprintf(`<figure class="figure">%s</figure>`, image)
+	}
+
+	return fmt.Sprintf(`<figure class="figure">%s<figcaption>%s</figcaption></figure>`, image, html.EscapeString(caption))
+}
+
+// renderYouTubeShortcode renders an embedded YouTube video (e.g. {{youtube id="dQw4w9WgXcQ" title="..."}}).
+func renderYouTubeShortcode(args map[string]string) string {
+	videoID := args["id"]
+	if !youTubeVideoIDPattern.MatchString(videoID) {
+		return getShortcodeError("The youtube shortcode requires a valid video id")
+	}
+
+	title := html.EscapeString(args["title"])
+	header := ""
+	if title != "" {
+		header = fmt.Sprintf(`<header><a href="https://www.youtube.com/watch?v=%s" target="_blank" title="%s">%s</a></header>`, videoID, title, title)
+	}
+
+	return fmt.Sprintf(`<section class="video video-external video-youtube">%s<iframe width="560" height="315" src="https://www.youtube.com/embed/%s" title="%s" frameborder="0" allowfullscreen></iframe></section>`, header, videoID, title)
+}
@@ -0,0 +1,177 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markdowntohtml
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_parseShortcodeArguments(t *testing.T) {
+	// arrange
+	inputs := []struct {
+		code     string
+		expected map[string]string
+	}{
+		{``, map[string]string{}},
+		{` src="a.png"`, map[string]string{"src": "a.png"}},
+		{` src='a.png' caption="A nice photo"`, map[string]string{"src": "a.png", "caption": "A nice photo"}},
+		{` width=200 Height = 100`, map[string]string{"width": "200", "height": "100"}},
+		{` caption="A \"quoted\" caption" alt='It\'s a photo'`, map[string]string{"caption": `A "quoted" caption`, "alt": "It's a photo"}},
+		{` path="C:\\files"`, map[string]string{"path": `C:\files`}},
+		{` caption=""`, map[string]string{"caption": ""}},
+	}
+
+	for _, input := range inputs {
+
+		// act
+		result := parseShortcodeArguments(input.code)
+
+		// assert
+		if len(result) != len(input.expected) {
+			t.Errorf("parseShortcodeArguments(%q) returned %d arguments (%v) but expected %d (%v).", input.code, len(result), result, len(input.expected), input.expected)
+			continue
+		}
+
+		for key, value := range input.expected {
+			if actual, exists := result[key]; !exists || actual != value {
+				t.Errorf("parseShortcodeArguments(%q) returned %q for %q but expected %q.", input.code, actual, key, value)
+			}
+		}
+	}
+}
+
+func Test_insertShortcodePlaceholders_ShortcodesInCodeAreNotReplaced(t *testing.T) {
+	// arrange
+	markdown := "{{figure src=\"a.png\"}}\n\nUse `{{figure src=\"b.png\"}}` for figures:\n\n```\n{{youtube id=\"abc\"}}\n```\n\n{{youtube id=def}}"
+
+	// act
+	result, invocations := insertShortcodePlaceholders(markdown)
+
+	// assert
+	if len(invocations) != 2 {
+		t.Fatalf("Expected 2 shortcodes but found %d (%v).", len(invocations), invocations)
+	}
+
+	if invocations[0].name != "figure" || invocations[0].arguments["src"] != "a.png" {
+		t.Errorf("The first shortcode should be the figure with the source %q but was %v.", "a.png", invocations[0])
+	}
+
+	if invocations[1].name != "youtube" || invocations[1].arguments["id"] != "def" {
+		t.Errorf("The second shortcode should be the youtube video %q but was %v.", "def", invocations[1])
+	}
+
+	for _, unchanged := range []string{"`{{figure src=\"b.png\"}}`", "{{youtube id=\"abc\"}}"} {
+		if !strings.Contains(result, unchanged) {
+			t.Errorf("The shortcode %q in code should not have been replaced: %s", unchanged, result)
+		}
+	}
+}
+
+func Test_Convert_Shortcode_Figure(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/sample": `Before
+
+{{figure src="http://example.com/a.png" caption="A <nice> photo"}}
+
+After`,
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/sample")
+
+	// assert
+	expected := `<figure class="figure"><img loading="lazy" src="http://example.com/a.png" alt="A &lt;nice&gt; photo"`
+	if !strings.Contains(html, expected) {
+		t.Errorf("The result should contain %q but was: %s", expected, html)
+	}
+
+	if !strings.Contains(html, "<figcaption>A &lt;nice&gt; photo</figcaption></figure>") {
+		t.Errorf("The result should contain the escaped caption: %s", html)
+	}
+
+	if strings.Contains(html, "<p><figure") || strings.Contains(html, "{{") {
+		t.Errorf("The figure should replace the whole paragraph: %s", html)
+	}
+}
+
+func Test_Convert_Shortcode_YouTube(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/sample": "{{youtube id=\"dQw4w9WgXcQ\"}}\n\n{{youtube id=\"\\\"><script>\"}}",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/sample")
+
+	// assert
+	if !strings.Contains(html, `src="https://www.youtube.com/embed/dQw4w9WgXcQ"`) {
+		t.Errorf("The result should contain the embedded video: %s", html)
+	}
+
+	if strings.Contains(html, "<script>") || !strings.Contains(html, `<span class="shortcode-error">The youtube shortcode requires a valid video id</span>`) {
+		t.Errorf("The invalid video id should have been replaced with an error: %s", html)
+	}
+}
+
+func Test_Convert_UnknownShortcode_ErrorIsRendered(t *testing.T) {
+	// arrange
+	items := map[string]string{
+		"documents/sample": "Text {{unknown-shortcode key=\"value\"}} text",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/sample")
+
+	// assert
+	expected := `<p>Text <span class="shortcode-error">Unknown shortcode &#34;unknown-shortcode&#34;</span> text</p>`
+	if !strings.Contains(html, expected) {
+		t.Errorf("The result should contain %q but was: %s", expected, html)
+	}
+}
+
+func Test_Convert_RegisteredShortcode_OutputIsInserted(t *testing.T) {
+	// arrange
+	RegisterShortcode("Greeting", func(args map[string]string) string {
+		return "<strong>Hello " + args["name"] + "</strong>"
+	})
+
+	items := map[string]string{
+		"documents/sample": "Intro: {{greeting name=World}}",
+	}
+
+	// act
+	html := convertTestItem(t, items, "documents/sample")
+
+	// assert
+	expected := "<p>Intro: <strong>Hello World</strong></p>"
+	if !strings.Contains(html, expected) {
+		t.Errorf("The result should contain %q but was: %s", expected, html)
+	}
+}
+
+func Test_insertShortcodePlaceholders_ShortcodesInIndentedCodeAreNotReplaced(t *testing.T) {
+	// arrange
+	markdown := "Example:\n\n    {{figure src=\"a.png\"}}\n\n\t{{youtube id=abc}}\n\n- A list item\n\n    {{youtube id=def}}\n\nText\n    {{figure src=\"b.png\"}}"
+
+	// act
+	result, invocations := insertShortcodePlaceholders(markdown)
+
+	// assert
+	if len(invocations) != 2 {
+		t.Fatalf("Expected 2 shortcodes but found %d (%v).", len(invocations), invocations)
+	}
+
+	if invocations[0].arguments["id"] != "def" || invocations[1].arguments["src"] != "b.png" {
+		t.Errorf("Only the shortcodes of the list item and the paragraph should have been replaced but the shortcodes were %v.", invocations)
+	}
+
+	for _, unchanged := range []string{"    {{figure src=\"a.png\"}}", "\t{{youtube id=abc}}"} {
+		if !strings.Contains(result, unchanged) {
+			t.Errorf("The shortcode %q in code should not have been replaced: %s", unchanged, result)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markdowntohtml
+
+import (
+	"regexp"
+	"strings"
+)
+
+// taskListItemPattern matches list items which start with a task marker ("[ ]" or "[x]"),
+// including the items of loose lists whose content is wrapped in a paragraph.
+var taskListItemPattern = regexp.MustCompile(`<li>(\s*<p>)?\[([ xX])\]\s+`)
+
+// renderTaskLists replaces the task markers at the beginning of list items with disabled checkboxes
+// (e.g. "<li>[x] Done</li>" -> "<li class="task-list-item"><input type="checkbox" disabled="disabled" checked="checked" /> Done</li>").
+func renderTaskLists(html string) string {
+	return taskListItemPattern.ReplaceAllStringFunc(html, func(listItem string) string {
+		match := taskListItemPattern.FindStringSubmatch(listItem)
+		paragraph, marker := match[1], match[2]
+
+		checked := ""
+		if strings.ToLower(marker) == "x" {
+			checked = ` checked="checked"`
+		}
+
+		return `<li class="task-list-item">` + paragraph + `<input type="checkbox" class="task-list-item-checkbox" disabled="disabled"` + checked + ` /> `
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package frontmatter parses front matter sections (YAML fenced by "---" or TOML fenced by "+++")
+// at the beginning of markdown documents into meta data blocks.
+package frontmatter
+
+import (
+	"strings"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+// A format describes a front matter format by its fence delimiter
+// and the function which converts the enclosed lines into blocks.
+type format struct {
+	delimiter string
+	parse     func(lines []string) []model.Block
+}
+
+// The supported front matter formats. The format of a front matter section is detected by its opening delimiter
+// so that different formats can be used side by side in the same repository.
+var formats = []format{
+	{"---", parseYAML},
+	{"+++", parseTOML},
+}
+
+// Parse checks if the supplied lines start with a fenced front matter section
+// and converts its content into blocks. Nested structures are flattened into dotted names (e.g. "author.name").
+// If front matter has been found the remaining lines without the front matter section are returned.
+// Otherwise found is false and the supplied lines are returned unchanged.
+func Parse(lines []string) (blocks []model.Block, remainingLines []string, found bool) {
+
+	if len(lines) == 0 {
+		return nil, lines, false
+	}
+
+	openingDelimiter := strings.TrimSpace(lines[0])
+	for _, frontMatterFormat := range formats {
+
+		if openingDelimiter != frontMatterFormat.delimiter {
+			continue
+		}
+
+		// search for the closing delimiter
+		for lineNumber := 1; lineNumber < len(lines); lineNumber++ {
+			if strings.TrimSpace(lines[lineNumber]) != frontMatterFormat.delimiter {
+				continue
+			}
+
+			blocks := frontMatterFormat.parse(lines[1:lineNumber])
+			return blocks, lines[(lineNumber + 1):], true
+		}
+
+		// no closing delimiter; this is not a front matter section
+		break
+	}
+
+	return nil, lines, false
+}
+
+// unquote removes the enclosing single or double quotes from the supplied value.
+func unquote(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+
+	first, last := value[0], value[len(value)-1]
+	if first != last || (first != '"' && first != '\'') {
+		return value
+	}
+
+	return value[1:(len(value) - 1)]
+}
+
+// removeComment removes a trailing comment (e.g. "value # comment") from the supplied value.
+// Hash characters inside of quoted strings are not treated as comments.
+func removeComment(value string) string {
+	var quote rune
+	for index, character := range value {
+		switch {
+		case quote != 0:
+			if character == quote {
+				quote = 0
+			}
+
+		case character == '"' || character == '\'':
+			quote = character
+
+		case character == '#' && (index == 0 || value[index-1] == ' ' || value[index-1] == '\t'):
+			return strings.TrimSpace(value[:index])
+		}
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// splitInlineList splits an inline list (e.g. "[one, two]") into its entries.
+// isList is false if the supplied value is not an inline list.
+func splitInlineList(value string) (isList bool, entries []string) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return false, nil
+	}
+
+	for _, entry := range strings.Split(value[1:(len(value)-1)], ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		entries = append(entries, unquote(entry))
+	}
+
+	return true, entries
+}
+
+// addBlock appends a block with the given name and value to the supplied list of blocks.
+// Invalid blocks are skipped.
+func addBlock(blocks []model.Block, name, value string) []model.Block {
+	block, err := model.NewBlock(name, value)
+	if err != nil {
+		return blocks
+	}
+
+	return append(blocks, block)
+}
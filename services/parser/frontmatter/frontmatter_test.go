@@ -0,0 +1,211 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+func Test_Parse_YAMLFrontMatter_BlocksAreReturnedAndFrontMatterIsStripped(t *testing.T) {
+	// arrange
+	lines := strings.Split(`---
+title: "Hello World"
+author:
+  name: John Doe
+  email: john@example.com # the e-mail address
+tags:
+- go
+- markdown
+categories: [one, 'two']
+summary: >
+  A short
+  summary
+---
+# Hello World
+
+Content`, "\n")
+
+	// act
+	blocks, remainingLines, found := Parse(lines)
+
+	// assert
+	if !found {
+		t.Fatalf("Parse should detect the YAML front matter.")
+	}
+
+	expectedBlocks := []string{
+		"title: Hello World",
+		"author.name: John Doe",
+		"author.email: john@example.com",
+		"tags: go",
+		"tags: markdown",
+		"categories: one",
+		"categories: two",
+		"summary: A short summary",
+	}
+
+	if len(blocks) != len(expectedBlocks) {
+		t.Fatalf("Parse should return %d blocks but returned %d: %v", len(expectedBlocks), len(blocks), blocks)
+	}
+
+	for index, expectedBlock := range expectedBlocks {
+		if blocks[index].String() != expectedBlock {
+			t.Errorf("Block %d should be %q but was %q.", index, expectedBlock, blocks[index].String())
+		}
+	}
+
+	expectedContent := "# Hello World\n\nContent"
+	if content := strings.Join(remainingLines, "\n"); content != expectedContent {
+		t.Errorf("The front matter should have been stripped. Expected %q but got %q.", expectedContent, content)
+	}
+}
+
+func Test_Parse_NativeMetaData_LinesAreReturnedUnchanged(t *testing.T) {
+	// arrange
+	lines := strings.Split(`# Hello World
+
+Content
+
+---
+author: John Doe
+tags: go, markdown`, "\n")
+
+	// act
+	blocks, remainingLines, found := Parse(lines)
+
+	// assert
+	if found {
+		t.Errorf("Parse should not detect front matter in a document with native meta data.")
+	}
+
+	if len(blocks) != 0 {
+		t.Errorf("Parse should not return any blocks but returned %v.", blocks)
+	}
+
+	if len(remainingLines) != len(lines) {
+		t.Errorf("Parse should return the lines unchanged but returned %v.", remainingLines)
+	}
+}
+
+func Test_Parse_NoMetaData_LinesAreReturnedUnchanged(t *testing.T) {
+	// arrange
+	lines := strings.Split(`# Hello World
+
+Content`, "\n")
+
+	// act
+	blocks, remainingLines, found := Parse(lines)
+
+	// assert
+	if found {
+		t.Errorf("Parse should not detect front matter in a document without meta data.")
+	}
+
+	if len(blocks) != 0 {
+		t.Errorf("Parse should not return any blocks but returned %v.", blocks)
+	}
+
+	if strings.Join(remainingLines, "\n") != strings.Join(lines, "\n") {
+		t.Errorf("Parse should return the lines unchanged but returned %v.", remainingLines)
+	}
+}
+
+func Test_Parse_UnclosedFence_LinesAreReturnedUnchanged(t *testing.T) {
+	// arrange
+	lines := []string{"---", "author: John Doe", "", "Content"}
+
+	// act
+	_, remainingLines, found := Parse(lines)
+
+	// assert
+	if found {
+		t.Errorf("Parse should not detect front matter without a closing delimiter.")
+	}
+
+	if len(remainingLines) != len(lines) {
+		t.Errorf("Parse should return the lines unchanged but returned %v.", remainingLines)
+	}
+}
+
+func Test_Parse_TOMLFrontMatter_BlocksAreReturnedAndFrontMatterIsStripped(t *testing.T) {
+	// arrange
+	lines := strings.Split(`+++
+title = "Hello World"
+date = 2015-03-01T21:13:00Z
+tags = ["go", "markdown"]
+categories = [
+  "one", # the first category
+  "two"
+]
+
+[author]
+name = 'John Doe'
++++
+# Hello World`, "\n")
+
+	// act
+	blocks, remainingLines, found := Parse(lines)
+
+	// assert
+	if !found {
+		t.Fatalf("Parse should detect the TOML front matter.")
+	}
+
+	expectedBlocks := []string{
+		"title: Hello World",
+		"date: 2015-03-01T21:13:00Z",
+		"tags: go",
+		"tags: markdown",
+		"categories: one",
+		"categories: two",
+		"author.name: John Doe",
+	}
+
+	if len(blocks) != len(expectedBlocks) {
+		t.Fatalf("Parse should return %d blocks but returned %d: %v", len(expectedBlocks), len(blocks), blocks)
+	}
+
+	for index, expectedBlock := range expectedBlocks {
+		if blocks[index].String() != expectedBlock {
+			t.Errorf("Block %d should be %q but was %q.", index, expectedBlock, blocks[index].String())
+		}
+	}
+
+	if len(remainingLines) != 1 || remainingLines[0] != "# Hello World" {
+		t.Errorf("The front matter should have been stripped but the remaining lines are %v.", remainingLines)
+	}
+}
+
+func Test_Parse_TOMLFrontMatter_BlocksCanBeReadFromTheMetaData(t *testing.T) {
+	// arrange
+	lines := []string{"+++", `date = 2015-03-01`, `tags = ["go", "markdown"]`, `author = "John Doe"`, "+++"}
+	blocks, _, _ := Parse(lines)
+
+	metaData := model.NewMetaData()
+	for _, block := range blocks {
+		metaData.AddBlock(block.Name, block.Value)
+	}
+
+	// act
+	date, dateErr := metaData.GetBlockDate("date")
+	tags := metaData.GetBlockValues("tags")
+	author := metaData.GetBlockValue("author")
+
+	// assert
+	if dateErr != nil || date.Format("2006-01-02") != "2015-03-01" {
+		t.Errorf("The date should be 2015-03-01 but was %s (Error: %v).", date, dateErr)
+	}
+
+	if len(tags) != 2 || tags[0] != "go" || tags[1] != "markdown" {
+		t.Errorf("The tags should be [go markdown] but were %v.", tags)
+	}
+
+	if author != "John Doe" {
+		t.Errorf("The author should be %q but was %q.", "John Doe", author)
+	}
+}
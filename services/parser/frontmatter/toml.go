@@ -0,0 +1,99 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontmatter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+var (
+	// The regular expression which matches TOML key-value pairs (e.g. `author = "John Doe"`).
+	tomlKeyValuePattern = regexp.MustCompile(`^([^=\[#]+?)\s*=\s*(.*)$`)
+
+	// The regular expression which matches TOML table headers (e.g. "[author]" or "[[links]]").
+	tomlTablePattern = regexp.MustCompile(`^\[{1,2}\s*([^\[\]]+?)\s*\]{1,2}$`)
+)
+
+// parseTOML converts the supplied TOML lines into blocks.
+// Only the subset of TOML that is commonly used in front matter is supported:
+// key-value pairs, tables, arrays (which become repeated blocks) and inline tables.
+func parseTOML(lines []string) []model.Block {
+
+	blocks := make([]model.Block, 0)
+	tableName := ""
+
+	for lineNumber := 0; lineNumber < len(lines); lineNumber++ {
+		content := strings.TrimSpace(lines[lineNumber])
+
+		// skip empty lines and comments
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+
+		// tables prefix the names of the following keys
+		if matches := tomlTablePattern.FindStringSubmatch(content); matches != nil {
+			tableName = matches[1]
+			continue
+		}
+
+		matches := tomlKeyValuePattern.FindStringSubmatch(content)
+		if matches == nil {
+			continue
+		}
+
+		name := getTOMLName(tableName, unquote(matches[1]))
+		value := removeComment(matches[2])
+
+		// collect the remaining lines of multi-line arrays
+		if strings.HasPrefix(value, "[") {
+			for !strings.HasSuffix(value, "]") && lineNumber+1 < len(lines) {
+				lineNumber++
+				value = strings.TrimSuffix(value, ",") + ", " + removeComment(strings.TrimSpace(lines[lineNumber]))
+			}
+		}
+
+		blocks = appendTOMLValue(blocks, name, value)
+	}
+
+	return blocks
+}
+
+// appendTOMLValue appends the blocks for a single TOML value.
+// Arrays become repeated blocks and inline tables (e.g. "{ name = "John" }") are flattened into dotted names.
+func appendTOMLValue(blocks []model.Block, name, value string) []model.Block {
+
+	if isList, entries := splitInlineList(value); isList {
+		for _, entry := range entries {
+			blocks = addBlock(blocks, name, entry)
+		}
+
+		return blocks
+	}
+
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		for _, entry := range strings.Split(value[1:(len(value)-1)], ",") {
+			if matches := tomlKeyValuePattern.FindStringSubmatch(strings.TrimSpace(entry)); matches != nil {
+				blocks = addBlock(blocks, getTOMLName(name, unquote(matches[1])), unquote(matches[2]))
+			}
+		}
+
+		return blocks
+	}
+
+	return addBlock(blocks, name, unquote(value))
+}
+
+// getTOMLName returns the dotted name (e.g. "author.name") of the given key in the supplied table.
+func getTOMLName(tableName, key string) string {
+	key = strings.TrimSpace(key)
+	if tableName == "" {
+		return key
+	}
+
+	return tableName + "." + key
+}
@@ -0,0 +1,152 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontmatter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+// The regular expression which matches YAML key-value pairs (e.g. "author: John Doe" or "author:").
+var yamlKeyValuePattern = regexp.MustCompile(`^([^:#\-\s][^:#]*?)\s*:(?:\s+(.*))?$`)
+
+// A yamlParent is a key of a nested YAML mapping.
+type yamlParent struct {
+	indentation int
+	name        string
+}
+
+// parseYAML converts the supplied YAML lines into blocks.
+// Only the subset of YAML that is commonly used in front matter is supported:
+// key-value pairs, nested mappings, lists, inline lists and literal or folded multi-line values.
+func parseYAML(lines []string) []model.Block {
+
+	blocks := make([]model.Block, 0)
+	parents := make([]yamlParent, 0)
+
+	for lineNumber := 0; lineNumber < len(lines); lineNumber++ {
+		line := lines[lineNumber]
+		content := strings.TrimSpace(line)
+
+		// skip empty lines and comments
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+
+		indentation := getIndentation(line)
+		isListItem := content == "-" || strings.HasPrefix(content, "- ")
+
+		// leave the mappings which have been closed by this line.
+		// List items may have the same indentation as their parent key.
+		for len(parents) > 0 {
+			parent := parents[len(parents)-1]
+			if parent.indentation < indentation || (isListItem && parent.indentation == indentation) {
+				break
+			}
+
+			parents = parents[:(len(parents) - 1)]
+		}
+
+		parentName := getYAMLName(parents, "")
+
+		if isListItem {
+			content = strings.TrimSpace(strings.TrimPrefix(content, "-"))
+
+			// list items which are mappings (e.g. "- name: value")
+			if matches := yamlKeyValuePattern.FindStringSubmatch(content); matches != nil {
+				blocks = addBlock(blocks, getYAMLName(parents, matches[1]), unquote(removeComment(matches[2])))
+				continue
+			}
+
+			// list items without a parent key cannot be named
+			if parentName == "" {
+				continue
+			}
+
+			blocks = addBlock(blocks, parentName, unquote(removeComment(content)))
+			continue
+		}
+
+		matches := yamlKeyValuePattern.FindStringSubmatch(content)
+		if matches == nil {
+			continue
+		}
+
+		name := getYAMLName(parents, matches[1])
+		value := removeComment(strings.TrimSpace(matches[2]))
+
+		// a key without a value starts a nested mapping or a list
+		if value == "" {
+			parents = append(parents, yamlParent{indentation, name})
+			continue
+		}
+
+		// literal ("|") and folded (">") multi-line values
+		if isMultiLineIndicator(value) {
+			valueLines := make([]string, 0)
+			for lineNumber+1 < len(lines) {
+				nextLine := lines[lineNumber+1]
+				if strings.TrimSpace(nextLine) != "" && getIndentation(nextLine) <= indentation {
+					break
+				}
+
+				valueLines = append(valueLines, strings.TrimSpace(nextLine))
+				lineNumber++
+			}
+
+			separator := "\n"
+			if strings.HasPrefix(value, ">") {
+				separator = " "
+			}
+
+			blocks = addBlock(blocks, name, strings.Join(valueLines, separator))
+			continue
+		}
+
+		// inline lists (e.g. "[one, two]") become repeated blocks
+		if isList, entries := splitInlineList(value); isList {
+			for _, entry := range entries {
+				blocks = addBlock(blocks, name, entry)
+			}
+
+			continue
+		}
+
+		blocks = addBlock(blocks, name, unquote(value))
+	}
+
+	return blocks
+}
+
+// getYAMLName returns the dotted name (e.g. "author.name") of the given key below the supplied parents.
+func getYAMLName(parents []yamlParent, key string) string {
+	names := make([]string, 0, len(parents)+1)
+	for _, parent := range parents {
+		names = append(names, parent.name)
+	}
+
+	if key != "" {
+		names = append(names, strings.TrimSpace(key))
+	}
+
+	return strings.Join(names, ".")
+}
+
+// isMultiLineIndicator checks if the supplied value is a YAML block scalar indicator (e.g. "|", ">" or "|-").
+func isMultiLineIndicator(value string) bool {
+	switch value {
+	case "|", "|-", "|+", ">", ">-", ">+":
+		return true
+	}
+
+	return false
+}
+
+// getIndentation returns the number of leading whitespace characters of the supplied line.
+func getIndentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -33,12 +33,72 @@ func Parse(item *model.Item, lastModifiedDate time.Time, lines []string) (parseE
 	remainingLines = parseLastModifiedDate(metaData, lastModifiedDate, remainingLines)
 	remainingLines = parseTags(metaData, remainingLines)
 	remainingLines = parseGeoInformation(metaData, remainingLines)
+	parseBlocks(metaData, metaDataLines)
 
 	// assign the meta data to the item
 	item.MetaData = *metaData
 	return
 }
 
+// ParseBlocks sets the language, the author, the creation date and the tags of the supplied meta data
+// from the given blocks (e.g. the blocks of a front matter section) unless the meta data already defines them.
+// Repeated blocks (e.g. "tags" for every entry of a YAML list) and comma-separated values are combined.
+func ParseBlocks(metaData *model.MetaData, blocks []model.Block) {
+
+	var rawTags []string
+	for _, block := range blocks {
+		value := strings.TrimSpace(block.Value)
+
+		switch {
+
+		case keyNamesMatch(block.Name, []string{"language", "lang"}) && metaData.Language == "":
+			metaData.Language = value
+
+		case keyNamesMatch(block.Name, []string{"author"}) && metaData.Author == "":
+			metaData.Author = value
+
+		case keyNamesMatch(block.Name, []string{"created at", "date"}) && metaData.CreationDate.IsZero():
+			if date, err := dateutil.ParseIso8601Date(value, time.Time{}); err == nil {
+				metaData.CreationDate = date
+			}
+
+		case keyNamesMatch(block.Name, []string{"tags"}) && len(metaData.Tags) == 0:
+			rawTags = append(rawTags, strings.Split(value, ",")...)
+
+		}
+	}
+
+	if len(rawTags) > 0 {
+		metaData.Tags = normalizeTags(rawTags)
+	}
+}
+
+// parseBlocks adds a block for every name-value pair in the supplied meta data lines.
+// Each entry of a multi-line list (e.g. "tags:" followed by "- tag") becomes a separate block with the name of the list.
+func parseBlocks(metaData *model.MetaData, lines []string) {
+
+	listName := ""
+	for _, line := range lines {
+
+		name, value := pattern.GetSingleLineMetaDataKeyAndValue(line)
+		if name != "" {
+			listName = ""
+		} else if key := pattern.GetMetaDataKey(line); key != "" {
+			listName = key
+			continue
+		} else if isListItem, listItem := pattern.IsListItem(line); isListItem && listName != "" {
+			name, value = listName, listItem
+		} else {
+			continue
+		}
+
+		// invalid blocks are skipped
+		if err := metaData.AddBlock(name, value); err != nil {
+			continue
+		}
+	}
+}
+
 func parseLanguage(metaData *model.MetaData, lines []string) (remainingLines []string) {
 	found, value, remainingLines := getSingleLineMetaData([]string{"language", "lang"}, lines)
 	if found {
@@ -5,6 +5,7 @@
 package metadata
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/andreaskoch/allmark/model"
@@ -40,3 +41,97 @@ func Test_parseTags(t *testing.T) {
 		t.Errorf("The parser should have found 3 tags but contained only %v.", len(metaData.Tags))
 	}
 }
+
+func Test_ParseBlocks_FrontMatterBlocks_KnownKeysAreMapped(t *testing.T) {
+	// arrange
+	metaData := model.NewMetaData()
+	blocks := []model.Block{
+		{Name: "author", Value: "Jane Doe"},
+		{Name: "date", Value: "2015-03-01"},
+		{Name: "tags", Value: "go"},
+		{Name: "tags", Value: "web, markdown"},
+		{Name: "layout", Value: "post"},
+	}
+
+	// act
+	ParseBlocks(metaData, blocks)
+
+	// assert
+	if metaData.Author != "Jane Doe" {
+		t.Errorf("The author should be %q but was %q.", "Jane Doe", metaData.Author)
+	}
+
+	if metaData.CreationDate.Format("2006-01-02") != "2015-03-01" {
+		t.Errorf("The creation date should be %q but was %q.", "2015-03-01", metaData.CreationDate)
+	}
+
+	if strings.Join(metaData.Tags, ",") != "go,web,markdown" {
+		t.Errorf("The tags should be %q but were %v.", "go,web,markdown", metaData.Tags)
+	}
+}
+
+func Test_ParseBlocks_MetaDataIsDefined_MetaDataIsNotChanged(t *testing.T) {
+	// arrange
+	metaData := model.NewMetaData()
+	metaData.Author = "John Doe"
+	metaData.Tags = []string{"native"}
+
+	// act
+	ParseBlocks(metaData, []model.Block{{Name: "author", Value: "Jane Doe"}, {Name: "tags", Value: "go"}})
+
+	// assert
+	if metaData.Author != "John Doe" || strings.Join(metaData.Tags, ",") != "native" {
+		t.Errorf("The meta data should not be changed but the author was %q and the tags were %v.", metaData.Author, metaData.Tags)
+	}
+}
+
+func Test_parseBlocks_SingleLineAndListBlocksAreAdded(t *testing.T) {
+	// arrange
+	metaData := model.NewMetaData()
+	lines := []string{
+		"author: John Doe",
+		"tags:",
+		"- tag1",
+		"- tag2",
+		"custom field: some value",
+	}
+
+	// act
+	parseBlocks(metaData, lines)
+
+	// assert
+	expected := []model.Block{
+		{Name: "author", Value: "John Doe"},
+		{Name: "tags", Value: "tag1"},
+		{Name: "tags", Value: "tag2"},
+		{Name: "custom field", Value: "some value"},
+	}
+
+	if len(metaData.Blocks) != len(expected) {
+		t.Errorf("The parser should have found %d blocks but found %d (%v).", len(expected), len(metaData.Blocks), metaData.Blocks)
+		return
+	}
+
+	for index, block := range expected {
+		if metaData.Blocks[index] != block {
+			t.Errorf("Block %d should be %q but was %q.", index, block, metaData.Blocks[index])
+		}
+	}
+}
+
+func Test_parseBlocks_ValueIsAPath_BlockIsAdded(t *testing.T) {
+	// arrange
+	metaData := model.NewMetaData()
+	lines := []string{
+		"aliases: /documents/old-name, /2014/old-name.html",
+	}
+
+	// act
+	parseBlocks(metaData, lines)
+
+	// assert
+	expected := model.Block{Name: "aliases", Value: "/documents/old-name, /2014/old-name.html"}
+	if len(metaData.Blocks) != 1 || metaData.Blocks[0] != expected {
+		t.Errorf("The parser should have found the block %q but found %v.", expected, metaData.Blocks)
+	}
+}
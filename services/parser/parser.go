@@ -15,6 +15,8 @@ import (
 	"github.com/andreaskoch/allmark/model"
 	"github.com/andreaskoch/allmark/services/parser/cleanup"
 	"github.com/andreaskoch/allmark/services/parser/document"
+	"github.com/andreaskoch/allmark/services/parser/frontmatter"
+	"github.com/andreaskoch/allmark/services/parser/metadata"
 	"github.com/andreaskoch/allmark/services/parser/presentation"
 	"github.com/andreaskoch/allmark/services/parser/typedetection"
 )
@@ -63,10 +65,25 @@ func (parser *Parser) ParseItem(item dataaccess.Item) (*model.Item, error) {
 	lines := getLines(bytes.NewReader(data))
 	lines = cleanup.Cleanup(lines)
 
-	// detect the item type
-	switch itemModel.Type = typedetection.DetectType(lines); itemModel.Type {
+	// strip the front matter section (YAML fenced by "---" or TOML fenced by "+++") from the content
+	frontMatterBlocks, lines, _ := frontmatter.Parse(lines)
 
-	case model.TypeDocument, model.TypeRepository:
+	// detect the item type (the meta data at the end of the content takes precedence over the front matter)
+	typeName := typedetection.GetTypeNameFromMetaData(lines)
+	if typeName == "" {
+		typeName = typedetection.GetTypeNameFromBlocks(frontMatterBlocks)
+	}
+
+	itemModel.Type = typedetection.DetectItemTypeByName(item.FileName(), typeName)
+	if typeName != "" {
+		if _, isValidType := model.GetItemTypeByName(typeName); !isValidType {
+			parser.logger.Warn("Item %q defines the unknown type %q. Using the type %q instead.", item, typeName, itemModel.Type)
+		}
+	}
+
+	switch itemModel.Type {
+
+	case model.TypeDocument, model.TypeRepository, model.TypeRedirect, model.TypeCollection, model.TypeComment, model.TypeMessage:
 		{
 			if _, err := document.Parse(itemModel, lastModifiedDate, lines); err != nil {
 				return nil, fmt.Errorf("Unable to parse item %q (Type: %s, Error: %s)", item, itemModel.Type, err.Error())
@@ -81,10 +98,35 @@ func (parser *Parser) ParseItem(item dataaccess.Item) (*model.Item, error) {
 		}
 
 	default:
-		return nil, fmt.Errorf("Cannot parse item %q. Unknown item type.", item)
+		{
+			if !itemModel.Type.IsCustom() {
+				return nil, fmt.Errorf("Cannot parse item %q. Unknown item type.", item)
+			}
+
+			// custom item types are parsed like documents
+			if _, err := document.Parse(itemModel, lastModifiedDate, lines); err != nil {
+				return nil, fmt.Errorf("Unable to parse item %q (Type: %s, Error: %s)", item, itemModel.Type, err.Error())
+			}
+		}
 
 	}
 
+	// use the language, the author, the date and the tags of the front matter section if the meta data does not define them
+	metadata.ParseBlocks(&itemModel.MetaData, frontMatterBlocks)
+
+	// use the language of localized markdown files (e.g. "document.de.md") if the meta data does not define one
+	if itemModel.MetaData.Language == "" {
+		itemModel.MetaData.Language = dataaccess.GetMarkdownFileLanguage(item.FileName())
+	}
+
+	// add the blocks of the front matter section to the meta data
+	for _, block := range frontMatterBlocks {
+		itemModel.MetaData.AddBlock(block.Name, block.Value)
+	}
+
+	// resolve the title (title block, first headline or directory name)
+	itemModel.Title = itemModel.GetTitle()
+
 	// item hash
 	hash, err := item.Hash()
 	if err != nil {
@@ -0,0 +1,90 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess/filesystem"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// parseTestItem writes the supplied markdown to "post/document.md" of a new repository and parses the item.
+func parseTestItem(t *testing.T, markdown string) *model.Item {
+	directory, _ := ioutil.TempDir("", "allmark-parser")
+	defer os.RemoveAll(directory)
+
+	os.MkdirAll(filepath.Join(directory, "post"), 0700)
+	ioutil.WriteFile(filepath.Join(directory, "post", "document.md"), []byte(markdown), 0600)
+
+	logger := console.New(loglevel.Fatal)
+	repository, err := filesystem.NewRepository(logger, directory, *config.New(directory))
+	if err != nil {
+		t.Fatalf("The repository could not be created. Error: %s", err)
+	}
+
+	repositoryItem := repository.Item(route.NewFromRequest("post"))
+	if repositoryItem == nil {
+		t.Fatalf("The repository should contain the item %q.", "post")
+	}
+
+	parser, _ := New(logger)
+	item, err := parser.ParseItem(repositoryItem)
+	if err != nil {
+		t.Fatalf("The item could not be parsed. Error: %s", err)
+	}
+
+	return item
+}
+
+func Test_ParseItem_FrontMatterOnly_TypeAndMetaDataAreParsed(t *testing.T) {
+	// arrange
+	markdown := "---\ntype: presentation\nauthor: Jane Doe\ndate: 2015-03-01\ntags: [go, web]\n---\n# Slides\n\nSlide 1\n\n---\n\nSlide 2\n"
+
+	// act
+	item := parseTestItem(t, markdown)
+
+	// assert
+	if item.Type != model.TypePresentation {
+		t.Errorf("The type should be %s but was %s.", model.TypePresentation, item.Type)
+	}
+
+	if item.MetaData.Author != "Jane Doe" {
+		t.Errorf("The author should be %q but was %q.", "Jane Doe", item.MetaData.Author)
+	}
+
+	if item.MetaData.CreationDate.Format("2006-01-02") != "2015-03-01" {
+		t.Errorf("The creation date should be %q but was %q.", "2015-03-01", item.MetaData.CreationDate)
+	}
+
+	if strings.Join(item.MetaData.Tags, ",") != "go,web" {
+		t.Errorf("The tags should be %q but were %v.", "go,web", item.MetaData.Tags)
+	}
+}
+
+func Test_ParseItem_FrontMatterAndMetaData_MetaDataTakesPrecedence(t *testing.T) {
+	// arrange
+	markdown := "---\ntype: presentation\nauthor: Jane Doe\n---\n# Document\n\nThe content.\n\n---\ntype: document\nauthor: John Doe\n"
+
+	// act
+	item := parseTestItem(t, markdown)
+
+	// assert
+	if item.Type != model.TypeDocument {
+		t.Errorf("The type should be %s but was %s.", model.TypeDocument, item.Type)
+	}
+
+	if item.MetaData.Author != "John Doe" {
+		t.Errorf("The author should be %q but was %q.", "John Doe", item.MetaData.Author)
+	}
+}
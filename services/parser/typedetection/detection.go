@@ -12,16 +12,71 @@ import (
 	"github.com/andreaskoch/allmark/services/parser/pattern"
 )
 
+// TreatUnknownMarkdownAsDocument controls how markdown files are handled that neither define a type in their
+// meta data nor have a registered file name (see RegisterItemType). If true (default) they are documents,
+// otherwise their type is unknown and they will not be parsed. Items which are not backed by a markdown file
+// (virtual and file-collection items) are always documents.
+// There is no configuration setting or command line flag for it; programs which use the parser set it before parsing.
+var TreatUnknownMarkdownAsDocument = true
+
+// DetectType returns the item type defined in the meta data of the supplied lines.
+// If no (valid) type is defined, the type document is returned.
 func DetectType(lines []string) model.ItemType {
+	return DetectItemType("", lines)
+}
+
+// DetectItemType returns the type of an item with the given markdown file name (e.g. "presentation.md") and content lines.
+// A type defined in the meta data takes precedence over the type that is registered for the file name.
+// If neither defines a type, the type document is returned unless TreatUnknownMarkdownAsDocument is disabled.
+func DetectItemType(fileName string, lines []string) model.ItemType {
+	return DetectItemTypeByName(fileName, GetTypeNameFromMetaData(lines))
+}
+
+// DetectItemTypeByName returns the type of an item with the given markdown file name and the type name
+// of its meta data (e.g. "presentation" from a front matter section). See DetectItemType.
+func DetectItemTypeByName(fileName, typeName string) model.ItemType {
+
+	// meta data
+	if itemType, found := model.GetItemTypeByName(typeName); found {
+		return itemType
+	}
+
+	// file name
+	if itemType, found := getItemTypeByFileName(fileName); found {
+		return itemType
+	}
+
+	// strict mode
+	if !TreatUnknownMarkdownAsDocument && fileName != "" {
+		return model.TypeUnknown
+	}
+
+	return model.TypeDocument // fallback
+}
+
+// GetTypeNameFromBlocks returns the (lower-case) value of the type block of the supplied blocks (e.g. of a front matter section).
+// If there is no type block an empty string is returned.
+func GetTypeNameFromBlocks(blocks []model.Block) string {
+	for _, block := range blocks {
+		if strings.ToLower(strings.TrimSpace(block.Name)) == "type" && strings.TrimSpace(block.Value) != "" {
+			return strings.TrimSpace(strings.ToLower(block.Value))
+		}
+	}
+
+	return ""
+}
+
+// GetTypeNameFromMetaData returns the (lower-case) value of the type definition from the meta data of the supplied lines.
+// If there is no type definition an empty string is returned.
+func GetTypeNameFromMetaData(lines []string) string {
 
 	// get the meta data definitions
 	lines = metadata.GetMetaDataLines(lines)
 	if len(lines) == 0 {
-		return model.TypeDocument
+		return ""
 	}
 
 	// find the type name
-	typeName := ""
 	for _, line := range lines {
 		if !pattern.IsMetaDataDefinition(line) {
 			continue
@@ -29,22 +84,9 @@ func DetectType(lines []string) model.ItemType {
 
 		// search for a type definition
 		if key, value := pattern.GetSingleLineMetaDataKeyAndValue(line); strings.ToLower(key) == "type" && strings.TrimSpace(value) != "" {
-			typeName = strings.TrimSpace(strings.ToLower(value))
-			break // found a type definition
+			return strings.TrimSpace(strings.ToLower(value))
 		}
 	}
 
-	// detect the type
-	switch typeName {
-	case "document":
-		return model.TypeDocument
-	case "presentation":
-		return model.TypePresentation
-	case "repository":
-		return model.TypeRepository
-	default:
-		return model.TypeDocument // fallback
-	}
-
-	panic("Unreachable")
+	return ""
 }
@@ -81,3 +81,21 @@ func Test_DetectType_UnknownTypeFallsBackToDocument(t *testing.T) {
 		t.Errorf("The result type should be %s but was %s", expectedType, result)
 	}
 }
+
+func Test_DetectType_Redirect(t *testing.T) {
+	// arrange
+	inputLines := []string{
+		"",
+		"---",
+		"type: redirect",
+	}
+	expectedType := model.TypeRedirect
+
+	// act
+	result := DetectType(inputLines)
+
+	// assert
+	if result != expectedType {
+		t.Errorf("The result type should be %s but was %s", expectedType, result)
+	}
+}
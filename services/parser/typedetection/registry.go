@@ -0,0 +1,62 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typedetection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// itemTypesByFileName maps markdown file base names (e.g. "recipe") to item types.
+// Markdown files without a registration are documents unless their meta data defines another type
+// (or unknown if TreatUnknownMarkdownAsDocument is disabled).
+var itemTypesByFileName = map[string]model.ItemType{
+	"readme":       model.TypeDocument,
+	"document":     model.TypeDocument,
+	"presentation": model.TypePresentation,
+	"redirect":     model.TypeRedirect,
+	"collection":   model.TypeCollection,
+	"comment":      model.TypeComment,
+	"message":      model.TypeMessage,
+}
+
+// RegisterItemType assigns the item type with the given name to all items whose markdown file
+// has the given name (e.g. "recipe.md" -> "recipe"). The extension of the file name does not matter as long
+// as it is a markdown extension, so "recipe.md" also covers "recipe.markdown".
+// If the type name is not one of the built-in types (document, presentation, repository, redirect, collection, comment, message) a new custom item type is created.
+// Registering a file name which is already registered replaces the previous registration.
+func RegisterItemType(fileName, typeName string) error {
+
+	if strings.TrimSpace(typeName) == "" {
+		return fmt.Errorf("Cannot register an item type for %q without a type name.", fileName)
+	}
+
+	baseName := dataaccess.GetMarkdownFileBaseName(fileName)
+	if baseName == "" {
+		return fmt.Errorf("Cannot register the item type %q for %q because it is not a markdown file name (%s).", typeName, fileName, strings.Join(dataaccess.MarkdownFileExtensions, ", "))
+	}
+
+	itemType, err := model.NewCustomItemType(typeName)
+	if err != nil {
+		return fmt.Errorf("Cannot register the item type %q for %q. Error: %s", typeName, fileName, err.Error())
+	}
+
+	itemTypesByFileName[baseName] = itemType
+	return nil
+}
+
+// getItemTypeByFileName returns the item type that is registered for the given markdown file name.
+func getItemTypeByFileName(fileName string) (itemType model.ItemType, found bool) {
+	baseName := dataaccess.GetMarkdownFileBaseName(fileName)
+	if baseName == "" {
+		return model.TypeUnknown, false
+	}
+
+	itemType, found = itemTypesByFileName[baseName]
+	return itemType, found
+}
@@ -0,0 +1,227 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typedetection
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+func Test_RegisterItemType_CustomType_FileNameIsDetected(t *testing.T) {
+	// arrange
+	RegisterItemType("recipe.md", "recipe")
+	inputLines := []string{
+		"# Pancakes",
+	}
+
+	// act
+	result := DetectItemType("recipe.markdown", inputLines)
+
+	// assert
+	if result.String() != "recipe" {
+		t.Errorf("The result type should be %q but was %q", "recipe", result)
+	}
+
+	if !result.IsCustom() {
+		t.Errorf("The type %q should be a custom type", result)
+	}
+}
+
+func Test_RegisterItemType_BuiltInType_FileNameIsDetected(t *testing.T) {
+	// arrange
+	RegisterItemType("slides.md", "presentation")
+	expectedType := model.TypePresentation
+
+	// act
+	result := DetectItemType("slides.md", []string{})
+
+	// assert
+	if result != expectedType {
+		t.Errorf("The result type should be %s but was %s", expectedType, result)
+	}
+}
+
+func Test_RegisterItemType_MetaDataOverridesFileName(t *testing.T) {
+	// arrange
+	RegisterItemType("changelog.md", "changelog")
+	inputLines := []string{
+		"",
+		"---",
+		"type: presentation",
+	}
+	expectedType := model.TypePresentation
+
+	// act
+	result := DetectItemType("changelog.md", inputLines)
+
+	// assert
+	if result != expectedType {
+		t.Errorf("The result type should be %s but was %s", expectedType, result)
+	}
+}
+
+func Test_RegisterItemType_EmptyTypeName_ErrorIsReturned(t *testing.T) {
+	// act
+	err := RegisterItemType("recipe.md", " ")
+
+	// assert
+	if err == nil {
+		t.Errorf("RegisterItemType should return an error if the type name is empty")
+	}
+}
+
+func Test_RegisterItemType_NoMarkdownFileName_ErrorIsReturned(t *testing.T) {
+	// arrange
+	inputs := []string{
+		"recipe",
+		"recipe.txt",
+		"recipe.markdownx",
+	}
+
+	for _, input := range inputs {
+
+		// act
+		err := RegisterItemType(input, "recipe")
+
+		// assert
+		if err == nil {
+			t.Errorf("RegisterItemType should return an error for the file name %q", input)
+		}
+	}
+}
+
+func Test_DetectItemType_StrictMode_UnknownFileName_TypeIsUnknown(t *testing.T) {
+	// arrange
+	TreatUnknownMarkdownAsDocument = false
+	defer func() { TreatUnknownMarkdownAsDocument = true }()
+
+	inputLines := []string{
+		"# Notes",
+	}
+	expectedType := model.TypeUnknown
+
+	// act
+	result := DetectItemType("notes.md", inputLines)
+
+	// assert
+	if result != expectedType {
+		t.Errorf("The result type should be %s but was %s", expectedType, result)
+	}
+}
+
+func Test_DetectItemType_StrictMode_BuiltInFileNames_TypesAreDetected(t *testing.T) {
+	// arrange
+	TreatUnknownMarkdownAsDocument = false
+	defer func() { TreatUnknownMarkdownAsDocument = true }()
+
+	inputs := map[string]model.ItemType{
+		"README.md":       model.TypeDocument,
+		"document.md":     model.TypeDocument,
+		"presentation.md": model.TypePresentation,
+		"collection.md":   model.TypeCollection,
+	}
+
+	for fileName, expectedType := range inputs {
+
+		// act
+		result := DetectItemType(fileName, []string{"# Title"})
+
+		// assert
+		if result != expectedType {
+			t.Errorf("The type of %q should be %s but was %s", fileName, expectedType, result)
+		}
+	}
+}
+
+func Test_DetectItemType_StrictMode_NoMarkdownFile_TypeIsDocument(t *testing.T) {
+	// arrange
+	TreatUnknownMarkdownAsDocument = false
+	defer func() { TreatUnknownMarkdownAsDocument = true }()
+
+	inputLines := []string{
+		"# Virtual Item",
+	}
+	expectedType := model.TypeDocument
+
+	// act
+	result := DetectItemType("", inputLines)
+
+	// assert
+	if result != expectedType {
+		t.Errorf("The result type should be %s but was %s", expectedType, result)
+	}
+}
+
+func Test_DetectItemType_DefaultMode_UnknownFileName_TypeIsDocument(t *testing.T) {
+	// arrange
+	inputLines := []string{
+		"# Notes",
+	}
+	expectedType := model.TypeDocument
+
+	// act
+	result := DetectItemType("notes.md", inputLines)
+
+	// assert
+	if result != expectedType {
+		t.Errorf("The result type should be %s but was %s", expectedType, result)
+	}
+}
+
+func Test_DetectItemType_InvalidMetaDataType_FileNameTypeIsUsed(t *testing.T) {
+	// arrange
+	RegisterItemType("talk.md", "presentation")
+	inputLines := []string{
+		"",
+		"---",
+		"type: slideshow",
+	}
+	expectedType := model.TypePresentation
+
+	// act
+	result := DetectItemType("talk.md", inputLines)
+
+	// assert
+	if result != expectedType {
+		t.Errorf("The result type should be %s but was %s", expectedType, result)
+	}
+}
+
+func Test_GetTypeNameFromMetaData_TypeIsDefined_TypeNameIsReturned(t *testing.T) {
+	// arrange
+	inputLines := []string{
+		"",
+		"---",
+		"Type: Presentation",
+	}
+	expected := "presentation"
+
+	// act
+	result := GetTypeNameFromMetaData(inputLines)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q", expected, result)
+	}
+}
+
+func Test_DetectItemType_RedirectFileName_TypeIsRedirect(t *testing.T) {
+	// arrange
+	inputLines := []string{
+		"# Moved",
+		"",
+		"target: /new/location",
+	}
+	expectedType := model.TypeRedirect
+
+	// act
+	result := DetectItemType("redirect.md", inputLines)
+
+	// assert
+	if result != expectedType {
+		t.Errorf("The result type should be %s but was %s", expectedType, result)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/andreaskoch/allmark/services/imageconversion"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 )
 
@@ -32,7 +33,18 @@ var (
 	}
 )
 
-func NewConversionService(logger logger.Logger, repository dataaccess.Repository, thumbnailIndex *Index) *ConversionService {
+// GallerySize returns the dimensions of the thumbnails for image galleries
+// which fit into a square with the given edge length.
+func GallerySize(maxDimension uint) ThumbDimension {
+	return ThumbDimension{
+		MaxWidth:  maxDimension,
+		MaxHeight: maxDimension,
+	}
+}
+
+// NewConversionService creates a new thumbnail conversion service which creates thumbnails in the small,
+// medium, large and the supplied gallery size for all image files of the given repository.
+func NewConversionService(logger logger.Logger, repository dataaccess.Repository, thumbnailIndex *Index, gallerySize ThumbDimension) *ConversionService {
 
 	// create a new conversion service
 	conversionService := &ConversionService{
@@ -41,6 +53,7 @@ func NewConversionService(logger logger.Logger, repository dataaccess.Repository
 		repository:      repository,
 		index:           thumbnailIndex,
 		thumbnailFolder: thumbnailIndex.GetThumbnailFolder(),
+		sizes:           []ThumbDimension{SizeSmall, SizeMedium, SizeLarge, gallerySize},
 	}
 
 	// start the conversion
@@ -57,6 +70,7 @@ type ConversionService struct {
 
 	index           *Index
 	thumbnailFolder string
+	sizes           []ThumbDimension
 }
 
 // Start the conversion process.
@@ -107,9 +121,9 @@ func (conversion *ConversionService) createThumbnailsForItem(item dataaccess.Ite
 
 // Create thumbnail for all image files found in the supplied item.
 func (conversion *ConversionService) createThumbnailsForFile(file dataaccess.File) {
-	conversion.createThumbnail(file, SizeSmall)
-	conversion.createThumbnail(file, SizeMedium)
-	conversion.createThumbnail(file, SizeLarge)
+	for _, dimensions := range conversion.sizes {
+		conversion.createThumbnail(file, dimensions)
+	}
 }
 
 // Creates a thumbnail for the supplied file with the specified dimensions.
@@ -132,7 +146,14 @@ func (conversion *ConversionService) createThumbnail(file dataaccess.File, dimen
 	fileExtension := imageconversion.GetFileExtensionFromMimeType(mimeType)
 	filename := fmt.Sprintf("%s-%v-%v.%s", file.Id(), dimensions.MaxWidth, dimensions.MaxHeight, fileExtension)
 
-	thumb := newThumb(file.Route(), filename, dimensions)
+	// the content hash of the source image
+	sourceHash, err := file.ContentHash()
+	if err != nil {
+		conversion.logger.Warn("Unable to determine the hash of file %q. Error: %s", file, err.Error())
+		return
+	}
+
+	thumb := newThumb(file.Route(), filename, dimensions, sourceHash)
 
 	// check the index
 	if conversion.isInIndex(thumb) {
@@ -143,23 +164,16 @@ func (conversion *ConversionService) createThumbnail(file dataaccess.File, dimen
 	// determine the file path
 	filePath := filepath.Join(conversion.thumbnailFolder, filename)
 
-	// create the target file
-	created, createError := fsutil.CreateFile(filePath)
-	if !created {
-		conversion.logger.Warn("Could not create thumbnail file %q. Error: %s", filePath, createError.Error())
-		return
-	}
-
-	// open the target file
-	target, fileError := fsutil.OpenFile(filePath)
+	// create (or truncate) the target file
+	target, fileError := os.Create(filePath)
 	if fileError != nil {
-		conversion.logger.Warn("Unable to detect mime type for file. Error: %s", fileError.Error())
+		conversion.logger.Warn("Could not create thumbnail file %q. Error: %s", filePath, fileError.Error())
 		return
 	}
 
 	defer target.Close()
 
-	// convert the image
+	// convert the image. The thumbnails are re-encoded, so they don't contain any meta data of the source image.
 	conversionError := file.Data(func(content io.ReadSeeker) error {
 		return imageconversion.Resize(content, mimeType, dimensions.MaxWidth, dimensions.MaxHeight, target)
 	})
@@ -175,6 +189,7 @@ func (conversion *ConversionService) createThumbnail(file dataaccess.File, dimen
 	conversion.logger.Debug("Adding Thumb %q to index", thumb.String())
 }
 
+// isInIndex checks if the supplied thumb has already been created from the same source image.
 func (conversion *ConversionService) isInIndex(thumb Thumb) bool {
 
 	// check if there are thumb for the route
@@ -184,7 +199,13 @@ func (conversion *ConversionService) isInIndex(thumb Thumb) bool {
 	}
 
 	// check if there is a thumb with that dimensions
-	if _, thumbExists := thumbs[thumb.Dimensions.String()]; thumbExists {
+	if existingThumb, thumbExists := thumbs[thumb.Dimensions.String()]; thumbExists {
+
+		// check if the source image has changed
+		if existingThumb.SourceHash != thumb.SourceHash {
+			return false
+		}
+
 		// check if the file exists
 		thumbnailFilePath := conversion.index.GetThumbnailFilepath(thumb)
 		return fsutil.FileExists(thumbnailFilePath)
@@ -0,0 +1,143 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+)
+
+// A testImageFile is a dataaccess.File with in-memory image data.
+type testImageFile struct {
+	dataaccess.File
+	data []byte
+	hash string
+}
+
+func (file *testImageFile) Data(contentReader func(content io.ReadSeeker) error) error {
+	return contentReader(bytes.NewReader(file.data))
+}
+
+func (file *testImageFile) MimeType() (string, error) {
+	return "image/png", nil
+}
+
+func (file *testImageFile) ContentHash() (string, error) {
+	return file.hash, nil
+}
+
+func (file *testImageFile) Id() string {
+	return "photo"
+}
+
+func (file *testImageFile) Route() route.Route {
+	return route.NewFromRequest("gallery/files/photo.png")
+}
+
+func (file *testImageFile) String() string {
+	return "photo.png"
+}
+
+func newTestConversionService(thumbnailFolder string) *ConversionService {
+	index := EmptyIndex()
+	index.thumbnailFolder = thumbnailFolder
+
+	return &ConversionService{
+		logger:          console.New(loglevel.Fatal),
+		index:           index,
+		thumbnailFolder: thumbnailFolder,
+	}
+}
+
+func newTestImageFile(width, height int, hash string) *testImageFile {
+	buffer := new(bytes.Buffer)
+	png.Encode(buffer, image.NewRGBA(image.Rect(0, 0, width, height)))
+	return &testImageFile{data: buffer.Bytes(), hash: hash}
+}
+
+func Test_createThumbnail_ThumbnailPreservesTheAspectRatio(t *testing.T) {
+	// arrange
+	thumbnailFolder, _ := ioutil.TempDir("", "allmark-thumbnails")
+	defer os.RemoveAll(thumbnailFolder)
+
+	conversion := newTestConversionService(thumbnailFolder)
+	file := newTestImageFile(1200, 600, "hash1")
+	dimensions := GallerySize(300)
+
+	// act
+	conversion.createThumbnail(file, dimensions)
+
+	// assert
+	thumb, exists := conversion.index.GetThumb(file.Route(), dimensions)
+	if !exists {
+		t.Fatalf("The thumbnail should have been added to the index.")
+	}
+
+	thumbnailFile, err := os.Open(conversion.index.GetThumbnailFilepath(thumb))
+	if err != nil {
+		t.Fatalf("The thumbnail file should exist. Error: %s", err)
+	}
+
+	defer thumbnailFile.Close()
+
+	config, _, err := image.DecodeConfig(thumbnailFile)
+	if err != nil {
+		t.Fatalf("The thumbnail should be a valid image. Error: %s", err)
+	}
+
+	if config.Width != 300 || config.Height != 150 {
+		t.Errorf("The thumbnail should be 300x150 but was %dx%d.", config.Width, config.Height)
+	}
+}
+
+func Test_createThumbnail_UnchangedSource_ThumbnailIsReused(t *testing.T) {
+	// arrange
+	thumbnailFolder, _ := ioutil.TempDir("", "allmark-thumbnails")
+	defer os.RemoveAll(thumbnailFolder)
+
+	conversion := newTestConversionService(thumbnailFolder)
+	dimensions := GallerySize(300)
+	conversion.createThumbnail(newTestImageFile(600, 600, "hash1"), dimensions)
+
+	// act
+	conversion.createThumbnail(newTestImageFile(100, 50, "hash1"), dimensions)
+	unchangedThumb, _ := conversion.index.GetThumb(route.NewFromRequest("gallery/files/photo.png"), dimensions)
+	unchangedConfig := getThumbnailConfig(conversion, unchangedThumb)
+
+	conversion.createThumbnail(newTestImageFile(100, 50, "hash2"), dimensions)
+	changedThumb, _ := conversion.index.GetThumb(route.NewFromRequest("gallery/files/photo.png"), dimensions)
+	changedConfig := getThumbnailConfig(conversion, changedThumb)
+
+	// assert
+	if unchangedConfig.Width != 300 {
+		t.Errorf("The thumbnail should not be recreated if the source hash is unchanged (width: %d).", unchangedConfig.Width)
+	}
+
+	if changedThumb.SourceHash != "hash2" || changedConfig.Width != 100 {
+		t.Errorf("The thumbnail should be recreated if the source has changed (hash: %q, width: %d).", changedThumb.SourceHash, changedConfig.Width)
+	}
+}
+
+func getThumbnailConfig(conversion *ConversionService, thumb Thumb) image.Config {
+	thumbnailFile, err := os.Open(conversion.index.GetThumbnailFilepath(thumb))
+	if err != nil {
+		return image.Config{}
+	}
+
+	defer thumbnailFile.Close()
+
+	config, _, _ := image.DecodeConfig(thumbnailFile)
+	return config
+}
@@ -113,12 +113,13 @@ func (indexSerializer) DeserializeIndex(reader io.Reader) (*Index, error) {
 	return index, err
 }
 
-func newThumb(route route.Route, path string, dimensions ThumbDimension) Thumb {
+func newThumb(route route.Route, path string, dimensions ThumbDimension, sourceHash string) Thumb {
 
 	return Thumb{
 		Route:      route.Value(),
 		Path:       path,
 		Dimensions: dimensions,
+		SourceHash: sourceHash,
 	}
 
 }
@@ -127,6 +128,9 @@ type Thumb struct {
 	Route      string         `json:"route"`
 	Path       string         `json:"path"`
 	Dimensions ThumbDimension `json:"dimensions"`
+
+	// the content hash of the source image the thumb has been created from
+	SourceHash string `json:"sourceHash"`
 }
 
 func (t Thumb) String() string {
@@ -167,6 +171,16 @@ func (i *Index) SetThumbs(thumbnailRoute string, thumbs Thumbs) {
 	i.Thumbs[thumbnailRoute] = thumbs
 }
 
+// GetThumb returns the thumb with the given dimensions for the file with the supplied route.
+func (i *Index) GetThumb(fileRoute route.Route, dimensions ThumbDimension) (Thumb, bool) {
+	thumbs, exists := i.GetThumbs(fileRoute.Value())
+	if !exists {
+		return Thumb{}, false
+	}
+
+	return thumbs.GetThumbBySize(dimensions)
+}
+
 func (i *Index) GetThumbnailFolder() string {
 	return i.thumbnailFolder
 }
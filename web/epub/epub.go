@@ -0,0 +1,332 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package epub writes books in the EPUB 3 format (with an EPUB 2 table of contents for older readers).
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	htmlparser "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MimeType is the media type of EPUB files.
+const MimeType = "application/epub+zip"
+
+// A Book contains the metadata, chapters and images of an EPUB file.
+type Book struct {
+	Identifier string
+	Title      string
+	Author     string
+	Language   string
+	Modified   time.Time
+
+	// Cover is the path of the image which is used as the cover (e.g. "images/cover.jpg");
+	// it must be one of the images. Books without a cover image are valid too.
+	Cover string
+
+	// Chapters contains the chapters in reading order.
+	Chapters []Chapter
+
+	// Images contains all images which are referenced by the chapters or used as the cover.
+	Images []Image
+}
+
+// A Chapter is a single content document of a book.
+type Chapter struct {
+	Title string
+
+	// Content is the HTML code of the chapter body. It is converted to XHTML when the book is written.
+	Content string
+}
+
+// An Image is embedded in a book.
+type Image struct {
+	// Path is the path of the image relative to the chapters (e.g. "images/photo.jpg").
+	Path      string
+	MediaType string
+	Data      []byte
+}
+
+// Write writes the book as an EPUB file to the supplied writer.
+func (book Book) Write(writer io.Writer) error {
+	archive := zip.NewWriter(writer)
+
+	// the mimetype must be the first, uncompressed file of the archive
+	if err := addStoredFile(archive, "mimetype", []byte(MimeType)); err != nil {
+		archive.Close()
+		return err
+	}
+
+	files := []bookFile{
+		{"META-INF/container.xml", containerXML},
+		{"OEBPS/content.opf", book.getPackageDocument()},
+		{"OEBPS/nav.xhtml", book.getNavigationDocument()},
+		{"OEBPS/toc.ncx", book.getNCX()},
+	}
+
+	if book.Cover != "" {
+		files = append(files, bookFile{"OEBPS/" + coverFileName, book.getCoverDocument()})
+	}
+
+	for index, chapter := range book.Chapters {
+		content, err := book.getChapterDocument(chapter)
+		if err != nil {
+			archive.Close()
+			return fmt.Errorf("Cannot convert chapter %q. Error: %s", chapter.Title, err.Error())
+		}
+
+		files = append(files, bookFile{"OEBPS/" + getChapterFileName(index), content})
+	}
+
+	for _, file := range files {
+		if err := addFile(archive, file.name, []byte(file.content)); err != nil {
+			archive.Close()
+			return err
+		}
+	}
+
+	for _, image := range book.Images {
+		if err := addFile(archive, "OEBPS/"+image.Path, image.Data); err != nil {
+			archive.Close()
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// A bookFile is a text file of the archive (e.g. the package document).
+type bookFile struct {
+	name    string
+	content string
+}
+
+const (
+	coverFileName = "cover.xhtml"
+
+	containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+	<rootfiles>
+		<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+	</rootfiles>
+</container>
+`
+)
+
+// getChapterFileName returns the file name of the chapter with the given (zero-based) index (e.g. "chapter-001.xhtml").
+func getChapterFileName(index int) string {
+	return fmt.Sprintf("chapter-%03d.xhtml", index+1)
+}
+
+func (book Book) getPackageDocument() string {
+	var document bytes.Buffer
+
+	document.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	document.WriteString(`<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id">` + "\n")
+
+	// metadata
+	document.WriteString("\t" + `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">` + "\n")
+	fmt.Fprintf(&document, "\t\t<dc:identifier id=\"book-id\">%s</dc:identifier>\n", escape(book.Identifier))
+	fmt.Fprintf(&document, "\t\t<dc:title>%s</dc:title>\n", escape(book.Title))
+	fmt.Fprintf(&document, "\t\t<dc:language>%s</dc:language>\n", escape(book.getLanguage()))
+	if book.Author != "" {
+		fmt.Fprintf(&document, "\t\t<dc:creator>%s</dc:creator>\n", escape(book.Author))
+	}
+
+	fmt.Fprintf(&document, "\t\t<meta property=\"dcterms:modified\">%s</meta>\n", book.Modified.UTC().Format("2006-01-02T15:04:05Z"))
+	if coverIndex := book.getCoverIndex(); coverIndex >= 0 {
+		fmt.Fprintf(&document, "\t\t<meta name=\"cover\" content=\"%s\"/>\n", getImageID(coverIndex))
+	}
+
+	document.WriteString("\t</metadata>\n")
+
+	// manifest
+	document.WriteString("\t<manifest>\n")
+	document.WriteString("\t\t" + `<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>` + "\n")
+	document.WriteString("\t\t" + `<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>` + "\n")
+	if book.Cover != "" {
+		fmt.Fprintf(&document, "\t\t<item id=\"cover\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", coverFileName)
+	}
+
+	for index := range book.Chapters {
+		fmt.Fprintf(&document, "\t\t<item id=\"chapter-%d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", index+1, getChapterFileName(index))
+	}
+
+	for index, image := range book.Images {
+		properties := ""
+		if image.Path == book.Cover {
+			properties = ` properties="cover-image"`
+		}
+
+		fmt.Fprintf(&document, "\t\t<item id=\"%s\" href=\"%s\" media-type=\"%s\"%s/>\n", getImageID(index), escape(image.Path), escape(image.MediaType), properties)
+	}
+
+	document.WriteString("\t</manifest>\n")
+
+	// spine
+	document.WriteString("\t" + `<spine toc="ncx">` + "\n")
+	if book.Cover != "" {
+		document.WriteString("\t\t" + `<itemref idref="cover" linear="no"/>` + "\n")
+	}
+
+	for index := range book.Chapters {
+		fmt.Fprintf(&document, "\t\t<itemref idref=\"chapter-%d\"/>\n", index+1)
+	}
+
+	document.WriteString("\t</spine>\n")
+	document.WriteString("</package>\n")
+
+	return document.String()
+}
+
+// getNavigationDocument returns the EPUB 3 table of contents.
+func (book Book) getNavigationDocument() string {
+	var navigation bytes.Buffer
+
+	fmt.Fprintf(&navigation, `<nav epub:type="toc" id="toc"><h1>%s</h1>`+"\n<ol>\n", escape(book.Title))
+	for index, chapter := range book.Chapters {
+		fmt.Fprintf(&navigation, "<li><a href=\"%s\">%s</a></li>\n", getChapterFileName(index), escape(chapter.Title))
+	}
+
+	navigation.WriteString("</ol>\n</nav>")
+
+	return book.getXHTMLDocument(book.Title, navigation.String())
+}
+
+// getNCX returns the EPUB 2 table of contents.
+func (book Book) getNCX() string {
+	var ncx bytes.Buffer
+
+	ncx.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	ncx.WriteString(`<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">` + "\n")
+	fmt.Fprintf(&ncx, "\t<head><meta name=\"dtb:uid\" content=\"%s\"/></head>\n", escape(book.Identifier))
+	fmt.Fprintf(&ncx, "\t<docTitle><text>%s</text></docTitle>\n", escape(book.Title))
+	ncx.WriteString("\t<navMap>\n")
+
+	for index, chapter := range book.Chapters {
+		fmt.Fprintf(&ncx, "\t\t<navPoint id=\"navpoint-%d\" playOrder=\"%d\"><navLabel><text>%s</text></navLabel><content src=\"%s\"/></navPoint>\n",
+			index+1,
+			index+1,
+			escape(chapter.Title),
+			getChapterFileName(index))
+	}
+
+	ncx.WriteString("\t</navMap>\n")
+	ncx.WriteString("</ncx>\n")
+
+	return ncx.String()
+}
+
+func (book Book) getCoverDocument() string {
+	return book.getXHTMLDocument(book.Title, fmt.Sprintf(`<img src="%s" alt="%s"/>`, escape(book.Cover), escape(book.Title)))
+}
+
+func (book Book) getChapterDocument(chapter Chapter) (string, error) {
+	body, err := toXHTML(chapter.Content)
+	if err != nil {
+		return "", err
+	}
+
+	return book.getXHTMLDocument(chapter.Title, body), nil
+}
+
+// getXHTMLDocument returns an XHTML document with the given title and body.
+func (book Book) getXHTMLDocument(title, body string) string {
+	language := escape(book.getLanguage())
+
+	return `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		"<!DOCTYPE html>\n" +
+		`<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="` + language + `" xml:lang="` + language + `">` + "\n" +
+		"<head>\n" +
+		`<meta charset="utf-8"/>` + "\n" +
+		"<title>" + escape(title) + "</title>\n" +
+		"</head>\n" +
+		"<body>\n" +
+		body + "\n" +
+		"</body>\n" +
+		"</html>\n"
+}
+
+func (book Book) getLanguage() string {
+	if book.Language == "" {
+		return "en"
+	}
+
+	return book.Language
+}
+
+// getCoverIndex returns the index of the cover image or -1 if the cover is not one of the images.
+func (book Book) getCoverIndex() int {
+	for index, image := range book.Images {
+		if image.Path == book.Cover {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func getImageID(index int) string {
+	return fmt.Sprintf("image-%d", index+1)
+}
+
+// toXHTML converts the supplied HTML fragment into well-formed XHTML
+// (e.g. "<br>" -> "<br/>", "&nbsp;" -> " ").
+func toXHTML(htmlCode string) (string, error) {
+	context := &htmlparser.Node{Type: htmlparser.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := htmlparser.ParseFragment(strings.NewReader(htmlCode), context)
+	if err != nil {
+		return "", err
+	}
+
+	var xhtml bytes.Buffer
+	for _, node := range nodes {
+		if err := htmlparser.Render(&xhtml, node); err != nil {
+			return "", err
+		}
+	}
+
+	return xhtml.String(), nil
+}
+
+func escape(text string) string {
+	return html.EscapeString(text)
+}
+
+// addStoredFile adds an uncompressed file without a data descriptor to the supplied archive.
+func addStoredFile(archive *zip.Writer, name string, data []byte) error {
+	entry, err := archive.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(data),
+		CompressedSize64:   uint64(len(data)),
+		UncompressedSize64: uint64(len(data)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = entry.Write(data)
+	return err
+}
+
+func addFile(archive *zip.Writer, name string, data []byte) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = entry.Write(data)
+	return err
+}
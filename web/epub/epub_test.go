@@ -0,0 +1,205 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// packageDocument contains the parts of the content.opf file which are checked by the tests.
+type packageDocument struct {
+	Manifest []struct {
+		ID         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+
+	Spine []struct {
+		IDRef  string `xml:"idref,attr"`
+		Linear string `xml:"linear,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// navigationDocument contains the entries of the table of contents in the nav.xhtml file.
+type navigationDocument struct {
+	Entries []struct {
+		Href  string `xml:"href,attr"`
+		Title string `xml:",chardata"`
+	} `xml:"body>nav>ol>li>a"`
+}
+
+func getTestBook() Book {
+	return Book{
+		Identifier: "urn:allmark:test",
+		Title:      "Cooking & Baking",
+		Author:     "Andreas Koch",
+		Language:   "en",
+		Modified:   time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC),
+		Cover:      "images/image-1.jpg",
+		Chapters: []Chapter{
+			{Title: "Introduction", Content: "<h1>Introduction</h1><p>Some&nbsp;text<br>with a line break.</p>"},
+			{Title: "Pancakes", Content: `<h1>Pancakes</h1><p><img src="images/image-2.png" alt="Pancakes"></p>`},
+			{Title: "Waffles <Deluxe>", Content: `<h1>Waffles</h1><ul><li>Flour<li>Eggs</ul><input type="checkbox" checked>`},
+		},
+		Images: []Image{
+			{Path: "images/image-1.jpg", MediaType: "image/jpeg", Data: []byte("cover")},
+			{Path: "images/image-2.png", MediaType: "image/png", Data: []byte("pancakes")},
+		},
+	}
+}
+
+// readEPUB writes the supplied book and returns the content of all files of the archive by their name.
+func readEPUB(t *testing.T, book Book) ([]*zip.File, map[string][]byte) {
+	var epubFile bytes.Buffer
+	if err := book.Write(&epubFile); err != nil {
+		t.Fatalf("Write should not return an error but returned %s.", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(epubFile.Bytes()), int64(epubFile.Len()))
+	if err != nil {
+		t.Fatalf("The EPUB file cannot be opened. Error: %s", err)
+	}
+
+	files := make(map[string][]byte)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("The file %q cannot be opened. Error: %s", file.Name, err)
+		}
+
+		content, _ := ioutil.ReadAll(reader)
+		reader.Close()
+		files[file.Name] = content
+	}
+
+	return archive.File, files
+}
+
+func Test_Write_Book_MimetypeIsTheFirstUncompressedFile(t *testing.T) {
+	// act
+	entries, files := readEPUB(t, getTestBook())
+
+	// assert
+	if entries[0].Name != "mimetype" || entries[0].Method != zip.Store {
+		t.Fatalf("The first file should be the uncompressed mimetype but was %q (method %d).", entries[0].Name, entries[0].Method)
+	}
+
+	if mimeType := string(files["mimetype"]); mimeType != MimeType {
+		t.Errorf("The mimetype should be %q but was %q.", MimeType, mimeType)
+	}
+
+	if !strings.Contains(string(files["META-INF/container.xml"]), `full-path="OEBPS/content.opf"`) {
+		t.Errorf("The container should reference the package document: %s", files["META-INF/container.xml"])
+	}
+}
+
+func Test_Write_ThreeChapters_SpineAndTableOfContentsContainAllChapters(t *testing.T) {
+	// arrange
+	book := getTestBook()
+
+	// act
+	_, files := readEPUB(t, book)
+
+	// assert
+	var opf packageDocument
+	if err := xml.Unmarshal(files["OEBPS/content.opf"], &opf); err != nil {
+		t.Fatalf("The package document cannot be parsed. Error: %s", err)
+	}
+
+	var chapterReferences []string
+	for _, itemReference := range opf.Spine {
+		if itemReference.Linear != "no" {
+			chapterReferences = append(chapterReferences, itemReference.IDRef)
+		}
+	}
+
+	if len(chapterReferences) != 3 {
+		t.Errorf("The spine should contain %d chapters but contained %d: %v", 3, len(chapterReferences), chapterReferences)
+	}
+
+	var navigation navigationDocument
+	if err := xml.Unmarshal(files["OEBPS/nav.xhtml"], &navigation); err != nil {
+		t.Fatalf("The navigation document cannot be parsed. Error: %s", err)
+	}
+
+	if len(navigation.Entries) != len(book.Chapters) {
+		t.Fatalf("The table of contents should contain %d entries but contained %d.", len(book.Chapters), len(navigation.Entries))
+	}
+
+	for index, entry := range navigation.Entries {
+		if entry.Title != book.Chapters[index].Title {
+			t.Errorf("The title of entry %d should be %q but was %q.", index+1, book.Chapters[index].Title, entry.Title)
+		}
+
+		if _, exists := files["OEBPS/"+entry.Href]; !exists {
+			t.Errorf("The chapter %q of entry %d does not exist.", entry.Href, index+1)
+		}
+	}
+
+	if ncx := string(files["OEBPS/toc.ncx"]); strings.Count(ncx, "<navPoint ") != 3 {
+		t.Errorf("The NCX should contain %d navigation points: %s", 3, ncx)
+	}
+}
+
+func Test_Write_HTMLChapters_AllContentDocumentsAreWellFormedXML(t *testing.T) {
+	// act
+	_, files := readEPUB(t, getTestBook())
+
+	// assert
+	for name, content := range files {
+		if !strings.HasSuffix(name, ".xhtml") && !strings.HasSuffix(name, ".opf") && !strings.HasSuffix(name, ".ncx") {
+			continue
+		}
+
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				t.Errorf("%q is not well-formed. Error: %s\n%s", name, err, content)
+				break
+			}
+		}
+	}
+}
+
+func Test_Write_CoverAndImages_AreEmbedded(t *testing.T) {
+	// act
+	_, files := readEPUB(t, getTestBook())
+
+	// assert
+	for path, content := range map[string]string{"OEBPS/images/image-1.jpg": "cover", "OEBPS/images/image-2.png": "pancakes"} {
+		if string(files[path]) != content {
+			t.Errorf("The archive should contain the image %q.", path)
+		}
+	}
+
+	var opf packageDocument
+	xml.Unmarshal(files["OEBPS/content.opf"], &opf)
+
+	coverImages := 0
+	for _, item := range opf.Manifest {
+		if item.Properties == "cover-image" {
+			coverImages++
+			if item.Href != "images/image-1.jpg" {
+				t.Errorf("The cover image should be %q but was %q.", "images/image-1.jpg", item.Href)
+			}
+		}
+	}
+
+	if coverImages != 1 {
+		t.Errorf("The manifest should contain exactly one cover image but contained %d.", coverImages)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/header"
+)
+
+const (
+	// defaultItemsAPIDepth is the default number of child levels returned by the items API.
+	defaultItemsAPIDepth = 1
+
+	// itemBlocksAPIExcerptLength is the maximum length of the excerpts returned by the item-blocks API.
+	itemBlocksAPIExcerptLength = 200
+)
+
+// An ItemProvider returns the items of the repository and their children.
+type ItemProvider interface {
+	GetItem(itemRoute route.Route) (*model.Item, bool)
+	GetChildren(parent *model.Item) []*model.Item
+}
+
+// An ItemExcerptProvider returns the items of the repository, their children and their excerpts.
+type ItemExcerptProvider interface {
+	ItemProvider
+	GetExcerpt(item *model.Item, maxChars int) string
+}
+
+// apiError is the JSON body of failed API requests.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// ItemsAPI returns a http handler which writes the item addressed by the path after the ItemsAPIRoutePrefix
+// (e.g. "/api/items/guides/install") and its children as JSON. The "depth" url-parameter defines the number of
+// child levels (default: 1 = direct children only, negative numbers: all descendants).
+func ItemsAPI(headerWriter header.HeaderWriter, itemProvider ItemProvider) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// set headers
+		headerWriter.Write(w, header.CONTENTTYPE_JSON)
+
+		// read the depth url-parameter
+		depth := defaultItemsAPIDepth
+		if depthParam := r.URL.Query().Get("depth"); depthParam != "" {
+			parsedDepth, err := strconv.Atoi(depthParam)
+			if err != nil {
+				writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("%q is not a valid depth.", depthParam))
+				return
+			}
+
+			depth = parsedDepth
+		}
+
+		// get the item
+		itemRoute := route.NewFromRequest(strings.TrimPrefix(r.URL.Path, ItemsAPIRoutePrefix))
+		item, found := itemProvider.GetItem(itemRoute)
+		if !found {
+			writeAPIError(w, http.StatusNotFound, fmt.Sprintf("No item found for %q.", r.URL.Path))
+			return
+		}
+
+		// convert to json
+		bytes, err := item.MarshalJSONWithChildren(itemProvider.GetChildren, depth)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Write(bytes)
+	})
+
+}
+
+// ItemBlocksAPI returns a http handler which writes the meta data of the item addressed by the path after the
+// ItemBlocksAPIRoutePrefix (e.g. "/api/blocks/guides/install") as JSON: its type, title, excerpt and hash,
+// all blocks of the meta data section and the links to its children.
+func ItemBlocksAPI(headerWriter header.HeaderWriter, itemProvider ItemExcerptProvider) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// set headers
+		headerWriter.Write(w, header.CONTENTTYPE_JSON)
+
+		// get the item
+		itemRoute := route.NewFromRequest(strings.TrimPrefix(r.URL.Path, ItemBlocksAPIRoutePrefix))
+		item, found := itemProvider.GetItem(itemRoute)
+		if !found {
+			writeAPIError(w, http.StatusNotFound, fmt.Sprintf("No item found for %q.", r.URL.Path))
+			return
+		}
+
+		// convert to json
+		bytes, err := item.MarshalBlocksJSON(itemProvider.GetChildren(item), itemProvider.GetExcerpt(item, itemBlocksAPIExcerptLength))
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Write(bytes)
+	})
+
+}
+
+// writeAPIError writes the supplied status code and a JSON body with the error message.
+func writeAPIError(w http.ResponseWriter, statusCode int, message string) {
+	bytes, _ := json.Marshal(apiError{message})
+
+	w.WriteHeader(statusCode)
+	w.Write(bytes)
+}
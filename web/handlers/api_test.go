@@ -0,0 +1,284 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/gorilla/mux"
+)
+
+// A testItemProvider provides the items of a small repository (root -> guides -> install).
+type testItemProvider struct {
+	items    map[string]*model.Item
+	children map[string][]*model.Item
+}
+
+func newTestItemProvider() testItemProvider {
+	provider := testItemProvider{
+		items:    make(map[string]*model.Item),
+		children: make(map[string][]*model.Item),
+	}
+
+	for _, itemRoute := range []string{"", "guides", "guides/install"} {
+		item := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+		item.Title = "Title of /" + itemRoute
+		provider.items[itemRoute] = item
+
+		if parentRoute, exists := item.Route().Parent(); exists && itemRoute != "" {
+			provider.children[parentRoute.Value()] = append(provider.children[parentRoute.Value()], item)
+		}
+	}
+
+	return provider
+}
+
+func (provider testItemProvider) GetItem(itemRoute route.Route) (*model.Item, bool) {
+	item, exists := provider.items[itemRoute.Value()]
+	return item, exists
+}
+
+func (provider testItemProvider) GetChildren(parent *model.Item) []*model.Item {
+	return provider.children[parent.Route().Value()]
+}
+
+func (provider testItemProvider) GetExcerpt(item *model.Item, maxChars int) string {
+	return item.Excerpt("<p>Content of /"+item.Route().Value()+"</p>", maxChars)
+}
+
+// testItemJSON is the JSON representation of an item returned by the items API.
+type testItemJSON struct {
+	Title    string         `json:"title"`
+	URL      string         `json:"url"`
+	Children []testItemJSON `json:"children"`
+}
+
+func getItemsAPIResponse(path string) *httptest.ResponseRecorder {
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	handler := ItemsAPI(headerWriterFactory.NoCache(), newTestItemProvider())
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	handler.ServeHTTP(response, request)
+
+	return response
+}
+
+func Test_ItemsAPI_Root_RootAndDirectChildrenAreReturned(t *testing.T) {
+	// act
+	response := getItemsAPIResponse("/api/items")
+
+	// assert
+	if response.Code != http.StatusOK {
+		t.Fatalf("The status code should be %d but was %d.", http.StatusOK, response.Code)
+	}
+
+	var item testItemJSON
+	if err := json.Unmarshal(response.Body.Bytes(), &item); err != nil {
+		t.Fatalf("The response is not valid JSON. Error: %s", err)
+	}
+
+	if item.URL != "/" || len(item.Children) != 1 || item.Children[0].URL != "/guides" {
+		t.Errorf("The response should contain the root and its direct children but was %s.", response.Body.String())
+	}
+
+	if len(item.Children[0].Children) != 0 {
+		t.Errorf("The response should not contain grandchildren but was %s.", response.Body.String())
+	}
+}
+
+func Test_ItemsAPI_NestedPathWithDepth_SubtreeIsReturned(t *testing.T) {
+	// act
+	nested := getItemsAPIResponse("/api/items/guides/install")
+	subtree := getItemsAPIResponse("/api/items/?depth=-1")
+
+	// assert
+	var nestedItem testItemJSON
+	json.Unmarshal(nested.Body.Bytes(), &nestedItem)
+	if nested.Code != http.StatusOK || nestedItem.Title != "Title of /guides/install" || len(nestedItem.Children) != 0 {
+		t.Errorf("The response for the nested item is invalid (%d): %s", nested.Code, nested.Body.String())
+	}
+
+	var subtreeItem testItemJSON
+	json.Unmarshal(subtree.Body.Bytes(), &subtreeItem)
+	if len(subtreeItem.Children) != 1 || len(subtreeItem.Children[0].Children) != 1 || subtreeItem.Children[0].Children[0].URL != "/guides/install" {
+		t.Errorf("The response with unlimited depth should contain all descendants but was %s.", subtree.Body.String())
+	}
+}
+
+func Test_ItemsAPI_MissingPath_NotFoundWithJSONError(t *testing.T) {
+	// act
+	response := getItemsAPIResponse("/api/items/does/not/exist")
+
+	// assert
+	if response.Code != http.StatusNotFound {
+		t.Errorf("The status code should be %d but was %d.", http.StatusNotFound, response.Code)
+	}
+
+	var body apiError
+	if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil || body.Error == "" {
+		t.Errorf("The response should contain a JSON error but was %q.", response.Body.String())
+	}
+
+	if contentType := response.Header().Get("Content-Type"); contentType != header.CONTENTTYPE_JSON {
+		t.Errorf("The content type should be %q but was %q.", header.CONTENTTYPE_JSON, contentType)
+	}
+}
+
+// testItemBlocksJSON is the JSON representation of an item returned by the item-blocks API.
+type testItemBlocksJSON struct {
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Excerpt string `json:"excerpt"`
+	URL     string `json:"url"`
+	Hash    string `json:"hash"`
+	Blocks  []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"blocks"`
+	Children []struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	} `json:"children"`
+}
+
+func getItemBlocksAPIResponse(t *testing.T, path string) (*httptest.ResponseRecorder, testItemBlocksJSON) {
+	itemProvider := newTestItemProvider()
+
+	guides := itemProvider.items["guides"]
+	guides.Hash = "guides-hash"
+	guides.MetaData.AddBlock("title", "All Guides")
+	guides.MetaData.AddBlock("summary", "Guides for all occasions")
+	guides.MetaData.AddBlock("author", "Andreas Koch")
+	guides.MetaData.AddBlock("tags", "guides, howto")
+
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	handler := ItemBlocksAPI(headerWriterFactory.NoCache(), itemProvider)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	handler.ServeHTTP(response, request)
+
+	var item testItemBlocksJSON
+	if response.Code == http.StatusOK {
+		if err := json.Unmarshal(response.Body.Bytes(), &item); err != nil {
+			t.Fatalf("The response is not valid JSON: %s (%s)", err, response.Body.String())
+		}
+	}
+
+	return response, item
+}
+
+func Test_ItemBlocksAPI_ItemWithBlocks_BlocksAndChildrenAreReturned(t *testing.T) {
+	// act
+	response, item := getItemBlocksAPIResponse(t, "/api/blocks/guides")
+
+	// assert
+	if response.Code != http.StatusOK {
+		t.Fatalf("The status code should be %d but was %d.", http.StatusOK, response.Code)
+	}
+
+	if contentType := response.Header().Get("Content-Type"); contentType != header.CONTENTTYPE_JSON {
+		t.Errorf("The content type should be %q but was %q.", header.CONTENTTYPE_JSON, contentType)
+	}
+
+	if item.Type != "document" || item.Title != "All Guides" || item.URL != "/guides" || item.Hash != "guides-hash" {
+		t.Errorf("The type, title, URL or hash of the item are invalid: %s", response.Body.String())
+	}
+
+	if item.Excerpt != "Guides for all occasions" {
+		t.Errorf("The excerpt should be the summary %q but was %q.", "Guides for all occasions", item.Excerpt)
+	}
+
+	expectedBlocks := []string{"title=All Guides", "summary=Guides for all occasions", "author=Andreas Koch", "tags=guides, howto"}
+	if len(item.Blocks) != len(expectedBlocks) {
+		t.Fatalf("The item should have %d blocks but had %d: %s", len(expectedBlocks), len(item.Blocks), response.Body.String())
+	}
+
+	for index, block := range item.Blocks {
+		if nameAndValue := block.Name + "=" + block.Value; nameAndValue != expectedBlocks[index] {
+			t.Errorf("Block %d should be %q but was %q.", index, expectedBlocks[index], nameAndValue)
+		}
+	}
+
+	if len(item.Children) != 1 || item.Children[0].Title != "Install" || item.Children[0].URL != "/guides/install" {
+		t.Errorf("The item should link to its child %q: %s", "/guides/install", response.Body.String())
+	}
+}
+
+func Test_ItemBlocksAPI_ItemWithoutBlocks_EmptyListsAreReturned(t *testing.T) {
+	// act
+	response, item := getItemBlocksAPIResponse(t, "/api/blocks/guides/install")
+
+	// assert
+	if response.Code != http.StatusOK {
+		t.Fatalf("The status code should be %d but was %d.", http.StatusOK, response.Code)
+	}
+
+	// the title is derived from the folder name
+	if item.Title != "Install" {
+		t.Errorf("The title should be %q but was %q.", "Install", item.Title)
+	}
+
+	body := response.Body.String()
+	if !strings.Contains(body, `"blocks":[]`) || !strings.Contains(body, `"children":[]`) {
+		t.Errorf("The blocks and children should be empty lists: %s", body)
+	}
+}
+
+func Test_ItemBlocksAPI_MissingPath_NotFoundWithJSONError(t *testing.T) {
+	// act
+	response, _ := getItemBlocksAPIResponse(t, "/api/blocks/does/not/exist")
+
+	// assert
+	if response.Code != http.StatusNotFound {
+		t.Errorf("The status code should be %d but was %d.", http.StatusNotFound, response.Code)
+	}
+
+	var body apiError
+	if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil || body.Error == "" {
+		t.Errorf("The response should contain a JSON error but was %q.", response.Body.String())
+	}
+}
+
+func Test_APIHandlerRoutes_OnlyThePrefixAndItsSubPathsAreMatched(t *testing.T) {
+	// arrange
+	router := mux.NewRouter()
+	router.Handle(ItemsAPIHandlerRoute, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("items")) }))
+	router.Handle(ItemBlocksAPIHandlerRoute, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("blocks")) }))
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("fallback")) })
+
+	requests := map[string]string{
+		"/api/items":                 "items",
+		"/api/items/":                "items",
+		"/api/items/guides/install":  "items",
+		"/api/itemsfoo":              "fallback",
+		"/api/blocks":                "blocks",
+		"/api/blocks/guides/install": "blocks",
+		"/api/blocksfoo":             "fallback",
+		"/api/blocksfoo/bar":         "fallback",
+	}
+
+	for path, expected := range requests {
+
+		// act
+		response := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(response, request)
+
+		// assert
+		if body := response.Body.String(); body != expected {
+			t.Errorf("The request for %q should have been handled by the %s handler but was handled by the %s handler.", path, expected, body)
+		}
+	}
+}
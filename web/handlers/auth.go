@@ -1,24 +1,84 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
 package handlers
 
 import (
 	"github.com/andreaskoch/allmark/common/logger"
 	"github.com/abbot/go-http-auth"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"net/http"
+	"strings"
 )
 
-// RequireDigestAuthentication forces digest access authentication for the given handler.
-func RequireDigestAuthentication(logger logger.Logger, baseHandler http.Handler, secretProvider auth.SecretProvider) http.Handler {
+// A CredentialsChecker returns true if the supplied username and password are valid.
+type CredentialsChecker func(username, password string) bool
+
+// StaticCredentials returns a CredentialsChecker that accepts only the given username and password.
+// The credentials are compared in constant time.
+func StaticCredentials(username, password string) CredentialsChecker {
+	expectedUsername := sha256.Sum256([]byte(username))
+	expectedPassword := sha256.Sum256([]byte(password))
+
+	return func(username, password string) bool {
+		actualUsername := sha256.Sum256([]byte(username))
+		actualPassword := sha256.Sum256([]byte(password))
+
+		usernameMatches := subtle.ConstantTimeCompare(actualUsername[:], expectedUsername[:])
+		passwordMatches := subtle.ConstantTimeCompare(actualPassword[:], expectedPassword[:])
+		return usernameMatches&passwordMatches == 1
+	}
+}
+
+// HtpasswdCredentials returns a CredentialsChecker that validates the credentials against the
+// SHA1 ("{SHA}...") or MD5 ("$apr1$...") password entries of the supplied htpasswd secret provider.
+// The password hashes are compared in constant time.
+func HtpasswdCredentials(secretProvider auth.SecretProvider) CredentialsChecker {
+	return func(username, password string) bool {
+		secret := secretProvider(username, "")
+		if secret == "" {
+			return false
+		}
+
+		var passwordHash string
+		if strings.HasPrefix(secret, "{SHA}") {
+			digest := sha1.Sum([]byte(password))
+			passwordHash = "{SHA}" + base64.StdEncoding.EncodeToString(digest[:])
+		} else {
+			entry := auth.NewMD5Entry(secret)
+			if entry == nil {
+				return false
+			}
+
+			passwordHash = string(auth.MD5Crypt([]byte(password), entry.Salt, entry.Magic))
+		}
+
+		return subtle.ConstantTimeCompare([]byte(passwordHash), []byte(secret)) == 1
+	}
+}
+
+// RequireBasicAuthentication forces basic access authentication for the given handler.
+// Requests without valid credentials are answered with "401 Unauthorized" and a WWW-Authenticate challenge.
+func RequireBasicAuthentication(logger logger.Logger, baseHandler http.Handler, realm string, credentialsAreValid CredentialsChecker) http.Handler {
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		authenticator := auth.NewBasicAuthenticator("", secretProvider)
+		username, password, hasCredentials := r.BasicAuth()
+		if hasCredentials && credentialsAreValid(username, password) {
+			baseHandler.ServeHTTP(w, r)
+			return
+		}
 
-		baseHandlerWithAuthentication := func(w http.ResponseWriter, r *auth.AuthenticatedRequest) {
-			baseHandler.ServeHTTP(w, &r.Request)
+		if hasCredentials {
+			logger.Warn("Invalid credentials for user %q (%s).", username, r.RemoteAddr)
 		}
 
-		authHandler := authenticator.Wrap(baseHandlerWithAuthentication)
-		authHandler.ServeHTTP(w, r)
+		w.Header().Set("WWW-Authenticate", `Basic realm="`+strings.Replace(realm, `"`, "", -1)+`"`)
+		http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
 	})
 
 }
@@ -0,0 +1,114 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+)
+
+func getAuthenticatedResponse(credentialsChecker CredentialsChecker, username, password string, sendCredentials bool) *httptest.ResponseRecorder {
+	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "protected")
+	})
+
+	handler := RequireBasicAuthentication(console.New(loglevel.Fatal), baseHandler, "staging", credentialsChecker)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/", nil)
+	if sendCredentials {
+		request.SetBasicAuth(username, password)
+	}
+
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func Test_RequireBasicAuthentication_MissingCredentials_Unauthorized(t *testing.T) {
+	// act
+	response := getAuthenticatedResponse(StaticCredentials("editor", "secret"), "", "", false)
+
+	// assert
+	if response.Code != http.StatusUnauthorized {
+		t.Errorf("The status code should be %d but was %d.", http.StatusUnauthorized, response.Code)
+	}
+
+	if challenge := response.Header().Get("WWW-Authenticate"); challenge != `Basic realm="staging"` {
+		t.Errorf("The WWW-Authenticate header should be %q but was %q.", `Basic realm="staging"`, challenge)
+	}
+}
+
+func Test_RequireBasicAuthentication_WrongCredentials_Unauthorized(t *testing.T) {
+	// arrange
+	inputs := []struct {
+		username string
+		password string
+	}{
+		{"editor", "wrong"},
+		{"admin", "secret"},
+		{"editor", ""},
+		{"", ""},
+	}
+
+	for _, input := range inputs {
+
+		// act
+		response := getAuthenticatedResponse(StaticCredentials("editor", "secret"), input.username, input.password, true)
+
+		// assert
+		if response.Code != http.StatusUnauthorized {
+			t.Errorf("The status code for %q/%q should be %d but was %d.", input.username, input.password, http.StatusUnauthorized, response.Code)
+		}
+
+		if response.Body.String() == "protected" {
+			t.Errorf("The protected content must not be served for %q/%q.", input.username, input.password)
+		}
+	}
+}
+
+func Test_RequireBasicAuthentication_CorrectCredentials_ContentIsServed(t *testing.T) {
+	// act
+	response := getAuthenticatedResponse(StaticCredentials("editor", "secret"), "editor", "secret", true)
+
+	// assert
+	if response.Code != http.StatusOK {
+		t.Errorf("The status code should be %d but was %d.", http.StatusOK, response.Code)
+	}
+
+	if body := response.Body.String(); body != "protected" {
+		t.Errorf("The body should be %q but was %q.", "protected", body)
+	}
+}
+
+func Test_HtpasswdCredentials_SHAEntry(t *testing.T) {
+	// arrange
+	secretProvider := func(user, realm string) string {
+		if user == "editor" {
+			return "{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=" // "secret"
+		}
+
+		return ""
+	}
+
+	credentialsAreValid := HtpasswdCredentials(secretProvider)
+
+	// act & assert
+	if !credentialsAreValid("editor", "secret") {
+		t.Errorf("The credentials %q/%q should be valid.", "editor", "secret")
+	}
+
+	if credentialsAreValid("editor", "wrong") {
+		t.Errorf("The credentials %q/%q should be invalid.", "editor", "wrong")
+	}
+
+	if credentialsAreValid("admin", "secret") {
+		t.Errorf("The credentials %q/%q should be invalid.", "admin", "secret")
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/orchestrator"
+	"github.com/andreaskoch/allmark/web/view/templates"
+)
+
+// Combined creates a http handler which displays all documents of the repository on a single page
+// (e.g. for printing the whole documentation).
+func Combined(logger logger.Logger,
+	headerWriter header.HeaderWriter,
+	combinedOrchestrator *orchestrator.CombinedOrchestrator,
+	templateProvider templates.Provider,
+	error404Handler http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		baseURL := getBaseURLFromRequest(r)
+		viewModel, found := combinedOrchestrator.GetCombinedConversionModel(baseURL)
+		if !found {
+			error404Handler.ServeHTTP(w, r)
+			return
+		}
+
+		template, err := templateProvider.GetCombinedConversionTemplate(baseURL)
+		if err != nil {
+			logger.Error("No template for the combined view. Error: %s", err)
+			http.Error(w, "Template not found", http.StatusInternalServerError)
+			return
+		}
+
+		headerWriter.Write(w, header.CONTENTTYPE_HTML)
+		if err := renderTemplate(template, viewModel, w); err != nil {
+			logger.Error("%s", err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
+)
+
+const (
+	// commentHoneypotField is a form field which is hidden from human readers. Spam bots which fill it in are rejected.
+	commentHoneypotField = "website"
+
+	maxCommentAuthorLength = 100
+	maxCommentBodyLength   = 10000
+)
+
+// A CommentWriter stores comments for the items of the repository.
+type CommentWriter interface {
+	ItemExists(itemRoute route.Route) bool
+	AddComment(itemRoute route.Route, author, body string) (route.Route, error)
+}
+
+// Comment returns a http handler which accepts comments (form fields "author" and "body") for the item
+// addressed by the request path (e.g. "POST /documents/sample.comment") and redirects back to the item.
+// If comments are disabled the handler answers all requests with "404 Not Found". Every client can only
+// submit one comment per minimum interval.
+func Comment(logger logger.Logger, enabled bool, minimumInterval time.Duration, commentWriter CommentWriter) http.Handler {
+
+	rateLimiter := newCommentRateLimiter(minimumInterval)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if !enabled {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Method != "POST" {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, "Comments must be submitted via POST.", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// strip the "comment" or ".comment" suffix from the path
+		path := r.URL.Path
+		path = strings.TrimSuffix(path, "comment")
+		path = strings.TrimSuffix(path, ".")
+
+		// get the commented item
+		itemRoute := route.NewFromRequest(path)
+		if !commentWriter.ItemExists(itemRoute) {
+			http.NotFound(w, r)
+			return
+		}
+
+		// validate the comment
+		author := strings.TrimSpace(r.FormValue("author"))
+		body := strings.TrimSpace(r.FormValue("body"))
+
+		if r.FormValue(commentHoneypotField) != "" {
+			logger.Warn("Rejected a comment for %q from %s (honeypot).", itemRoute, r.RemoteAddr)
+			http.Error(w, "The comment has been rejected.", http.StatusBadRequest)
+			return
+		}
+
+		if author == "" || utf8.RuneCountInString(author) > maxCommentAuthorLength {
+			http.Error(w, "Please enter your name (up to 100 characters).", http.StatusBadRequest)
+			return
+		}
+
+		if body == "" || utf8.RuneCountInString(body) > maxCommentBodyLength {
+			http.Error(w, "Please enter a comment (up to 10000 characters).", http.StatusBadRequest)
+			return
+		}
+
+		if !rateLimiter.Allow(getClientAddress(r), time.Now()) {
+			http.Error(w, "Please wait a moment before submitting another comment.", http.StatusTooManyRequests)
+			return
+		}
+
+		// store the comment
+		if _, err := commentWriter.AddComment(itemRoute, author, body); err != nil {
+			logger.Error("Cannot add the comment for %q. Error: %s", itemRoute, err.Error())
+			http.Error(w, "The comment could not be saved.", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Added a comment for %q from %s.", itemRoute, r.RemoteAddr)
+		http.Redirect(w, r, "/"+itemRoute.Value(), http.StatusSeeOther)
+	})
+}
+
+// getClientAddress returns the IP address of the client without the port.
+func getClientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func newCommentRateLimiter(minimumInterval time.Duration) *commentRateLimiter {
+	return &commentRateLimiter{
+		minimumInterval: minimumInterval,
+		lastComments:    make(map[string]time.Time),
+	}
+}
+
+// A commentRateLimiter allows one comment per client and interval.
+type commentRateLimiter struct {
+	minimumInterval time.Duration
+
+	lock         sync.Mutex
+	lastComments map[string]time.Time
+}
+
+// Allow returns true and registers the comment if the client has not commented within the minimum interval.
+func (limiter *commentRateLimiter) Allow(client string, now time.Time) bool {
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+
+	// forget clients whose interval has passed
+	for address, lastComment := range limiter.lastComments {
+		if now.Sub(lastComment) >= limiter.minimumInterval {
+			delete(limiter.lastComments, address)
+		}
+	}
+
+	if _, exists := limiter.lastComments[client]; exists {
+		return false
+	}
+
+	limiter.lastComments[client] = now
+	return true
+}
@@ -0,0 +1,135 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+)
+
+// A testCommentWriter records all comments for the item "documents/sample".
+type testCommentWriter struct {
+	comments []string
+}
+
+func (writer *testCommentWriter) ItemExists(itemRoute route.Route) bool {
+	return itemRoute.Value() == "documents/sample"
+}
+
+func (writer *testCommentWriter) AddComment(itemRoute route.Route, author, body string) (route.Route, error) {
+	writer.comments = append(writer.comments, author+": "+body)
+	return route.NewFromRequest(itemRoute.Value() + "/comment-1"), nil
+}
+
+func postTestComment(handler http.Handler, path string, form url.Values) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.RemoteAddr = "192.0.2.1:1234"
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func Test_Comment_ValidSubmission_CommentIsAddedAndClientIsRedirected(t *testing.T) {
+	// arrange
+	writer := &testCommentWriter{}
+	handler := Comment(console.New(loglevel.Fatal), true, time.Minute, writer)
+
+	// act
+	response := postTestComment(handler, "/documents/sample.comment", url.Values{"author": {"John Doe"}, "body": {"Nice article!"}})
+
+	// assert
+	if response.Code != http.StatusSeeOther {
+		t.Fatalf("The status code should be %d but was %d.", http.StatusSeeOther, response.Code)
+	}
+
+	if location := response.Header().Get("Location"); location != "/documents/sample" {
+		t.Errorf("The client should be redirected to %q but was redirected to %q.", "/documents/sample", location)
+	}
+
+	if len(writer.comments) != 1 || writer.comments[0] != "John Doe: Nice article!" {
+		t.Errorf("The comment should have been added but the comments were %v.", writer.comments)
+	}
+}
+
+func Test_Comment_EmptyBody_CommentIsRejected(t *testing.T) {
+	// arrange
+	writer := &testCommentWriter{}
+	handler := Comment(console.New(loglevel.Fatal), true, time.Minute, writer)
+
+	// act
+	response := postTestComment(handler, "/documents/sample.comment", url.Values{"author": {"John Doe"}, "body": {"   "}})
+
+	// assert
+	if response.Code != http.StatusBadRequest {
+		t.Errorf("The status code should be %d but was %d.", http.StatusBadRequest, response.Code)
+	}
+
+	if len(writer.comments) != 0 {
+		t.Errorf("No comment should have been added but the comments were %v.", writer.comments)
+	}
+}
+
+func Test_Comment_Honeypot_CommentIsRejected(t *testing.T) {
+	// arrange
+	writer := &testCommentWriter{}
+	handler := Comment(console.New(loglevel.Fatal), true, time.Minute, writer)
+
+	// act
+	response := postTestComment(handler, "/documents/sample.comment", url.Values{"author": {"Bot"}, "body": {"Spam"}, "website": {"http://spam.example.com"}})
+
+	// assert
+	if response.Code != http.StatusBadRequest || len(writer.comments) != 0 {
+		t.Errorf("Comments with a filled in honeypot field should be rejected (status: %d, comments: %v).", response.Code, writer.comments)
+	}
+}
+
+func Test_Comment_SecondCommentWithinTheInterval_IsRejected(t *testing.T) {
+	// arrange
+	writer := &testCommentWriter{}
+	handler := Comment(console.New(loglevel.Fatal), true, time.Minute, writer)
+	postTestComment(handler, "/documents/sample.comment", url.Values{"author": {"John Doe"}, "body": {"First"}})
+
+	// act
+	response := postTestComment(handler, "/documents/sample.comment", url.Values{"author": {"John Doe"}, "body": {"Second"}})
+
+	// assert
+	if response.Code != http.StatusTooManyRequests {
+		t.Errorf("The status code should be %d but was %d.", http.StatusTooManyRequests, response.Code)
+	}
+
+	if len(writer.comments) != 1 {
+		t.Errorf("Only the first comment should have been added but the comments were %v.", writer.comments)
+	}
+}
+
+func Test_Comment_DefaultConfiguration_CommentsAreDisabled(t *testing.T) {
+	// arrange
+	configuration := config.Default("")
+	writer := &testCommentWriter{}
+	handler := Comment(console.New(loglevel.Fatal), configuration.Comments.Enabled, configuration.CommentsMinimumInterval(), writer)
+
+	// act
+	response := postTestComment(handler, "/documents/sample.comment", url.Values{"author": {"John Doe"}, "body": {"Nice article!"}})
+
+	// assert
+	if response.Code != http.StatusNotFound {
+		t.Errorf("The status code should be %d but was %d.", http.StatusNotFound, response.Code)
+	}
+
+	if len(writer.comments) != 0 {
+		t.Errorf("No comment should have been added but the comments were %v.", writer.comments)
+	}
+}
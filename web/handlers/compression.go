@@ -1,10 +1,284 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
 package handlers
 
 import (
-	gorillahandlers "github.com/gorilla/handlers"
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
 )
 
-func CompressResponses(baseHandler http.Handler) http.Handler {
-	return gorillahandlers.CompressHandler(baseHandler)
+// compressedContentTypePrefixes contains the prefixes of content types which are already compressed.
+var compressedContentTypePrefixes = []string{
+	"image/",
+	"audio/",
+	"video/",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-rar-compressed",
+	"application/x-7z-compressed",
+	"application/pdf",
+	"application/vnd.openxmlformats-officedocument",
+}
+
+// CompressResponses compresses the responses of the supplied handler with gzip (or deflate as a fallback)
+// if the client accepts it (see the Accept-Encoding header of the request).
+// Responses smaller than the minimum size (in bytes), responses without a body
+// and responses which are already compressed (e.g. images) are passed through unchanged.
+func CompressResponses(baseHandler http.Handler, minimumSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := getAcceptedEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			baseHandler.ServeHTTP(w, r)
+			return
+		}
+
+		compressWriter := &compressResponseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			minimumSize:    minimumSize,
+			statusCode:     http.StatusOK,
+		}
+		defer compressWriter.Close()
+
+		baseHandler.ServeHTTP(compressWriter, r)
+	})
+}
+
+// getAcceptedEncoding returns the preferred compression encoding ("gzip" or "deflate")
+// of the supplied Accept-Encoding header value or an empty string if neither is accepted.
+func getAcceptedEncoding(acceptEncoding string) string {
+	accepted := make(map[string]bool)
+
+	for _, value := range strings.Split(acceptEncoding, ",") {
+		components := strings.Split(value, ";")
+		name := strings.ToLower(strings.TrimSpace(components[0]))
+		if name == "" {
+			continue
+		}
+
+		// an encoding with a quality value of zero is not acceptable (e.g. "gzip;q=0")
+		quality := 1.0
+		for _, parameter := range components[1:] {
+			parameter = strings.TrimSpace(parameter)
+			if !strings.HasPrefix(parameter, "q=") {
+				continue
+			}
+
+			if parsedQuality, err := strconv.ParseFloat(strings.TrimPrefix(parameter, "q="), 64); err == nil {
+				quality = parsedQuality
+			}
+		}
+
+		accepted[name] = quality > 0
+	}
+
+	for _, encoding := range []string{encodingGzip, encodingDeflate} {
+		if isAccepted, isListed := accepted[encoding]; isListed {
+			if isAccepted {
+				return encoding
+			}
+
+			continue
+		}
+
+		if accepted["*"] {
+			return encoding
+		}
+	}
+
+	return ""
+}
+
+// isCompressedContentType returns true if the supplied content type is already compressed (e.g. "image/png").
+func isCompressedContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+
+	// svg images are plain text
+	if strings.HasPrefix(contentType, "image/svg+xml") {
+		return false
+	}
+
+	for _, prefix := range compressedContentTypePrefixes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// A compressResponseWriter buffers the beginning of a response until the minimum size is reached
+// and then decides whether the response is compressed or passed through.
+type compressResponseWriter struct {
+	http.ResponseWriter
+
+	encoding    string
+	minimumSize int
+
+	statusCode int
+	buffer     []byte
+
+	// started is true as soon as the headers have been written to the underlying response writer
+	started    bool
+	compressor io.WriteCloser
+}
+
+func (w *compressResponseWriter) WriteHeader(statusCode int) {
+	if w.started {
+		return
+	}
+
+	w.statusCode = statusCode
+
+	// responses without a body can be passed through immediately
+	if !bodyAllowedForStatus(statusCode) {
+		w.start(false)
+	}
+}
+
+func (w *compressResponseWriter) Write(data []byte) (int, error) {
+	if w.started {
+		if w.compressor != nil {
+			return w.compressor.Write(data)
+		}
+
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buffer = append(w.buffer, data...)
+	if len(w.buffer) >= w.minimumSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Flush writes the buffered data to the client.
+func (w *compressResponseWriter) Flush() {
+	if !w.started {
+		w.start(len(w.buffer) >= w.minimumSize)
+	}
+
+	if flusher, ok := w.compressor.(interface {
+		Flush() error
+	}); ok {
+		flusher.Flush()
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the caller take over the connection (e.g. for websockets).
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("The response writer does not support hijacking.")
+	}
+
+	w.started = true
+	return hijacker.Hijack()
+}
+
+// Close writes any buffered data and finishes the compressed stream.
+func (w *compressResponseWriter) Close() error {
+	if !w.started {
+		// the response is smaller than the minimum size
+		if err := w.start(false); err != nil {
+			return err
+		}
+	}
+
+	if w.compressor != nil {
+		return w.compressor.Close()
+	}
+
+	return nil
+}
+
+// start writes the headers to the underlying response writer
+// and flushes the buffer either compressed or uncompressed.
+func (w *compressResponseWriter) start(compress bool) error {
+	w.started = true
+
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buffer) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buffer))
+	}
+
+	compress = compress &&
+		bodyAllowedForStatus(w.statusCode) &&
+		w.statusCode != http.StatusPartialContent &&
+		header.Get("Content-Encoding") == "" &&
+		!isCompressedContentType(header.Get("Content-Type"))
+
+	if compress {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		switch w.encoding {
+		case encodingGzip:
+			w.compressor = gzip.NewWriter(w.ResponseWriter)
+
+		case encodingDeflate:
+			deflateWriter, err := flate.NewWriter(w.ResponseWriter, flate.DefaultCompression)
+			if err != nil {
+				return err
+			}
+
+			w.compressor = deflateWriter
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.statusCode)
+
+	if len(w.buffer) == 0 {
+		return nil
+	}
+
+	buffer := w.buffer
+	w.buffer = nil
+
+	if w.compressor != nil {
+		_, err := w.compressor.Write(buffer)
+		return err
+	}
+
+	_, err := w.ResponseWriter.Write(buffer)
+	return err
+}
+
+// bodyAllowedForStatus returns false for status codes which must not have a response body.
+func bodyAllowedForStatus(statusCode int) bool {
+	switch {
+	case statusCode >= 100 && statusCode < 200:
+		return false
+	case statusCode == http.StatusNoContent:
+		return false
+	case statusCode == http.StatusNotModified:
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,187 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/web/header"
+)
+
+var largeHTML = "<html><body>" + strings.Repeat("<p>Lorem ipsum dolor sit amet.</p>", 200) + "</body></html>"
+
+func newTestContentHandler(contentType, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(body))
+	})
+}
+
+func getCompressedResponse(handler http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/document", nil)
+	if acceptEncoding != "" {
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	CompressResponses(handler, 1024).ServeHTTP(response, request)
+	return response
+}
+
+func Test_CompressResponses_LargeHTMLAndGzipAccepted_ResponseIsGzipped(t *testing.T) {
+	// arrange
+	handler := newTestContentHandler(header.CONTENTTYPE_HTML, largeHTML)
+
+	// act
+	response := getCompressedResponse(handler, "gzip, deflate")
+
+	// assert
+	if encoding := response.Header().Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("The Content-Encoding should be %q but was %q.", "gzip", encoding)
+	}
+
+	if vary := response.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("The Vary header should be %q but was %q.", "Accept-Encoding", vary)
+	}
+
+	if response.Body.Len() >= len(largeHTML) {
+		t.Errorf("The compressed body (%d bytes) should be smaller than the original (%d bytes).", response.Body.Len(), len(largeHTML))
+	}
+
+	reader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		t.Fatalf("The body should be gzipped but could not be read: %s", err)
+	}
+
+	body, _ := ioutil.ReadAll(reader)
+	if string(body) != largeHTML {
+		t.Errorf("The uncompressed body should match the original body.")
+	}
+}
+
+func Test_CompressResponses_LargeHTMLAndOnlyDeflateAccepted_ResponseIsDeflated(t *testing.T) {
+	// arrange
+	handler := newTestContentHandler(header.CONTENTTYPE_HTML, largeHTML)
+
+	// act
+	response := getCompressedResponse(handler, "deflate, gzip;q=0")
+
+	// assert
+	if encoding := response.Header().Get("Content-Encoding"); encoding != "deflate" {
+		t.Fatalf("The Content-Encoding should be %q but was %q.", "deflate", encoding)
+	}
+
+	body, _ := ioutil.ReadAll(flate.NewReader(response.Body))
+	if string(body) != largeHTML {
+		t.Errorf("The uncompressed body should match the original body.")
+	}
+}
+
+func Test_CompressResponses_NoEncodingAccepted_ResponseIsPassedThrough(t *testing.T) {
+	// arrange
+	handler := newTestContentHandler(header.CONTENTTYPE_HTML, largeHTML)
+
+	// act
+	response := getCompressedResponse(handler, "")
+
+	// assert
+	if encoding := response.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("The Content-Encoding should be empty but was %q.", encoding)
+	}
+
+	if vary := response.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("The Vary header should be %q but was %q.", "Accept-Encoding", vary)
+	}
+
+	if response.Body.String() != largeHTML {
+		t.Errorf("The body should be passed through unchanged.")
+	}
+}
+
+func Test_CompressResponses_SmallResponse_ResponseIsPassedThrough(t *testing.T) {
+	// arrange
+	handler := newTestContentHandler(header.CONTENTTYPE_HTML, "<p>Hello</p>")
+
+	// act
+	response := getCompressedResponse(handler, "gzip")
+
+	// assert
+	if encoding := response.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("The Content-Encoding should be empty but was %q.", encoding)
+	}
+
+	if body := response.Body.String(); body != "<p>Hello</p>" {
+		t.Errorf("The body should be %q but was %q.", "<p>Hello</p>", body)
+	}
+}
+
+func Test_CompressResponses_LargeImage_ResponseIsPassedThrough(t *testing.T) {
+	// arrange
+	image := strings.Repeat("x", 4096)
+	handler := newTestContentHandler("image/png", image)
+
+	// act
+	response := getCompressedResponse(handler, "gzip")
+
+	// assert
+	if encoding := response.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("The Content-Encoding should be empty but was %q.", encoding)
+	}
+
+	if response.Body.String() != image {
+		t.Errorf("The body should be passed through unchanged.")
+	}
+}
+
+func Test_CompressResponses_NotModified_NoBodyIsWritten(t *testing.T) {
+	// arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header.NotModified(w)
+	})
+
+	// act
+	response := getCompressedResponse(handler, "gzip")
+
+	// assert
+	if response.Code != http.StatusNotModified {
+		t.Errorf("The status code should be %d but was %d.", http.StatusNotModified, response.Code)
+	}
+
+	if response.Body.Len() != 0 {
+		t.Errorf("The body should be empty but was %d bytes long.", response.Body.Len())
+	}
+}
+
+func Test_getAcceptedEncoding(t *testing.T) {
+	// arrange
+	inputs := map[string]string{
+		"":                      "",
+		"gzip":                  "gzip",
+		"deflate, gzip":         "gzip",
+		"deflate":               "deflate",
+		"gzip;q=0, deflate":     "deflate",
+		"gzip;q=0, deflate;q=0": "",
+		"br":                    "",
+		"*":                     "gzip",
+		"identity":              "",
+	}
+
+	for acceptEncoding, expected := range inputs {
+
+		// act
+		result := getAcceptedEncoding(acceptEncoding)
+
+		// assert
+		if result != expected {
+			t.Errorf("getAcceptedEncoding(%q) should return %q but returned %q.", acceptEncoding, expected, result)
+		}
+	}
+}
@@ -146,7 +146,7 @@ func DOCX(logger logger.Logger,
 			}
 		}()
 
-		w.Header().Add("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, getTargetFilename(model)))
+		w.Header().Add("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, getTargetFilename(model, "docx")))
 
 		io.Copy(w, docxFile)
 
@@ -155,8 +155,8 @@ func DOCX(logger logger.Logger,
 
 }
 
-// getTargetFilename returns a filename from the given conversion model.
-func getTargetFilename(model viewmodel.ConversionModel) string {
+// getTargetFilename returns a filename with the given extension (e.g. "docx") from the given conversion model.
+func getTargetFilename(model viewmodel.ConversionModel, extension string) string {
 	originalRoute := route.NewFromRequest(model.Route)
 	fileNameRoute := route.NewFromRequest(originalRoute.LastComponentName())
 
@@ -164,7 +164,7 @@ func getTargetFilename(model viewmodel.ConversionModel) string {
 		fileNameRoute = route.NewFromRequest(model.Title)
 	}
 
-	return fmt.Sprintf("%s.docx", fileNameRoute.Value())
+	return fmt.Sprintf("%s.%s", fileNameRoute.Value(), extension)
 }
 
 // deleteFile removes the file with the specified path.
@@ -0,0 +1,63 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/orchestrator"
+)
+
+// EPUB creates a handler which returns the collection with the requested route (e.g. "/books/sample.epub")
+// as an EPUB file. Requests for items which are not collections are passed to the fallback handler.
+func EPUB(logger logger.Logger,
+	headerWriter header.HeaderWriter,
+	epubOrchestrator *orchestrator.EPUBOrchestrator,
+	fallbackHandler http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// strip the "epub" or ".epub" suffix from the path
+		path := r.URL.Path
+		path = strings.TrimSuffix(path, "epub")
+		path = strings.TrimSuffix(path, ".")
+
+		// get the request route
+		requestRoute := route.NewFromRequest(path)
+
+		// make sure the request body is closed
+		defer r.Body.Close()
+
+		book, found := epubOrchestrator.GetBook(requestRoute)
+		if !found {
+			fallbackHandler.ServeHTTP(w, r)
+			return
+		}
+
+		// create the book before writing the headers so errors can still be reported
+		var epubFile bytes.Buffer
+		if err := book.Write(&epubFile); err != nil {
+			logger.Error("Cannot create the EPUB file for %q. Error: %s", requestRoute, err.Error())
+			http.Error(w, "The EPUB file could not be created.", http.StatusInternalServerError)
+			return
+		}
+
+		fileName := requestRoute.LastComponentName()
+		if fileName == "" {
+			fileName = "book"
+		}
+
+		headerWriter.Write(w, header.CONTENTTYPE_EPUB)
+		w.Header().Add("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.epub"`, fileName))
+
+		epubFile.WriteTo(w)
+	})
+}
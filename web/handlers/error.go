@@ -7,38 +7,74 @@ package handlers
 import (
 	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/web/header"
-	"github.com/andreaskoch/allmark/web/orchestrator"
 	"github.com/andreaskoch/allmark/web/view/templates"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"fmt"
 	"net/http"
 )
 
-func Error(headerWriter header.HeaderWriter, templateProvider templates.Provider, navigationOrchestrator *orchestrator.NavigationOrchestrator) http.Handler {
+// A NavigationProvider provides the site navigation for pages which are not backed by an item.
+type NavigationProvider interface {
+	GetToplevelNavigation() viewmodel.ToplevelNavigation
+	GetBreadcrumbNavigation(route route.Route) viewmodel.BreadcrumbNavigation
+}
+
+// Error creates a handler which displays a "not found" page with status code 404.
+// If a user-authored 404 page (see NotFoundPageRoute) is supplied it is rendered with the template of its type,
+// otherwise the error template of the theme is used.
+func Error(headerWriter header.HeaderWriter, templateProvider templates.Provider, navigationProvider NavigationProvider, notFoundPage *viewmodel.Model) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		// set headers
 		headerWriter.Write(w, header.CONTENTTYPE_HTML)
 		w.WriteHeader(http.StatusNotFound)
 
-		// get the error template
 		hostname := getBaseURLFromRequest(r)
-		errorTemplate, err := templateProvider.GetErrorTemplate(hostname)
-		if err != nil {
-			fmt.Fprintf(w, "Template not found. Error: %s", err)
-			return
+
+		// user-authored 404 page
+		if notFoundPage != nil {
+			if itemTemplate, err := templateProvider.GetItemTemplate(notFoundPage.Type, hostname); err == nil {
+				renderTemplate(itemTemplate, *notFoundPage, w)
+				return
+			}
 		}
 
-		// create the view model
-		errorModel := viewmodel.Model{}
+		renderErrorPage(w, r, templateProvider, navigationProvider, "Not found", "The requested resource was not found.")
+	})
+}
 
-		errorModel.Type = "error"
-		errorModel.Title = "Not found"
-		errorModel.Description = "The requested resource was not found."
-		errorModel.ToplevelNavigation = navigationOrchestrator.GetToplevelNavigation()
-		errorModel.BreadcrumbNavigation = navigationOrchestrator.GetBreadcrumbNavigation(route.New())
+// InternalServerError creates a handler which displays the error template of the theme with status code 500
+// (e.g. for conversions which have failed).
+func InternalServerError(headerWriter header.HeaderWriter, templateProvider templates.Provider, navigationProvider NavigationProvider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		// render the template
-		renderTemplate(errorTemplate, errorModel, w)
+		// set headers
+		headerWriter.Write(w, header.CONTENTTYPE_HTML)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		renderErrorPage(w, r, templateProvider, navigationProvider, "Internal server error", "The request could not be processed.")
 	})
 }
+
+// renderErrorPage renders the error template of the theme with the given title and description.
+func renderErrorPage(w http.ResponseWriter, r *http.Request, templateProvider templates.Provider, navigationProvider NavigationProvider, title, description string) {
+
+	// get the error template
+	errorTemplate, err := templateProvider.GetErrorTemplate(getBaseURLFromRequest(r))
+	if err != nil {
+		fmt.Fprintf(w, "Template not found. Error: %s", err)
+		return
+	}
+
+	// create the view model
+	errorModel := viewmodel.Model{}
+
+	errorModel.Type = "error"
+	errorModel.Title = title
+	errorModel.Description = description
+	errorModel.ToplevelNavigation = navigationProvider.GetToplevelNavigation()
+	errorModel.BreadcrumbNavigation = navigationProvider.GetBreadcrumbNavigation(route.New())
+
+	// render the template
+	renderTemplate(errorTemplate, errorModel, w)
+}
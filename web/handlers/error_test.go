@@ -0,0 +1,88 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/view/templates"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// A testNavigationProvider returns a top-level navigation with a single "Guides" entry.
+type testNavigationProvider struct{}
+
+func (provider testNavigationProvider) GetToplevelNavigation() viewmodel.ToplevelNavigation {
+	return viewmodel.ToplevelNavigation{
+		Entries: []viewmodel.ToplevelEntry{
+			viewmodel.ToplevelEntry{Title: "Guides", Path: "/guides"},
+		},
+	}
+}
+
+func (provider testNavigationProvider) GetBreadcrumbNavigation(route route.Route) viewmodel.BreadcrumbNavigation {
+	return viewmodel.BreadcrumbNavigation{}
+}
+
+func getErrorResponse(notFoundPage *viewmodel.Model) *httptest.ResponseRecorder {
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	handler := Error(headerWriterFactory.NoCache(), templates.NewProvider(""), testNavigationProvider{}, notFoundPage)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/does/not/exist", nil)
+	handler.ServeHTTP(response, request)
+
+	return response
+}
+
+func Test_Error_CustomNotFoundPage_CustomPageIsRenderedWithStatus404(t *testing.T) {
+	// arrange
+	notFoundPage := viewmodel.Model{}
+	notFoundPage.Type = "document"
+	notFoundPage.Title = "Lost in the woods"
+	notFoundPage.Content = "<p>Try the guides instead.</p>"
+	notFoundPage.ToplevelNavigation = testNavigationProvider{}.GetToplevelNavigation()
+
+	// act
+	response := getErrorResponse(&notFoundPage)
+
+	// assert
+	if response.Code != http.StatusNotFound {
+		t.Errorf("The status code should be %d but was %d.", http.StatusNotFound, response.Code)
+	}
+
+	body := response.Body.String()
+	if !strings.Contains(body, "Lost in the woods") || !strings.Contains(body, "Try the guides instead.") {
+		t.Errorf("The response should contain the custom 404 page but was %q.", body)
+	}
+
+	if !strings.Contains(body, `href="/guides"`) {
+		t.Errorf("The response should contain the site navigation but was %q.", body)
+	}
+}
+
+func Test_Error_NoCustomNotFoundPage_ThemeErrorPageIsRenderedWithStatus404(t *testing.T) {
+	// act
+	response := getErrorResponse(nil)
+
+	// assert
+	if response.Code != http.StatusNotFound {
+		t.Errorf("The status code should be %d but was %d.", http.StatusNotFound, response.Code)
+	}
+
+	body := response.Body.String()
+	if !strings.Contains(body, "The requested resource was not found.") {
+		t.Errorf("The response should contain the error page of the theme but was %q.", body)
+	}
+
+	if !strings.Contains(body, `href="/guides"`) {
+		t.Errorf("The response should contain the site navigation but was %q.", body)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andreaskoch/allmark/web/header"
+)
+
+// newTestHashHandler returns a handler which serves the current hash as the body (like the item handler serves items).
+func newTestHashHandler(hash *string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if writeETag(w, r, *hash) {
+			return
+		}
+
+		fmt.Fprintf(w, "Content %s", *hash)
+	})
+}
+
+func serveRequest(handler http.Handler, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	if ifNoneMatch != "" {
+		request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func Test_InMemoryTheme_SecondRequestWithETag_NotModifiedIsReturned(t *testing.T) {
+	// arrange
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	handler := InMemoryTheme("/theme/", headerWriterFactory.NoCache(), http.NotFoundHandler())
+
+	// act
+	firstResponse := serveRequest(handler, "/theme/screen.css", "")
+	secondResponse := serveRequest(handler, "/theme/screen.css", firstResponse.Header().Get("ETag"))
+
+	// assert
+	if firstResponse.Code != http.StatusOK {
+		t.Fatalf("The status code of the first response should be %d but was %d.", http.StatusOK, firstResponse.Code)
+	}
+
+	if firstResponse.Header().Get("ETag") == "" {
+		t.Fatalf("The first response should have an ETag header.")
+	}
+
+	if secondResponse.Code != http.StatusNotModified {
+		t.Errorf("The status code of the second response should be %d but was %d.", http.StatusNotModified, secondResponse.Code)
+	}
+
+	if secondResponse.Body.Len() != 0 {
+		t.Errorf("The second response should not have a body but was %q.", secondResponse.Body.String())
+	}
+}
+
+func Test_writeETag_HashIsQuotedFullLengthETag(t *testing.T) {
+	// arrange
+	hash := "9a0364b9e99bb480dd25e1f0284c8555dd0a3b4e"
+	handler := newTestHashHandler(&hash)
+
+	// act
+	response := serveRequest(handler, "/document", "")
+
+	// assert
+	expected := `"9a0364b9e99bb480dd25e1f0284c8555dd0a3b4e"`
+	if etag := response.Header().Get("ETag"); etag != expected {
+		t.Errorf("The ETag should be %s but was %s.", expected, etag)
+	}
+}
+
+func Test_writeETag_HashChanged_ContentIsReturned(t *testing.T) {
+	// arrange
+	hash := "a1"
+	handler := newTestHashHandler(&hash)
+	firstResponse := serveRequest(handler, "/document", "")
+
+	// act
+	hash = "b2"
+	secondResponse := serveRequest(handler, "/document", firstResponse.Header().Get("ETag"))
+
+	// assert
+	if secondResponse.Code != http.StatusOK {
+		t.Errorf("The status code should be %d but was %d.", http.StatusOK, secondResponse.Code)
+	}
+
+	if body := secondResponse.Body.String(); body != "Content b2" {
+		t.Errorf("The body should be %q but was %q.", "Content b2", body)
+	}
+}
+
+func Test_writeETag_WeakAndListedETags_NotModifiedIsReturned(t *testing.T) {
+	// arrange
+	hash := "a1"
+	handler := newTestHashHandler(&hash)
+
+	inputs := []string{`W/"a1"`, `"x9", "a1"`, `*`}
+
+	for _, ifNoneMatch := range inputs {
+
+		// act
+		response := serveRequest(handler, "/document", ifNoneMatch)
+
+		// assert
+		if response.Code != http.StatusNotModified {
+			t.Errorf("The status code for If-None-Match %s should be %d but was %d.", ifNoneMatch, http.StatusNotModified, response.Code)
+		}
+	}
+}
@@ -7,10 +7,13 @@ package handlers
 import (
 	"github.com/andreaskoch/allmark/common/config"
 	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/common/util/fsutil"
 	"github.com/andreaskoch/allmark/web/header"
 	"github.com/andreaskoch/allmark/web/orchestrator"
 	"github.com/andreaskoch/allmark/web/view/templates"
+	"github.com/andreaskoch/allmark/web/view/themes"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"fmt"
 	"net/http"
 )
@@ -21,11 +24,17 @@ var (
 	BasePath = "/"
 
 	// TagPathPrefix defines the prefix for tag-routes.
-	TagPathPrefix = "/tags.html#"
+	TagPathPrefix = "/tags/"
+
+	// TagHandlerRoute defines the route for the pages of a single tag.
+	TagHandlerRoute = TagPathPrefix + "{tag:.+$}"
 
 	// TagmapHandlerRoute defines the route for tagmap-handler requests.
 	TagmapHandlerRoute = "/tags.html"
 
+	// TagsOPMLHandlerRoute defines the route for the OPML outline of all tags.
+	TagsOPMLHandlerRoute = "/tags.opml"
+
 	// ThemeRoutePrefix defines the route-prefix for theme files.
 	ThemeRoutePrefix = "/theme"
 
@@ -50,9 +59,18 @@ var (
 	// LatestHandlerRoute defines the route for latest-handler requests.
 	LatestHandlerRoute = `/{path:.+\.latest$|latest$}`
 
+	// PresentationPDFHandlerRoute defines the route for presentation-pdf-handler requests.
+	PresentationPDFHandlerRoute = `/{path:.+\.pdf$|pdf$}`
+
 	// DOCXHandlerRoute defines the route for rich-text-handler requests.
 	DOCXHandlerRoute = `/{path:.+\.docx$|docx$}`
 
+	// EPUBHandlerRoute defines the route for epub-handler requests.
+	EPUBHandlerRoute = `/{path:.+\.epub$|epub$}`
+
+	// CommentHandlerRoute defines the route for comment-handler requests.
+	CommentHandlerRoute = `/{path:.+\.comment$|comment$}`
+
 	// UpdateHandlerRoute defines the route for update-handler requests.
 	UpdateHandlerRoute = `/{path:.+\.ws$|ws$}`
 
@@ -65,9 +83,15 @@ var (
 	// XMLSitemapHandlerRoute defines the route for xml-sitemap-handler requests.
 	XMLSitemapHandlerRoute = "/sitemap.xml"
 
+	// LocationsHandlerRoute defines the route for the GeoJSON-locations-handler requests.
+	LocationsHandlerRoute = "/locations.geojson"
+
 	// RSSHandlerRoute defines the route for RSS-feed-handler requests.
 	RSSHandlerRoute = "/feed.rss"
 
+	// AtomHandlerRoute defines the route for Atom-feed-handler requests.
+	AtomHandlerRoute = "/feed.atom"
+
 	// RobotsTxtHandlerRoute defines the route for robotstxt-handler requests.
 	RobotsTxtHandlerRoute = "/robots.txt"
 
@@ -83,6 +107,25 @@ var (
 	// TypeAheadTitlesHandlerRoute defines the route for typeahead-titles-handler requests.
 	TypeAheadTitlesHandlerRoute = "/titles.json"
 
+	// ItemsAPIRoutePrefix defines the prefix for items-api requests.
+	ItemsAPIRoutePrefix = "/api/items"
+
+	// ItemsAPIHandlerRoute defines the route for items-api requests.
+	ItemsAPIHandlerRoute = ItemsAPIRoutePrefix + "{path:(?:/.*)?$}"
+
+	// ItemBlocksAPIRoutePrefix defines the prefix for item-blocks-api requests.
+	ItemBlocksAPIRoutePrefix = "/api/blocks"
+
+	// ItemBlocksAPIHandlerRoute defines the route for item-blocks-api requests.
+	ItemBlocksAPIHandlerRoute = ItemBlocksAPIRoutePrefix + "{path:(?:/.*)?$}"
+
+	// SearchIndexHandlerRoute defines the route for the client-side search index.
+	SearchIndexHandlerRoute = "/search-index.json"
+
+	// NotFoundPageRoute defines the route of the user-authored page (e.g. "404/404.md")
+	// which is displayed when no handler, item or file matches a request.
+	NotFoundPageRoute = "404"
+
 	// RedirectHandlerRoute defines the route for redirect-handler requests.
 	RedirectHandlerRoute = "/{path:.*$}"
 
@@ -91,6 +134,15 @@ var (
 
 	// AliasIndexHandlerRoute defines the route for alias-lookup-handler requests.
 	AliasIndexHandlerRoute = "/!"
+
+	// MessagePermalinkHandlerRoute defines the route for message-permalink-handler requests.
+	MessagePermalinkHandlerRoute = "/" + orchestrator.MessagePermalinkPrefix + "/{timestamp:[0-9]{8}-[0-9]{6}$}"
+
+	// TimelineHandlerRoute defines the route for timeline-handler requests.
+	TimelineHandlerRoute = "/timeline.html"
+
+	// CombinedHandlerRoute defines the route for the single-page view of all documents.
+	CombinedHandlerRoute = "/all.html"
 )
 
 // RouteAndHandler combines routes and http-handlers.
@@ -115,7 +167,7 @@ func GetRedirectHandlers(logger logger.Logger, baseURITarget string, baseHandler
 }
 
 // GetBaseHandlers returns a full-list of all http-handlers in this package.
-func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvider templates.Provider, orchestratorFactory orchestrator.Factory, headerWriterFactory header.WriterFactory) HandlerList {
+func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvider templates.Provider, theme *themes.Theme, orchestratorFactory orchestrator.Factory, headerWriterFactory header.WriterFactory) HandlerList {
 	handlers := make(HandlerList, 0)
 
 	// orchestrators
@@ -124,36 +176,32 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 	fileOrchestrator := orchestratorFactory.NewFileOrchestrator()
 
 	// global handlers
-	errorHandler := Error(headerWriterFactory.Static(), templateProvider, navigationOrchestrator)
+	errorHandler := Error(headerWriterFactory.Static(), templateProvider, navigationOrchestrator, getNotFoundPage(logger, viewModelOrchestrator))
 
-	itemHandler := Item(
+	itemsOrchestrator := orchestratorFactory.NewItemsOrchestrator()
+	itemHandler := RawMarkdown(
 		logger,
 		headerWriterFactory.Dynamic(),
-		fileOrchestrator,
-		viewModelOrchestrator,
-		templateProvider, errorHandler)
-
-	// theme
-	if themeFolder := config.ThemeFolder(); fsutil.DirectoryExists(themeFolder) {
-		requestPrefixToStripFromRequestURI := "/" + config.Server.ThemeFolderName
-
-		handlers.Add(
-			ThemeHandlerRoute,
-			AddETAgToStaticFileHandler(
-				Static(
-					themeFolder,
-					ThemeRoutePrefix),
-				headerWriterFactory.Static(), themeFolder, requestPrefixToStripFromRequestURI))
-
-	} else {
-
-		handlers.Add(
-			ThemeHandlerRoute,
-			InMemoryTheme(
-				"/"+config.Server.ThemeFolderName+"/",
-				headerWriterFactory.Static(),
-				errorHandler))
-	}
+		itemsOrchestrator,
+		ItemRedirect(
+			logger,
+			itemsOrchestrator,
+			Item(
+				logger,
+				headerWriterFactory.Dynamic(),
+				fileOrchestrator,
+				viewModelOrchestrator,
+				templateProvider, errorHandler)))
+
+	// theme (the files in the theme folder override the files of the default theme)
+	handlers.Add(
+		ThemeHandlerRoute,
+		Theme(
+			"/"+config.Server.ThemeFolderName+"/",
+			config.ThemeOverrideFolder(),
+			theme,
+			headerWriterFactory.Static(),
+			errorHandler))
 
 	// alias lookup
 	handlers.Add(
@@ -171,6 +219,31 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 			orchestratorFactory.NewAliasIndexOrchestrator(),
 			templateProvider))
 
+	// message permalinks
+	handlers.Add(
+		MessagePermalinkHandlerRoute,
+		MessagePermalink(headerWriterFactory.Dynamic(),
+			orchestratorFactory.NewMessageOrchestrator(),
+			itemHandler))
+
+	// all.html
+	handlers.Add(
+		CombinedHandlerRoute,
+		Combined(logger,
+			headerWriterFactory.Dynamic(),
+			orchestratorFactory.NewCombinedOrchestrator(),
+			templateProvider,
+			errorHandler))
+
+	// timeline.html
+	handlers.Add(
+		TimelineHandlerRoute,
+		Timeline(
+			headerWriterFactory.Dynamic(),
+			navigationOrchestrator,
+			orchestratorFactory.NewMessageOrchestrator(),
+			templateProvider))
+
 	// thumbnails
 	if thumbnailsFolder := config.ThumbnailFolder(); fsutil.DirectoryExists(thumbnailsFolder) {
 		requestPrefixToStripFromRequestURI := "/" + config.Conversion.Thumbnails.FolderName
@@ -185,7 +258,7 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 	}
 
 	// robots.txt
-	handlers.Add(RobotsTxtHandlerRoute, RobotsTxt(headerWriterFactory.Static(), templateProvider))
+	handlers.Add(RobotsTxtHandlerRoute, RobotsTxt(headerWriterFactory.Static(), config.Web.BaseURL, orchestratorFactory.NewRobotsTxtOrchestrator(), templateProvider))
 
 	// sitemap.html
 	handlers.Add(
@@ -203,6 +276,23 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 			orchestratorFactory.NewTagsOrchestrator(),
 			templateProvider))
 
+	// tags.opml
+	handlers.Add(
+		TagsOPMLHandlerRoute,
+		TagsOPML(headerWriterFactory.Dynamic(),
+			config.Web.BaseURL,
+			orchestratorFactory.NewTagsOrchestrator(),
+			templateProvider))
+
+	// tags/<tag>
+	handlers.Add(
+		TagHandlerRoute,
+		Tag(headerWriterFactory.Dynamic(),
+			navigationOrchestrator,
+			orchestratorFactory.NewTagsOrchestrator(),
+			templateProvider,
+			errorHandler))
+
 	// search
 	handlers.Add(
 		SearchHandlerRoute,
@@ -217,9 +307,16 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 	handlers.Add(
 		XMLSitemapHandlerRoute,
 		XMLSitemap(headerWriterFactory.Dynamic(),
+			config.Web.BaseURL,
 			orchestratorFactory.NewXMLSitemapOrchestrator(),
 			templateProvider))
 
+	// locations.geojson
+	handlers.Add(
+		LocationsHandlerRoute,
+		Locations(headerWriterFactory.Dynamic(),
+			orchestratorFactory.NewLocationsOrchestrator()))
+
 	// opensearch.xml
 	handlers.Add(
 		OpenSearchDescriptionHandlerRoute,
@@ -233,6 +330,24 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 		Titles(headerWriterFactory.Dynamic(),
 			orchestratorFactory.NewTitlesOrchestrator()))
 
+	// items api
+	handlers.Add(
+		ItemsAPIHandlerRoute,
+		ItemsAPI(headerWriterFactory.Dynamic(),
+			orchestratorFactory.NewItemsOrchestrator()))
+
+	// item blocks api
+	handlers.Add(
+		ItemBlocksAPIHandlerRoute,
+		ItemBlocksAPI(headerWriterFactory.Dynamic(),
+			orchestratorFactory.NewItemsOrchestrator()))
+
+	// search-index.json
+	handlers.Add(
+		SearchIndexHandlerRoute,
+		SearchIndex(headerWriterFactory.Dynamic(),
+			orchestratorFactory.NewSearchIndexOrchestrator()))
+
 	// search.json
 	handlers.Add(
 		TypeAheadSearchHandlerRoute,
@@ -246,6 +361,17 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 	handlers.Add(
 		RSSHandlerRoute,
 		RSS(headerWriterFactory.Dynamic(),
+			config.Web.BaseURL,
+			config.FeedItemCount(),
+			orchestratorFactory.NewFeedOrchestrator(),
+			templateProvider,
+			errorHandler))
+	// atom
+	handlers.Add(
+		AtomHandlerRoute,
+		Atom(headerWriterFactory.Dynamic(),
+			config.Web.BaseURL,
+			config.FeedItemCount(),
 			orchestratorFactory.NewFeedOrchestrator(),
 			templateProvider,
 			errorHandler))
@@ -287,6 +413,36 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 			templateProvider,
 			errorHandler))
 
+	// pdf (presentations only)
+	if config.Conversion.PDF.IsEnabled() {
+		handlers.Add(
+			PresentationPDFHandlerRoute,
+			PresentationPDF(logger,
+				config.Conversion.PDF.Tool(),
+				conversionEndpointAddress,
+				headerWriterFactory.Dynamic(),
+				conversionModelOrchestrator,
+				templateProvider,
+				itemHandler,
+				InternalServerError(headerWriterFactory.NoCache(), templateProvider, navigationOrchestrator)))
+	}
+
+	// epub (collections only)
+	handlers.Add(
+		EPUBHandlerRoute,
+		EPUB(logger,
+			headerWriterFactory.Dynamic(),
+			orchestratorFactory.NewEPUBOrchestrator(),
+			itemHandler))
+
+	// comments
+	handlers.Add(
+		CommentHandlerRoute,
+		Comment(logger,
+			config.Comments.Enabled,
+			config.CommentsMinimumInterval(),
+			orchestratorFactory.NewCommentOrchestrator()))
+
 	// update
 	handlers.Add(
 		UpdateHandlerRoute,
@@ -303,3 +459,14 @@ func GetBaseHandlers(logger logger.Logger, config config.Config, templateProvide
 
 	return handlers
 }
+
+// getNotFoundPage returns the view model of the user-authored 404 page or nil if the repository does not contain one.
+func getNotFoundPage(logger logger.Logger, viewModelOrchestrator *orchestrator.ViewModelOrchestrator) *viewmodel.Model {
+	notFoundPage, found := viewModelOrchestrator.GetFullViewModel(route.NewFromRequest(NotFoundPageRoute))
+	if !found {
+		logger.Debug("No custom 404 page found. Using the error page of the theme.")
+		return nil
+	}
+
+	return &notFoundPage
+}
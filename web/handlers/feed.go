@@ -0,0 +1,78 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/orchestrator"
+	"github.com/andreaskoch/allmark/web/view/templates"
+	"fmt"
+	"net/http"
+	"text/template"
+)
+
+// RSS cretes a new RSS-Feed handler which renders the given number of items per page.
+func RSS(headerWriter header.HeaderWriter,
+	configuredBaseURL string,
+	itemsPerPage int,
+	feedOrchestrator *orchestrator.FeedOrchestrator,
+	templateProvider templates.Provider,
+	error404Handler http.Handler) http.Handler {
+
+	return feed(headerWriter, header.CONTENTTYPE_XML, configuredBaseURL, itemsPerPage, feedOrchestrator, templateProvider.GetRSSTemplate, error404Handler)
+}
+
+// Atom creates a new Atom-Feed handler which renders the same items as the RSS-Feed handler.
+func Atom(headerWriter header.HeaderWriter,
+	configuredBaseURL string,
+	itemsPerPage int,
+	feedOrchestrator *orchestrator.FeedOrchestrator,
+	templateProvider templates.Provider,
+	error404Handler http.Handler) http.Handler {
+
+	return feed(headerWriter, header.CONTENTTYPE_ATOM, configuredBaseURL, itemsPerPage, feedOrchestrator, templateProvider.GetAtomTemplate, error404Handler)
+}
+
+// feed creates a handler which renders the feed model with the template returned by getTemplate.
+func feed(headerWriter header.HeaderWriter,
+	contentType string,
+	configuredBaseURL string,
+	itemsPerPage int,
+	feedOrchestrator *orchestrator.FeedOrchestrator,
+	getTemplate func(hostname string) (*template.Template, error),
+	error404Handler http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// get the current baseURL
+		baseURL := getBaseURL(configuredBaseURL, r)
+
+		// set headers
+		headerWriter.Write(w, contentType)
+
+		// read the page url-parameter
+		page, pageParameterIsAvailable := getPageParameterFromURL(*r.URL)
+		if !pageParameterIsAvailable || page == 0 {
+			page = 1
+		}
+
+		// get the feed template
+		feedTemplate, err := getTemplate(baseURL)
+		if err != nil {
+			fmt.Fprintf(w, "Template not found. Error: %s", err)
+			return
+		}
+
+		feedModel, err := feedOrchestrator.GetFeed(baseURL, itemsPerPage, page)
+
+		// display error 404 non-existing page has been requested
+		if err != nil {
+			error404Handler.ServeHTTP(w, r)
+			return
+		}
+
+		renderTemplate(feedTemplate, feedModel, w)
+	})
+}
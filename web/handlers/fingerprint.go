@@ -0,0 +1,142 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"html"
+	"net/http"
+	"net/url"
+	pathpkg "path"
+	"regexp"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+	"github.com/andreaskoch/allmark/web/orchestrator"
+	"github.com/andreaskoch/allmark/web/view/themes"
+)
+
+var (
+	// a file name with a fingerprint (e.g. "presentation.<sha1>.js")
+	fingerprintedFileNamePattern = regexp.MustCompile(`^(.+)\.([0-9a-f]{40})(\.[^.]+)?$`)
+
+	// the elements which reference assets (e.g. <script src="...">); links to other pages (<a href="...">) are not changed
+	assetElementPattern = regexp.MustCompile(`(?i)<(?:link|script|img|source)\b[^>]*>`)
+	assetURLPattern     = regexp.MustCompile(`(?i)(\s(?:src|href)\s*=\s*)"([^"]*)"`)
+	baseElementPattern  = regexp.MustCompile(`(?i)<base\b[^>]*\shref\s*=\s*"([^"]*)"`)
+)
+
+// An AssetHashProvider returns the content hash of the asset with the given request path
+// (e.g. "/theme/screen.css" or "/documents/sample/files/photo.jpg").
+type AssetHashProvider func(requestPath string) (hash string, found bool)
+
+// NewAssetHashProvider creates an AssetHashProvider for the files of the supplied theme (which can be overridden
+// by the files in the theme directory) and for the item files of the supplied file orchestrator (which can be nil).
+func NewAssetHashProvider(themeDirectory string, theme *themes.Theme, fileOrchestrator *orchestrator.FileOrchestrator) AssetHashProvider {
+
+	// the theme files never change, so their hashes are only calculated once
+	themeFileHashes := make(map[string]string)
+	for _, themeFilePath := range theme.Paths() {
+		themeFileHashes[themeFilePath] = hashutil.SHA1FromBytes(theme.Get(themeFilePath).Data())
+	}
+
+	return func(requestPath string) (hash string, found bool) {
+		if strings.HasPrefix(requestPath, ThemeRoutePrefix+"/") {
+			themeFilePath := strings.TrimPrefix(pathpkg.Clean(strings.TrimPrefix(requestPath, ThemeRoutePrefix)), "/")
+			if data, found := getThemeFileData(themeDirectory, themeFilePath); found {
+				return hashutil.SHA1FromBytes(data), true
+			}
+
+			hash, found := themeFileHashes[themeFilePath]
+			return hash, found
+		}
+
+		if fileOrchestrator == nil {
+			return "", false
+		}
+
+		return fileOrchestrator.GetFileHash(route.NewFromRequest(requestPath))
+	}
+}
+
+// GetFingerprintedPath inserts the supplied hash before the extension of the file name of the given path
+// (e.g. "/theme/presentation.js" -> "/theme/presentation.<hash>.js").
+func GetFingerprintedPath(path, hash string) string {
+	directory, fileName := pathpkg.Split(path)
+
+	extension := pathpkg.Ext(fileName)
+	if extension == fileName {
+		extension = ""
+	}
+
+	return directory + strings.TrimSuffix(fileName, extension) + "." + hash + extension
+}
+
+// parseFingerprintedPath returns the path without the fingerprint and the fingerprint itself
+// if the file name of the supplied path contains a fingerprint.
+func parseFingerprintedPath(path string) (originalPath, hash string, isFingerprinted bool) {
+	directory, fileName := pathpkg.Split(path)
+
+	match := fingerprintedFileNamePattern.FindStringSubmatch(fileName)
+	if match == nil {
+		return path, "", false
+	}
+
+	return directory + match[1] + match[3], match[2], true
+}
+
+// FingerprintAssetURLs replaces the URLs of all assets (theme files and item files) which are referenced
+// by the HTML responses of the supplied handler with their fingerprinted URLs (see GetFingerprintedPath).
+// All other responses are passed through unchanged.
+func FingerprintAssetURLs(baseHandler http.Handler, assetHashes AssetHashProvider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		getRewriter := func(contentType string) rewriter {
+			if getMediaType(contentType) != "text/html" {
+				return nil
+			}
+
+			return func(htmlCode string) string {
+				return fingerprintAssetURLs(htmlCode, r.URL.Path, assetHashes)
+			}
+		}
+
+		fingerprintWriter := newRewritingResponseWriter(w, r, getRewriter)
+		defer fingerprintWriter.Close()
+
+		baseHandler.ServeHTTP(fingerprintWriter, r)
+	})
+}
+
+// fingerprintAssetURLs replaces the asset URLs of the supplied HTML code with their fingerprinted URLs.
+// Relative URLs are resolved against the base element of the document or the given request path.
+func fingerprintAssetURLs(htmlCode, requestPath string, assetHashes AssetHashProvider) string {
+	baseURL := &url.URL{Path: requestPath}
+	if match := baseElementPattern.FindStringSubmatch(htmlCode); match != nil {
+		if baseHref, err := url.Parse(html.UnescapeString(match[1])); err == nil {
+			baseURL = baseURL.ResolveReference(baseHref)
+		}
+	}
+
+	return assetElementPattern.ReplaceAllStringFunc(htmlCode, func(element string) string {
+		return assetURLPattern.ReplaceAllStringFunc(element, func(attribute string) string {
+			match := assetURLPattern.FindStringSubmatch(attribute)
+			assetURL, err := url.Parse(html.UnescapeString(match[2]))
+			if err != nil || assetURL.Scheme != "" || assetURL.Host != "" || assetURL.Path == "" {
+				return attribute
+			}
+
+			hash, found := assetHashes(baseURL.ResolveReference(assetURL).Path)
+			if !found {
+				return attribute
+			}
+
+			assetURL.Path = GetFingerprintedPath(assetURL.Path, hash)
+			assetURL.RawPath = ""
+
+			return match[1] + `"` + html.EscapeString(assetURL.String()) + `"`
+		})
+	})
+}
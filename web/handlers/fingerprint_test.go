@@ -0,0 +1,210 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/view/themes"
+	"github.com/andreaskoch/allmark/web/view/themes/themefiles"
+)
+
+var presentationScriptPattern = regexp.MustCompile(`src="(/theme/presentation[^"]*)"`)
+
+func Test_GetFingerprintedPath(t *testing.T) {
+	hash := hashutil.SHA1FromString("content")
+	inputs := map[string]string{
+		"/theme/presentation.js":      "/theme/presentation." + hash + ".js",
+		"/theme/jquery.tmpl.js":       "/theme/jquery.tmpl." + hash + ".js",
+		"files/photo.jpg":             "files/photo." + hash + ".jpg",
+		"/documents/sample/files/doc": "/documents/sample/files/doc." + hash,
+	}
+
+	for input, expected := range inputs {
+		// act
+		result := GetFingerprintedPath(input, hash)
+
+		// assert
+		if result != expected {
+			t.Errorf("The fingerprinted path of %q should be %q but was %q.", input, expected, result)
+		}
+
+		originalPath, parsedHash, isFingerprinted := parseFingerprintedPath(result)
+		if !isFingerprinted || originalPath != input || parsedHash != hash {
+			t.Errorf("Parsing %q should return %q and %q but returned %q, %q and %t.", result, input, hash, originalPath, parsedHash, isFingerprinted)
+		}
+	}
+}
+
+func Test_parseFingerprintedPath_NoFingerprint_PathIsNotFingerprinted(t *testing.T) {
+	inputs := []string{"/theme/presentation.js", "/theme/jquery.tmpl.js", "files/photo.abcdef.jpg", "/theme/"}
+
+	for _, input := range inputs {
+		// act
+		_, _, isFingerprinted := parseFingerprintedPath(input)
+
+		// assert
+		if isFingerprinted {
+			t.Errorf("%q should not be a fingerprinted path.", input)
+		}
+	}
+}
+
+func Test_fingerprintAssetURLs_RelativeAndAbsoluteURLs_AssetURLsAreFingerprinted(t *testing.T) {
+	// arrange
+	hashes := map[string]string{
+		"/theme/screen.css":             hashutil.SHA1FromString("screen"),
+		"/documents/sample/files/a.png": hashutil.SHA1FromString("image"),
+	}
+
+	assetHashes := func(requestPath string) (string, bool) {
+		hash, found := hashes[requestPath]
+		return hash, found
+	}
+
+	input := `<head><base href="/documents/sample/"><link rel="stylesheet" href="/theme/screen.css"></head>` +
+		`<body><img src="files/a.png?size=small" alt="A"><a href="files/a.png">A</a><img src="http://example.com/files/a.png"></body>`
+
+	expected := `<head><base href="/documents/sample/"><link rel="stylesheet" href="/theme/screen.` + hashes["/theme/screen.css"] + `.css"></head>` +
+		`<body><img src="files/a.` + hashes["/documents/sample/files/a.png"] + `.png?size=small" alt="A"><a href="files/a.png">A</a><img src="http://example.com/files/a.png"></body>`
+
+	// act
+	result := fingerprintAssetURLs(input, "/documents/sample", assetHashes)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_FingerprintAssetURLs_AssetChanges_URLInRenderedOutputChanges(t *testing.T) {
+	// arrange
+	themeDirectory := newTestThemeDirectory(t, map[string]string{"presentation.js": "/* version 1 */"})
+	defer os.RemoveAll(themeDirectory)
+
+	page := newTestContentHandler(header.CONTENTTYPE_HTML, `<html><body><script src="/theme/presentation.js"></script></body></html>`)
+	handler := FingerprintAssetURLs(page, NewAssetHashProvider(themeDirectory, themes.GetTheme(), nil))
+
+	// act
+	firstURL := getPresentationScriptURL(t, serveRequest(handler, "/", "").Body.String())
+
+	if err := ioutil.WriteFile(filepath.Join(themeDirectory, "presentation.js"), []byte("/* version 2 */"), 0600); err != nil {
+		t.Fatalf("Could not change the theme file. Error: %s", err)
+	}
+
+	secondURL := getPresentationScriptURL(t, serveRequest(handler, "/", "").Body.String())
+
+	// assert
+	if firstURL == "/theme/presentation.js" || !strings.HasSuffix(firstURL, ".js") {
+		t.Errorf("The script URL should be fingerprinted but was %q.", firstURL)
+	}
+
+	if firstURL == secondURL {
+		t.Errorf("The script URL should change when the content of the script changes but was %q both times.", firstURL)
+	}
+}
+
+func Test_FingerprintAssetURLs_AssetChangesButPageIsUnchanged_ConditionalRequestReturnsNewPage(t *testing.T) {
+	// arrange
+	themeDirectory := newTestThemeDirectory(t, map[string]string{"presentation.js": "/* version 1 */"})
+	defer os.RemoveAll(themeDirectory)
+
+	code := `<html><body><script src="/theme/presentation.js"></script></body></html>`
+	page := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", header.CONTENTTYPE_HTML)
+		if writeETag(w, r, hashutil.FromString(code)) {
+			return
+		}
+
+		io.WriteString(w, code)
+	})
+
+	handler := FingerprintAssetURLs(page, NewAssetHashProvider(themeDirectory, themes.GetTheme(), nil))
+	firstResponse := serveRequest(handler, "/", "")
+	unchangedResponse := serveRequest(handler, "/", firstResponse.Header().Get("ETag"))
+
+	if err := ioutil.WriteFile(filepath.Join(themeDirectory, "presentation.js"), []byte("/* version 2 */"), 0600); err != nil {
+		t.Fatalf("Could not change the theme file. Error: %s", err)
+	}
+
+	// act
+	secondResponse := serveRequest(handler, "/", firstResponse.Header().Get("ETag"))
+
+	// assert
+	if unchangedResponse.Code != http.StatusNotModified {
+		t.Errorf("The status code should be %d as long as the asset is unchanged but was %d.", http.StatusNotModified, unchangedResponse.Code)
+	}
+
+	if secondResponse.Code != http.StatusOK {
+		t.Fatalf("The status code should be %d after the asset changed but was %d.", http.StatusOK, secondResponse.Code)
+	}
+
+	if getPresentationScriptURL(t, secondResponse.Body.String()) == getPresentationScriptURL(t, firstResponse.Body.String()) {
+		t.Errorf("The page should reference the new version of the script.")
+	}
+
+	if secondResponse.Header().Get("ETag") == firstResponse.Header().Get("ETag") {
+		t.Errorf("The ETag should change when a fingerprinted asset changes but was %s both times.", firstResponse.Header().Get("ETag"))
+	}
+}
+
+func Test_Theme_FingerprintedRequest_FileIsServedWithLongCacheLifetime(t *testing.T) {
+	// arrange
+	handler := newTestThemeHandler("")
+	requestPath := GetFingerprintedPath("/theme/presentation.js", hashutil.SHA1FromString(themefiles.PresentationJs))
+
+	// act
+	response := serveRequest(handler, requestPath, "")
+
+	// assert
+	if response.Code != http.StatusOK {
+		t.Fatalf("The status code should be %d but was %d.", http.StatusOK, response.Code)
+	}
+
+	if body := response.Body.String(); body != themefiles.PresentationJs {
+		t.Errorf("The response should contain the presentation script.")
+	}
+
+	if cacheControl := response.Header().Get("Cache-Control"); cacheControl != "public, max-age=31536000, immutable" {
+		t.Errorf("The response should be cacheable for a year but the Cache-Control header was %q.", cacheControl)
+	}
+}
+
+func Test_Theme_OutdatedFingerprint_RequestIsRedirectedToCurrentVersion(t *testing.T) {
+	// arrange
+	handler := newTestThemeHandler("")
+	requestPath := GetFingerprintedPath("/theme/presentation.js", hashutil.SHA1FromString("an older version"))
+	expectedLocation := GetFingerprintedPath("/theme/presentation.js", hashutil.SHA1FromString(themefiles.PresentationJs))
+
+	// act
+	response := serveRequest(handler, requestPath, "")
+
+	// assert
+	if response.Code != http.StatusFound {
+		t.Fatalf("The status code should be %d but was %d.", http.StatusFound, response.Code)
+	}
+
+	if location := response.Header().Get("Location"); location != expectedLocation {
+		t.Errorf("The request should be redirected to %q but was redirected to %q.", expectedLocation, location)
+	}
+}
+
+func getPresentationScriptURL(t *testing.T, html string) string {
+	match := presentationScriptPattern.FindStringSubmatch(html)
+	if match == nil {
+		t.Fatalf("The rendered output does not reference the presentation script: %q", html)
+	}
+
+	return match[1]
+}
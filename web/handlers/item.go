@@ -6,6 +6,8 @@ package handlers
 
 import (
 	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/common/util/hashutil"
 	"github.com/andreaskoch/allmark/web/header"
 	"github.com/andreaskoch/allmark/web/orchestrator"
 	"github.com/andreaskoch/allmark/web/view/templates"
@@ -21,22 +23,53 @@ func Item(logger logger.Logger,
 	templateProvider templates.Provider,
 	error404Handler http.Handler) http.Handler {
 
-	render := func(writer io.Writer, baseURL string, viewModel viewmodel.Model) {
+	render := func(baseURL string, viewModel viewmodel.Model) (code string, ok bool) {
 
 		// get a template
 		templateName := viewModel.Type
 		template, err := templateProvider.GetItemTemplate(templateName, baseURL)
 		if err != nil {
 			logger.Error("No template for item of type %q.", templateName)
-			return
+			return "", false
 		}
 
 		// render template
-		if err := renderTemplate(template, viewModel, writer); err != nil {
+		code, err = getRenderedCode(template, viewModel)
+		if err != nil {
 			logger.Error("%s", err)
+			return "", false
+		}
+
+		return code, true
+	}
+
+	// serveFile writes the supplied file; immutable files (e.g. fingerprinted files) can be cached forever.
+	serveFile := func(w http.ResponseWriter, r *http.Request, fileRoute route.Route, file viewmodel.File, immutable bool) {
+
+		logger.Debug("Returning file %q", fileRoute)
+
+		// set headers (http.ServeContent answers If-None-Match requests with 304 based on the ETag)
+		headerWriter.Write(w, file.MimeType)
+		if immutable {
+			header.CacheImmutable(w)
+		}
+
+		header.ETag(w, file.Hash)
+
+		// get the content provider
+		contentProvider := fileOrchestrator.GetFileContentProvider(fileRoute)
+		if contentProvider == nil {
+			logger.Error("There is no content provider for file %q", fileRoute)
 			return
 		}
 
+		filename := file.Name
+		lastModifiedTime := file.LastModified
+
+		contentProvider.Data(func(content io.ReadSeeker) error {
+			http.ServeContent(w, r, filename, lastModifiedTime, content)
+			return nil
+		})
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -51,43 +84,69 @@ func Item(logger logger.Logger,
 
 		logger.Debug("Requesting %q", requestRoute)
 
-		// stage 1: check if there is a item for the request
-		if model, found := viewModelOrchestrator.GetFullViewModel(requestRoute); found {
+		// read the page url-parameter
+		page, pageParameterIsAvailable := getPageParameterFromURL(*r.URL)
+		if !pageParameterIsAvailable {
+			page = 1
+		}
+
+		// stage 1: check if there is a item (or an additional page of the children of an item) for the request
+		model, found := viewModelOrchestrator.GetFullViewModel(requestRoute)
+		if itemRoute, pageNumber, isChildrenPage := orchestrator.GetChildrenPageRoute(requestRoute); !found && isChildrenPage && pageNumber > 1 {
+			model, found = viewModelOrchestrator.GetFullViewModel(itemRoute)
+			page = pageNumber
+		}
+
+		if found {
+
+			// display error 404 if a non-existing page of children has been requested
+			model, pageFound := viewModelOrchestrator.GetChildrenPage(model, page)
+			if !pageFound {
+				error404Handler.ServeHTTP(w, r)
+				return
+			}
 
 			logger.Debug("Returning item %q", requestRoute)
 
-			// set headers
+			code, ok := render(baseURL, model)
+			if !ok {
+				return
+			}
+
+			// set headers (the page contains the navigation, tags, related items and comments,
+			// so the etag cache validator is the hash of the rendered page and not the hash of the item)
 			headerWriter.Write(w, header.CONTENTTYPE_HTML)
-			header.ETag(w, model.Hash)
+			if writeETag(w, r, hashutil.FromString(code)) {
+				logger.Debug("Item %q has not been modified", requestRoute)
+				return
+			}
 
-			render(w, baseURL, model)
+			io.WriteString(w, code)
 			return
 		}
 
 		// stage 2: check if there is a file for the request
 		if file, found := fileOrchestrator.GetFile(requestRoute); found {
+			serveFile(w, r, requestRoute, file, false)
+			return
+		}
 
-			logger.Debug("Returning file %q", requestRoute)
-
-			// set  headers
-			headerWriter.Write(w, file.MimeType)
-			header.ETag(w, file.Hash)
-
-			// get the content provider
-			contentProvider := fileOrchestrator.GetFileContentProvider(requestRoute)
-			if contentProvider == nil {
-				logger.Error("There is no content provider for file %q", requestRoute)
+		// stage 3: check if there is a file for the fingerprinted request (e.g. "files/photo.<hash>.jpg")
+		if originalPath, hash, isFingerprinted := parseFingerprintedPath(requestRoute.Value()); isFingerprinted {
+			fileRoute := route.NewFromRequest(originalPath)
+			if currentHash, found := fileOrchestrator.GetFileHash(fileRoute); found {
+
+				// redirect outdated fingerprints to the current version of the file
+				if currentHash != hash {
+					http.Redirect(w, r, GetFingerprintedPath("/"+fileRoute.Value(), currentHash), http.StatusFound)
+					return
+				}
+
+				if file, found := fileOrchestrator.GetFile(fileRoute); found {
+					serveFile(w, r, fileRoute, file, true)
+					return
+				}
 			}
-
-			filename := file.Name
-			lastModifiedTime := file.LastModified
-
-			contentProvider.Data(func(content io.ReadSeeker) error {
-				http.ServeContent(w, r, filename, lastModifiedTime, content)
-				return nil
-			})
-
-			return
 		}
 
 		logger.Debug("No item or file found for route %q", requestRoute)
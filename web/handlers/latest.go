@@ -44,8 +44,8 @@ func Latest(logger logger.Logger, headerWriter header.HeaderWriter, viewModelOrc
 
 			// etag cache validator
 			etag := hashutil.FromBytes(jsonBytes)
-			if etag != "" {
-				header.ETag(w, etag)
+			if writeETag(w, r, etag) {
+				return
 			}
 
 			w.Write(jsonBytes)
@@ -19,9 +19,9 @@ func Markdown(headerWriter header.HeaderWriter, viewModelOrchestrator *orchestra
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		// set headers
-		headerWriter.Write(w, header.CONTENTTYPE_JSON)
+		headerWriter.Write(w, header.CONTENTTYPE_MARKDOWN)
 
-		// strip the "md" or ".md" suffix from the path
+		// strip the "markdown" or ".markdown" suffix from the path
 		path := r.URL.Path
 		path = strings.TrimSuffix(path, "markdown")
 		path = strings.TrimSuffix(path, ".")
@@ -0,0 +1,83 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/orchestrator"
+	"github.com/andreaskoch/allmark/web/view/templates"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// MessagePermalink creates a http handler which redirects message permalinks to their messages.
+func MessagePermalink(
+	headerWriter header.HeaderWriter,
+	messageOrchestrator *orchestrator.MessageOrchestrator,
+	fallbackHandler http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// strip the leading slash from the path (e.g. "/message/20150301-153000")
+		permalink := strings.TrimPrefix(r.URL.Path, "/")
+
+		messageRoute, found := messageOrchestrator.GetMessageRoute(permalink)
+		if !found {
+			fallbackHandler.ServeHTTP(w, r)
+			return
+		}
+
+		redirectURL := getBaseURLFromRequest(r) + "/" + messageRoute.Value()
+		http.Redirect(w, r, redirectURL, http.StatusMovedPermanently)
+	})
+
+}
+
+// Timeline creates a http handler which displays all messages in chronological order (newest first).
+func Timeline(
+	headerWriter header.HeaderWriter,
+	navigationOrchestrator *orchestrator.NavigationOrchestrator,
+	messageOrchestrator *orchestrator.MessageOrchestrator,
+	templateProvider templates.Provider) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// set headers
+		headerWriter.Write(w, header.CONTENTTYPE_HTML)
+
+		hostname := getBaseURLFromRequest(r)
+
+		timelineTemplate, err := templateProvider.GetTimelineTemplate(hostname)
+		if err != nil {
+			fmt.Fprintf(w, "Template not found. Error: %s", err)
+			return
+		}
+
+		// assemble the base view model
+		title := "Timeline"
+		description := "All messages of this repository, newest first."
+		viewModel := viewmodel.Model{}
+
+		viewModel.Type = "timeline"
+		viewModel.Title = title
+		viewModel.Description = description
+		viewModel.PageTitle = messageOrchestrator.GetPageTitle(title)
+		viewModel.ToplevelNavigation = navigationOrchestrator.GetToplevelNavigation()
+		viewModel.BreadcrumbNavigation = navigationOrchestrator.GetBreadcrumbNavigation(route.New())
+
+		// assemble the timeline viewmodel
+		timelineViewModel := viewmodel.Timeline{}
+		timelineViewModel.Model = viewModel
+		timelineViewModel.Messages = messageOrchestrator.GetTimeline()
+
+		renderTemplate(timelineTemplate, timelineViewModel, w)
+
+	})
+
+}
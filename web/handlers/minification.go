@@ -0,0 +1,46 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/andreaskoch/allmark/web/minify"
+)
+
+// getMinifier returns the minifier for the supplied content type (e.g. "text/html; charset=utf-8")
+// or nil if responses of this type are not minified.
+func getMinifier(contentType string) rewriter {
+	switch getMediaType(contentType) {
+	case "text/html":
+		return minify.HTML
+
+	case "text/css":
+		return minify.CSS
+
+	case "text/javascript", "application/javascript", "application/x-javascript":
+		return minify.JS
+	}
+
+	return nil
+}
+
+// getMediaType returns the lower-case media type of the supplied content type (e.g. "text/html; charset=utf-8" -> "text/html").
+func getMediaType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+}
+
+// MinifyResponses removes comments and redundant whitespace from all HTML, CSS and JavaScript
+// responses of the supplied handler. All other responses are passed through unchanged.
+func MinifyResponses(baseHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		minifyWriter := newRewritingResponseWriter(w, r, getMinifier)
+		defer minifyWriter.Close()
+
+		baseHandler.ServeHTTP(minifyWriter, r)
+	})
+}
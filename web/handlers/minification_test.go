@@ -0,0 +1,68 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andreaskoch/allmark/web/header"
+)
+
+func getMinifiedResponse(handler http.Handler) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/document", nil)
+	MinifyResponses(handler).ServeHTTP(response, request)
+	return response
+}
+
+func Test_MinifyResponses_HTML_ResponseIsMinified(t *testing.T) {
+	// arrange
+	handler := newTestContentHandler(header.CONTENTTYPE_HTML, "<html>\n  <body>\n    <p>Text</p>\n  </body>\n</html>\n")
+	expected := "<html> <body> <p>Text</p> </body> </html>"
+
+	// act
+	response := getMinifiedResponse(handler)
+
+	// assert
+	if body := response.Body.String(); body != expected {
+		t.Errorf("The body should be %q but was %q.", expected, body)
+	}
+}
+
+func Test_MinifyResponses_Image_ResponseIsNotChanged(t *testing.T) {
+	// arrange
+	body := "\x89PNG\r\n\x1a\n  some   binary  \n data"
+	handler := newTestContentHandler("image/png", body)
+
+	// act
+	response := getMinifiedResponse(handler)
+
+	// assert
+	if response.Body.String() != body {
+		t.Errorf("The body should be %q but was %q.", body, response.Body.String())
+	}
+}
+
+func Test_MinifyResponses_NotModified_NoBodyIsWritten(t *testing.T) {
+	// arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", header.CONTENTTYPE_HTML)
+		w.WriteHeader(http.StatusNotModified)
+	})
+
+	// act
+	response := getMinifiedResponse(handler)
+
+	// assert
+	if response.Code != http.StatusNotModified {
+		t.Errorf("The status code should be %d but was %d.", http.StatusNotModified, response.Code)
+	}
+
+	if response.Body.Len() != 0 {
+		t.Errorf("The body should be empty but was %q.", response.Body.String())
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/common/util/fsutil"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/view/templates"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// A PresentationConversionModelProvider returns the conversion models of presentations.
+type PresentationConversionModelProvider interface {
+	GetPresentationConversionModel(baseURL string, route route.Route) (viewmodel.PresentationConversionModel, bool)
+}
+
+// PresentationPDF creates a handler which converts presentations into PDF documents with one page per slide.
+// Requests for items which are not presentations (e.g. PDF files) are passed to the fallback handler,
+// failed conversions to the error handler.
+func PresentationPDF(logger logger.Logger,
+	conversionToolPath string,
+	conversionEndpointHostname string,
+	headerWriter header.HeaderWriter,
+	converterModelOrchestrator PresentationConversionModelProvider,
+	templateProvider templates.Provider,
+	fallbackHandler http.Handler,
+	errorHandler http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// strip the "pdf" or ".pdf" suffix from the path
+		path := r.URL.Path
+		path = strings.TrimSuffix(path, "pdf")
+		path = strings.TrimSuffix(path, ".")
+
+		// get the request route
+		requestRoute := route.NewFromRequest(path)
+
+		// make sure the request body is closed
+		defer r.Body.Close()
+
+		// make sure wkhtmltopdf only performs local requests (via HTTP)
+		baseURL := getBaseURLFromRequest(r)
+		baseURL = strings.Replace(baseURL, "https://", "http://", 1)
+		baseURL = strings.Replace(baseURL, r.Host, conversionEndpointHostname, 1)
+
+		model, found := converterModelOrchestrator.GetPresentationConversionModel(baseURL, requestRoute)
+		if !found {
+			fallbackHandler.ServeHTTP(w, r)
+			return
+		}
+
+		// render the slides
+		template, err := templateProvider.GetPresentationConversionTemplate(baseURL)
+		if err != nil {
+			logger.Error("No presentation conversion template found. Error: %s", err.Error())
+			errorHandler.ServeHTTP(w, r)
+			return
+		}
+
+		html, err := getRenderedCode(template, model)
+		if err != nil {
+			logger.Error("%s", err)
+			errorHandler.ServeHTTP(w, r)
+			return
+		}
+
+		// the temporary working directory
+		targetDirectory := fsutil.GetTempDirectory()
+		defer func() {
+			logger.Debug("Deleting conversion file directory: %q", targetDirectory)
+			if err := deleteFile(targetDirectory); err != nil {
+				logger.Error("Could not delete the temporary working directory (%q) that has been created during the conversion. Error: %s", targetDirectory, err.Error())
+			}
+		}()
+
+		pdfFilePath, err := convertHTMLToPDF(conversionToolPath, html, targetDirectory)
+		if err != nil {
+			logger.Error("Could not convert presentation %q to PDF. Error: %s", requestRoute, err.Error())
+			errorHandler.ServeHTTP(w, r)
+			return
+		}
+
+		pdfFile, err := fsutil.OpenFile(pdfFilePath)
+		if err != nil {
+			logger.Error("Cannot open target file. Error: %s", err.Error())
+			errorHandler.ServeHTTP(w, r)
+			return
+		}
+
+		defer pdfFile.Close()
+
+		headerWriter.Write(w, header.CONTENTTYPE_PDF)
+		w.Header().Add("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, getTargetFilename(model.ConversionModel, "pdf")))
+
+		io.Copy(w, pdfFile)
+	})
+
+}
+
+// convertHTMLToPDF converts the supplied HTML document into a landscape PDF document
+// using the given conversion tool (wkhtmltopdf) and returns the path of the PDF file in the target directory.
+func convertHTMLToPDF(conversionToolPath, html, targetDirectory string) (pdfFilePath string, err error) {
+
+	// Note: the file extensions are important for wkhtmltopdf
+	htmlFilePath := filepath.Join(targetDirectory, "source.html")
+	pdfFilePath = filepath.Join(targetDirectory, "target.pdf")
+
+	if err := ioutil.WriteFile(htmlFilePath, []byte(html), 0600); err != nil {
+		return "", fmt.Errorf("Cannot write the HTML file %q. Error: %s", htmlFilePath, err.Error())
+	}
+
+	args := []string{
+		"--quiet",
+		"--print-media-type",
+		"--orientation", "Landscape",
+		htmlFilePath,
+		pdfFilePath,
+	}
+
+	cmd := exec.Command(conversionToolPath, args...)
+	cmd.Dir = targetDirectory
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("Could not run %s: %v (%s)", conversionToolPath, err, strings.TrimSpace(string(output)))
+	}
+
+	return pdfFilePath, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/view/templates"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// pdfPagePattern matches the page objects of a PDF document (but not the page tree "/Type /Pages").
+var pdfPagePattern = regexp.MustCompile(`/Type\s*/Page[^s]`)
+
+func Test_convertHTMLToPDF_PresentationWithThreeSlides_PDFHasThreePages(t *testing.T) {
+	toolPath, err := exec.LookPath("wkhtmltopdf")
+	if err != nil {
+		t.Skip("wkhtmltopdf is not installed.")
+	}
+
+	// arrange
+	model := viewmodel.PresentationConversionModel{
+		Slides: []string{"<h1>First</h1>", "<h2>Second</h2>", "<h2>Third</h2>"},
+	}
+	model.Title = "Presentation"
+
+	templateProvider := templates.NewProvider("")
+	template, err := templateProvider.GetPresentationConversionTemplate("localhost")
+	if err != nil {
+		t.Fatalf("The presentation conversion template should be available. Error: %s", err)
+	}
+
+	html, err := getRenderedCode(template, model)
+	if err != nil {
+		t.Fatalf("The presentation should be rendered. Error: %s", err)
+	}
+
+	targetDirectory, _ := ioutil.TempDir("", "allmark-pdf-test")
+	defer os.RemoveAll(targetDirectory)
+
+	// act
+	pdfFilePath, err := convertHTMLToPDF(toolPath, html, targetDirectory)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The conversion should succeed. Error: %s", err)
+	}
+
+	pdf, _ := ioutil.ReadFile(pdfFilePath)
+	if pages := len(pdfPagePattern.FindAll(pdf, -1)); pages != 3 {
+		t.Errorf("The PDF should have 3 pages (one per slide) but had %d.", pages)
+	}
+}
+
+// A testPresentationProvider returns a conversion model with a single slide for every route.
+type testPresentationProvider struct{}
+
+func (provider testPresentationProvider) GetPresentationConversionModel(baseURL string, route route.Route) (viewmodel.PresentationConversionModel, bool) {
+	model := viewmodel.PresentationConversionModel{
+		Slides: []string{"<h1>First</h1>"},
+	}
+	model.Title = "Presentation"
+
+	return model, true
+}
+
+func Test_PresentationPDF_ConversionFails_ErrorPageIsRenderedWithStatus500(t *testing.T) {
+	// arrange
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	templateProvider := templates.NewProvider("")
+	errorHandler := InternalServerError(headerWriterFactory.NoCache(), templateProvider, testNavigationProvider{})
+
+	handler := PresentationPDF(console.New(loglevel.Fatal),
+		filepath.Join(os.TempDir(), "allmark-missing-conversion-tool"),
+		"localhost",
+		headerWriterFactory.NoCache(),
+		testPresentationProvider{},
+		templateProvider,
+		http.NotFoundHandler(),
+		errorHandler)
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/presentations/slides.pdf", nil)
+
+	// act
+	handler.ServeHTTP(response, request)
+
+	// assert
+	if response.Code != http.StatusInternalServerError {
+		t.Errorf("The status code should be %d but was %d.", http.StatusInternalServerError, response.Code)
+	}
+
+	if body := response.Body.String(); !strings.Contains(body, "The request could not be processed.") {
+		t.Errorf("The response should contain the error page of the theme but was %q.", body)
+	}
+}
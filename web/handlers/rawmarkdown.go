@@ -0,0 +1,114 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/header"
+)
+
+const (
+	// rawMarkdownSuffix is the suffix of the item URLs which return the markdown source (e.g. "/documents/sample.md").
+	rawMarkdownSuffix = ".md"
+
+	// rawMarkdownParameter is the url-parameter which requests the markdown source (e.g. "/documents/sample?raw=1").
+	rawMarkdownParameter = "raw"
+)
+
+// RawMarkdown creates a handler which returns the markdown source of the requested item instead of the rendered page
+// if the client prefers markdown ("Accept: text/markdown"), the url-parameter "raw=1" is set or the item URL has a ".md" suffix.
+// All other requests (and requests for items which do not exist or are not published) are passed to the item handler.
+func RawMarkdown(logger logger.Logger, headerWriter header.HeaderWriter, itemProvider ItemProvider, itemHandler http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		requestRoute := getRouteFromRequest(r)
+		item, found := itemProvider.GetItem(requestRoute)
+
+		// the ".md" suffix only applies if there is no item (or file) with this name
+		isRawRequest := r.URL.Query().Get(rawMarkdownParameter) == "1" || prefersMarkdown(r.Header.Get("Accept"))
+		if !found && strings.HasSuffix(r.URL.Path, rawMarkdownSuffix) {
+			itemRoute := route.NewFromRequest(strings.TrimSuffix(r.URL.Path, rawMarkdownSuffix))
+			item, found = itemProvider.GetItem(itemRoute)
+			isRawRequest = found
+		}
+
+		if !found {
+			itemHandler.ServeHTTP(w, r)
+			return
+		}
+
+		// the representation of the item depends on the Accept header
+		header.VaryAccept(w)
+
+		if !isRawRequest {
+			itemHandler.ServeHTTP(w, r)
+			return
+		}
+
+		logger.Debug("Returning the markdown of item %q", item)
+
+		headerWriter.Write(w, header.CONTENTTYPE_MARKDOWN)
+		if writeETag(w, r, item.Hash+"-markdown") {
+			return
+		}
+
+		w.Write([]byte(item.Markdown))
+	})
+
+}
+
+// prefersMarkdown returns true if the supplied Accept header ranks markdown ("text/markdown") higher than HTML
+// (e.g. "text/markdown", "text/markdown, text/html;q=0.9" but not "text/html, */*").
+func prefersMarkdown(accept string) bool {
+	qualities := make(map[string]float64)
+
+	for _, value := range strings.Split(accept, ",") {
+		components := strings.Split(value, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(components[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, parameter := range components[1:] {
+			parameter = strings.TrimSpace(parameter)
+			if !strings.HasPrefix(parameter, "q=") {
+				continue
+			}
+
+			if parsedQuality, err := strconv.ParseFloat(strings.TrimPrefix(parameter, "q="), 64); err == nil {
+				quality = parsedQuality
+			}
+		}
+
+		qualities[mediaType] = quality
+	}
+
+	markdownQuality, isListed := qualities["text/markdown"]
+	if !isListed {
+		markdownQuality, isListed = qualities["text/x-markdown"]
+	}
+
+	if !isListed || markdownQuality <= 0 {
+		return false
+	}
+
+	// HTML is accepted explicitly or by a wildcard
+	htmlQuality := 0.0
+	for _, mediaType := range []string{"text/html", "text/*", "*/*"} {
+		if quality, isListed := qualities[mediaType]; isListed {
+			htmlQuality = quality
+			break
+		}
+	}
+
+	return markdownQuality > htmlQuality
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/web/header"
+)
+
+const testInstallGuideMarkdown = "# Install\n\nRun `make install`.\n"
+
+// getRawMarkdownResponse requests the supplied path with the given Accept header.
+// The item handler behind the raw markdown handler answers with an HTML page.
+func getRawMarkdownResponse(path, accept string) *httptest.ResponseRecorder {
+	itemProvider := newTestItemProvider()
+	itemProvider.items["guides/install"].Markdown = testInstallGuideMarkdown
+
+	itemHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", header.CONTENTTYPE_HTML)
+		fmt.Fprintf(w, "<html>item page</html>")
+	})
+
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	handler := RawMarkdown(console.New(loglevel.Fatal), headerWriterFactory.NoCache(), itemProvider, itemHandler)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+
+	handler.ServeHTTP(response, request)
+
+	return response
+}
+
+func Test_RawMarkdown_BrowserRequest_HTMLIsReturned(t *testing.T) {
+	// act
+	response := getRawMarkdownResponse("/guides/install", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	// assert
+	if body := response.Body.String(); body != "<html>item page</html>" {
+		t.Errorf("The rendered page should have been returned but the response was %q.", body)
+	}
+
+	if contentType := response.Header().Get("Content-Type"); contentType != header.CONTENTTYPE_HTML {
+		t.Errorf("The content type should be %q but was %q.", header.CONTENTTYPE_HTML, contentType)
+	}
+
+	if vary := response.Header().Get("Vary"); vary != "Accept" {
+		t.Errorf("The Vary header should be %q but was %q.", "Accept", vary)
+	}
+}
+
+func Test_RawMarkdown_NoAcceptHeader_HTMLIsReturned(t *testing.T) {
+	// act
+	response := getRawMarkdownResponse("/guides/install", "")
+
+	// assert
+	if body := response.Body.String(); body != "<html>item page</html>" {
+		t.Errorf("The rendered page should have been returned but the response was %q.", body)
+	}
+}
+
+func Test_RawMarkdown_MarkdownRequested_SourceIsReturned(t *testing.T) {
+	// arrange
+	requests := []struct {
+		path   string
+		accept string
+	}{
+		{"/guides/install", "text/markdown"},
+		{"/guides/install", "text/html;q=0.5, text/markdown"},
+		{"/guides/install?raw=1", ""},
+		{"/guides/install.md", ""},
+	}
+
+	for _, request := range requests {
+
+		// act
+		response := getRawMarkdownResponse(request.path, request.accept)
+
+		// assert
+		if response.Code != http.StatusOK {
+			t.Errorf("The status code for %q (Accept: %q) should be %d but was %d.", request.path, request.accept, http.StatusOK, response.Code)
+		}
+
+		if body := response.Body.String(); body != testInstallGuideMarkdown {
+			t.Errorf("The markdown source should have been returned for %q (Accept: %q) but the response was %q.", request.path, request.accept, body)
+		}
+
+		if contentType := response.Header().Get("Content-Type"); contentType != header.CONTENTTYPE_MARKDOWN {
+			t.Errorf("The content type for %q (Accept: %q) should be %q but was %q.", request.path, request.accept, header.CONTENTTYPE_MARKDOWN, contentType)
+		}
+	}
+}
+
+func Test_RawMarkdown_UnknownItem_RequestIsPassedToTheItemHandler(t *testing.T) {
+	// arrange
+	paths := []string{"/drafts/unpublished.md", "/drafts/unpublished?raw=1", "/guides/files/notes.md"}
+
+	for _, path := range paths {
+
+		// act
+		response := getRawMarkdownResponse(path, "text/markdown")
+
+		// assert
+		if body := response.Body.String(); body != "<html>item page</html>" {
+			t.Errorf("The request for %q should have been passed to the item handler but the response was %q.", path, body)
+		}
+	}
+}
+
+func Test_prefersMarkdown(t *testing.T) {
+	// arrange
+	inputs := map[string]bool{
+		"":                                  false,
+		"*/*":                               false,
+		"text/html, */*;q=0.8":              false,
+		"text/markdown":                     true,
+		"text/x-markdown":                   true,
+		"text/markdown;q=0":                 false,
+		"text/markdown;q=0.5, text/html":    false,
+		"text/html;q=0.5, text/markdown":    true,
+		"text/markdown, */*;q=0.1":          true,
+		"TEXT/MARKDOWN; charset=utf-8, */*": false,
+		"text/markdown; charset=utf-8; q=1": true,
+	}
+
+	for accept, expected := range inputs {
+
+		// act
+		result := prefersMarkdown(accept)
+
+		// assert
+		if result != expected {
+			t.Errorf("prefersMarkdown(%q) returned %t but expected %t.", accept, result, expected)
+		}
+	}
+}
@@ -6,6 +6,7 @@ package handlers
 
 import (
 	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/model"
 	"net/http"
 )
 
@@ -20,3 +21,28 @@ func Redirect(logger logger.Logger, baseURITarget string) http.Handler {
 	})
 
 }
+
+// ItemRedirect creates a handler which redirects requests for redirect items (e.g. "redirect.md")
+// to their target. All other requests and redirects with an invalid target are passed to the item handler.
+func ItemRedirect(logger logger.Logger, itemProvider ItemProvider, itemHandler http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		item, found := itemProvider.GetItem(getRouteFromRequest(r))
+		if !found || item.Type != model.TypeRedirect {
+			itemHandler.ServeHTTP(w, r)
+			return
+		}
+
+		target, statusCode, err := item.RedirectTarget()
+		if err != nil {
+			logger.Warn("%s", err.Error())
+			itemHandler.ServeHTTP(w, r)
+			return
+		}
+
+		logger.Debug("Redirecting %q to %q (%d)", item, target, statusCode)
+		http.Redirect(w, r, target, statusCode)
+	})
+
+}
@@ -0,0 +1,106 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// getItemRedirectResponse requests the "old" item which is a redirect with the supplied blocks.
+// The item handler behind the redirect handler answers with "item page".
+func getItemRedirectResponse(blocks ...string) *httptest.ResponseRecorder {
+	itemProvider := newTestItemProvider()
+
+	redirect := model.NewItem(route.NewFromRequest("old"), nil, dataaccess.TypePhysical)
+	redirect.Type = model.TypeRedirect
+	for i := 0; i < len(blocks); i += 2 {
+		redirect.MetaData.AddBlock(blocks[i], blocks[i+1])
+	}
+
+	itemProvider.items["old"] = redirect
+
+	itemHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "item page")
+	})
+
+	handler := ItemRedirect(console.New(loglevel.Fatal), itemProvider, itemHandler)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/old", nil)
+	handler.ServeHTTP(response, request)
+
+	return response
+}
+
+func Test_ItemRedirect_NoStatus_MovedPermanently(t *testing.T) {
+	// act
+	response := getItemRedirectResponse("target", "/guides/install")
+
+	// assert
+	if response.Code != http.StatusMovedPermanently {
+		t.Errorf("The status code should be %d but was %d.", http.StatusMovedPermanently, response.Code)
+	}
+
+	if location := response.Header().Get("Location"); location != "/guides/install" {
+		t.Errorf("The Location header should be %q but was %q.", "/guides/install", location)
+	}
+}
+
+func Test_ItemRedirect_Status302_Found(t *testing.T) {
+	// act
+	response := getItemRedirectResponse("target", "https://example.com/docs", "status", "302")
+
+	// assert
+	if response.Code != http.StatusFound {
+		t.Errorf("The status code should be %d but was %d.", http.StatusFound, response.Code)
+	}
+
+	if location := response.Header().Get("Location"); location != "https://example.com/docs" {
+		t.Errorf("The Location header should be %q but was %q.", "https://example.com/docs", location)
+	}
+}
+
+func Test_ItemRedirect_MissingTarget_ItemPageIsRendered(t *testing.T) {
+	// act
+	response := getItemRedirectResponse()
+
+	// assert
+	if response.Code != http.StatusOK {
+		t.Errorf("The status code should be %d but was %d.", http.StatusOK, response.Code)
+	}
+
+	if body := response.Body.String(); body != "item page" {
+		t.Errorf("The item page should be rendered but the body was %q.", body)
+	}
+}
+
+func Test_ItemRedirect_NoRedirectItem_ItemPageIsRendered(t *testing.T) {
+	// arrange
+	itemHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "item page")
+	})
+
+	handler := ItemRedirect(console.New(loglevel.Fatal), newTestItemProvider(), itemHandler)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/guides", nil)
+
+	// act
+	handler.ServeHTTP(response, request)
+
+	// assert
+	if response.Code != http.StatusOK || response.Body.String() != "item page" {
+		t.Errorf("The item page should be rendered but the response was %d %q.", response.Code, response.Body.String())
+	}
+}
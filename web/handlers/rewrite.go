@@ -0,0 +1,157 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+	"github.com/andreaskoch/allmark/web/header"
+)
+
+// A rewriter changes the body of a response (e.g. minifies it).
+type rewriter func(body string) string
+
+// A rewritingResponseWriter buffers the responses which are rewritten until the handler has finished.
+// The rewriter of a response is determined by the Content-Type header when the first data is written.
+// The ETag of a rewritten response is replaced by the hash of the rewritten body.
+type rewritingResponseWriter struct {
+	http.ResponseWriter
+
+	// request is the request which is answered (for the conditional requests of rewritten responses)
+	request *http.Request
+
+	// getRewriter returns the rewriter for the given content type or nil if the response is passed through
+	getRewriter func(contentType string) rewriter
+
+	statusCode  int
+	wroteHeader bool
+
+	// rewriter is set if the current response is buffered and rewritten
+	rewriter rewriter
+	buffer   []byte
+
+	// started is true as soon as the headers have been written to the underlying response writer
+	started bool
+}
+
+func newRewritingResponseWriter(w http.ResponseWriter, r *http.Request, getRewriter func(contentType string) rewriter) *rewritingResponseWriter {
+	return &rewritingResponseWriter{
+		ResponseWriter: w,
+		request:        r,
+		getRewriter:    getRewriter,
+		statusCode:     http.StatusOK,
+	}
+}
+
+func (w *rewritingResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.statusCode = statusCode
+}
+
+func (w *rewritingResponseWriter) Write(data []byte) (int, error) {
+	if !w.started && w.rewriter == nil {
+		w.decide(data)
+	}
+
+	if w.rewriter != nil {
+		w.buffer = append(w.buffer, data...)
+		return len(data), nil
+	}
+
+	return w.ResponseWriter.Write(data)
+}
+
+// decide passes the response through or starts buffering it depending on its content type.
+func (w *rewritingResponseWriter) decide(data []byte) {
+	header := w.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(data))
+	}
+
+	rewriter := w.getRewriter(header.Get("Content-Type"))
+	if rewriter == nil || !bodyAllowedForStatus(w.statusCode) || w.statusCode == http.StatusPartialContent || header.Get("Content-Encoding") != "" {
+		w.start()
+		return
+	}
+
+	w.rewriter = rewriter
+}
+
+// start writes the headers to the underlying response writer.
+func (w *rewritingResponseWriter) start() {
+	w.started = true
+	w.ResponseWriter.WriteHeader(w.statusCode)
+}
+
+// Flush writes the response to the client unless it is being rewritten.
+func (w *rewritingResponseWriter) Flush() {
+	if w.rewriter != nil {
+		return
+	}
+
+	if !w.started {
+		w.start()
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the caller take over the connection (e.g. for websockets).
+func (w *rewritingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("The response writer does not support hijacking.")
+	}
+
+	w.started = true
+	return hijacker.Hijack()
+}
+
+// Close rewrites and writes the buffered response.
+func (w *rewritingResponseWriter) Close() error {
+	if w.rewriter == nil {
+		if !w.started {
+			w.start()
+		}
+
+		return nil
+	}
+
+	originalBody := string(w.buffer)
+	body := w.rewriter(originalBody)
+	w.buffer = nil
+	w.rewriter = nil
+
+	w.Header().Del("Content-Length")
+
+	// the etag of the handler is the hash of the original body (e.g. without the asset fingerprints),
+	// so it is replaced and conditional requests are answered for the rewritten body
+	if w.Header().Get("ETag") != "" && body != originalBody {
+		hash := hashutil.FromString(body)
+		header.ETag(w, hash)
+
+		if w.statusCode == http.StatusOK && header.IsNotModified(w.request, hash) {
+			w.Header().Del("Content-Type")
+			w.statusCode = http.StatusNotModified
+			w.start()
+			return nil
+		}
+	}
+
+	w.start()
+
+	_, err := w.ResponseWriter.Write([]byte(body))
+	return err
+}
@@ -0,0 +1,33 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/orchestrator"
+)
+
+// SearchIndex returns a http handler which writes the index for the client-side search.
+func SearchIndex(headerWriter header.HeaderWriter, searchIndexOrchestrator *orchestrator.SearchIndexOrchestrator) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// set headers
+		headerWriter.Write(w, header.CONTENTTYPE_JSON)
+
+		// convert to json
+		bytes, err := json.Marshal(searchIndexOrchestrator.GetSearchIndex())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Write(bytes)
+	})
+
+}
@@ -7,6 +7,7 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/web/header"
@@ -53,3 +54,80 @@ func Tags(headerWriter header.HeaderWriter,
 		renderTemplate(tagmapTemplate, tagsPageModel, w)
 	})
 }
+
+// Tag creates a http handler which displays all items carrying a single tag.
+func Tag(headerWriter header.HeaderWriter,
+	navigationOrchestrator *orchestrator.NavigationOrchestrator,
+	tagsOrchestrator *orchestrator.TagsOrchestrator,
+	templateProvider templates.Provider,
+	error404Handler http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		tag, found := tagsOrchestrator.GetTag(getTagNameFromRequest(r))
+		if !found {
+			error404Handler.ServeHTTP(w, r)
+			return
+		}
+
+		// set headers
+		headerWriter.Write(w, header.CONTENTTYPE_HTML)
+
+		hostname := getBaseURLFromRequest(r)
+
+		tagmapTemplate, err := templateProvider.GetTagMapTemplate(hostname)
+		if err != nil {
+			fmt.Fprintf(w, "Template not found. Error: %s", err)
+			return
+		}
+
+		// Page parameters
+		pageType := "tagmap"
+		headline := fmt.Sprintf("Tag: %s", tag.Name)
+		pageTitle := tagsOrchestrator.GetPageTitle(headline)
+
+		pageModel := viewmodel.Model{}
+		pageModel.Type = pageType
+		pageModel.Title = headline
+		pageModel.PageTitle = pageTitle
+		pageModel.ToplevelNavigation = navigationOrchestrator.GetToplevelNavigation()
+		pageModel.BreadcrumbNavigation = navigationOrchestrator.GetBreadcrumbNavigation(route.New())
+		pageModel.TagCloud = tagsOrchestrator.GetTagCloud()
+
+		tagsPageModel := viewmodel.Tags{}
+		tagsPageModel.Model = pageModel
+		tagsPageModel.Tags = []viewmodel.Tag{tag}
+
+		renderTemplate(tagmapTemplate, tagsPageModel, w)
+	})
+}
+
+// TagsOPML returns a http handler which renders an OPML outline of all tags and the items carrying them.
+// The URLs are prefixed with the supplied base URL or, if it is empty, with the base URL of the request.
+func TagsOPML(headerWriter header.HeaderWriter,
+	baseURL string,
+	tagsOrchestrator *orchestrator.TagsOrchestrator,
+	templateProvider templates.Provider) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// set headers
+		headerWriter.Write(w, header.CONTENTTYPE_OPML)
+
+		hostname := getBaseURL(baseURL, r)
+
+		opmlTemplate, err := templateProvider.GetTagsOPMLTemplate(hostname)
+		if err != nil {
+			fmt.Fprintf(w, "Template not found. Error: %s", err)
+			return
+		}
+
+		renderTemplate(opmlTemplate, tagsOrchestrator.GetTagsOPML(hostname), w)
+	})
+}
+
+// getTagNameFromRequest returns the name of the tag of the supplied request (e.g. "/tags/c++" -> "c++", "/tags/my%20tag" -> "my tag").
+// The tag URLs are path-escaped and the path of the request URL is already unescaped.
+func getTagNameFromRequest(r *http.Request) string {
+	return strings.TrimPrefix(r.URL.Path, TagPathPrefix)
+}
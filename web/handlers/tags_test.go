@@ -0,0 +1,31 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func Test_getTagNameFromRequest_PathEscapedTagURLs_TagNameIsReturned(t *testing.T) {
+	// arrange
+	tagNames := []string{"go", "c++", "c#", "my tag", "50%", "a+b=c", "ci/cd", "über"}
+
+	for _, tagName := range tagNames {
+		request, err := http.NewRequest("GET", "http://example.com"+TagPathPrefix+url.PathEscape(tagName), nil)
+		if err != nil {
+			t.Fatalf("The request for tag %q could not be created. Error: %s", tagName, err)
+		}
+
+		// act
+		result := getTagNameFromRequest(request)
+
+		// assert
+		if result != tagName {
+			t.Errorf("The tag name of %q should be %q but was %q.", request.URL.String(), tagName, result)
+		}
+	}
+}
@@ -5,37 +5,67 @@
 package handlers
 
 import (
+	"github.com/andreaskoch/allmark/common/util/fsutil"
 	"github.com/andreaskoch/allmark/common/util/hashutil"
 	"github.com/andreaskoch/allmark/web/header"
 	"github.com/andreaskoch/allmark/web/view/themes"
 	"fmt"
+	"io/ioutil"
 	"mime"
 	"net/http"
+	pathpkg "path"
 	"path/filepath"
 	"strings"
 )
 
 // InMemoryTheme creates a theme-handler that serves the theme-files from memory.
 func InMemoryTheme(themeFolderPath string, headerWriter header.HeaderWriter, error404Handler http.Handler) http.Handler {
+	return Theme(themeFolderPath, "", themes.GetTheme(), headerWriter, error404Handler)
+}
 
-	defaultTheme := themes.GetTheme()
+// Theme creates a theme-handler that serves the theme-files from the given theme directory
+// and falls back to the files of the supplied theme for all files which don't exist in the directory
+// (e.g. a theme directory which only contains "presentation.js").
+// If the theme directory is empty only the files of the supplied theme are served.
+func Theme(themeFolderPath, themeDirectory string, theme *themes.Theme, headerWriter header.HeaderWriter, error404Handler http.Handler) http.Handler {
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		path := r.URL.Path
 		path = strings.TrimPrefix(path, themeFolderPath)
 
-		themeFile := defaultTheme.Get(path)
-		if themeFile == nil {
+		// normalize the path so it cannot point outside of the theme directory
+		path = strings.TrimPrefix(pathpkg.Clean("/"+path), "/")
 
-			// display a 404 error page
-			error404Handler.ServeHTTP(w, r)
-			return
+		isFingerprinted := false
+		data, found := getThemeFile(themeDirectory, theme, path)
+		if !found {
+
+			// fingerprinted theme files (e.g. "presentation.<hash>.js")
+			originalPath, hash, fingerprinted := parseFingerprintedPath(path)
+			if fingerprinted {
+				data, found = getThemeFile(themeDirectory, theme, originalPath)
+			}
+
+			if !found {
+
+				// display a 404 error page
+				error404Handler.ServeHTTP(w, r)
+				return
+
+			}
 
+			// redirect outdated fingerprints to the current version of the file
+			if currentHash := hashutil.SHA1FromBytes(data); currentHash != hash {
+				http.Redirect(w, r, GetFingerprintedPath(strings.TrimSuffix(r.URL.Path, path)+originalPath, currentHash), http.StatusFound)
+				return
+			}
+
+			path = originalPath
+			isFingerprinted = true
 		}
 
 		// detect the mime type
-		data := themeFile.Data()
 		mimeType := getMimeType(path, data)
 
 		// etag
@@ -43,14 +73,52 @@ func InMemoryTheme(themeFolderPath string, headerWriter header.HeaderWriter, err
 
 		// set headers
 		headerWriter.Write(w, mimeType)
-		if etag != "" {
-			header.ETag(w, etag)
+		if isFingerprinted {
+			header.CacheImmutable(w)
+		}
+
+		if writeETag(w, r, etag) {
+			return
 		}
 
 		fmt.Fprintf(w, `%s`, data)
 	})
 }
 
+// getThemeFile returns the content of the theme-file with the given path from the supplied theme directory
+// or, if the theme directory does not contain the file, from the supplied theme.
+func getThemeFile(themeDirectory string, theme *themes.Theme, path string) (data []byte, found bool) {
+	if data, found := getThemeFileData(themeDirectory, path); found {
+		return data, true
+	}
+
+	themeFile := theme.Get(path)
+	if themeFile == nil {
+		return nil, false
+	}
+
+	return themeFile.Data(), true
+}
+
+// getThemeFileData returns the content of the theme-file with the given path from the supplied theme directory.
+func getThemeFileData(themeDirectory, path string) (data []byte, found bool) {
+	if themeDirectory == "" || path == "" {
+		return nil, false
+	}
+
+	filePath := filepath.Join(themeDirectory, filepath.FromSlash(path))
+	if !fsutil.FileExists(filePath) {
+		return nil, false
+	}
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, false
+	}
+
+	return data, true
+}
+
 // getMimeType derives the mime-type from the given URI and data.
 func getMimeType(uri string, data []byte) string {
 	extention := filepath.Ext(uri)
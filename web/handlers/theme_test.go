@@ -0,0 +1,173 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/services/initialization"
+	"github.com/andreaskoch/allmark/web/header"
+	"github.com/andreaskoch/allmark/web/view/themes"
+	"github.com/andreaskoch/allmark/web/view/themes/themefiles"
+)
+
+func newTestThemeHandler(themeDirectory string) http.Handler {
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	return Theme("/theme/", themeDirectory, themes.GetTheme(), headerWriterFactory.NoCache(), http.NotFoundHandler())
+}
+
+func newTestThemeDirectory(t *testing.T, files map[string]string) string {
+	themeDirectory, err := ioutil.TempDir("", "allmark-theme-test")
+	if err != nil {
+		t.Fatalf("Could not create the theme directory. Error: %s", err)
+	}
+
+	for path, content := range files {
+		filePath := filepath.Join(themeDirectory, path)
+		os.MkdirAll(filepath.Dir(filePath), 0700)
+		if err := ioutil.WriteFile(filePath, []byte(content), 0600); err != nil {
+			t.Fatalf("Could not create the theme file %q. Error: %s", filePath, err)
+		}
+	}
+
+	return themeDirectory
+}
+
+func Test_Theme_FileExistsInThemeDirectory_OverrideIsServed(t *testing.T) {
+	// arrange
+	customPresentationJs := "/* custom presentation script */"
+	themeDirectory := newTestThemeDirectory(t, map[string]string{"presentation.js": customPresentationJs})
+	defer os.RemoveAll(themeDirectory)
+
+	handler := newTestThemeHandler(themeDirectory)
+
+	// act
+	response := serveRequest(handler, "/theme/presentation.js", "")
+
+	// assert
+	if response.Code != http.StatusOK {
+		t.Fatalf("The status code should be %d but was %d.", http.StatusOK, response.Code)
+	}
+
+	if body := response.Body.String(); body != customPresentationJs {
+		t.Errorf("The response should contain the file from the theme directory but was %q.", body)
+	}
+}
+
+func Test_Theme_FileDoesNotExistInThemeDirectory_BuiltInFileIsServed(t *testing.T) {
+	// arrange
+	themeDirectory := newTestThemeDirectory(t, map[string]string{"presentation.js": "/* custom */"})
+	defer os.RemoveAll(themeDirectory)
+
+	handler := newTestThemeHandler(themeDirectory)
+
+	// act
+	response := serveRequest(handler, "/theme/screen.css", "")
+
+	// assert
+	if response.Code != http.StatusOK {
+		t.Fatalf("The status code should be %d but was %d.", http.StatusOK, response.Code)
+	}
+
+	if body := response.Body.String(); body != themefiles.ScreenCss {
+		t.Errorf("The response should contain the built-in screen.css.")
+	}
+}
+
+func Test_Theme_PathOutsideOfThemeDirectory_FileIsNotServed(t *testing.T) {
+	// arrange
+	parentDirectory := newTestThemeDirectory(t, map[string]string{
+		"secret.txt":    "secret",
+		"theme/site.js": "/* custom */",
+	})
+	defer os.RemoveAll(parentDirectory)
+
+	handler := newTestThemeHandler(filepath.Join(parentDirectory, "theme"))
+
+	// act
+	response := serveRequest(handler, "/theme/../secret.txt", "")
+
+	// assert
+	if response.Code != http.StatusNotFound {
+		t.Errorf("The status code should be %d but was %d (%q).", http.StatusNotFound, response.Code, response.Body.String())
+	}
+}
+
+func Test_Theme_ConfiguredThemeChanged_AssetsOfTheSelectedThemeAreServed(t *testing.T) {
+	// arrange
+	themesFolder := newTestThemeDirectory(t, map[string]string{
+		"solarized/theme.json": `{"name": "solarized"}`,
+		"solarized/screen.css": "body { background: #fdf6e3; }",
+	})
+	defer os.RemoveAll(themesFolder)
+
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	getScreenCss := func(themeName string) string {
+		theme, err := themes.GetThemeByName(themesFolder, themeName)
+		if err != nil {
+			t.Fatalf("The theme %q should exist. Error: %s", themeName, err)
+		}
+
+		handler := Theme("/theme/", "", theme, headerWriterFactory.NoCache(), http.NotFoundHandler())
+		return serveRequest(handler, "/theme/screen.css", "").Body.String()
+	}
+
+	// act
+	defaultScreenCss := getScreenCss("default")
+	solarizedScreenCss := getScreenCss("solarized")
+
+	// assert
+	if defaultScreenCss != themefiles.ScreenCss {
+		t.Errorf("The default theme should serve the built-in screen.css.")
+	}
+
+	if solarizedScreenCss != "body { background: #fdf6e3; }" {
+		t.Errorf("The solarized theme should serve its own screen.css but served %q.", solarizedScreenCss)
+	}
+}
+
+func Test_Theme_InitializedRepositoryWithSelectedTheme_FilesOfTheSelectedThemeAreServed(t *testing.T) {
+	// arrange
+	repositoryDirectory, err := ioutil.TempDir("", "allmark-theme-init-test")
+	if err != nil {
+		t.Fatalf("Could not create the repository directory. Error: %s", err)
+	}
+
+	defer os.RemoveAll(repositoryDirectory)
+
+	if _, err := initialization.Initialize(repositoryDirectory); err != nil {
+		t.Fatalf("Could not initialize the repository. Error: %s", err)
+	}
+
+	repositoryConfig := config.Get(repositoryDirectory)
+	repositoryConfig.Web.Theme = "solarized"
+
+	solarizedScreenCss := "/* solarized */"
+	solarizedFolder := filepath.Join(repositoryConfig.ThemesFolder(), "solarized")
+	os.MkdirAll(solarizedFolder, 0700)
+	ioutil.WriteFile(filepath.Join(solarizedFolder, themes.ManifestFileName), []byte(`{"name": "solarized"}`), 0600)
+	ioutil.WriteFile(filepath.Join(solarizedFolder, "screen.css"), []byte(solarizedScreenCss), 0600)
+
+	theme, err := themes.GetThemeByName(repositoryConfig.ThemesFolder(), repositoryConfig.ThemeName())
+	if err != nil {
+		t.Fatalf("Could not load the selected theme. Error: %s", err)
+	}
+
+	headerWriterFactory := header.NewHeaderWriterFactory(0)
+	handler := Theme("/theme/", repositoryConfig.ThemeOverrideFolder(), theme, headerWriterFactory.NoCache(), http.NotFoundHandler())
+
+	// act
+	response := serveRequest(handler, "/theme/screen.css", "")
+
+	// assert
+	if body := response.Body.String(); body != solarizedScreenCss {
+		t.Errorf("The screen.css of the selected theme should have been served but the response was %q.", body)
+	}
+}
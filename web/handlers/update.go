@@ -39,7 +39,7 @@ func Update(logger logger.Logger,
 				viewModel, found := updateOrchestrator.GetUpdatedModel(update.Route())
 				if !found {
 					logger.Warn("The item for route %q was no longer found.", update.Route())
-					return
+					continue
 				}
 
 				var updateModel viewmodel.Update
@@ -59,10 +59,10 @@ func Update(logger logger.Logger,
 
 				hub.Message(updateModel)
 
-			} else if update.Type() == orchestrator.UpdateTypeNew {
+			} else if update.Type() == orchestrator.UpdateTypeDeleted {
 
-				// send an empty update to the client
-				hub.Message(viewmodel.Update{})
+				// let the clients reload the page
+				hub.Reload(update.Route())
 
 			} else {
 
@@ -6,6 +6,7 @@ package update
 
 import (
 	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/web/orchestrator"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 )
@@ -53,6 +54,14 @@ func (hub *Hub) Message(updateModel viewmodel.Update) {
 	}()
 }
 
+// Reload asks all clients of the given route to reload the page.
+func (hub *Hub) Reload(route route.Route) {
+	go func() {
+		hub.logger.Debug("Broadcasting reload message for route %s", route)
+		hub.broadcast <- NewReloadMessage(route)
+	}()
+}
+
 func (hub *Hub) Subscribe(connection *connection) {
 	hub.logger.Debug("Subscribing connection: %s", connection.String())
 
@@ -0,0 +1,114 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package update
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+	"golang.org/x/net/websocket"
+)
+
+// newTestHubServer creates a hub and a websocket server which registers every connection for the requested route.
+func newTestHubServer() (*Hub, *httptest.Server, chan bool) {
+	hub := NewHub(console.New(loglevel.Fatal), nil)
+	done := make(chan bool)
+
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		path := strings.TrimSuffix(ws.Request().URL.Path, ".ws")
+		c := NewConnection(hub, ws, route.NewFromRequest(path))
+		hub.subscribe <- c
+
+		go c.Writer()
+		<-done
+	}))
+
+	return hub, server, done
+}
+
+func dialTestHubServer(t *testing.T, server *httptest.Server, path string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + path
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("Cannot connect to %q. Error: %s", url, err)
+	}
+
+	return ws
+}
+
+func receiveMessage(ws *websocket.Conn) (message Message, err error) {
+	ws.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	err = websocket.JSON.Receive(ws, &message)
+	return
+}
+
+func Test_Hub_Message_ConnectionsOfTheRouteReceiveTheUpdate(t *testing.T) {
+	// arrange
+	hub, server, done := newTestHubServer()
+	defer server.Close()
+	defer close(done)
+
+	affected := dialTestHubServer(t, server, "/documents/first.ws")
+	defer affected.Close()
+
+	unrelated := dialTestHubServer(t, server, "/documents/second.ws")
+	defer unrelated.Close()
+
+	// wait for the connections to be registered
+	time.Sleep(100 * time.Millisecond)
+
+	var updateModel viewmodel.Update
+	updateModel.Route = "documents/first"
+	updateModel.Title = "First (changed)"
+
+	// act
+	hub.Message(updateModel)
+
+	// assert
+	message, err := receiveMessage(affected)
+	if err != nil {
+		t.Fatalf("The connection for the changed route should receive a message. Error: %s", err)
+	}
+
+	if message.Name != MessageNameUpdate || message.UpdateModel.Title != "First (changed)" {
+		t.Errorf("The message should be an update with the latest model but was %#v.", message)
+	}
+
+	if _, err := receiveMessage(unrelated); err == nil {
+		t.Errorf("The connection for an unrelated route should not receive a message.")
+	}
+}
+
+func Test_Hub_Reload_ConnectionsOfTheRouteReceiveAReloadMessage(t *testing.T) {
+	// arrange
+	hub, server, done := newTestHubServer()
+	defer server.Close()
+	defer close(done)
+
+	ws := dialTestHubServer(t, server, "/documents/first.ws")
+	defer ws.Close()
+
+	// wait for the connection to be registered
+	time.Sleep(100 * time.Millisecond)
+
+	// act
+	hub.Reload(route.NewFromRequest("documents/first"))
+
+	// assert
+	message, err := receiveMessage(ws)
+	if err != nil {
+		t.Fatalf("The connection should receive a message. Error: %s", err)
+	}
+
+	if message.Name != MessageNameReload {
+		t.Errorf("The message name should be %q but was %q.", MessageNameReload, message.Name)
+	}
+}
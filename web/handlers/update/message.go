@@ -9,6 +9,14 @@ import (
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 )
 
+const (
+	// MessageNameUpdate is the name of messages which contain the latest view model of an item.
+	MessageNameUpdate = "update"
+
+	// MessageNameReload is the name of messages which ask the client to reload the page.
+	MessageNameReload = "reload"
+)
+
 type Message struct {
 	Route       string           `json:"route"`
 	Name        string           `json:"name"`
@@ -20,7 +28,15 @@ func NewMessage(updateModel viewmodel.Update) Message {
 
 	return Message{
 		Route:       route.Value(),
-		Name:        "update",
+		Name:        MessageNameUpdate,
 		UpdateModel: updateModel,
 	}
 }
+
+// NewReloadMessage creates a message which asks all clients of the given route to reload the page.
+func NewReloadMessage(route route.Route) Message {
+	return Message{
+		Route: route.Value(),
+		Name:  MessageNameReload,
+	}
+}
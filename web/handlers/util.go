@@ -6,6 +6,7 @@ package handlers
 
 import (
 	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/header"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"bufio"
 	"bytes"
@@ -14,6 +15,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"text/template"
 )
 
@@ -21,6 +23,16 @@ func getRouteFromRequest(r *http.Request) route.Route {
 	return route.NewFromRequest(r.URL.Path)
 }
 
+// getBaseURL returns the configured base URL without a trailing slash
+// or the base URL of the supplied request if no base URL is configured.
+func getBaseURL(configuredBaseURL string, r *http.Request) string {
+	if baseURL := strings.TrimSuffix(strings.TrimSpace(configuredBaseURL), "/"); baseURL != "" {
+		return baseURL
+	}
+
+	return getBaseURLFromRequest(r)
+}
+
 func getBaseURLFromRequest(r *http.Request) string {
 	scheme := "http"
 	if r.TLS != nil {
@@ -30,6 +42,20 @@ func getBaseURLFromRequest(r *http.Request) string {
 	return scheme + "://" + r.Host
 }
 
+// writeETag sets the ETag header for the supplied hash and answers the request with
+// "304 Not Modified" if the client already has the current version (If-None-Match).
+// It returns true if the request has been answered and no body must be written.
+func writeETag(w http.ResponseWriter, r *http.Request, hash string) (notModified bool) {
+	header.ETag(w, hash)
+
+	if !header.IsNotModified(r, hash) {
+		return false
+	}
+
+	header.NotModified(w)
+	return true
+}
+
 func getRenderedCode(template *template.Template, model interface{}) (string, error) {
 	buffer := new(bytes.Buffer)
 	writer := bufio.NewWriter(buffer)
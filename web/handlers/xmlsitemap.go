@@ -15,7 +15,10 @@ import (
 	"text/template"
 )
 
+// XMLSitemap returns a http handler which renders the XML sitemap with all published items.
+// The locations are prefixed with the supplied base URL or, if it is empty, with the base URL of the request.
 func XMLSitemap(headerWriter header.HeaderWriter,
+	baseURL string,
 	xmlSitemapOrchestrator *orchestrator.XmlSitemapOrchestrator,
 	templateProvider templates.Provider) http.Handler {
 
@@ -25,7 +28,7 @@ func XMLSitemap(headerWriter header.HeaderWriter,
 		headerWriter.Write(w, header.CONTENTTYPE_XML)
 
 		// get the current hostname
-		hostname := getBaseURLFromRequest(r)
+		hostname := getBaseURL(baseURL, r)
 
 		// get the sitemap template
 		xmlSitemapTemplate, err := templateProvider.GetXMLSitemapTemplate(hostname)
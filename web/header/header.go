@@ -7,26 +7,63 @@ package header
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 const (
-	CONTENTTYPE_HTML = "text/html; charset=utf-8"
-	CONTENTTYPE_TEXT = "text/plain; charset=utf-8"
-	CONTENTTYPE_XML  = "text/xml; charset=utf-8"
-	CONTENTTYPE_JSON = "application/json; charset=utf-8"
-	CONTENTTYPE_DOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document; charset=utf-8"
+	CONTENTTYPE_HTML     = "text/html; charset=utf-8"
+	CONTENTTYPE_TEXT     = "text/plain; charset=utf-8"
+	CONTENTTYPE_XML      = "text/xml; charset=utf-8"
+	CONTENTTYPE_ATOM     = "application/atom+xml; charset=utf-8"
+	CONTENTTYPE_JSON     = "application/json; charset=utf-8"
+	CONTENTTYPE_PDF      = "application/pdf"
+	CONTENTTYPE_DOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document; charset=utf-8"
+	CONTENTTYPE_EPUB     = "application/epub+zip"
+	CONTENTTYPE_OPML     = "text/x-opml; charset=utf-8"
+	CONTENTTYPE_MARKDOWN = "text/markdown; charset=utf-8"
 )
 
 func Cache(w http.ResponseWriter, seconds int) {
 	w.Header().Add("Cache-Control", fmt.Sprintf("public, max-age=%d", seconds))
 }
 
+// CacheImmutable marks the response as never changing (e.g. fingerprinted assets) so clients can cache it for a year.
+// Cache headers which have been written before are replaced.
+func CacheImmutable(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+}
+
+// ETag sets the ETag header of the response to the supplied hash (as a quoted entity tag).
 func ETag(w http.ResponseWriter, hash string) {
 	if hash == "" {
 		return
 	}
 
-	w.Header().Add("ETag", hash)
+	w.Header().Set("ETag", `"`+hash+`"`)
+}
+
+// IsNotModified returns true if the If-None-Match header of the supplied request
+// contains the entity tag of the given hash (or "*"). Weak and unquoted tags are accepted too.
+func IsNotModified(r *http.Request, hash string) bool {
+	if hash == "" {
+		return false
+	}
+
+	for _, entityTag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		entityTag = strings.TrimPrefix(strings.TrimSpace(entityTag), "W/")
+		if entityTag == "*" || strings.Trim(entityTag, `"`) == hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotModified answers the request with "304 Not Modified".
+func NotModified(w http.ResponseWriter) {
+	w.Header().Del("Content-Type")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
 }
 
 func NoCache(w http.ResponseWriter) {
@@ -42,7 +79,25 @@ func ContentType(w http.ResponseWriter, contentType string) {
 }
 
 func VaryAcceptEncoding(w http.ResponseWriter) {
-	w.Header().Set("Vary", "Accept-Encoding")
+	addVary(w, "Accept-Encoding")
+}
+
+// VaryAccept marks the response as depending on the Accept header (e.g. pages which can be returned as HTML or markdown).
+func VaryAccept(w http.ResponseWriter) {
+	addVary(w, "Accept")
+}
+
+// addVary adds the supplied request header field to the Vary header unless it is already listed.
+func addVary(w http.ResponseWriter, field string) {
+	for _, value := range w.Header()["Vary"] {
+		for _, listedField := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(listedField), field) {
+				return
+			}
+		}
+	}
+
+	w.Header().Add("Vary", field)
 }
 
 // configurable header writer
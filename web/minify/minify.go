@@ -0,0 +1,328 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package minify removes comments and redundant whitespace from HTML, CSS and JavaScript code.
+// The minifiers are deliberately conservative: they never rename or reorder anything,
+// so the minified code behaves exactly like the original code.
+package minify
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+var (
+	// the opening tags of the elements whose content is passed through unchanged (e.g. <pre>)
+	rawHTMLElementPattern = regexp.MustCompile(`(?i)<(pre|textarea|script|style)\b`)
+
+	// all comments except for conditional comments (e.g. <!--[if lt IE 9]>)
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--(?:[^\[].*?)?-->`)
+
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// HTML removes comments and collapses whitespace in the supplied HTML code.
+// The content of <pre>, <textarea>, <script> and <style> elements is not changed.
+func HTML(html string) string {
+	var result bytes.Buffer
+
+	for {
+		location := rawHTMLElementPattern.FindStringSubmatchIndex(html)
+		if location == nil {
+			break
+		}
+
+		// the raw element ends with its closing tag (or the end of the document)
+		closingTag := "</" + strings.ToLower(html[location[2]:location[3]])
+		end := strings.Index(strings.ToLower(html[location[1]:]), closingTag)
+		if end < 0 {
+			end = len(html)
+		} else {
+			end += location[1] + len(closingTag)
+		}
+
+		result.WriteString(minifyHTMLText(html[:location[0]]))
+		result.WriteString(html[location[0]:end])
+		html = html[end:]
+	}
+
+	result.WriteString(minifyHTMLText(html))
+	return strings.TrimSpace(result.String())
+}
+
+// minifyHTMLText removes the comments of the supplied HTML code and replaces every
+// sequence of whitespace with a single space (which is how browsers render it anyway).
+func minifyHTMLText(html string) string {
+	html = htmlCommentPattern.ReplaceAllString(html, "")
+	return whitespacePattern.ReplaceAllString(html, " ")
+}
+
+// CSS removes comments and redundant whitespace from the supplied style sheet.
+// Strings are not changed.
+func CSS(css string) string {
+	var result bytes.Buffer
+	pendingSpace := false
+
+	for index := 0; index < len(css); index++ {
+		character := css[index]
+
+		switch {
+		case character == '"' || character == '\'':
+			end := getStringEnd(css, index)
+			writePendingSpace(&result, &pendingSpace, character)
+			result.WriteString(css[index:end])
+			index = end - 1
+
+		case character == '/' && index+1 < len(css) && css[index+1] == '*':
+			end := strings.Index(css[index+2:], "*/")
+			if end < 0 {
+				return strings.TrimSpace(result.String())
+			}
+
+			index += end + 3
+			pendingSpace = pendingSpace || result.Len() > 0
+
+		case isWhitespace(character):
+			pendingSpace = result.Len() > 0
+
+		case strings.IndexByte("{};,>", character) >= 0:
+			pendingSpace = false
+
+			// the last semicolon of a block is optional
+			if character == '}' {
+				trimTrailingSemicolon(&result)
+			}
+
+			result.WriteByte(character)
+
+		default:
+			writePendingSpace(&result, &pendingSpace, character)
+			result.WriteByte(character)
+		}
+	}
+
+	return strings.TrimSpace(result.String())
+}
+
+// writePendingSpace writes a space if there was whitespace before the supplied character
+// and the whitespace is significant (i.e. it does not follow a delimiter or the colon of a declaration;
+// a space before a colon is kept because it is significant in selectors like "nav :hover").
+func writePendingSpace(result *bytes.Buffer, pendingSpace *bool, character byte) {
+	if !*pendingSpace {
+		return
+	}
+
+	*pendingSpace = false
+
+	output := result.Bytes()
+	if len(output) == 0 || strings.IndexByte("{};,>:", output[len(output)-1]) >= 0 {
+		return
+	}
+
+	result.WriteByte(' ')
+}
+
+func trimTrailingSemicolon(result *bytes.Buffer) {
+	if output := result.Bytes(); len(output) > 0 && output[len(output)-1] == ';' {
+		result.Truncate(len(output) - 1)
+	}
+}
+
+// JS removes comments, indentation and empty lines from the supplied JavaScript code.
+// Line breaks are kept because they can be significant (automatic semicolon insertion);
+// strings, template literals and regular expressions are not changed.
+func JS(js string) string {
+	var result bytes.Buffer
+	pendingSpace, pendingNewline := false, false
+
+	// the last character which is neither whitespace nor part of a comment
+	var lastCharacter byte
+	lastWord := ""
+
+	writeToken := func(token string) {
+		if result.Len() > 0 {
+			if pendingNewline {
+				result.WriteByte('\n')
+			} else if pendingSpace && isSpaceSignificant(lastCharacter, token[0]) {
+				result.WriteByte(' ')
+			}
+		}
+
+		pendingSpace, pendingNewline = false, false
+		result.WriteString(token)
+		lastCharacter = token[len(token)-1]
+	}
+
+	for index := 0; index < len(js); index++ {
+		character := js[index]
+
+		switch {
+		case character == '"' || character == '\'' || character == '`':
+			end := getStringEnd(js, index)
+			writeToken(js[index:end])
+			index = end - 1
+			lastWord = ""
+
+		case character == '/' && index+1 < len(js) && js[index+1] == '/':
+			end := strings.IndexByte(js[index:], '\n')
+			if end < 0 {
+				index = len(js)
+				continue
+			}
+
+			index += end - 1
+
+		case character == '/' && index+1 < len(js) && js[index+1] == '*':
+			end := strings.Index(js[index+2:], "*/")
+			if end < 0 {
+				index = len(js)
+				continue
+			}
+
+			comment := js[index : index+end+4]
+			if strings.Contains(comment, "\n") {
+				pendingNewline = true
+			} else {
+				pendingSpace = true
+			}
+
+			index += end + 3
+
+		case character == '/' && isRegularExpressionStart(lastCharacter, lastWord):
+			end := getRegularExpressionEnd(js, index)
+			writeToken(js[index:end])
+			index = end - 1
+			lastWord = ""
+
+		case character == '\n':
+			pendingNewline = true
+
+		case isWhitespace(character):
+			pendingSpace = true
+
+		case isIdentifierCharacter(character):
+			end := index
+			for end < len(js) && isIdentifierCharacter(js[end]) {
+				end++
+			}
+
+			lastWord = js[index:end]
+			writeToken(lastWord)
+			index = end - 1
+
+		default:
+			writeToken(string(character))
+			lastWord = ""
+		}
+	}
+
+	return result.String()
+}
+
+// isSpaceSignificant returns true if the whitespace between the supplied characters cannot be removed
+// (e.g. "var a", "a + +b", "1 .toString()").
+func isSpaceSignificant(previous, next byte) bool {
+	switch {
+	case isIdentifierCharacter(previous) && isIdentifierCharacter(next):
+		return true
+
+	case (previous == '+' || previous == '-') && previous == next:
+		return true
+
+	case previous == '/' && (next == '/' || next == '*'):
+		return true
+
+	case previous >= '0' && previous <= '9' && next == '.':
+		return true
+	}
+
+	return false
+}
+
+// isRegularExpressionStart returns true if a slash after the supplied character
+// (or keyword) starts a regular expression instead of being the division operator.
+func isRegularExpressionStart(lastCharacter byte, lastWord string) bool {
+	if lastWord != "" {
+		switch lastWord {
+		case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+			return true
+		}
+
+		return false
+	}
+
+	return lastCharacter == 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", lastCharacter) >= 0
+}
+
+// getStringEnd returns the position after the end of the string which starts
+// at the given position (the opening quote).
+func getStringEnd(code string, start int) int {
+	quote := code[start]
+	for index := start + 1; index < len(code); index++ {
+		switch code[index] {
+		case '\\':
+			index++
+
+		case quote:
+			return index + 1
+
+		case '\n':
+			// unterminated strings end at the line break (except for template literals)
+			if quote != '`' {
+				return index
+			}
+		}
+	}
+
+	return len(code)
+}
+
+// getRegularExpressionEnd returns the position after the end of the regular expression
+// (including its flags) which starts at the given position.
+func getRegularExpressionEnd(code string, start int) int {
+	inCharacterClass := false
+
+	for index := start + 1; index < len(code); index++ {
+		switch code[index] {
+		case '\\':
+			index++
+
+		case '[':
+			inCharacterClass = true
+
+		case ']':
+			inCharacterClass = false
+
+		case '\n':
+			return index
+
+		case '/':
+			if inCharacterClass {
+				continue
+			}
+
+			end := index + 1
+			for end < len(code) && isIdentifierCharacter(code[end]) {
+				end++
+			}
+
+			return end
+		}
+	}
+
+	return len(code)
+}
+
+func isWhitespace(character byte) bool {
+	return character == ' ' || character == '\t' || character == '\n' || character == '\r' || character == '\f' || character == '\v'
+}
+
+func isIdentifierCharacter(character byte) bool {
+	return character == '_' || character == '$' ||
+		(character >= 'a' && character <= 'z') ||
+		(character >= 'A' && character <= 'Z') ||
+		(character >= '0' && character <= '9') ||
+		character >= 0x80
+}
@@ -0,0 +1,430 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package minify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/web/view/themes/themefiles"
+)
+
+func Test_HTML_CommentsAndWhitespace_AreRemoved(t *testing.T) {
+	// arrange
+	input := "<html>\n  <body>\n    <!-- navigation -->\n    <p>Some   text</p>\n  </body>\n</html>\n"
+	expected := "<html> <body> <p>Some text</p> </body> </html>"
+
+	// act
+	result := HTML(input)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_HTML_ConditionalComment_IsKept(t *testing.T) {
+	// arrange
+	input := "<head><!--[if lt IE 9]><script src=\"html5.js\"></script><![endif]--></head>"
+
+	// act
+	result := HTML(input)
+
+	// assert
+	if result != input {
+		t.Errorf("The result should be %q but was %q.", input, result)
+	}
+}
+
+func Test_HTML_RawElements_AreNotChanged(t *testing.T) {
+	// arrange
+	pre := "<pre><code>func main() {\n\t<!-- not a comment -->\n    fmt.Println(\"a  b\")\n}</code></pre>"
+	textarea := "<TEXTAREA name=\"comment\">  line 1\n\n  line 2</TEXTAREA>"
+	script := "<script>\n  var a = 1;\n</script>"
+	input := "<body>\n  " + pre + "\n  " + textarea + "\n  " + script + "\n</body>"
+
+	// act
+	result := HTML(input)
+
+	// assert
+	for _, element := range []string{pre, textarea, script} {
+		if !strings.Contains(result, element) {
+			t.Errorf("The result should contain %q unchanged but was %q.", element, result)
+		}
+	}
+
+	if len(result) >= len(input) {
+		t.Errorf("The result (%d bytes) should be smaller than the input (%d bytes).", len(result), len(input))
+	}
+}
+
+func Test_CSS_CommentsAndWhitespace_AreRemoved(t *testing.T) {
+	// arrange
+	input := "/* header */\nbody > header,\nnav a {\n  margin: 0 auto;\n  font-family: \"Open  Sans\", sans-serif;\n}\n"
+	expected := "body>header,nav a{margin:0 auto;font-family:\"Open  Sans\",sans-serif}"
+
+	// act
+	result := CSS(input)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_JS_CommentsAndIndentation_AreRemoved(t *testing.T) {
+	// arrange
+	input := "// setup\nvar a = 1; /* inline */ var b = a + +1;\n\n    return a\n"
+	expected := "var a=1;var b=a+ +1;\nreturn a"
+
+	// act
+	result := JS(input)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_JS_StringsAndRegularExpressions_AreNotChanged(t *testing.T) {
+	inputs := []string{
+		`var url = "http://example.com/*path*/";`,
+		`var s = 'a // b';`,
+		"var t = `line 1\n    // line 2`;",
+		`var r = /\/\/[a-z/]+/g.test(s);`,
+		`if (x) return /a b/.test(y);`,
+		`var c = a / b / c;`,
+	}
+
+	for _, input := range inputs {
+		// act
+		result := JS(input)
+
+		// assert
+		if strings.Replace(result, " ", "", -1) != strings.Replace(input, " ", "", -1) {
+			t.Errorf("The result for %q should only differ in whitespace but was %q.", input, result)
+		}
+
+		for _, literal := range []string{`"http://example.com/*path*/"`, `'a // b'`, "`line 1\n    // line 2`", `/\/\/[a-z/]+/g`, `/a b/`} {
+			if strings.Contains(input, literal) && !strings.Contains(result, literal) {
+				t.Errorf("The result for %q should contain %q but was %q.", input, literal, result)
+			}
+		}
+	}
+}
+
+func Test_ThemeFiles_AreSmallerAndMinificationIsIdempotent(t *testing.T) {
+	files := map[string]func(code string) string{
+		themefiles.ScreenCss:      CSS,
+		themefiles.PrintCss:       CSS,
+		themefiles.SiteJs:         JS,
+		themefiles.TocJs:          JS,
+		themefiles.PresentationJs: JS,
+		themefiles.AutoupdateJs:   JS,
+	}
+
+	for code, minifier := range files {
+		// act
+		result := minifier(code)
+
+		// assert
+		if len(result) >= len(code) {
+			t.Errorf("The minified file (%d bytes) should be smaller than the original (%d bytes).", len(result), len(code))
+		}
+
+		if again := minifier(result); again != result {
+			t.Errorf("Minifying a minified file should not change it.")
+		}
+	}
+}
+
+// jsPunctuators are the JavaScript punctuators which consist of more than one character (longest first).
+var jsPunctuators = []string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<", ">>", "**",
+}
+
+// jsRegularExpressionKeywords are the keywords after which a slash starts a regular expression.
+var jsRegularExpressionKeywords = map[string]bool{"return": true, "typeof": true, "case": true, "do": true, "else": true, "in": true, "new": true, "throw": true, "void": true}
+
+// getJSTokens returns the tokens of the supplied JavaScript code without comments and whitespace.
+// Line breaks between tokens are returned as a "\n" token because they are significant (automatic semicolon insertion).
+func getJSTokens(code string) []string {
+	tokens := make([]string, 0)
+	lineBreak := false
+
+	addToken := func(token string) {
+		if lineBreak && len(tokens) > 0 {
+			tokens = append(tokens, "\n")
+		}
+
+		lineBreak = false
+		tokens = append(tokens, token)
+	}
+
+	// a slash starts a regular expression unless it follows an operand
+	isRegularExpression := func() bool {
+		if len(tokens) == 0 {
+			return true
+		}
+
+		last := tokens[len(tokens)-1]
+		if last == "\n" && len(tokens) > 1 {
+			last = tokens[len(tokens)-2]
+		}
+
+		if last == ")" || last == "]" {
+			return false
+		}
+
+		if isIdentifierCharacter(last[0]) {
+			return jsRegularExpressionKeywords[last]
+		}
+
+		return true
+	}
+
+	for index := 0; index < len(code); {
+		character := code[index]
+
+		switch {
+		case character == '\n':
+			lineBreak = true
+			index++
+
+		case isWhitespace(character):
+			index++
+
+		case strings.HasPrefix(code[index:], "//"):
+			for index < len(code) && code[index] != '\n' {
+				index++
+			}
+
+		case strings.HasPrefix(code[index:], "/*"):
+			end := index + 2 + strings.Index(code[index+2:], "*/") + 2
+			lineBreak = lineBreak || strings.Contains(code[index:end], "\n")
+			index = end
+
+		case character == '"' || character == '\'' || character == '`':
+			end := index + 1
+			for code[end] != character {
+				if code[end] == '\\' {
+					end++
+				}
+				end++
+			}
+
+			addToken(code[index : end+1])
+			index = end + 1
+
+		case character == '/' && isRegularExpression():
+			end := index + 1
+			inCharacterClass := false
+			for inCharacterClass || code[end] != '/' {
+				switch code[end] {
+				case '\\':
+					end++
+				case '[':
+					inCharacterClass = true
+				case ']':
+					inCharacterClass = false
+				}
+				end++
+			}
+
+			end++
+			for end < len(code) && isIdentifierCharacter(code[end]) {
+				end++
+			}
+
+			addToken(code[index:end])
+			index = end
+
+		case isIdentifierCharacter(character):
+			end := index
+			for end < len(code) && isIdentifierCharacter(code[end]) {
+				end++
+			}
+
+			addToken(code[index:end])
+			index = end
+
+		default:
+			token := string(character)
+			for _, punctuator := range jsPunctuators {
+				if strings.HasPrefix(code[index:], punctuator) {
+					token = punctuator
+					break
+				}
+			}
+
+			addToken(token)
+			index += len(token)
+		}
+	}
+
+	return tokens
+}
+
+// getCSSTokens returns the tokens of the supplied style sheet without comments.
+// Whitespace between two tokens is returned as a " " token unless it is next to a delimiter
+// (it separates selectors, values and the parts of media queries).
+func getCSSTokens(code string) []string {
+	tokens := make([]string, 0)
+	whitespace := false
+
+	addToken := func(token string) {
+		if whitespace && len(tokens) > 0 && !strings.Contains("{};,>:", tokens[len(tokens)-1]) && !strings.Contains("{};,>", token) {
+			tokens = append(tokens, " ")
+		}
+
+		whitespace = false
+		tokens = append(tokens, token)
+	}
+
+	for index := 0; index < len(code); {
+		character := code[index]
+
+		switch {
+		case isWhitespace(character):
+			whitespace = true
+			index++
+
+		case strings.HasPrefix(code[index:], "/*"):
+			index += 2 + strings.Index(code[index+2:], "*/") + 2
+			whitespace = true
+
+		case character == '"' || character == '\'':
+			end := index + 1
+			for code[end] != character {
+				if code[end] == '\\' {
+					end++
+				}
+				end++
+			}
+
+			addToken(code[index : end+1])
+			index = end + 1
+
+		case strings.IndexByte("{}();,:>+~*", character) >= 0:
+			addToken(string(character))
+			index++
+
+		default:
+			end := index
+			for end < len(code) && !isWhitespace(code[end]) && strings.IndexByte("{}();,:>+~*\"'/", code[end]) < 0 {
+				end++
+			}
+
+			if end == index {
+				end++
+			}
+
+			addToken(code[index:end])
+			index = end
+		}
+	}
+
+	// the last semicolon of a block is optional
+	result := make([]string, 0, len(tokens))
+	for index, token := range tokens {
+		if token == ";" && index+1 < len(tokens) && tokens[index+1] == "}" {
+			continue
+		}
+
+		result = append(result, token)
+	}
+
+	return result
+}
+
+// assertSameTokens fails the test if the supplied code and its minified version consist of different tokens.
+func assertSameTokens(t *testing.T, name string, getTokens func(code string) []string, code, minified string) {
+	expected, result := getTokens(code), getTokens(minified)
+	if strings.Join(expected, "\x00") == strings.Join(result, "\x00") {
+		return
+	}
+
+	for index := range expected {
+		if index >= len(result) || expected[index] != result[index] {
+			start := index - 5
+			if start < 0 {
+				start = 0
+			}
+
+			t.Errorf("The tokens of %s changed at token %d: expected %q but was %q.", name, index, expected[start:index+1], result[start:minInt(index+1, len(result))])
+			return
+		}
+	}
+
+	t.Errorf("The minified code of %s has %d tokens instead of %d.", name, len(result), len(expected))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func Test_JS_RepresentativeCode_TokensAreNotChanged(t *testing.T) {
+	inputs := map[string]string{
+		"regular expressions":             "var r = /\\/\\/[a-z/]+/g.test(s);\nvar q = s.replace(/\"/g, \"'\");\nvar c = /[/]/.test(s) ? a / b / c : /x/i;\nif (x) return /a b/.test(y);\nvar d = x ? / a b /.test(s) : [/c  d/, !/e f/];",
+		"automatic semicolon insertion":   "var a = 1\nvar b = a\n++b\nreturn\nb\nvar c = a\n(function() {})()\ni++\nj--",
+		"strings containing comments":     "var url = \"http://example.com/\"; // comment\nvar s = 'a /* b */ c'; /* comment */ var t = `// ${url}\n  line 2`;",
+		"operators separated by spaces":   "var d = a + +b - -c + ++e;\nvar f = 1 .toString() + a / /x/.source.length;",
+		"comments containing line breaks": "var a = 1 /* first\nsecond */ var b = 2\n/** @return {number} */ return a",
+	}
+
+	for name, input := range inputs {
+		// act
+		result := JS(input)
+
+		// assert
+		assertSameTokens(t, name, getJSTokens, input, result)
+	}
+}
+
+func Test_CSS_RepresentativeCode_TokensAreNotChanged(t *testing.T) {
+	inputs := map[string]string{
+		"strings containing comments": "a::after {\n  content: \"// not /* a */ comment\";\n  background: url(\"http://example.com/a.png\");\n}",
+		"selectors and combinators":   "nav :hover,\nnav a:hover > span + b ~ i,\nul  li * {\n  margin: 0 auto;\n}",
+		"media queries and functions": "@media screen and (max-width: 600px) {\n  div { width: calc(100% - 2em); }\n}",
+	}
+
+	for name, input := range inputs {
+		// act
+		result := CSS(input)
+
+		// assert
+		assertSameTokens(t, name, getCSSTokens, input, result)
+	}
+}
+
+func Test_ThemeFiles_TokensAreNotChanged(t *testing.T) {
+	jsFiles := map[string]string{
+		"site.js":         themefiles.SiteJs,
+		"toc.js":          themefiles.TocJs,
+		"presentation.js": themefiles.PresentationJs,
+		"autoupdate.js":   themefiles.AutoupdateJs,
+		"math.js":         themefiles.MathJs,
+		"mermaid.js":      themefiles.MermaidJs,
+		"map.js":          themefiles.MapJs,
+	}
+
+	for name, code := range jsFiles {
+		assertSameTokens(t, name, getJSTokens, code, JS(code))
+	}
+
+	cssFiles := map[string]string{
+		"screen.css": themefiles.ScreenCss,
+		"print.css":  themefiles.PrintCss,
+	}
+
+	for name, code := range cssFiles {
+		assertSameTokens(t, name, getCSSTokens, code, CSS(code))
+	}
+}
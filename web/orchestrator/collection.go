@@ -0,0 +1,70 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/model"
+)
+
+const (
+	// CollectionChildrenPlaceholder marks the position of the child listing in the content of a collection.
+	CollectionChildrenPlaceholder = "{{children}}"
+
+	// collectionExcerptLength is the maximum number of characters of the excerpts in the child listing.
+	collectionExcerptLength = 200
+)
+
+// getCollectionListing returns an HTML list with the title, link and excerpt (see getExcerpt) of the supplied children.
+// The children are listed in the supplied order; nested collections are linked, not expanded.
+// Comments are not listed because they are displayed in the comment thread.
+func getCollectionListing(pathProvider paths.Pather, children []*model.Item, getExcerpt func(child *model.Item) string) string {
+	var listing bytes.Buffer
+
+	listing.WriteString(`<ul class="collection-children">` + "\n")
+	for _, child := range children {
+		if child.Type == model.TypeComment {
+			continue
+		}
+
+		listing.WriteString(`<li class="collection-item">`)
+		fmt.Fprintf(&listing, `<a href="%s" class="collection-item-title">%s</a>`, html.EscapeString(pathProvider.Path(child.Route().Value())), html.EscapeString(child.GetTitle()))
+
+		if excerpt := getExcerpt(child); excerpt != "" {
+			fmt.Fprintf(&listing, `<p class="collection-item-excerpt">%s</p>`, html.EscapeString(excerpt))
+		}
+
+		listing.WriteString("</li>\n")
+	}
+
+	listing.WriteString("</ul>")
+	return listing.String()
+}
+
+// insertCollectionListing replaces the children placeholder in the supplied HTML content with the listing.
+// If there is no placeholder the listing is only added if the body of the collection is empty.
+func insertCollectionListing(content, listing string, bodyIsEmpty bool) string {
+
+	// the placeholder is usually converted into a paragraph of its own
+	paragraphPlaceholder := "<p>" + CollectionChildrenPlaceholder + "</p>"
+	if strings.Contains(content, paragraphPlaceholder) {
+		return strings.Replace(content, paragraphPlaceholder, listing, -1)
+	}
+
+	if strings.Contains(content, CollectionChildrenPlaceholder) {
+		return strings.Replace(content, CollectionChildrenPlaceholder, listing, -1)
+	}
+
+	if bodyIsEmpty {
+		return strings.TrimSpace(content + "\n" + listing)
+	}
+
+	return content
+}
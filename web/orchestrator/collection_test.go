@@ -0,0 +1,105 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+func newTestCollectionChild(itemRoute, markdown, content string, itemType model.ItemType) *model.Item {
+	item := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+	item.Type = itemType
+	item.Markdown = markdown
+	item.Content = content
+	return item
+}
+
+func Test_getCollectionListing_ThreeChildren_ThreeLinkedEntriesAreRendered(t *testing.T) {
+	// arrange
+	children := []*model.Item{
+		newTestCollectionChild("recipes/pancakes", "# Pancakes", "Fluffy pancakes for breakfast.", model.TypeDocument),
+		newTestCollectionChild("recipes/waffles", "# Waffles & Syrup", "Crispy waffles.", model.TypeDocument),
+		newTestCollectionChild("recipes/desserts", "", "", model.TypeCollection),
+	}
+
+	getExcerpt := func(child *model.Item) string {
+		return child.Excerpt("<p>"+child.Content+"</p>", collectionExcerptLength)
+	}
+
+	// act
+	result := insertCollectionListing("<h1>Recipes</h1>\n<p>{{children}}</p>", getCollectionListing(testPather{}, children, getExcerpt), false)
+
+	// assert
+	if count := strings.Count(result, `<li class="collection-item">`); count != 3 {
+		t.Fatalf("The listing should contain %d entries but contained %d: %s", 3, count, result)
+	}
+
+	expectedEntries := []string{
+		`<a href="/recipes/pancakes" class="collection-item-title">Pancakes</a><p class="collection-item-excerpt">Fluffy pancakes for breakfast.</p>`,
+		`<a href="/recipes/waffles" class="collection-item-title">Waffles &amp; Syrup</a>`,
+		`<a href="/recipes/desserts" class="collection-item-title">Desserts</a></li>`,
+	}
+
+	for _, expectedEntry := range expectedEntries {
+		if !strings.Contains(result, expectedEntry) {
+			t.Errorf("The listing should contain %q but was: %s", expectedEntry, result)
+		}
+	}
+
+	if strings.Index(result, "pancakes") > strings.Index(result, "waffles") {
+		t.Errorf("The entries should be listed in the order of the children: %s", result)
+	}
+
+	if strings.Contains(result, CollectionChildrenPlaceholder) {
+		t.Errorf("The placeholder should have been replaced: %s", result)
+	}
+}
+
+func Test_getCollectionListing_ChildIsAComment_CommentIsNotListed(t *testing.T) {
+	// arrange
+	children := []*model.Item{
+		newTestCollectionChild("recipes/pancakes", "# Pancakes", "Fluffy pancakes.", model.TypeDocument),
+		newTestCollectionChild("recipes/comment-20150301-153000", "# Comment by Jane", "Delicious!", model.TypeComment),
+	}
+
+	getExcerpt := func(child *model.Item) string {
+		return ""
+	}
+
+	// act
+	result := getCollectionListing(testPather{}, children, getExcerpt)
+
+	// assert
+	if count := strings.Count(result, `<li class="collection-item">`); count != 1 {
+		t.Errorf("The listing should contain %d entry but contained %d: %s", 1, count, result)
+	}
+
+	if strings.Contains(result, "comment-20150301-153000") {
+		t.Errorf("The listing should not contain the comment: %s", result)
+	}
+}
+
+func Test_insertCollectionListing_NoPlaceholder(t *testing.T) {
+	// arrange
+	listing := `<ul class="collection-children"></ul>`
+
+	// act
+	emptyBody := insertCollectionListing("<h1>Recipes</h1>", listing, true)
+	nonEmptyBody := insertCollectionListing("<h1>Recipes</h1>\n<p>Text</p>", listing, false)
+
+	// assert
+	if emptyBody != "<h1>Recipes</h1>\n"+listing {
+		t.Errorf("The listing should be appended to collections with an empty body but the result was %q.", emptyBody)
+	}
+
+	if strings.Contains(nonEmptyBody, listing) {
+		t.Errorf("The listing should not be added to collections with a body but no placeholder.")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/util/slugutil"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// defaultSectionID is the anchor of the root item and of items whose route does not contain any letters or digits.
+const defaultSectionID = "home"
+
+var (
+	sectionIDAttributePattern  = regexp.MustCompile(`(\sid\s*=\s*")([^"]*)(")`)
+	sectionFragmentLinkPattern = regexp.MustCompile(`(\shref\s*=\s*"#)([^"]*)(")`)
+)
+
+type CombinedOrchestrator struct {
+	*Orchestrator
+}
+
+// GetCombinedConversionModel returns all documents of the repository in tree order as the sections of a single page.
+// Presentations are linked rather than inlined; comments and messages are not included.
+// Like everywhere else, drafts are only part of the index (and therefore of the page) in preview mode.
+func (orchestrator *CombinedOrchestrator) GetCombinedConversionModel(baseURL string) (combinedModel viewmodel.CombinedConversionModel, found bool) {
+
+	root := orchestrator.rootItem()
+	if root == nil {
+		return combinedModel, false
+	}
+
+	pathProvider := orchestrator.absolutePather(fmt.Sprintf("%s/", baseURL))
+	usedIDs := make(map[string]bool)
+
+	combinedModel.Base = getBaseModel(root, root, orchestrator.config)
+	combinedModel.Type = "all"
+	combinedModel.Sections = orchestrator.getSections(pathProvider, root, root, usedIDs)
+
+	return combinedModel, true
+}
+
+// getSections returns the section of the supplied item followed by the sections of all its descendants.
+func (orchestrator *CombinedOrchestrator) getSections(pathProvider paths.Pather, root, item *model.Item, usedIDs map[string]bool) []viewmodel.CombinedSection {
+
+	var sections []viewmodel.CombinedSection
+
+	if section, included := orchestrator.getSection(pathProvider, root, item, usedIDs); included {
+		sections = append(sections, section)
+	}
+
+	for _, child := range orchestrator.getChildren(item.Route()) {
+		sections = append(sections, orchestrator.getSections(pathProvider, root, child, usedIDs)...)
+	}
+
+	return sections
+}
+
+// getSection returns the section of the supplied item. Items whose type is not included in the combined view are skipped.
+func (orchestrator *CombinedOrchestrator) getSection(pathProvider paths.Pather, root, item *model.Item, usedIDs map[string]bool) (section viewmodel.CombinedSection, included bool) {
+
+	if item.IsVirtual() {
+		return section, false
+	}
+
+	switch item.Type {
+	case model.TypeComment, model.TypeMessage, model.TypeRedirect, model.TypeUnknown:
+		return section, false
+	}
+
+	section = viewmodel.CombinedSection{
+		Base:   getBaseModel(root, item, orchestrator.config),
+		ID:     getUniqueSectionID(getSectionID(item), usedIDs),
+		Linked: item.Type == model.TypePresentation,
+	}
+
+	if section.Linked {
+		return section, true
+	}
+
+	content, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, pathProvider, item)
+	if err != nil {
+		orchestrator.logger.Warn("Cannot convert content for route %q. Error: %s.", item.Route(), err.Error())
+		return section, true
+	}
+
+	// the child listing of collections is replaced by the sections of the children
+	if item.Type == model.TypeCollection {
+		content = insertCollectionListing(content, "", false)
+	}
+
+	section.Content = prefixSectionAnchors(content, section.ID, usedIDs)
+	return section, true
+}
+
+// getSectionID returns the anchor for the supplied item which is derived from its route (e.g. "documents/Sample" -> "documents-sample").
+func getSectionID(item *model.Item) string {
+	if sectionID := slugutil.FromText(strings.Replace(item.Route().Value(), "/", " ", -1)); sectionID != "" {
+		return sectionID
+	}
+
+	return defaultSectionID
+}
+
+// getUniqueSectionID returns the supplied section id or, if it has already been used,
+// the id with the first free numeric suffix ("id-2", "id-3", ...).
+func getUniqueSectionID(sectionID string, usedIDs map[string]bool) string {
+	uniqueID := sectionID
+	for suffix := 2; usedIDs[uniqueID]; suffix++ {
+		uniqueID = sectionID + "-" + strconv.Itoa(suffix)
+	}
+
+	usedIDs[uniqueID] = true
+	return uniqueID
+}
+
+// prefixSectionAnchors prefixes all ids of the supplied HTML code and the links pointing to them
+// with the section id (e.g. "#introduction" -> "#documents-sample-introduction"),
+// so the anchors of different documents do not collide when they are combined.
+// The prefixed ids are registered in the used ids, so they do not collide with the ids of other sections either
+// (e.g. the heading "sample" of the section "documents" and the section "documents/sample").
+func prefixSectionAnchors(htmlCode, sectionID string, usedIDs map[string]bool) string {
+	prefixedIDs := make(map[string]string)
+
+	htmlCode = sectionIDAttributePattern.ReplaceAllStringFunc(htmlCode, func(attribute string) string {
+		match := sectionIDAttributePattern.FindStringSubmatch(attribute)
+		prefixedID, exists := prefixedIDs[match[2]]
+		if !exists {
+			prefixedID = getUniqueSectionID(sectionID+"-"+match[2], usedIDs)
+			prefixedIDs[match[2]] = prefixedID
+		}
+
+		return match[1] + prefixedID + match[3]
+	})
+
+	return sectionFragmentLinkPattern.ReplaceAllStringFunc(htmlCode, func(attribute string) string {
+		match := sectionFragmentLinkPattern.FindStringSubmatch(attribute)
+		prefixedID, exists := prefixedIDs[match[2]]
+		if !exists {
+			prefixedID = sectionID + "-" + match[2]
+		}
+
+		return match[1] + prefixedID + match[3]
+	})
+}
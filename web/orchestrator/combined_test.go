@@ -0,0 +1,194 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/dataaccess/filesystem"
+	"github.com/andreaskoch/allmark/services/converter/markdowntohtml"
+	"github.com/andreaskoch/allmark/services/converter/markdowntohtml/imageprovider"
+	"github.com/andreaskoch/allmark/services/parser"
+	"github.com/andreaskoch/allmark/web/webpaths"
+)
+
+var testCombinedRepositoryFiles = map[string]string{
+	"readme.md":                          "# Root\n\nThe documentation.\n\nWelcome to the documentation.",
+	"documents/collection.md":            "# Documents\n\nAll documents.\n\nThe documents of this repository.",
+	"documents/a-first/document.md":      "# First\n\nThe first document.\n\n## Introduction\n\nSee [below](#introduction).",
+	"documents/b-second/document.md":     "# Second\n\nThe second document.\n\n## Introduction\n\nAnother introduction.",
+	"documents/b-second/draft/readme.md": "# Draft\n\nWork in progress.\n\nUnfinished.\n\n---\ndraft: yes\n",
+	"documents/c-slides/readme.md":       "# Slides\n\nA presentation.\n\n## Slide 1\n\n---\n\n## Slide 2\n\n---\ntype: presentation\n",
+}
+
+// newTestCombinedOrchestrator creates a combined orchestrator with a markdown converter for a repository with the supplied files.
+func newTestCombinedOrchestrator(t *testing.T, files map[string]string) (*CombinedOrchestrator, string) {
+	directory, _ := ioutil.TempDir("", "allmark-repository")
+
+	for relativePath, content := range files {
+		path := filepath.Join(directory, relativePath)
+		os.MkdirAll(filepath.Dir(path), 0700)
+		ioutil.WriteFile(path, []byte(content), 0600)
+	}
+
+	return newTestCombinedOrchestratorFromDirectory(t, directory), directory
+}
+
+// newTestCombinedOrchestratorFromDirectory creates a combined orchestrator for the repository in the given directory.
+func newTestCombinedOrchestratorFromDirectory(t *testing.T, directory string) *CombinedOrchestrator {
+	filesystem.ClearHashCache()
+	logger := console.New(loglevel.Fatal)
+	configuration := *config.New(directory)
+
+	repository, err := filesystem.NewRepository(logger, directory, configuration)
+	if err != nil {
+		os.RemoveAll(directory)
+		t.Fatalf("The repository could not be created. Error: %s", err)
+	}
+
+	webPathProvider := webpaths.NewWebPathProvider(webpaths.NewFactory(logger, repository), "/", "/tags/")
+	converter := markdowntohtml.New(logger, configuration.Markdown, imageprovider.NewImageProvider(webPathProvider.AbsolutePather("/"), nil))
+
+	itemParser, _ := parser.New(logger)
+	baseOrchestrator := newBaseOrchestrator(logger, configuration, repository, itemParser, converter, webPathProvider, nil)
+	return &CombinedOrchestrator{baseOrchestrator}
+}
+
+func Test_GetCombinedConversionModel_SectionsAreInTreeOrder(t *testing.T) {
+	// arrange
+	combinedOrchestrator, directory := newTestCombinedOrchestrator(t, testCombinedRepositoryFiles)
+	defer os.RemoveAll(directory)
+
+	// act
+	combinedModel, found := combinedOrchestrator.GetCombinedConversionModel("http://example.com")
+
+	// assert
+	if !found {
+		t.Fatalf("The combined model should be found.")
+	}
+
+	expectedIDs := []string{"home", "documents", "documents-a-first", "documents-b-second", "documents-c-slides"}
+	var sectionIDs []string
+	for _, section := range combinedModel.Sections {
+		sectionIDs = append(sectionIDs, section.ID)
+	}
+
+	if strings.Join(sectionIDs, " ") != strings.Join(expectedIDs, " ") {
+		t.Errorf("The sections should be %v but were %v.", expectedIDs, sectionIDs)
+	}
+}
+
+func Test_GetCombinedConversionModel_PresentationsAreLinked(t *testing.T) {
+	// arrange
+	combinedOrchestrator, directory := newTestCombinedOrchestrator(t, testCombinedRepositoryFiles)
+	defer os.RemoveAll(directory)
+
+	// act
+	combinedModel, _ := combinedOrchestrator.GetCombinedConversionModel("http://example.com")
+
+	// assert
+	for _, section := range combinedModel.Sections {
+		isPresentation := section.Type == "presentation"
+		if section.Linked != isPresentation {
+			t.Errorf("The section %q should be linked: %t", section.ID, isPresentation)
+		}
+
+		if isPresentation && section.Content != "" {
+			t.Errorf("The content of the presentation %q should not be inlined.", section.ID)
+		}
+	}
+}
+
+func Test_GetCombinedConversionModel_HeadingAnchorsArePrefixedWithTheSectionID(t *testing.T) {
+	// arrange
+	combinedOrchestrator, directory := newTestCombinedOrchestrator(t, testCombinedRepositoryFiles)
+	defer os.RemoveAll(directory)
+
+	// act
+	combinedModel, _ := combinedOrchestrator.GetCombinedConversionModel("http://example.com")
+
+	// assert
+	idPattern := regexp.MustCompile(`\sid="([^"]*)"`)
+	var anchors []string
+	for _, section := range combinedModel.Sections {
+		anchors = append(anchors, section.ID)
+		for _, match := range idPattern.FindAllStringSubmatch(section.Content, -1) {
+			anchors = append(anchors, match[1])
+		}
+	}
+
+	expectedAnchors := []string{
+		"home",
+		"documents",
+		"documents-a-first", "documents-a-first-introduction",
+		"documents-b-second", "documents-b-second-introduction",
+		"documents-c-slides",
+	}
+
+	if strings.Join(anchors, " ") != strings.Join(expectedAnchors, " ") {
+		t.Errorf("The anchors should be %v but were %v.", expectedAnchors, anchors)
+	}
+
+	if first := combinedModel.Sections[2].Content; !strings.Contains(first, `href="#documents-a-first-introduction"`) {
+		t.Errorf("The links to the sections of a document should be prefixed too: %s", first)
+	}
+}
+
+func Test_prefixSectionAnchors(t *testing.T) {
+	// arrange
+	html := `<h2 id="usage">Usage <a class="heading-anchor" href="#usage">#</a></h2><a href="/other#usage">Other</a>`
+	expected := `<h2 id="sample-usage">Usage <a class="heading-anchor" href="#sample-usage">#</a></h2><a href="/other#usage">Other</a>`
+
+	// act
+	result := prefixSectionAnchors(html, "sample", make(map[string]bool))
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_GetCombinedConversionModel_HeadingAndChildHaveTheSameAnchor_AnchorsAreUnique(t *testing.T) {
+	// arrange
+	combinedOrchestrator, directory := newTestCombinedOrchestrator(t, map[string]string{
+		"readme.md":                  "# Root\n\nThe documentation.\n\nWelcome.",
+		"documents/collection.md":    "# Documents\n\nAll documents.\n\n## Sample\n\nSee the [sample](#sample).",
+		"documents/sample/readme.md": "# Sample\n\nThe sample document.\n\nThe content.",
+	})
+	defer os.RemoveAll(directory)
+
+	// act
+	combinedModel, _ := combinedOrchestrator.GetCombinedConversionModel("http://example.com")
+
+	// assert
+	idPattern := regexp.MustCompile(`\sid="([^"]*)"`)
+	anchors := make(map[string]bool)
+	for _, section := range combinedModel.Sections {
+		if anchors[section.ID] {
+			t.Errorf("The anchor %q is used more than once.", section.ID)
+		}
+
+		anchors[section.ID] = true
+		for _, match := range idPattern.FindAllStringSubmatch(section.Content, -1) {
+			if anchors[match[1]] {
+				t.Errorf("The anchor %q is used more than once.", match[1])
+			}
+
+			anchors[match[1]] = true
+		}
+	}
+
+	if documents := combinedModel.Sections[1].Content; !strings.Contains(documents, `id="documents-sample"`) || !strings.Contains(documents, `href="#documents-sample"`) {
+		t.Errorf("The heading of the documents section and the link to it should keep their anchor: %s", documents)
+	}
+}
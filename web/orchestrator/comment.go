@@ -0,0 +1,135 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+)
+
+const (
+	// CommentFileName is the name of the markdown file of comment items.
+	CommentFileName = "comment.md"
+
+	// commentDirectoryPrefix is the prefix of the directory names of comment items (e.g. "comment-20150801-153000").
+	commentDirectoryPrefix = "comment-"
+)
+
+type CommentOrchestrator struct {
+	*Orchestrator
+}
+
+// AddComment stores a new comment item with the given author and body as a child of the item
+// with the supplied route and returns the route of the comment. The comment becomes visible
+// as soon as the repository index picks up the new file.
+func (orchestrator *CommentOrchestrator) AddComment(itemRoute route.Route, author, body string) (route.Route, error) {
+
+	// only comment on published items
+	if !orchestrator.ItemExists(itemRoute) {
+		return route.Route{}, fmt.Errorf("The item %q does not exist.", itemRoute)
+	}
+
+	repositoryItem := orchestrator.repository.Item(itemRoute)
+	directoryProvider, isDirectoryProvider := repositoryItem.(dataaccess.DirectoryProvider)
+	if repositoryItem == nil || !isDirectoryProvider || !repositoryItem.CanHaveChildren() {
+		return route.Route{}, fmt.Errorf("The item %q cannot be commented.", itemRoute)
+	}
+
+	date := time.Now()
+	commentDirectory, err := createCommentDirectory(directoryProvider.Directory(), date)
+	if err != nil {
+		return route.Route{}, err
+	}
+
+	commentFile := filepath.Join(commentDirectory, CommentFileName)
+	if err := ioutil.WriteFile(commentFile, []byte(getCommentMarkdown(author, body, date)), 0644); err != nil {
+		return route.Route{}, fmt.Errorf("Cannot write the comment file %q. Error: %s", commentFile, err.Error())
+	}
+
+	return route.NewFromRequest(itemRoute.Value() + "/" + filepath.Base(commentDirectory)), nil
+}
+
+// createCommentDirectory creates a new, unique directory for a comment with the given date in the supplied parent directory.
+func createCommentDirectory(parentDirectory string, date time.Time) (string, error) {
+	baseName := commentDirectoryPrefix + date.Format("20060102-150405")
+
+	for number := 1; number < 100; number++ {
+		directoryName := baseName
+		if number > 1 {
+			directoryName = fmt.Sprintf("%s-%d", baseName, number)
+		}
+
+		directory := filepath.Join(parentDirectory, directoryName)
+		err := os.Mkdir(directory, 0755)
+		if err == nil {
+			return directory, nil
+		}
+
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("Cannot create the comment directory %q. Error: %s", directory, err.Error())
+		}
+	}
+
+	return "", fmt.Errorf("Cannot create a unique comment directory in %q.", parentDirectory)
+}
+
+// commentTextEscaper replaces the characters of comment texts which would otherwise be interpreted
+// as HTML or as the start of links, images, includes, shortcodes or wiki links with HTML entities.
+var commentTextEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"{", "&#123;",
+	"}", "&#125;",
+	"[", "&#91;",
+	"]", "&#93;",
+)
+
+// the characters of comment authors which are not allowed
+var commentAuthorDisallowedCharactersPattern = regexp.MustCompile(`[^\pL\pN .'_-]+`)
+
+// anonymousCommentAuthor is the author of comments whose author name contains no allowed characters.
+const anonymousCommentAuthor = "Anonymous"
+
+// getCommentAuthor returns the supplied author name reduced to letters, numbers, spaces and the
+// characters . ' _ - (e.g. "Mallory <img src=x>" -> "Mallory img srcx"). The name starts with a letter
+// or a number so it is a valid meta data value.
+func getCommentAuthor(author string) string {
+	author = commentAuthorDisallowedCharactersPattern.ReplaceAllString(author, "")
+	author = strings.TrimLeftFunc(author, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	author = strings.Join(strings.Fields(author), " ")
+	if author == "" {
+		return anonymousCommentAuthor
+	}
+
+	return author
+}
+
+// getCommentMarkdown returns the markdown of a comment item. The author and the body are posted by visitors:
+// the author is reduced to a safe set of characters (see getCommentAuthor), the body is escaped so it cannot
+// contain HTML code or reference other items and quoted line by line so it cannot add headlines or meta data to the comment.
+func getCommentMarkdown(author, body string, date time.Time) string {
+	author = getCommentAuthor(author)
+
+	quotedLines := make([]string, 0)
+	for _, line := range strings.Split(strings.Replace(strings.TrimSpace(body), "\r\n", "\n", -1), "\n") {
+		quotedLines = append(quotedLines, strings.TrimRight("> "+commentTextEscaper.Replace(line), " "))
+	}
+
+	return fmt.Sprintf("# Comment by %s\n\n%s\n\n---\nauthor: %s\ndate: %s\n", author, strings.Join(quotedLines, "\n"), author, date.Format(time.RFC3339))
+}
@@ -0,0 +1,141 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/route"
+)
+
+func Test_getCommentMarkdown_BodyIsQuoted(t *testing.T) {
+	// arrange
+	date := time.Date(2015, 8, 1, 15, 30, 0, 0, time.UTC)
+
+	// act
+	result := getCommentMarkdown(" John \n Doe ", "Great post!\r\n\r\n---\ntype: redirect", date)
+
+	// assert
+	expected := "# Comment by John Doe\n\n> Great post!\n>\n> ---\n> type: redirect\n\n---\nauthor: John Doe\ndate: 2015-08-01T15:30:00Z\n"
+	if result != expected {
+		t.Errorf("The comment markdown should be %q but was %q.", expected, result)
+	}
+}
+
+func Test_getCommentAuthor(t *testing.T) {
+	// arrange
+	inputs := map[string]string{
+		"John Doe":                "John Doe",
+		"  Jane   O'Brien-Smith ": "Jane O'Brien-Smith",
+		"<b>Mallory</b>":          "bMalloryb",
+		"...Eve":                  "Eve",
+		"Zoë 2000":                "Zoë 2000",
+		"<script>":                "script",
+		"<>\"(){}":                anonymousCommentAuthor,
+	}
+
+	for author, expected := range inputs {
+
+		// act
+		result := getCommentAuthor(author)
+
+		// assert
+		if result != expected {
+			t.Errorf("getCommentAuthor(%q) returned %q but expected %q.", author, result, expected)
+		}
+	}
+}
+
+func Test_AddComment_CommentFileIsWrittenBelowTheItem(t *testing.T) {
+	// arrange
+	orchestrator, directory := newTestDraftOrchestrator(t, testDraftRepositoryFiles, false)
+	defer os.RemoveAll(directory)
+
+	commentOrchestrator := &CommentOrchestrator{orchestrator}
+
+	// act
+	commentRoute, err := commentOrchestrator.AddComment(route.NewFromRequest("documents/published"), "John Doe", "Nice article!")
+
+	// assert
+	if err != nil {
+		t.Fatalf("AddComment should not return an error but returned %s.", err)
+	}
+
+	if !strings.HasPrefix(commentRoute.Value(), "documents/published/comment-") {
+		t.Errorf("The comment should be a child of the item but its route was %q.", commentRoute.Value())
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(directory, filepath.FromSlash(commentRoute.Value()), CommentFileName))
+	if err != nil || !strings.Contains(string(content), "> Nice article!") {
+		t.Errorf("The comment file should contain the comment but contained %q (Error: %v).", content, err)
+	}
+}
+
+func Test_AddComment_Draft_IsRejected(t *testing.T) {
+	// arrange
+	orchestrator, directory := newTestDraftOrchestrator(t, testDraftRepositoryFiles, false)
+	defer os.RemoveAll(directory)
+
+	commentOrchestrator := &CommentOrchestrator{orchestrator}
+
+	// act
+	_, err := commentOrchestrator.AddComment(route.NewFromRequest("documents/draft"), "John Doe", "Nice article!")
+
+	// assert
+	if err == nil {
+		t.Errorf("Comments for unpublished items should be rejected.")
+	}
+}
+
+func Test_getCommentMarkdown_HTMLAndDirectivesAreEscaped(t *testing.T) {
+	// arrange
+	date := time.Date(2015, 8, 1, 15, 30, 0, 0, time.UTC)
+	body := "<script>alert(1)</script>\n> quoted <img src=x onerror=alert(1)>\n{{include: /documents/secret}} {{youtube id=abc}} [[Secret]] [link](javascript:alert(1))"
+
+	orchestrator, directory := newTestCombinedOrchestrator(t, map[string]string{
+		"documents/sample/document.md":          "# Sample\n\nContent",
+		"documents/sample/comment-1/comment.md": getCommentMarkdown("Mallory <img src=x onerror=alert(1)>", body, date),
+		"documents/secret/document.md":          "# Secret\n\nTOP SECRET",
+	})
+	defer os.RemoveAll(directory)
+
+	comment := orchestrator.getItem(route.NewFromRequest("documents/sample/comment-1"))
+	if comment == nil {
+		t.Fatalf("The comment was not found.")
+	}
+
+	if title := comment.GetTitle(); title != "Comment by Mallory img srcx onerroralert1" {
+		t.Errorf("The author in the headline should only contain the allowed characters but the title was %q.", title)
+	}
+
+	if author := comment.MetaData.Author; author != "Mallory img srcx onerroralert1" {
+		t.Errorf("The author in the meta data should only contain the allowed characters but was %q.", author)
+	}
+
+	// act
+	html, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, orchestrator.absolutePather("/"), comment)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The comment could not be converted. Error: %s", err)
+	}
+
+	for _, unexpected := range []string{"<script", "<img", "<b>", "TOP SECRET", "youtube.com", "<a ", "data-placeholder"} {
+		if strings.Contains(html, unexpected) {
+			t.Errorf("The converted comment should not contain %q: %s", unexpected, html)
+		}
+	}
+
+	for _, expected := range []string{"&lt;script&gt;alert(1)&lt;/script&gt;", "&#123;&#123;include: /documents/secret&#125;&#125;", "&#91;&#91;Secret&#93;&#93;"} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("The converted comment should contain the escaped text %q: %s", expected, html)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// A commentNode is a comment item and its replies.
+type commentNode struct {
+	item    *model.Item
+	replies []*commentNode
+}
+
+// getCommentThread arranges the supplied comments on the item with the given route into a tree.
+// The parent of a comment is defined by its "parent" block (the route or the folder name of another comment)
+// or else by its directory placement. Comments whose parent cannot be found (or whose parent references
+// form a loop) are logged and listed as top-level comments. All levels are ordered by date.
+func getCommentThread(logger logger.Logger, itemRoute route.Route, comments []*model.Item) []*commentNode {
+
+	nodesByRoute := make(map[string]*commentNode)
+	nodesByFolderName := make(map[string]*commentNode)
+	for _, comment := range comments {
+		node := &commentNode{item: comment}
+		nodesByRoute[comment.Route().Value()] = node
+		nodesByFolderName[strings.ToLower(comment.FolderName())] = node
+	}
+
+	// determine the parent of every comment (nil = top-level)
+	parents := make(map[*commentNode]*commentNode)
+	for _, comment := range comments {
+		node := nodesByRoute[comment.Route().Value()]
+
+		if parentReference := strings.TrimSpace(comment.MetaData.GetBlockValue("parent")); parentReference != "" {
+			parentRoute := route.NewFromRequest(parentReference)
+			if parentRoute.Value() == itemRoute.Value() {
+				continue
+			}
+
+			if parent, exists := nodesByRoute[parentRoute.Value()]; exists {
+				parents[node] = parent
+			} else if parent, exists := nodesByFolderName[strings.ToLower(parentRoute.LastComponentName())]; exists {
+				parents[node] = parent
+			} else {
+				logger.Warn("The parent %q of the comment %q was not found. Showing it as a top-level comment.", parentReference, comment.Route())
+			}
+
+			continue
+		}
+
+		directoryParent, exists := comment.Route().Parent()
+		if !exists || directoryParent.Value() == itemRoute.Value() {
+			continue
+		}
+
+		if parent, exists := nodesByRoute[directoryParent.Value()]; exists {
+			parents[node] = parent
+			continue
+		}
+
+		logger.Warn("The comment %q is not placed below %q or another comment. Showing it as a top-level comment.", comment.Route(), itemRoute)
+	}
+
+	// assemble the tree; loops of parent references are broken by showing
+	// the first comment of the loop as a top-level comment
+	thread := make([]*commentNode, 0)
+	for _, comment := range comments {
+		node := nodesByRoute[comment.Route().Value()]
+		parent, hasParent := parents[node]
+
+		if hasParent && isCommentLoop(node, parents) {
+			logger.Warn("The comment %q is part of a loop of parent references. Showing it as a top-level comment.", comment.Route())
+			delete(parents, node)
+			hasParent = false
+		}
+
+		if !hasParent {
+			thread = append(thread, node)
+			continue
+		}
+
+		parent.replies = append(parent.replies, node)
+	}
+
+	sortCommentNodes(thread)
+	return thread
+}
+
+// isCommentLoop returns true if the parent chain of the supplied node leads back to the node.
+func isCommentLoop(node *commentNode, parents map[*commentNode]*commentNode) bool {
+	visited := map[*commentNode]bool{node: true}
+	for parent, exists := parents[node]; exists; parent, exists = parents[parent] {
+		if visited[parent] {
+			return true
+		}
+
+		visited[parent] = true
+	}
+
+	return false
+}
+
+// sortCommentNodes sorts the supplied comments and all of their replies by date (oldest first).
+func sortCommentNodes(nodes []*commentNode) {
+	sort.Sort(commentNodesByDate(nodes))
+
+	for _, node := range nodes {
+		sortCommentNodes(node.replies)
+	}
+}
+
+// commentNodesByDate sorts comments by date (oldest first) and by route if the dates are equal.
+type commentNodesByDate []*commentNode
+
+func (nodes commentNodesByDate) Len() int {
+	return len(nodes)
+}
+
+func (nodes commentNodesByDate) Swap(i, j int) {
+	nodes[i], nodes[j] = nodes[j], nodes[i]
+}
+
+func (nodes commentNodesByDate) Less(i, j int) bool {
+	date1, date2 := nodes[i].item.Date(), nodes[j].item.Date()
+	if !date1.Equal(date2) {
+		return date1.Before(date2)
+	}
+
+	return nodes[i].item.Route().Value() < nodes[j].item.Route().Value()
+}
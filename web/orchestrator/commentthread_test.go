@@ -0,0 +1,106 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+func newTestComment(commentRoute, date, parent string) *model.Item {
+	item := model.NewItem(route.NewFromRequest(commentRoute), nil, dataaccess.TypePhysical)
+	item.Type = model.TypeComment
+	item.MetaData.AddBlock("date", date)
+	if parent != "" {
+		item.MetaData.AddBlock("parent", parent)
+	}
+
+	return item
+}
+
+func getThreadRoutes(nodes []*commentNode) []string {
+	routes := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		routes = append(routes, node.item.Route().Value())
+	}
+
+	return routes
+}
+
+func assertThreadRoutes(t *testing.T, name string, nodes []*commentNode, expected ...string) {
+	routes := getThreadRoutes(nodes)
+	if len(routes) != len(expected) {
+		t.Errorf("The %s should be %v but were %v.", name, expected, routes)
+		return
+	}
+
+	for index, itemRoute := range routes {
+		if itemRoute != expected[index] {
+			t.Errorf("The %s should be %v but were %v.", name, expected, routes)
+			return
+		}
+	}
+}
+
+func Test_getCommentThread_TwoLevels_RepliesAreNestedAndOrderedByDate(t *testing.T) {
+	// arrange
+	itemRoute := route.NewFromRequest("documents/post")
+	comments := []*model.Item{
+		newTestComment("documents/post/comment-b", "2015-03-02 10:00", ""),
+		newTestComment("documents/post/comment-a", "2015-03-01 10:00", ""),
+		newTestComment("documents/post/comment-a/reply-2", "2015-03-01 12:00", ""),
+		newTestComment("documents/post/comment-a/reply-1", "2015-03-01 11:00", ""),
+		newTestComment("documents/post/comment-c", "2015-03-03 10:00", "comment-b"),
+		newTestComment("documents/post/comment-d", "2015-03-04 10:00", "documents/post/comment-missing"),
+	}
+
+	// act
+	thread := getCommentThread(console.New(loglevel.Fatal), itemRoute, comments)
+
+	// assert
+	assertThreadRoutes(t, "top-level comments", thread,
+		"documents/post/comment-a",
+		"documents/post/comment-b",
+		"documents/post/comment-d",
+	)
+
+	if len(thread) != 3 {
+		t.FailNow()
+	}
+
+	assertThreadRoutes(t, "replies of the first comment", thread[0].replies,
+		"documents/post/comment-a/reply-1",
+		"documents/post/comment-a/reply-2",
+	)
+
+	assertThreadRoutes(t, "replies of the second comment", thread[1].replies, "documents/post/comment-c")
+	assertThreadRoutes(t, "replies of the orphaned comment", thread[2].replies)
+}
+
+func Test_getCommentThread_ParentLoop_LoopIsBrokenAndNoCommentIsLost(t *testing.T) {
+	// arrange
+	itemRoute := route.NewFromRequest("documents/post")
+	comments := []*model.Item{
+		newTestComment("documents/post/comment-a", "2015-03-01 10:00", "comment-b"),
+		newTestComment("documents/post/comment-b", "2015-03-02 10:00", "comment-a"),
+	}
+
+	// act
+	thread := getCommentThread(console.New(loglevel.Fatal), itemRoute, comments)
+
+	// assert
+	assertThreadRoutes(t, "top-level comments", thread, "documents/post/comment-a")
+
+	if len(thread) != 1 {
+		t.FailNow()
+	}
+
+	assertThreadRoutes(t, "replies of the first comment", thread[0].replies, "documents/post/comment-b")
+}
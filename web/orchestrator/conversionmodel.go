@@ -6,6 +6,7 @@ package orchestrator
 
 import (
 	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"fmt"
 )
@@ -35,7 +36,7 @@ func (orchestrator *ConversionModelOrchestrator) GetConversionModel(baseURL stri
 	rootPathProvider := orchestrator.absolutePather(fmt.Sprintf("%s/", baseURL))
 
 	// convert content
-	convertedContent, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, rootPathProvider, item)
+	convertedContent, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, rootPathProvider, item)
 	if err != nil {
 		return model, false
 	}
@@ -51,3 +52,23 @@ func (orchestrator *ConversionModelOrchestrator) GetConversionModel(baseURL stri
 
 	return model, true
 }
+
+// GetPresentationConversionModel returns the conversion model of the presentation with the given route
+// and its slides (without speaker notes). Items which are not presentations are not found.
+func (orchestrator *ConversionModelOrchestrator) GetPresentationConversionModel(baseURL string, route route.Route) (presentationModel viewmodel.PresentationConversionModel, found bool) {
+
+	item := orchestrator.getItem(route)
+	if item == nil || item.Type != model.TypePresentation {
+		return presentationModel, false
+	}
+
+	conversionModel, found := orchestrator.GetConversionModel(baseURL, route)
+	if !found {
+		return presentationModel, false
+	}
+
+	return viewmodel.PresentationConversionModel{
+		ConversionModel: conversionModel,
+		Slides:          getSlides(conversionModel.Content),
+	}, true
+}
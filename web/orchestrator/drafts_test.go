@@ -0,0 +1,160 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/dataaccess/filesystem"
+	"github.com/andreaskoch/allmark/services/parser"
+	"github.com/andreaskoch/allmark/web/webpaths"
+)
+
+var testDraftRepositoryFiles = map[string]string{
+	"readme.md":                     "# Root",
+	"documents/published/readme.md": "# Published\n\nA published document.",
+	"documents/draft/readme.md":     "# Draft\n\nWork in progress.\n\n---\ndraft: yes\n",
+}
+
+var testScheduledRepositoryFiles = map[string]string{
+	"readme.md":                  "# Root",
+	"documents/past/readme.md":   "# Past\n\nPublished in the past.\n\n---\ndate: 2015-01-01\n",
+	"documents/future/readme.md": "# Future\n\nScheduled for the future.\n\n---\ndate: 2999-01-01\n",
+}
+
+// newTestDraftOrchestrator creates an orchestrator for a repository with the supplied files (relative path -> content).
+func newTestDraftOrchestrator(t *testing.T, files map[string]string, preview bool) (*Orchestrator, string) {
+	filesystem.ClearHashCache()
+	directory, _ := ioutil.TempDir("", "allmark-repository")
+
+	for relativePath, content := range files {
+		path := filepath.Join(directory, relativePath)
+		os.MkdirAll(filepath.Dir(path), 0700)
+		ioutil.WriteFile(path, []byte(content), 0600)
+	}
+
+	logger := console.New(loglevel.Fatal)
+	configuration := *config.New(directory)
+	configuration.Web.Preview = preview
+
+	repository, err := filesystem.NewRepository(logger, directory, configuration)
+	if err != nil {
+		os.RemoveAll(directory)
+		t.Fatalf("The repository could not be created. Error: %s", err)
+	}
+
+	itemParser, _ := parser.New(logger)
+	return newBaseOrchestrator(logger, configuration, repository, itemParser, nil, webpaths.WebPathProvider{}, nil), directory
+}
+
+func Test_index_DraftItem_IsNotPublished(t *testing.T) {
+	// arrange
+	orchestrator, directory := newTestDraftOrchestrator(t, testDraftRepositoryFiles, false)
+	defer os.RemoveAll(directory)
+
+	// act
+	draft := orchestrator.getItem(route.NewFromRequest("documents/draft"))
+	published := orchestrator.getItem(route.NewFromRequest("documents/published"))
+
+	// assert
+	if draft != nil {
+		t.Errorf("The draft should not be part of the index.")
+	}
+
+	if published == nil {
+		t.Errorf("Items without a draft block should be published by default.")
+	}
+
+	if children := orchestrator.getChildren(route.NewFromRequest("documents")); len(children) != 1 {
+		t.Errorf("The draft should not be listed as a child. Children: %v", children)
+	}
+}
+
+func Test_index_DraftItemAndPreview_IsPublished(t *testing.T) {
+	// arrange
+	orchestrator, directory := newTestDraftOrchestrator(t, testDraftRepositoryFiles, true)
+	defer os.RemoveAll(directory)
+
+	// act
+	draft := orchestrator.getItem(route.NewFromRequest("documents/draft"))
+
+	// assert
+	if draft == nil {
+		t.Errorf("The draft should be part of the index if the preview is enabled.")
+	}
+}
+
+func Test_index_ScheduledItems_OnlyItemsWithAPastDateArePublished(t *testing.T) {
+	// arrange
+	orchestrator, directory := newTestDraftOrchestrator(t, testScheduledRepositoryFiles, false)
+	defer os.RemoveAll(directory)
+
+	// act
+	past := orchestrator.getItem(route.NewFromRequest("documents/past"))
+	future := orchestrator.getItem(route.NewFromRequest("documents/future"))
+
+	// assert
+	if past == nil {
+		t.Errorf("Items with a past date should be published.")
+	}
+
+	if future != nil {
+		t.Errorf("Items with a future date should not be published before their date.")
+	}
+}
+
+func Test_index_ScheduledItem_IsPublishedWhenItsDateHasPassed(t *testing.T) {
+	// arrange
+	date := time.Now().Add(2 * time.Second).UTC().Format(time.RFC3339)
+	orchestrator, directory := newTestDraftOrchestrator(t, map[string]string{
+		"readme.md":                "# Root",
+		"documents/soon/readme.md": "# Soon\n\nScheduled for the next seconds.\n\n---\ndate: " + date + "\n",
+	}, false)
+	defer os.RemoveAll(directory)
+
+	updates := make(chan dataaccess.Update, 1)
+	orchestrator.updates = updates
+
+	if orchestrator.getItem(route.NewFromRequest("documents/soon")) != nil {
+		t.Fatalf("The item should not be published before its date.")
+	}
+
+	// act
+	select {
+	case update := <-updates:
+		orchestrator.UpdateCache(update)
+
+	case <-time.After(10 * time.Second):
+		t.Fatalf("No update has been sent for the scheduled item.")
+	}
+
+	// assert
+	if orchestrator.getItem(route.NewFromRequest("documents/soon")) == nil {
+		t.Errorf("The item should be published after its date has passed.")
+	}
+}
+
+func Test_index_ScheduledItemAndPreview_IsPublished(t *testing.T) {
+	// arrange
+	orchestrator, directory := newTestDraftOrchestrator(t, testScheduledRepositoryFiles, true)
+	defer os.RemoveAll(directory)
+
+	// act
+	future := orchestrator.getItem(route.NewFromRequest("documents/future"))
+
+	// assert
+	if future == nil {
+		t.Errorf("Items with a future date should be published if the preview is enabled.")
+	}
+}
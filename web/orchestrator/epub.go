@@ -0,0 +1,210 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/epub"
+)
+
+// CoverBlockName is the name of the meta data block which contains the path of the cover image
+// of a collection relative to the collection (e.g. "cover: files/cover.jpg").
+const CoverBlockName = "cover"
+
+var (
+	epubImagePattern           = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	epubImageSourcePattern     = regexp.MustCompile(`(?i)(\ssrc\s*=\s*")([^"]*)(")`)
+	epubResponsiveImagePattern = regexp.MustCompile(`(?i)\s(?:srcset|sizes)\s*=\s*"[^"]*"`)
+)
+
+type EPUBOrchestrator struct {
+	*Orchestrator
+}
+
+// GetBook returns the book of the collection with the given route. The collection itself and all of
+// its children become chapters (nested collections are expanded in place) and all images of the
+// repository which are referenced by the chapters are embedded. Items which are not collections are not found.
+func (orchestrator *EPUBOrchestrator) GetBook(collectionRoute route.Route) (book epub.Book, found bool) {
+	collection := orchestrator.getItem(collectionRoute)
+	if collection == nil || collection.Type != model.TypeCollection {
+		return book, false
+	}
+
+	images := newEPUBImages(orchestrator.getFile)
+	chapters := orchestrator.getChapters(orchestrator.absolutePather("/"), collection, images)
+
+	book = epub.Book{
+		Identifier: "urn:allmark:" + hashutil.SHA1FromString(collection.Route().Value()),
+		Title:      collection.GetTitle(),
+		Author:     collection.MetaData.Author,
+		Language:   collection.MetaData.Language,
+		Modified:   collection.MetaData.LastModifiedDate,
+		Chapters:   chapters,
+	}
+
+	if book.Author == "" {
+		book.Author = orchestrator.config.Web.DefaultAuthor
+	}
+
+	if book.Language == "" {
+		book.Language = orchestrator.config.Web.DefaultLanguage
+	}
+
+	// the cover image
+	if coverPath := collection.MetaData.GetBlockValue(CoverBlockName); coverPath != "" {
+		if !strings.HasPrefix(coverPath, "/") {
+			coverPath = "/" + collection.Route().Value() + "/" + coverPath
+		}
+
+		if imagePath, embedded := images.Add(coverPath); embedded {
+			book.Cover = imagePath
+		} else {
+			orchestrator.logger.Warn("The cover image %q of collection %q was not found.", coverPath, collection.Route())
+		}
+	}
+
+	book.Images = images.List()
+	return book, true
+}
+
+// getChapters returns the chapters of the supplied collection: the collection itself (unless it has no content)
+// followed by its children. Comments are not included.
+func (orchestrator *EPUBOrchestrator) getChapters(pathProvider paths.Pather, collection *model.Item, images *epubImages) []epub.Chapter {
+	var chapters []epub.Chapter
+
+	if strings.TrimSpace(collection.Content) != "" {
+		content := orchestrator.getChapterContent(pathProvider, collection)
+
+		// the table of contents of the book replaces the child listing
+		content = insertCollectionListing(content, "", false)
+		chapters = append(chapters, epub.Chapter{Title: collection.GetTitle(), Content: images.Embed(content)})
+	}
+
+	for _, child := range orchestrator.getChildren(collection.Route()) {
+		switch child.Type {
+		case model.TypeComment:
+			continue
+
+		case model.TypeCollection:
+			chapters = append(chapters, orchestrator.getChapters(pathProvider, child, images)...)
+
+		default:
+			content := orchestrator.getChapterContent(pathProvider, child)
+			chapters = append(chapters, epub.Chapter{Title: child.GetTitle(), Content: images.Embed(content)})
+		}
+	}
+
+	return chapters
+}
+
+// getChapterContent returns the converted HTML content of the supplied item.
+func (orchestrator *EPUBOrchestrator) getChapterContent(pathProvider paths.Pather, item *model.Item) string {
+	content, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, pathProvider, item)
+	if err != nil {
+		orchestrator.logger.Warn("Cannot convert content for route %q. Error: %s.", item.Route(), err.Error())
+		return ""
+	}
+
+	return content
+}
+
+// epubImages collects the images which are embedded in a book.
+type epubImages struct {
+	getFile func(route route.Route) *model.File
+
+	images []epub.Image
+
+	// the paths of the embedded images by the route of their file
+	paths map[string]string
+}
+
+func newEPUBImages(getFile func(route route.Route) *model.File) *epubImages {
+	return &epubImages{
+		getFile: getFile,
+		paths:   make(map[string]string),
+	}
+}
+
+// Add embeds the image with the given request path (e.g. "/documents/sample/files/photo.jpg")
+// and returns its path in the book (e.g. "images/image-1.jpg").
+// The image is not embedded if it does not exist or cannot be read.
+func (images *epubImages) Add(requestPath string) (imagePath string, embedded bool) {
+	fileRoute := route.NewFromRequest(requestPath)
+	if imagePath, exists := images.paths[fileRoute.Value()]; exists {
+		return imagePath, true
+	}
+
+	file := images.getFile(fileRoute)
+	if file == nil || !model.IsImageFile(file) {
+		return "", false
+	}
+
+	mimeType, err := model.GetMimeType(file)
+	if err != nil {
+		return "", false
+	}
+
+	var data []byte
+	err = file.Data(func(content io.ReadSeeker) error {
+		var readErr error
+		data, readErr = ioutil.ReadAll(content)
+		return readErr
+	})
+
+	if err != nil {
+		return "", false
+	}
+
+	// the file names are generated because the names of the files might not be valid in the archive (e.g. spaces)
+	imagePath = fmt.Sprintf("images/image-%d%s", len(images.images)+1, strings.ToLower(path.Ext(fileRoute.LastComponentName())))
+	images.images = append(images.images, epub.Image{
+		Path:      imagePath,
+		MediaType: mimeType,
+		Data:      data,
+	})
+
+	images.paths[fileRoute.Value()] = imagePath
+	return imagePath, true
+}
+
+// Embed embeds all local images which are referenced by the supplied HTML code and replaces their sources
+// with the paths in the book. The responsive image sources (thumbnails) are removed.
+func (images *epubImages) Embed(htmlCode string) string {
+	return epubImagePattern.ReplaceAllStringFunc(htmlCode, func(imageTag string) string {
+		imageTag = epubResponsiveImagePattern.ReplaceAllString(imageTag, "")
+
+		return epubImageSourcePattern.ReplaceAllStringFunc(imageTag, func(source string) string {
+			match := epubImageSourcePattern.FindStringSubmatch(source)
+			imageURL, err := url.Parse(html.UnescapeString(match[2]))
+			if err != nil || imageURL.Scheme != "" || imageURL.Host != "" {
+				return source
+			}
+
+			imagePath, embedded := images.Add(imageURL.Path)
+			if !embedded {
+				return source
+			}
+
+			return match[1] + html.EscapeString(imagePath) + match[3]
+		})
+	})
+}
+
+// List returns all embedded images in the order they have been added.
+func (images *epubImages) List() []epub.Image {
+	return images.images
+}
@@ -0,0 +1,76 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// A testEPUBFile is a file with a route, a mime type and content.
+type testEPUBFile struct {
+	testSocialFile
+	mimeType string
+	content  string
+}
+
+func (file testEPUBFile) MimeType() (string, error) {
+	return file.mimeType, nil
+}
+
+func (file testEPUBFile) Data(contentReader func(content io.ReadSeeker) error) error {
+	return contentReader(strings.NewReader(file.content))
+}
+
+func newTestEPUBImages(files ...testEPUBFile) *epubImages {
+	return newEPUBImages(func(fileRoute route.Route) *model.File {
+		for _, file := range files {
+			if file.route.Value() == fileRoute.Value() {
+				return &model.File{File: file}
+			}
+		}
+
+		return nil
+	})
+}
+
+func Test_epubImages_Embed_LocalImagesAreEmbeddedOnce(t *testing.T) {
+	// arrange
+	images := newTestEPUBImages(
+		testEPUBFile{testSocialFile{route: route.NewFromRequest("cookbook/pancakes/files/pancakes.JPG")}, "image/jpeg", "pancakes"},
+		testEPUBFile{testSocialFile{route: route.NewFromRequest("cookbook/pancakes/files/notes.pdf")}, "application/pdf", "notes"},
+	)
+
+	html := `<img srcset="/thumbnails/1-320-240.jpg 320w" sizes="100vw" src="/cookbook/pancakes/files/pancakes.JPG" alt="Pancakes"/>` +
+		`<img src="/cookbook/pancakes/files/pancakes.JPG" alt="Again"/>` +
+		`<img src="/cookbook/pancakes/files/notes.pdf" alt="Not an image"/>` +
+		`<img src="http://example.com/remote.png" alt="Remote"/>`
+
+	expected := `<img src="images/image-1.jpg" alt="Pancakes"/>` +
+		`<img src="images/image-1.jpg" alt="Again"/>` +
+		`<img src="/cookbook/pancakes/files/notes.pdf" alt="Not an image"/>` +
+		`<img src="http://example.com/remote.png" alt="Remote"/>`
+
+	// act
+	result := images.Embed(html)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result should be %q but was %q.", expected, result)
+	}
+
+	embeddedImages := images.List()
+	if len(embeddedImages) != 1 {
+		t.Fatalf("There should be %d embedded image but there were %d.", 1, len(embeddedImages))
+	}
+
+	if image := embeddedImages[0]; image.Path != "images/image-1.jpg" || image.MediaType != "image/jpeg" || string(image.Data) != "pancakes" {
+		t.Errorf("The embedded image is not correct: %q, %q, %q", image.Path, image.MediaType, image.Data)
+	}
+}
@@ -10,17 +10,21 @@ import (
 	"github.com/andreaskoch/allmark/dataaccess"
 	"github.com/andreaskoch/allmark/services/converter"
 	"github.com/andreaskoch/allmark/services/parser"
+	"github.com/andreaskoch/allmark/services/thumbnail"
 	"github.com/andreaskoch/allmark/web/webpaths"
 )
 
-func NewFactory(logger logger.Logger, config config.Config, repository dataaccess.Repository, parser parser.Parser, converter converter.Converter, webPathProvider webpaths.WebPathProvider) *Factory {
+func NewFactory(logger logger.Logger, config config.Config, repository dataaccess.Repository, parser parser.Parser, converter converter.Converter, webPathProvider webpaths.WebPathProvider, thumbnailIndex *thumbnail.Index) *Factory {
 
-	baseOrchestrator := newBaseOrchestrator(logger, config, repository, parser, converter, webPathProvider)
+	baseOrchestrator := newBaseOrchestrator(logger, config, repository, parser, converter, webPathProvider, thumbnailIndex)
 
 	// listen for updates
 	repositoryUpdates := make(chan dataaccess.Update, 1)
 	repository.Subscribe(repositoryUpdates)
 
+	// scheduled items are published through the same channel
+	baseOrchestrator.updates = repositoryUpdates
+
 	go func() {
 		for update := range repositoryUpdates {
 			logger.Info("Received and update (%s). Resetting the the cache.", update.String())
@@ -44,14 +48,21 @@ type Factory struct {
 	conversionModelOrchestrator       *ConversionModelOrchestrator
 	feedOrchestrator                  *FeedOrchestrator
 	fileOrchestrator                  *FileOrchestrator
+	locationsOrchestrator             *LocationsOrchestrator
+	searchIndexOrchestrator           *SearchIndexOrchestrator
+	itemsOrchestrator                 *ItemsOrchestrator
 	navigationOrchestrator            *NavigationOrchestrator
 	openSearchDescriptionOrchestrator *OpenSearchDescriptionOrchestrator
 	searchOrchestrator                *SearchOrchestrator
 	sitemapOrchestrator               *SitemapOrchestrator
 	tagsOrchestrator                  *TagsOrchestrator
 	xmlSitemapOrchestrator            *XmlSitemapOrchestrator
+	robotsTxtOrchestrator             *RobotsTxtOrchestrator
 	typeAheadOrchestrator             *TypeAheadOrchestrator
 	titlesOrchestrator                *TitlesOrchestrator
+	renderOrchestrator                *RenderOrchestrator
+	commentOrchestrator               *CommentOrchestrator
+	messageOrchestrator               *MessageOrchestrator
 	updateOrchestrator                *UpdateOrchestrator
 }
 
@@ -120,6 +131,42 @@ func (factory *Factory) NewOpenSearchDescriptionOrchestrator() *OpenSearchDescri
 	return factory.openSearchDescriptionOrchestrator
 }
 
+func (factory *Factory) NewLocationsOrchestrator() *LocationsOrchestrator {
+	if factory.locationsOrchestrator != nil {
+		return factory.locationsOrchestrator
+	}
+
+	factory.locationsOrchestrator = &LocationsOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+
+	return factory.locationsOrchestrator
+}
+
+func (factory *Factory) NewItemsOrchestrator() *ItemsOrchestrator {
+	if factory.itemsOrchestrator != nil {
+		return factory.itemsOrchestrator
+	}
+
+	factory.itemsOrchestrator = &ItemsOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+
+	return factory.itemsOrchestrator
+}
+
+func (factory *Factory) NewSearchIndexOrchestrator() *SearchIndexOrchestrator {
+	if factory.searchIndexOrchestrator != nil {
+		return factory.searchIndexOrchestrator
+	}
+
+	factory.searchIndexOrchestrator = &SearchIndexOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+
+	return factory.searchIndexOrchestrator
+}
+
 func (factory *Factory) NewSearchOrchestrator() *SearchOrchestrator {
 	if factory.searchOrchestrator != nil {
 		return factory.searchOrchestrator
@@ -179,6 +226,19 @@ func (factory *Factory) NewViewModelOrchestrator() *ViewModelOrchestrator {
 	return factory.viewModelOrchestrator
 }
 
+func (factory *Factory) NewRobotsTxtOrchestrator() *RobotsTxtOrchestrator {
+
+	if factory.robotsTxtOrchestrator != nil {
+		return factory.robotsTxtOrchestrator
+	}
+
+	factory.robotsTxtOrchestrator = &RobotsTxtOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+
+	return factory.robotsTxtOrchestrator
+}
+
 func (factory *Factory) NewXMLSitemapOrchestrator() *XmlSitemapOrchestrator {
 
 	if factory.xmlSitemapOrchestrator != nil {
@@ -218,6 +278,31 @@ func (factory *Factory) NewTitlesOrchestrator() *TitlesOrchestrator {
 	return factory.titlesOrchestrator
 }
 
+func (factory *Factory) NewRenderOrchestrator() *RenderOrchestrator {
+
+	if factory.renderOrchestrator != nil {
+		return factory.renderOrchestrator
+	}
+
+	factory.renderOrchestrator = &RenderOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+
+	return factory.renderOrchestrator
+}
+
+func (factory *Factory) NewCommentOrchestrator() *CommentOrchestrator {
+	if factory.commentOrchestrator != nil {
+		return factory.commentOrchestrator
+	}
+
+	factory.commentOrchestrator = &CommentOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+
+	return factory.commentOrchestrator
+}
+
 func (factory *Factory) NewUpdateOrchestrator() *UpdateOrchestrator {
 	if factory.updateOrchestrator != nil {
 		return factory.updateOrchestrator
@@ -231,9 +316,36 @@ func (factory *Factory) NewUpdateOrchestrator() *UpdateOrchestrator {
 	return factory.updateOrchestrator
 }
 
+// NewMessageOrchestrator creates a new message orchestrator.
+func (factory *Factory) NewMessageOrchestrator() *MessageOrchestrator {
+	if factory.messageOrchestrator != nil {
+		return factory.messageOrchestrator
+	}
+
+	factory.messageOrchestrator = &MessageOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+
+	return factory.messageOrchestrator
+}
+
 // NewAliasIndexOrchestrator creates a new alias-index orchestrator.
 func (factory *Factory) NewAliasIndexOrchestrator() *AliasIndexOrchestrator {
 	return &AliasIndexOrchestrator{
 		Orchestrator: factory.baseOrchestrator,
 	}
 }
+
+// NewEPUBOrchestrator creates a new EPUB orchestrator.
+func (factory *Factory) NewEPUBOrchestrator() *EPUBOrchestrator {
+	return &EPUBOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+}
+
+// NewCombinedOrchestrator creates a new orchestrator for the single-page view of all documents.
+func (factory *Factory) NewCombinedOrchestrator() *CombinedOrchestrator {
+	return &CombinedOrchestrator{
+		Orchestrator: factory.baseOrchestrator,
+	}
+}
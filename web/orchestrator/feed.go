@@ -5,11 +5,18 @@
 package orchestrator
 
 import (
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/util/hashutil"
 	"github.com/andreaskoch/allmark/model"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"fmt"
+	"html"
+	"time"
 )
 
+// feedExcerptLength is the maximum number of characters of a feed entry description.
+const feedExcerptLength = 300
+
 // A FeedOrchestrator provides feed models.
 type FeedOrchestrator struct {
 	*Orchestrator
@@ -58,7 +65,7 @@ func (orchestrator *FeedOrchestrator) getItems(baseURL string, itemsPerPage, pag
 
 	var feedEntries []viewmodel.FeedEntry
 
-	latestItems, found := pagedItems(orchestrator.getLatestItems(rootItem.Route()), itemsPerPage, page)
+	latestItems, found := pagedItems(getFeedItems(orchestrator.getLatestItems(rootItem.Route())), itemsPerPage, page)
 	if !found {
 		return []viewmodel.FeedEntry{}, fmt.Errorf("No items found (Items per page: %v, Page: %v)", itemsPerPage, page)
 	}
@@ -74,27 +81,66 @@ func (orchestrator *FeedOrchestrator) createFeedEntryModel(baseURL string, item
 
 	rootPathProvider := orchestrator.absolutePather(fmt.Sprintf("%s/", baseURL))
 
-	// item location
-	location := rootPathProvider.Path(item.Route().Value())
-
 	// content
-	content, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, rootPathProvider, item)
+	content, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, rootPathProvider, item)
 	if err != nil {
 		content = err.Error()
 	}
 
-	// append the description
-	if item.Description != "" {
-		content = fmt.Sprintf("<p>%s</p>\n\n%s", item.Description, content)
+	return newFeedEntry(rootPathProvider, item, content, orchestrator.getExcerpt(item, feedExcerptLength))
+}
+
+// newFeedEntry creates a feed entry for the supplied item, its rendered content and its excerpt (see getExcerpt)
+// which can be used for RSS as well as for Atom feeds.
+func newFeedEntry(pathProvider paths.Pather, item *model.Item, content, excerpt string) viewmodel.FeedEntry {
+
+	description := html.EscapeString(excerpt)
+
+	publicationDate := item.Date()
+	updated := item.MetaData.LastModifiedDate
+	if updated.Before(publicationDate) {
+		updated = publicationDate
 	}
 
-	// creation date
-	creationDate := item.MetaData.CreationDate.Format("2006-01-02")
+	// messages have no headline and are linked by their permalink
+	title := item.Title
+	link := pathProvider.Path(item.Route().Value())
+	if item.Type == model.TypeMessage {
+		title = model.GetExcerpt(content, messageTitleLength)
+		link = pathProvider.Path(getMessagePermalink(item))
+	}
 
 	return viewmodel.FeedEntry{
-		Title:       item.Title,
-		Description: content,
-		Link:        location,
-		PubDate:     creationDate,
+		ID:          getFeedEntryID(item),
+		Title:       title,
+		Description: description,
+		Content:     content,
+		Link:        link,
+		PubDate:     publicationDate.Format(time.RFC1123Z),
+		Updated:     updated.Format(time.RFC3339),
 	}
 }
+
+// getFeedEntryID returns a URN for the supplied item which is derived from the item route,
+// so the id of an entry stays the same when the content of the item changes.
+func getFeedEntryID(item *model.Item) string {
+	return fmt.Sprintf("urn:allmark:%s", hashutil.SHA1FromString(item.Route().Value()))
+}
+
+// getFeedItems returns all published documents and messages of the supplied items ordered by their date (newest first).
+func getFeedItems(items []*model.Item) []*model.Item {
+	feedItems := make([]*model.Item, 0, len(items))
+	for _, item := range items {
+		if (item.Type != model.TypeDocument && item.Type != model.TypeMessage) || item.IsVirtual() || item.IsDraft() {
+			continue
+		}
+
+		feedItems = append(feedItems, item)
+	}
+
+	model.SortItemsBy(func(item1, item2 *model.Item) bool {
+		return item1.Date().After(item2.Date())
+	}).Sort(feedItems)
+
+	return feedItems
+}
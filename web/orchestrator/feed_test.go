@@ -0,0 +1,182 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/templates"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// rssFeed is the structure of a RSS 2.0 feed.
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Items   []struct {
+		Title   string `xml:"title"`
+		Link    string `xml:"link"`
+		PubDate string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+func newTestFeedItem(itemRoute, title, date string) *model.Item {
+	item := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+	item.Title = title
+	item.MetaData.AddBlock("date", date)
+	return item
+}
+
+func Test_getFeedItems_PublishedDocumentsAreRenderedNewestFirst(t *testing.T) {
+	// arrange
+	presentation := newTestFeedItem("presentations/slides", "Slides", "2015-05-01")
+	presentation.Type = model.TypePresentation
+
+	draft := newTestFeedItem("documents/draft", "Draft", "2015-04-01")
+	draft.MetaData.AddBlock("draft", "yes")
+
+	items := []*model.Item{
+		newTestFeedItem("documents/old", "Old", "2014-12-24"),
+		newTestFeedItem("documents/new", "New", "2015-03-01 21:13"),
+		draft,
+		presentation,
+		newTestFeedItem("documents/middle", "Middle", "2015-01-15"),
+	}
+
+	pathProvider := prefixPather{"http://example.com/"}
+
+	feedModel := viewmodel.Feed{}
+	for _, item := range getFeedItems(items) {
+		feedModel.Items = append(feedModel.Items, newFeedEntry(pathProvider, item, "<p>Some <b>content</b> & more</p>", "Some content & more"))
+	}
+
+	templateProvider := templates.NewProvider("")
+	feedTemplate, _ := templateProvider.GetRSSTemplate("http://example.com")
+
+	// act
+	buffer := new(bytes.Buffer)
+	err := feedTemplate.Execute(buffer, feedModel)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The feed could not be rendered. Error: %s", err)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(buffer.Bytes(), &feed); err != nil {
+		t.Fatalf("The feed is not well-formed. Error: %s\n%s", err, buffer.String())
+	}
+
+	expectedLinks := []string{"http://example.com/documents/new", "http://example.com/documents/middle", "http://example.com/documents/old"}
+	if len(feed.Items) != len(expectedLinks) {
+		t.Fatalf("The feed should contain %d items but contained %d:\n%s", len(expectedLinks), len(feed.Items), buffer.String())
+	}
+
+	for index, expectedLink := range expectedLinks {
+		if feed.Items[index].Link != expectedLink {
+			t.Errorf("Item %d should link to %q but linked to %q.", index, expectedLink, feed.Items[index].Link)
+		}
+	}
+
+	if feed.Items[0].PubDate != "Sun, 01 Mar 2015 21:13:00 +0000" {
+		t.Errorf("The publication date should be %q but was %q.", "Sun, 01 Mar 2015 21:13:00 +0000", feed.Items[0].PubDate)
+	}
+}
+
+// atomFeed is the structure of an Atom 1.0 feed.
+type atomFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Entries []struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Content struct {
+			Type string `xml:"type,attr"`
+		} `xml:"content"`
+	} `xml:"entry"`
+}
+
+func Test_newFeedEntry_AtomEntryIDsAreStableWhenTheContentChanges(t *testing.T) {
+	// arrange
+	templateProvider := templates.NewProvider("")
+	feedTemplate, _ := templateProvider.GetAtomTemplate("http://example.com")
+
+	render := func(firstHash string) atomFeed {
+		first := newTestFeedItem("documents/first", "First", "2015-03-01")
+		first.Hash = firstHash
+
+		second := newTestFeedItem("documents/second", "Second", "2015-02-01")
+		second.Hash = "456-ABCDEF02"
+
+		feedModel := viewmodel.Feed{}
+		for _, item := range getFeedItems([]*model.Item{second, first}) {
+			feedModel.Items = append(feedModel.Items, newFeedEntry(prefixPather{"http://example.com/"}, item, "<p>Content</p>", "Content"))
+		}
+
+		buffer := new(bytes.Buffer)
+		if err := feedTemplate.Execute(buffer, feedModel); err != nil {
+			t.Fatalf("The feed could not be rendered. Error: %s", err)
+		}
+
+		var feed atomFeed
+		if err := xml.Unmarshal(buffer.Bytes(), &feed); err != nil {
+			t.Fatalf("The feed is not well-formed. Error: %s\n%s", err, buffer.String())
+		}
+
+		return feed
+	}
+
+	// act
+	firstRun := render("123-ABCDEF01")
+	secondRun := render("789-ABCDEF03")
+
+	// assert
+	if len(firstRun.Entries) != 2 || len(secondRun.Entries) != 2 {
+		t.Fatalf("Both feeds should contain two entries but contained %d and %d.", len(firstRun.Entries), len(secondRun.Entries))
+	}
+
+	expectedID := "urn:allmark:" + hashutil.SHA1FromString("documents/first")
+	if firstRun.Entries[0].ID != expectedID {
+		t.Errorf("The id of the first entry should be %q but was %q.", expectedID, firstRun.Entries[0].ID)
+	}
+
+	for index := range firstRun.Entries {
+		if firstRun.Entries[index].ID != secondRun.Entries[index].ID {
+			t.Errorf("The id of entry %d changed from %q to %q.", index, firstRun.Entries[index].ID, secondRun.Entries[index].ID)
+		}
+
+		if firstRun.Entries[index].Content.Type != "html" {
+			t.Errorf("The content type of entry %d should be %q but was %q.", index, "html", firstRun.Entries[index].Content.Type)
+		}
+	}
+
+	if firstRun.Entries[0].Updated != "2015-03-01T00:00:00Z" {
+		t.Errorf("The first entry should have been updated at %q but was updated at %q.", "2015-03-01T00:00:00Z", firstRun.Entries[0].Updated)
+	}
+}
+
+func Test_createFeedEntryModel_SummaryBlock_SummaryIsTheDescription(t *testing.T) {
+	// arrange
+	combined, directory := newTestCombinedOrchestrator(t, map[string]string{
+		"document/document.md": "# Document\n\nThe first paragraph.\n\nThe content.\n\n---\nsummary: An explicit summary\n",
+	})
+	defer os.RemoveAll(directory)
+
+	feedOrchestrator := &FeedOrchestrator{combined.Orchestrator}
+	item := feedOrchestrator.getItem(route.NewFromRequest("document"))
+
+	// act
+	result := feedOrchestrator.createFeedEntryModel("http://example.com", item)
+
+	// assert
+	if result.Description != "An explicit summary" {
+		t.Errorf("The description should be the summary %q but was %q.", "An explicit summary", result.Description)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/andreaskoch/allmark/common/paths"
 	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/services/thumbnail"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"fmt"
 )
@@ -40,9 +41,22 @@ func (orchestrator *FileOrchestrator) GetFile(fileRoute route.Route) (fileModel
 		return fileModel, false
 	}
 
+	convertedModel.Thumbnail = orchestrator.getThumbnailPath(file)
+
 	return convertedModel, true
 }
 
+// GetFileHash returns the content hash of the file with the given route (see model.File.GetHash).
+func (orchestrator *FileOrchestrator) GetFileHash(fileRoute route.Route) (hash string, found bool) {
+	file := orchestrator.getFile(fileRoute)
+	if file == nil {
+		return "", false
+	}
+
+	hash = file.GetHash()
+	return hash, hash != ""
+}
+
 func (orchestrator *FileOrchestrator) GetFiles(itemRoute route.Route) []viewmodel.File {
 	files := make([]viewmodel.File, 0)
 
@@ -60,6 +74,7 @@ func (orchestrator *FileOrchestrator) GetFiles(itemRoute route.Route) []viewmode
 			continue
 		}
 
+		fileModel.Thumbnail = orchestrator.getThumbnailPath(file)
 		files = append(files, fileModel)
 	}
 
@@ -84,6 +99,22 @@ func (orchestrator *FileOrchestrator) GetImages(itemRoute route.Route) []viewmod
 	return images
 }
 
+// getThumbnailPath returns the path of the gallery thumbnail of the supplied file
+// or an empty string if there is no thumbnail for the file.
+func (orchestrator *FileOrchestrator) getThumbnailPath(file *model.File) string {
+	if orchestrator.thumbnailIndex == nil {
+		return ""
+	}
+
+	gallerySize := thumbnail.GallerySize(orchestrator.config.ThumbnailMaxDimension())
+	thumb, exists := orchestrator.thumbnailIndex.GetThumb(file.Route(), gallerySize)
+	if !exists {
+		return ""
+	}
+
+	return orchestrator.absolutePather("/").Path(thumb.ThumbRoute().Value())
+}
+
 func toViewModel(pathProvider paths.Pather, file *model.File) (fileModel viewmodel.File, err error) {
 
 	// mime type
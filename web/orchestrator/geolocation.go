@@ -8,29 +8,66 @@ import (
 	"github.com/andreaskoch/allmark/model"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
+// The zoom level of location maps if the item has no (valid) "zoom" block.
+const defaultMapZoomLevel = 13
+
 func getGeoLocation(item *model.Item) viewmodel.GeoLocation {
-	emptyLocation := model.GeoInformation{}
-	if item.MetaData.GeoInformation == emptyLocation {
+	geoInformation := item.MetaData.GeoInformation
+
+	// coordinates from the "latitude" and "longitude" blocks (e.g. from front matter);
+	// invalid coordinates are discarded so no map is rendered for them.
+	geoInformation.Latitude = getCoordinate(item.MetaData.GetBlockValueOrDefault("latitude", geoInformation.Latitude), 90)
+	geoInformation.Longitude = getCoordinate(item.MetaData.GetBlockValueOrDefault("longitude", geoInformation.Longitude), 180)
+	if geoInformation.Latitude == "" || geoInformation.Longitude == "" {
+		geoInformation.Latitude, geoInformation.Longitude = "", ""
+	}
+
+	emptyLocation := model.GeoInformation{Zoom: geoInformation.Zoom, MapType: geoInformation.MapType}
+	if geoInformation == emptyLocation {
 		return viewmodel.GeoLocation{}
 	}
 
 	return viewmodel.GeoLocation{
 		PlaceName:   getPlaceName(item),
-		Address:     getAddress(item.MetaData.GeoInformation),
-		Coordinates: getCoordinates(item.MetaData.GeoInformation),
-
-		Street:    item.MetaData.GeoInformation.Street,
-		City:      item.MetaData.GeoInformation.City,
-		Postcode:  item.MetaData.GeoInformation.Postcode,
-		Country:   item.MetaData.GeoInformation.Country,
-		Latitude:  item.MetaData.GeoInformation.Latitude,
-		Longitude: item.MetaData.GeoInformation.Longitude,
-		MapType:   item.MetaData.GeoInformation.MapType,
-		Zoom:      item.MetaData.GeoInformation.Zoom,
+		Address:     getAddress(geoInformation),
+		Coordinates: getCoordinates(geoInformation),
+
+		Street:    geoInformation.Street,
+		City:      geoInformation.City,
+		Postcode:  geoInformation.Postcode,
+		Country:   geoInformation.Country,
+		Latitude:  geoInformation.Latitude,
+		Longitude: geoInformation.Longitude,
+		MapType:   geoInformation.MapType,
+		Zoom:      getMapZoomLevel(item),
+	}
+}
+
+// getCoordinate returns the supplied coordinate value if it is a number
+// within the range of -limit and +limit. Otherwise an empty string is returned.
+func getCoordinate(value string, limit float64) string {
+	value = strings.TrimSpace(value)
+	coordinate, err := strconv.ParseFloat(value, 64)
+	if err != nil || coordinate < -limit || coordinate > limit {
+		return ""
+	}
+
+	return value
+}
+
+// getMapZoomLevel returns the zoom level from the "zoom" block of the given item
+// or the default zoom level if the item has no valid zoom level in the range of 1 to 19.
+func getMapZoomLevel(item *model.Item) int {
+	zoomLevel, err := strconv.Atoi(item.MetaData.GetBlockValue("zoom"))
+	if err != nil || zoomLevel < 1 || zoomLevel > 19 {
+		return defaultMapZoomLevel
 	}
+
+	return zoomLevel
 }
 
 func getAddress(geoData model.GeoInformation) string {
@@ -0,0 +1,68 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+func Test_getGeoLocation_CoordinateBlocks_MapCoordinatesAreReturned(t *testing.T) {
+	// arrange
+	item := &model.Item{}
+	item.MetaData.AddBlock("latitude", "48.137")
+	item.MetaData.AddBlock("longitude", "11.575")
+	item.MetaData.AddBlock("zoom", "8")
+
+	// act
+	result := getGeoLocation(item)
+
+	// assert
+	if result.Coordinates != "48.137; 11.575" {
+		t.Errorf("The coordinates should be %q but were %q.", "48.137; 11.575", result.Coordinates)
+	}
+
+	if result.Zoom != 8 {
+		t.Errorf("The zoom level should be 8 but was %d.", result.Zoom)
+	}
+}
+
+func Test_getGeoLocation_InvalidCoordinate_NoCoordinatesAreReturned(t *testing.T) {
+	// arrange
+	inputs := [][]string{
+		{"48.137", "east"},
+		{"148.137", "11.575"},
+		{"48.137", ""},
+	}
+
+	for _, input := range inputs {
+		item := &model.Item{}
+		item.MetaData.AddBlock("latitude", input[0])
+		item.MetaData.AddBlock("longitude", input[1])
+
+		// act
+		result := getGeoLocation(item)
+
+		// assert
+		if result.Coordinates != "" || result.Latitude != "" || result.Longitude != "" {
+			t.Errorf("No coordinates should be returned for %q but the result was %#v.", input, result)
+		}
+	}
+}
+
+func Test_getMapZoomLevel_InvalidZoomBlock_DefaultIsReturned(t *testing.T) {
+	// arrange
+	item := &model.Item{}
+	item.MetaData.AddBlock("zoom", "far away")
+
+	// act
+	result := getMapZoomLevel(item)
+
+	// assert
+	if result != defaultMapZoomLevel {
+		t.Errorf("The zoom level should be %d but was %d.", defaultMapZoomLevel, result)
+	}
+}
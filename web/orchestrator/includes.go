@@ -0,0 +1,77 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"sort"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/services/converter/markdowntohtml"
+)
+
+// getIncludedRoutes returns the routes of all items which are included by the supplied item,
+// directly or through other included items, in alphabetical order. The routes of included items
+// which do not exist are returned too (e.g. the route of an item that has been deleted).
+func getIncludedRoutes(item *model.Item, getItem func(itemRoute route.Route) *model.Item) []route.Route {
+	includedRoutesByValue := make(map[string]route.Route)
+
+	queue := markdowntohtml.GetIncludeRoutes(item)
+	for len(queue) > 0 {
+		includedRoute := queue[0]
+		queue = queue[1:]
+
+		if _, visited := includedRoutesByValue[includedRoute.Value()]; visited || includedRoute.Value() == item.Route().Value() {
+			continue
+		}
+
+		includedRoutesByValue[includedRoute.Value()] = includedRoute
+
+		if includedItem := getItem(includedRoute); includedItem != nil {
+			queue = append(queue, markdowntohtml.GetIncludeRoutes(includedItem)...)
+		}
+	}
+
+	values := make([]string, 0, len(includedRoutesByValue))
+	for value := range includedRoutesByValue {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+
+	includedRoutes := make([]route.Route, 0, len(values))
+	for _, value := range values {
+		includedRoutes = append(includedRoutes, includedRoutesByValue[value])
+	}
+
+	return includedRoutes
+}
+
+// getIncludedItems returns all existing items which are included by the supplied item, directly or indirectly.
+func getIncludedItems(item *model.Item, getItem func(itemRoute route.Route) *model.Item) []*model.Item {
+	includedItems := make([]*model.Item, 0)
+	for _, includedRoute := range getIncludedRoutes(item, getItem) {
+		if includedItem := getItem(includedRoute); includedItem != nil {
+			includedItems = append(includedItems, includedItem)
+		}
+	}
+
+	return includedItems
+}
+
+// getIncludingRoutes returns the routes of all supplied items which include the item with the given route, directly or indirectly.
+func getIncludingRoutes(includedRoute route.Route, items []*model.Item, getItem func(itemRoute route.Route) *model.Item) []route.Route {
+	includingRoutes := make([]route.Route, 0)
+	for _, item := range items {
+		for _, candidate := range getIncludedRoutes(item, getItem) {
+			if candidate.Value() == includedRoute.Value() {
+				includingRoutes = append(includingRoutes, item.Route())
+				break
+			}
+		}
+	}
+
+	return includingRoutes
+}
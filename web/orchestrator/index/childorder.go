@@ -0,0 +1,138 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package index
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+// A SortKey defines the item property by which the children of an item are sorted.
+type SortKey int
+
+const (
+	SortByFileName SortKey = iota
+	SortByTitle
+	SortByDate
+)
+
+func (sortKey SortKey) String() string {
+	switch sortKey {
+	case SortByTitle:
+		return "title"
+
+	case SortByDate:
+		return "date"
+
+	default:
+		return "filename"
+	}
+}
+
+// A ChildOrder defines how the children of an item are sorted.
+type ChildOrder struct {
+	Key        SortKey
+	Descending bool
+}
+
+// DefaultChildOrder sorts the children by their file name (ascending).
+var DefaultChildOrder = ChildOrder{Key: SortByFileName}
+
+// ParseChildOrder parses a child order definition such as "filename", "title desc" or "date descending".
+// An empty definition returns the DefaultChildOrder.
+func ParseChildOrder(definition string) (ChildOrder, error) {
+	fields := strings.Fields(strings.ToLower(definition))
+	if len(fields) == 0 {
+		return DefaultChildOrder, nil
+	}
+
+	if len(fields) > 2 {
+		return DefaultChildOrder, fmt.Errorf("%q is not a valid child order.", definition)
+	}
+
+	childOrder := ChildOrder{}
+
+	switch fields[0] {
+	case SortByFileName.String(), "name":
+		childOrder.Key = SortByFileName
+
+	case SortByTitle.String():
+		childOrder.Key = SortByTitle
+
+	case SortByDate.String():
+		childOrder.Key = SortByDate
+
+	default:
+		return DefaultChildOrder, fmt.Errorf("%q is not a valid sort key (filename, title or date).", fields[0])
+	}
+
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "asc", "ascending":
+			childOrder.Descending = false
+
+		case "desc", "descending":
+			childOrder.Descending = true
+
+		default:
+			return DefaultChildOrder, fmt.Errorf("%q is not a valid sort direction (asc or desc).", fields[1])
+		}
+	}
+
+	return childOrder, nil
+}
+
+func (childOrder ChildOrder) String() string {
+	if childOrder.Descending {
+		return childOrder.Key.String() + " desc"
+	}
+
+	return childOrder.Key.String() + " asc"
+}
+
+// Sort sorts the supplied items according to the child order.
+// Items with equal sort values are sorted by their route so the order is always deterministic.
+func (childOrder ChildOrder) Sort(items []*model.Item) {
+	model.SortItemsBy(childOrder.less).Sort(items)
+}
+
+func (childOrder ChildOrder) less(item1, item2 *model.Item) bool {
+	switch comparison := childOrder.compare(item1, item2); {
+	case comparison < 0:
+		return !childOrder.Descending
+
+	case comparison > 0:
+		return childOrder.Descending
+
+	default:
+		return item1.Route().Value() < item2.Route().Value()
+	}
+}
+
+// compare returns a negative number if the first item is sorted before the second item in ascending order,
+// a positive number if the second item is sorted first and zero if both items are equal.
+func (childOrder ChildOrder) compare(item1, item2 *model.Item) int {
+	switch childOrder.Key {
+	case SortByTitle:
+		return strings.Compare(strings.ToLower(item1.Title), strings.ToLower(item2.Title))
+
+	case SortByDate:
+		date1, date2 := item1.Date(), item2.Date()
+		if date1.Before(date2) {
+			return -1
+		}
+
+		if date1.After(date2) {
+			return 1
+		}
+
+		return 0
+
+	default:
+		return strings.Compare(strings.ToLower(item1.FolderName()), strings.ToLower(item2.FolderName()))
+	}
+}
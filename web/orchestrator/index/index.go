@@ -5,14 +5,19 @@
 package index
 
 import (
+	"path"
+	"strings"
+
 	"github.com/andreaskoch/allmark/common/logger"
 	"github.com/andreaskoch/allmark/common/route"
 	"github.com/andreaskoch/allmark/model"
 )
 
-func New(logger logger.Logger) *Index {
+// New creates a new index which sorts the children of an item with the supplied child order.
+func New(logger logger.Logger, childOrder ChildOrder) *Index {
 	return &Index{
-		logger: logger,
+		logger:     logger,
+		childOrder: childOrder,
 
 		itemList: make([]*model.Item, 0),
 		routeMap: make(map[string]*model.Item),
@@ -21,7 +26,8 @@ func New(logger logger.Logger) *Index {
 }
 
 type Index struct {
-	logger logger.Logger
+	logger     logger.Logger
+	childOrder ChildOrder
 
 	// indizes
 	itemList []*model.Item
@@ -44,6 +50,14 @@ func (index *Index) IsMatch(r route.Route) (item *model.Item, isMatch bool) {
 	return nil, false
 }
 
+// FindByRelativePath returns the item for the supplied path relative to the given base path
+// (e.g. base path "documents" and relative path "guides/install" -> "documents/guides/install").
+// Leading and trailing slashes are ignored and ".." refers to the parent route.
+func (index *Index) FindByRelativePath(basePath, relativePath string) (item *model.Item, found bool) {
+	itemPath := path.Clean("/" + path.Join(strings.Trim(basePath, "/"), strings.Trim(relativePath, "/")))
+	return index.IsMatch(route.NewFromRequest(itemPath))
+}
+
 func (index *Index) IsFileMatch(r route.Route) (*model.File, bool) {
 
 	var parent *model.Item
@@ -83,6 +97,7 @@ func (index *Index) IsFileMatch(r route.Route) (*model.File, bool) {
 	return nil, false
 }
 
+// GetParent returns the closest ancestor of the item with the given route.
 func (index *Index) GetParent(childRoute route.Route) *model.Item {
 
 	if childRoute.IsEmpty() {
@@ -94,18 +109,33 @@ func (index *Index) GetParent(childRoute route.Route) *model.Item {
 		return nil
 	}
 
-	// get the parent route
-	parentRoute, exists := childRoute.Parent()
-	if !exists {
-		return nil
+	// use the parent reference of indexed items
+	if child, isMatch := index.IsMatch(childRoute); isMatch {
+		parent, _ := child.GetParent()
+		return parent
 	}
 
-	item, isMatch := index.IsMatch(parentRoute)
-	if !isMatch {
-		return nil
+	return index.findParent(childRoute)
+}
+
+// findParent searches the index for the closest ancestor of the given route.
+func (index *Index) findParent(childRoute route.Route) *model.Item {
+
+	parentRoute, exists := childRoute.Parent()
+	for exists {
+
+		if item, isMatch := index.IsMatch(parentRoute); isMatch {
+			return item
+		}
+
+		if parentRoute.Level() == 0 {
+			break
+		}
+
+		parentRoute, exists = parentRoute.Parent()
 	}
 
-	return item
+	return nil
 }
 
 func (index *Index) Root() *model.Item {
@@ -125,6 +155,11 @@ func (index *Index) GetAllItems() []*model.Item {
 	return items
 }
 
+// WalkByType passes all items of the given type to the supplied expression.
+func (index *Index) WalkByType(itemType model.ItemType, expression func(item *model.Item)) {
+	index.itemTree.WalkByType(itemType, expression)
+}
+
 // Get all children that match the given expression
 func (index *Index) GetAllChildren(route route.Route, expression func(item *model.Item) bool) []*model.Item {
 
@@ -148,8 +183,8 @@ func (index *Index) GetAllChildren(route route.Route, expression func(item *mode
 
 	}
 
-	// sort the items by ascending by route
-	model.SortItemsBy(sortItemsByDate).Sort(children)
+	// sort the items
+	index.childOrder.Sort(children)
 
 	return children
 }
@@ -158,8 +193,8 @@ func (index *Index) GetDirectChildren(route route.Route) []*model.Item {
 	// get all mathching children
 	children := index.itemTree.GetChildItems(route)
 
-	// sort the items by ascending by route
-	model.SortItemsBy(sortItemsByDate).Sort(children)
+	// sort the items
+	index.childOrder.Sort(children)
 
 	return children
 }
@@ -173,8 +208,14 @@ func (index *Index) GetLeafes(route route.Route) []*model.Item {
 		return []*model.Item{}
 	}
 
-	// leaf found
-	children := index.GetDirectChildren(route)
+	// leaf found (comments are displayed with their item, so an item with comments is a leaf too)
+	children := make([]*model.Item, 0)
+	for _, child := range index.GetDirectChildren(route) {
+		if child.Type != model.TypeComment {
+			children = append(children, child)
+		}
+	}
+
 	if len(children) == 0 {
 		return []*model.Item{item}
 	}
@@ -205,16 +246,45 @@ func (index *Index) Add(item *model.Item) {
 	index.itemList = append(index.itemList, item)
 	index.routeMap[route.ToKey(item.Route())] = item
 	index.itemTree.Insert(item)
+
+	// wire up the parent references
+	item.SetParent(index.findParent(item.Route()))
+	index.itemTree.WalkDescendants(item.Route(), func(descendant *model.Item) bool {
+		descendant.SetParent(item)
+		return false // the descendants of the descendant keep their parent
+	})
 }
 
+// Remove removes the item with the given route and all of its descendants from the index.
+// Ancestors which are left without an item and without children are removed as well.
 func (index *Index) Remove(itemRoute route.Route) {
-	delete(index.routeMap, route.ToKey(itemRoute))
-	index.itemTree.Delete(itemRoute)
-}
 
-// sort the models by date and name
-func sortItemsByDate(model1, model2 *model.Item) bool {
+	// collect the routes of the item and its descendants
+	removedRoutes := map[string]bool{route.ToKey(itemRoute): true}
+	index.itemTree.WalkDescendants(itemRoute, func(descendant *model.Item) bool {
+		removedRoutes[route.ToKey(descendant.Route())] = true
+		return true
+	})
 
-	return model1.MetaData.CreationDate.Before(model2.MetaData.CreationDate)
+	// remove the items from the indizes
+	for key := range removedRoutes {
+		if item, exists := index.routeMap[key]; exists {
+			item.SetParent(nil)
+			delete(index.routeMap, key)
+		}
+	}
+
+	remainingItems := make([]*model.Item, 0, len(index.itemList))
+	for _, item := range index.itemList {
+		if removedRoutes[route.ToKey(item.Route())] {
+			continue
+		}
 
+		remainingItems = append(remainingItems, item)
+	}
+
+	index.itemList = remainingItems
+
+	index.itemTree.Delete(itemRoute)
+	index.itemTree.DeleteEmptyAncestors(itemRoute)
 }
@@ -0,0 +1,397 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package index
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+type testItem struct {
+	route    string
+	itemType model.ItemType
+}
+
+// newTestIndex creates an index with the supplied items (parents must be listed before their children).
+func newTestIndex(items ...testItem) *Index {
+	index := New(console.New(loglevel.Fatal), DefaultChildOrder)
+
+	for _, testItem := range items {
+		item := model.NewItem(route.NewFromRequest(testItem.route), []*model.File{}, dataaccess.TypePhysical)
+		item.Type = testItem.itemType
+		item.Title = testItem.route
+		index.Add(item)
+	}
+
+	return index
+}
+
+func Test_WalkByType_MixedTree_OnlyMatchingItemsAreVisited(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"documents", model.TypeDocument},
+		testItem{"documents/slides", model.TypePresentation},
+		testItem{"documents/notes", model.TypeDocument},
+		testItem{"talks", model.TypePresentation},
+		testItem{"talks/2015/keynote", model.TypePresentation},
+	)
+
+	visitedRoutes := make([]string, 0)
+
+	// act
+	index.WalkByType(model.TypePresentation, func(item *model.Item) {
+		visitedRoutes = append(visitedRoutes, item.Route().Value())
+	})
+
+	// assert
+	if len(visitedRoutes) != 3 {
+		t.Errorf("WalkByType should have visited 3 presentations but visited %v.", visitedRoutes)
+	}
+
+	for _, visitedRoute := range visitedRoutes {
+		item, _ := index.IsMatch(route.NewFromRequest(visitedRoute))
+		if item.Type != model.TypePresentation {
+			t.Errorf("WalkByType passed the item %q of type %s to the expression.", visitedRoute, item.Type)
+		}
+	}
+}
+
+func Test_Add_ParentReferencesAreAssigned(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"documents", model.TypeDocument},
+		testItem{"documents/sample", model.TypeDocument},
+		testItem{"talks/2015/keynote", model.TypePresentation},
+	)
+
+	expectedParents := map[string]string{
+		"documents":          "",
+		"documents/sample":   "documents",
+		"talks/2015/keynote": "",
+	}
+
+	for childRoute, expectedParentRoute := range expectedParents {
+
+		// act
+		child, _ := index.IsMatch(route.NewFromRequest(childRoute))
+		parent, exists := child.GetParent()
+
+		// assert
+		if !exists {
+			t.Errorf("The item %q should have a parent.", childRoute)
+			continue
+		}
+
+		if parent.Route().Value() != expectedParentRoute {
+			t.Errorf("The parent of %q should be %q but was %q.", childRoute, expectedParentRoute, parent.Route().Value())
+		}
+	}
+}
+
+func Test_Add_Root_HasNoParent(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"documents", model.TypeDocument},
+	)
+
+	// act
+	_, exists := index.Root().GetParent()
+
+	// assert
+	if exists {
+		t.Errorf("The root item should not have a parent.")
+	}
+}
+
+func Test_Add_ReplacedParent_ChildrenReferenceTheNewParent(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"documents", model.TypeDocument},
+		testItem{"documents/sample", model.TypeDocument},
+	)
+
+	newParent := model.NewItem(route.NewFromRequest("documents"), []*model.File{}, dataaccess.TypePhysical)
+
+	// act
+	index.Add(newParent)
+
+	// assert
+	child, _ := index.IsMatch(route.NewFromRequest("documents/sample"))
+	if parent, _ := child.GetParent(); parent != newParent {
+		t.Errorf("The child %q should reference the new parent item.", child.Route())
+	}
+}
+
+func Test_GetBreadcrumbs_ThirdLevelItem_AncestorsAreReturnedInOrder(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"documents", model.TypeDocument},
+		testItem{"documents/guides", model.TypeDocument},
+		testItem{"documents/guides/install", model.TypeDocument},
+	)
+
+	item, _ := index.IsMatch(route.NewFromRequest("documents/guides/install"))
+
+	// act
+	result := item.GetBreadcrumbs()
+
+	// assert
+	expectedRoutes := []string{"", "documents", "documents/guides"}
+	if len(result) != len(expectedRoutes) {
+		t.Errorf("GetBreadcrumbs should return %d items but returned %d.", len(expectedRoutes), len(result))
+		return
+	}
+
+	for position, expectedRoute := range expectedRoutes {
+		if result[position].Route().Value() != expectedRoute {
+			t.Errorf("The breadcrumb at position %d should be %q but was %q.", position, expectedRoute, result[position].Route().Value())
+		}
+	}
+}
+
+func Test_GetBreadcrumbs_Root_ResultIsEmpty(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"documents", model.TypeDocument},
+	)
+
+	// act
+	result := index.Root().GetBreadcrumbs()
+
+	// assert
+	if len(result) != 0 {
+		t.Errorf("GetBreadcrumbs should return an empty list for the root but returned %d items.", len(result))
+	}
+}
+
+func Test_FindByRelativePath_LeadingAndTrailingSlashes_SameItemIsReturned(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"guides", model.TypeDocument},
+		testItem{"guides/install", model.TypeDocument},
+	)
+
+	inputs := []string{
+		"/guides/install",
+		"guides/install/",
+		"/guides/install/",
+		"guides//install",
+	}
+
+	for _, input := range inputs {
+
+		// act
+		item, found := index.FindByRelativePath("", input)
+
+		// assert
+		if !found {
+			t.Errorf("FindByRelativePath should find an item for %q.", input)
+			continue
+		}
+
+		if item.Route().Value() != "guides/install" {
+			t.Errorf("FindByRelativePath(%q) should return %q but returned %q.", input, "guides/install", item.Route().Value())
+		}
+	}
+}
+
+func Test_FindByRelativePath_BasePath_PathIsResolvedRelativeToTheBasePath(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"guides", model.TypeDocument},
+		testItem{"guides/install", model.TypeDocument},
+		testItem{"guides/upgrade", model.TypeDocument},
+	)
+
+	// act
+	item, found := index.FindByRelativePath("/guides/install/", "../upgrade")
+
+	// assert
+	if !found || item.Route().Value() != "guides/upgrade" {
+		t.Errorf("FindByRelativePath should resolve %q relative to %q to %q.", "../upgrade", "/guides/install/", "guides/upgrade")
+	}
+}
+
+func Test_FindByRelativePath_NoMatch_ResultIsFalse(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"guides", model.TypeDocument},
+	)
+
+	// act
+	_, found := index.FindByRelativePath("", "guides/uninstall")
+
+	// assert
+	if found {
+		t.Errorf("FindByRelativePath should not find an item for a route that does not exist.")
+	}
+}
+
+func Test_GetDirectChildren_RepeatedIndexing_ChildOrderIsIdentical(t *testing.T) {
+	// arrange
+	newIndex := func(childOrder ChildOrder, childRoutes ...string) *Index {
+		index := New(console.New(loglevel.Fatal), childOrder)
+		index.Add(model.NewItem(route.NewFromRequest("collection"), []*model.File{}, dataaccess.TypePhysical))
+
+		dates := map[string]string{"b-second": "2015-02-01", "a-third": "2015-03-01", "c-first": "2015-01-01"}
+		for _, childRoute := range childRoutes {
+			item := model.NewItem(route.NewFromRequest("collection/"+childRoute), []*model.File{}, dataaccess.TypePhysical)
+			item.MetaData.AddBlock("date", dates[childRoute])
+			index.Add(item)
+		}
+
+		return index
+	}
+
+	getChildNames := func(index *Index) string {
+		var names []string
+		for _, child := range index.GetDirectChildren(route.NewFromRequest("collection")) {
+			names = append(names, child.FolderName())
+		}
+
+		return strings.Join(names, "|")
+	}
+
+	dateOrder, _ := ParseChildOrder("date desc")
+
+	inputs := []struct {
+		childOrder ChildOrder
+		expected   string
+	}{
+		{DefaultChildOrder, "a-third|b-second|c-first"},
+		{dateOrder, "a-third|b-second|c-first"},
+		{ChildOrder{Key: SortByDate}, "c-first|b-second|a-third"},
+	}
+
+	for _, input := range inputs {
+
+		// act
+		firstRun := getChildNames(newIndex(input.childOrder, "b-second", "a-third", "c-first"))
+		secondRun := getChildNames(newIndex(input.childOrder, "c-first", "a-third", "b-second"))
+
+		// assert
+		if firstRun != input.expected || secondRun != input.expected {
+			t.Errorf("The children sorted by %q should be %q in every run but were %q and %q.", input.childOrder, input.expected, firstRun, secondRun)
+		}
+	}
+}
+
+func Test_ParseChildOrder_InvalidDefinition_ErrorIsReturned(t *testing.T) {
+	// arrange
+	definitions := []string{"size", "date sideways", "date desc now"}
+
+	for _, definition := range definitions {
+
+		// act
+		childOrder, err := ParseChildOrder(definition)
+
+		// assert
+		if err == nil {
+			t.Errorf("%q should not be a valid child order.", definition)
+		}
+
+		if childOrder != DefaultChildOrder {
+			t.Errorf("The child order for %q should be the default child order but was %q.", definition, childOrder)
+		}
+	}
+}
+
+func Test_GetLeafes_ItemWithComments_ItemIsALeafAndCommentsAreSkipped(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"documents", model.TypeCollection},
+		testItem{"documents/sample", model.TypeDocument},
+		testItem{"documents/sample/comment-1", model.TypeComment},
+		testItem{"documents/sample/comment-1/comment-2", model.TypeComment},
+		testItem{"documents/other", model.TypeDocument},
+	)
+
+	// act
+	leafes := index.GetLeafes(route.New())
+
+	// assert
+	routes := make([]string, 0, len(leafes))
+	for _, leaf := range leafes {
+		routes = append(routes, leaf.Route().Value())
+	}
+
+	if strings.Join(routes, ",") != "documents/other,documents/sample" && strings.Join(routes, ",") != "documents/sample,documents/other" {
+		t.Errorf("The leafes should be the two documents but were %v.", routes)
+	}
+}
+
+func Test_Remove_ItemWithDescendants_ItemAndDescendantsAreRemoved(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"documents", model.TypeDocument},
+		testItem{"documents/sample", model.TypeDocument},
+		testItem{"talks", model.TypeDocument},
+	)
+
+	sample, _ := index.IsMatch(route.NewFromRequest("documents/sample"))
+
+	// act
+	index.Remove(route.NewFromRequest("documents"))
+
+	// assert
+	for _, removedRoute := range []string{"documents", "documents/sample"} {
+		if _, isMatch := index.IsMatch(route.NewFromRequest(removedRoute)); isMatch {
+			t.Errorf("The item %q should have been removed from the index.", removedRoute)
+		}
+	}
+
+	if index.Size() != 2 {
+		t.Errorf("The index should contain 2 items but contained %d.", index.Size())
+	}
+
+	if _, exists := sample.GetParent(); exists {
+		t.Errorf("The removed item %q should not reference its former parent.", sample.Route())
+	}
+}
+
+func Test_Remove_ItemWithEmptyParents_EmptyParentsAreRemoved(t *testing.T) {
+	// arrange
+	index := newTestIndex(
+		testItem{"", model.TypeRepository},
+		testItem{"talks", model.TypeDocument},
+		testItem{"talks/2015/keynote", model.TypePresentation},
+		testItem{"documents/2015/sample", model.TypeDocument},
+		testItem{"documents/2016/sample", model.TypeDocument},
+	)
+
+	// act
+	index.Remove(route.NewFromRequest("talks/2015/keynote"))
+	index.Remove(route.NewFromRequest("documents/2015/sample"))
+
+	// assert
+	for _, removedRoute := range []string{"talks/2015", "documents/2015"} {
+		if node := index.itemTree.getNode(route.NewFromRequest(removedRoute)); node != nil {
+			t.Errorf("The empty parent %q should have been removed from the index.", removedRoute)
+		}
+	}
+
+	for _, remainingRoute := range []string{"talks", "documents", "documents/2016"} {
+		if node := index.itemTree.getNode(route.NewFromRequest(remainingRoute)); node == nil {
+			t.Errorf("The parent %q should still be part of the index.", remainingRoute)
+		}
+	}
+}
@@ -50,6 +50,23 @@ func (itemTree *ItemTree) Delete(itemRoute route.Route) (bool, error) {
 	return itemTree.Tree.Delete(itemRoute.Components())
 }
 
+// DeleteEmptyAncestors removes the ancestors of the given route which have neither an item nor children
+// (e.g. the intermediate nodes that were created when an item was inserted below a route without an item).
+func (itemTree *ItemTree) DeleteEmptyAncestors(itemRoute route.Route) {
+
+	parentRoute, exists := itemRoute.Parent()
+	for exists && parentRoute.Level() > 0 {
+
+		node := itemTree.getNode(parentRoute)
+		if node == nil || node.Value() != nil || len(node.Children()) > 0 {
+			break
+		}
+
+		itemTree.Delete(parentRoute)
+		parentRoute, exists = parentRoute.Parent()
+	}
+}
+
 func (itemTree *ItemTree) GetItem(route route.Route) *model.Item {
 
 	// locate the node
@@ -110,6 +127,61 @@ func (itemTree *ItemTree) Walk(expression func(item *model.Item)) {
 	})
 }
 
+// WalkErr visits the items in the same order as Walk but stops at the first error returned by the expression and returns it.
+func (itemTree *ItemTree) WalkErr(expression func(item *model.Item) error) error {
+	return itemTree.Tree.WalkErr(func(node *tree.Node) error {
+		item := nodeToItem(node)
+		if item == nil {
+			return nil
+		}
+
+		return expression(item)
+	})
+}
+
+// WalkDepth visits the items in the same order as Walk and passes the depth of each item to the expression (the root has the depth 0).
+// If the expression returns false, the children of that item are not visited.
+func (itemTree *ItemTree) WalkDepth(expression func(item *model.Item, depth int) bool) {
+	itemTree.Tree.WalkDepth(func(node *tree.Node, depth int) bool {
+		item := nodeToItem(node)
+		if item == nil {
+			return true
+		}
+
+		return expression(item, depth)
+	})
+}
+
+// WalkByType visits all items in the same order as Walk but only passes the items with the given type to the expression.
+// The children of items with other types are visited as well.
+func (itemTree *ItemTree) WalkByType(itemType model.ItemType, expression func(item *model.Item)) {
+	itemTree.Walk(func(item *model.Item) {
+		if item.Type != itemType {
+			return
+		}
+
+		expression(item)
+	})
+}
+
+// WalkDescendants visits all descendants of the item with the given route.
+// If the expression returns false, the descendants of that item are not visited.
+func (itemTree *ItemTree) WalkDescendants(itemRoute route.Route, expression func(item *model.Item) bool) {
+	node := itemTree.getNode(itemRoute)
+	if node == nil {
+		return
+	}
+
+	node.WalkDepth(func(node *tree.Node, depth int) bool {
+		item := nodeToItem(node)
+		if item == nil {
+			return true
+		}
+
+		return expression(item)
+	})
+}
+
 func nodeToItem(node *tree.Node) *model.Item {
 
 	if node.Value() == nil {
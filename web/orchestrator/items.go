@@ -0,0 +1,31 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// An ItemsOrchestrator provides access to the items of the repository index.
+type ItemsOrchestrator struct {
+	*Orchestrator
+}
+
+// GetItem returns the item with the given route and a flag indicating whether the item exists.
+func (orchestrator *ItemsOrchestrator) GetItem(itemRoute route.Route) (*model.Item, bool) {
+	item := orchestrator.getItem(itemRoute)
+	return item, item != nil
+}
+
+// GetExcerpt returns the excerpt of the supplied item which is at most maxChars characters long.
+func (orchestrator *ItemsOrchestrator) GetExcerpt(item *model.Item, maxChars int) string {
+	return orchestrator.getExcerpt(item, maxChars)
+}
+
+// GetChildren returns the direct children of the supplied item.
+func (orchestrator *ItemsOrchestrator) GetChildren(parent *model.Item) []*model.Item {
+	return orchestrator.getChildren(parent.Route())
+}
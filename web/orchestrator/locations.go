@@ -0,0 +1,73 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"strconv"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+type LocationsOrchestrator struct {
+	*Orchestrator
+}
+
+// GetLocations returns a GeoJSON FeatureCollection with a point for every item that has valid coordinates.
+func (orchestrator *LocationsOrchestrator) GetLocations() viewmodel.GeoJSONFeatureCollection {
+	return getGeoJSONFeatureCollection(orchestrator.logger, orchestrator.itemPather(), orchestrator.getAllItems())
+}
+
+// getGeoJSONFeatureCollection creates a GeoJSON FeatureCollection from all location items in the supplied list.
+// Items which define coordinates that are not valid are skipped with a warning.
+func getGeoJSONFeatureCollection(logger logger.Logger, pathProvider paths.Pather, items []*model.Item) viewmodel.GeoJSONFeatureCollection {
+
+	features := make([]viewmodel.GeoJSONFeature, 0)
+	for _, item := range items {
+
+		// skip items which are not locations
+		if !hasCoordinates(item) {
+			continue
+		}
+
+		geoLocation := getGeoLocation(item)
+		latitude, latitudeErr := strconv.ParseFloat(geoLocation.Latitude, 64)
+		longitude, longitudeErr := strconv.ParseFloat(geoLocation.Longitude, 64)
+		if latitudeErr != nil || longitudeErr != nil {
+			logger.Warn("Skipping location %q because it has no valid coordinates.", item)
+			continue
+		}
+
+		features = append(features, viewmodel.GeoJSONFeature{
+			Type: "Feature",
+			Geometry: viewmodel.GeoJSONGeometry{
+				Type:        "Point",
+				Coordinates: []float64{longitude, latitude},
+			},
+			Properties: viewmodel.GeoJSONFeatureProperties{
+				Title: item.Title,
+				URL:   pathProvider.Path(item.Route().Value()),
+			},
+		})
+	}
+
+	return viewmodel.GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+}
+
+// hasCoordinates checks if the supplied item defines a latitude or a longitude.
+func hasCoordinates(item *model.Item) bool {
+	if item.MetaData.GeoInformation.Latitude != "" || item.MetaData.GeoInformation.Longitude != "" {
+		return true
+	}
+
+	_, hasLatitude := item.MetaData.GetBlockValueOk("latitude")
+	_, hasLongitude := item.MetaData.GetBlockValueOk("longitude")
+	return hasLatitude || hasLongitude
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// A testPather returns the item paths prefixed with a slash.
+type testPather struct{}
+
+func (testPather) Path(itemPath string) string {
+	return "/" + itemPath
+}
+
+func (testPather) Base() route.Route {
+	return route.New()
+}
+
+func newTestLocation(itemRoute, title string, blocks ...string) *model.Item {
+	item := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+	item.Title = title
+	for index := 0; index+1 < len(blocks); index += 2 {
+		item.MetaData.AddBlock(blocks[index], blocks[index+1])
+	}
+
+	return item
+}
+
+func Test_getGeoJSONFeatureCollection_LocationItemsBecomeFeatures(t *testing.T) {
+	// arrange
+	items := []*model.Item{
+		newTestLocation("", "Home"),
+		newTestLocation("travel/munich", "Munich", "latitude", "48.137", "longitude", "11.575"),
+		newTestLocation("travel/nowhere", "Nowhere", "latitude", "north", "longitude", "11.575"),
+		newTestLocation("travel/sydney", "Sydney", "latitude", "-33.868", "longitude", "151.209"),
+	}
+
+	// act
+	result := getGeoJSONFeatureCollection(console.New(loglevel.Fatal), testPather{}, items)
+
+	// assert
+	json, _ := json.Marshal(result)
+	expected := `{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[11.575,48.137]},"properties":{"title":"Munich","url":"/travel/munich"}},` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[151.209,-33.868]},"properties":{"title":"Sydney","url":"/travel/sydney"}}]}`
+
+	if string(json) != expected {
+		t.Errorf("The GeoJSON should be\n%s\nbut was\n%s", expected, json)
+	}
+}
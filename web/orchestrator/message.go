@@ -0,0 +1,116 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// MessagePermalinkPrefix is the first component of all message permalinks (e.g. "message/20150301-153000").
+const MessagePermalinkPrefix = "message"
+
+// messagePermalinkLayout is the date layout of the timestamp in message permalinks.
+const messagePermalinkLayout = "20060102-150405"
+
+// messageTitleLength is the maximum number of characters of the feed title of a message.
+const messageTitleLength = 80
+
+// MessageOrchestrator provides message permalinks and the message timeline.
+type MessageOrchestrator struct {
+	*Orchestrator
+}
+
+// GetMessageRoute returns the route of the message with the given permalink (e.g. "message/20150301-153000").
+func (orchestrator *MessageOrchestrator) GetMessageRoute(permalink string) (messageRoute route.Route, found bool) {
+	message := getMessageByPermalink(orchestrator.logger, orchestrator.getAllItems(), permalink)
+	if message == nil {
+		return route.New(), false
+	}
+
+	return message.Route(), true
+}
+
+// GetTimeline returns all messages ordered by date (newest first).
+func (orchestrator *MessageOrchestrator) GetTimeline() []viewmodel.Message {
+	pathProvider := orchestrator.absolutePather("/")
+
+	messages := make([]viewmodel.Message, 0)
+	for _, message := range getTimelineItems(orchestrator.getAllItems()) {
+		content, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, pathProvider, message)
+		if err != nil {
+			orchestrator.logger.Warn("Unable to convert the message %q. Error: %s", message, err.Error())
+			continue
+		}
+
+		messages = append(messages, viewmodel.Message{
+			Route:     pathProvider.Path(message.Route().Value()),
+			Permalink: pathProvider.Path(getMessagePermalink(message)),
+			Date:      getFormattedDate(message.Date()),
+			Content:   content,
+		})
+	}
+
+	return messages
+}
+
+// getMessagePermalink returns the permalink route of the supplied message (e.g. "message/20150301-153000").
+// The permalink is derived from the date of the message, so it does not change if the message is moved or renamed.
+// Messages without a date would all get the same permalink, so their permalink is derived from their route instead.
+func getMessagePermalink(message *model.Item) string {
+	if message.Date().IsZero() {
+		return MessagePermalinkPrefix + "/" + hashutil.SHA1FromString(message.Route().Value())
+	}
+
+	return MessagePermalinkPrefix + "/" + message.Date().Format(messagePermalinkLayout)
+}
+
+// getMessageByPermalink returns the published message with the given permalink or nil if there is none
+// (drafts and virtual items have no permalink). If multiple messages have the same date the one with the lowest route is returned and a warning is logged.
+func getMessageByPermalink(logger logger.Logger, items []*model.Item, permalink string) *model.Item {
+	var match *model.Item
+	for _, item := range items {
+		if item.Type != model.TypeMessage || item.IsVirtual() || item.IsDraft() || getMessagePermalink(item) != permalink {
+			continue
+		}
+
+		if match != nil {
+			logger.Warn("The messages %q and %q have the same permalink %q. Use different dates to tell them apart.", match, item, permalink)
+
+			if item.Route().Value() > match.Route().Value() {
+				continue
+			}
+		}
+
+		match = item
+	}
+
+	return match
+}
+
+// getTimelineItems returns all published messages of the supplied items ordered by their date (newest first).
+func getTimelineItems(items []*model.Item) []*model.Item {
+	messages := make([]*model.Item, 0)
+	for _, item := range items {
+		if item.Type != model.TypeMessage || item.IsVirtual() || item.IsDraft() {
+			continue
+		}
+
+		messages = append(messages, item)
+	}
+
+	model.SortItemsBy(func(item1, item2 *model.Item) bool {
+		if item1.Date().Equal(item2.Date()) {
+			return item1.Route().Value() < item2.Route().Value()
+		}
+
+		return item1.Date().After(item2.Date())
+	}).Sort(messages)
+
+	return messages
+}
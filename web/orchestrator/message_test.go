@@ -0,0 +1,171 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+func newTestMessage(itemRoute, date string) *model.Item {
+	message := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+	message.Type = model.TypeMessage
+	if date != "" {
+		message.MetaData.AddBlock("date", date)
+	}
+
+	return message
+}
+
+func Test_getMessagePermalink_DateBlock_PermalinkContainsTheTimestamp(t *testing.T) {
+	// arrange
+	inputs := map[string]string{
+		"2015-03-01 15:30":          "message/20150301-153000",
+		"2015-03-01":                "message/20150301-000000",
+		"2015-03-01T15:30:12+01:00": "message/20150301-153012",
+	}
+
+	for date, expected := range inputs {
+		message := newTestMessage("messages/hello", date)
+
+		// act
+		result := getMessagePermalink(message)
+
+		// assert
+		if result != expected {
+			t.Errorf("The permalink of a message with the date %q should be %q but was %q.", date, expected, result)
+		}
+	}
+}
+
+func Test_getMessagePermalink_MessageIsMoved_PermalinkDoesNotChange(t *testing.T) {
+	// arrange
+	original := newTestMessage("messages/hello", "2015-03-01 15:30")
+	moved := newTestMessage("archive/2015/greeting", "2015-03-01 15:30")
+
+	// act
+	originalPermalink := getMessagePermalink(original)
+	movedPermalink := getMessagePermalink(moved)
+
+	// assert
+	if originalPermalink != movedPermalink {
+		t.Errorf("The permalink should not depend on the route but was %q and %q.", originalPermalink, movedPermalink)
+	}
+}
+
+func Test_getMessagePermalink_MessagesWithoutDate_PermalinksAreDifferent(t *testing.T) {
+	// arrange
+	first := newTestMessage("messages/first", "")
+	second := newTestMessage("messages/second", "")
+
+	// act
+	firstPermalink := getMessagePermalink(first)
+	secondPermalink := getMessagePermalink(second)
+
+	// assert
+	if firstPermalink == secondPermalink {
+		t.Errorf("Messages without a date should have different permalinks but both were %q.", firstPermalink)
+	}
+
+	if firstPermalink != getMessagePermalink(newTestMessage("messages/first", "")) {
+		t.Errorf("The permalink of a message without a date should not change.")
+	}
+}
+
+func Test_getMessageByPermalink_MessageIsFound(t *testing.T) {
+	// arrange
+	document := model.NewItem(route.NewFromRequest("documents/sample"), nil, dataaccess.TypePhysical)
+	document.MetaData.AddBlock("date", "2015-03-01 15:30")
+	message := newTestMessage("messages/hello", "2015-03-01 15:30")
+	items := []*model.Item{document, message, newTestMessage("messages/other", "2015-03-02")}
+
+	// act
+	result := getMessageByPermalink(console.New(loglevel.Fatal), items, "message/20150301-153000")
+
+	// assert
+	if result != message {
+		t.Errorf("The permalink should resolve to %q but resolved to %v.", message, result)
+	}
+
+	if missing := getMessageByPermalink(console.New(loglevel.Fatal), items, "message/20991231-000000"); missing != nil {
+		t.Errorf("An unknown permalink should not resolve to a message but resolved to %q.", missing)
+	}
+}
+
+func Test_getMessageByPermalink_DraftsAndVirtualMessages_AreNotFound(t *testing.T) {
+	// arrange
+	draft := newTestMessage("messages/draft", "2015-03-01 15:30")
+	draft.MetaData.AddBlock("draft", "yes")
+	virtual := model.NewItem(route.NewFromRequest("messages/virtual"), nil, dataaccess.TypeVirtual)
+	virtual.Type = model.TypeMessage
+	virtual.MetaData.AddBlock("date", "2015-03-02 15:30")
+	items := []*model.Item{draft, virtual}
+
+	for _, permalink := range []string{"message/20150301-153000", "message/20150302-153000"} {
+
+		// act
+		result := getMessageByPermalink(console.New(loglevel.Fatal), items, permalink)
+
+		// assert
+		if result != nil {
+			t.Errorf("The permalink %q should not resolve to the unpublished message %q.", permalink, result)
+		}
+	}
+}
+
+func Test_getTimelineItems_MessagesAreOrderedNewestFirst(t *testing.T) {
+	// arrange
+	document := model.NewItem(route.NewFromRequest("documents/sample"), nil, dataaccess.TypePhysical)
+	document.MetaData.AddBlock("date", "2015-03-04")
+
+	draft := newTestMessage("messages/draft", "2015-03-05")
+	draft.MetaData.AddBlock("draft", "yes")
+
+	items := []*model.Item{
+		newTestMessage("messages/b", "2015-03-01 10:00"),
+		newTestMessage("messages/c", "2015-03-03 08:15"),
+		document,
+		draft,
+		newTestMessage("messages/a", "2015-03-01 10:00"),
+		newTestMessage("messages/d", "2015-02-28 23:59"),
+	}
+
+	// act
+	result := getTimelineItems(items)
+
+	// assert
+	expectedRoutes := []string{"messages/c", "messages/a", "messages/b", "messages/d"}
+	if len(result) != len(expectedRoutes) {
+		t.Fatalf("The timeline should contain %d messages but contained %d.", len(expectedRoutes), len(result))
+	}
+
+	for index, expectedRoute := range expectedRoutes {
+		if result[index].Route().Value() != expectedRoute {
+			t.Errorf("Message %d of the timeline should be %q but was %q.", index, expectedRoute, result[index].Route().Value())
+		}
+	}
+}
+
+func Test_newFeedEntry_Message_PermalinkAndExcerptAreUsed(t *testing.T) {
+	// arrange
+	message := newTestMessage("messages/hello", "2015-03-01 15:30")
+
+	// act
+	result := newFeedEntry(prefixPather{"http://example.com/"}, message, "<p>Just setting up my blog.</p>", "Just setting up my blog.")
+
+	// assert
+	if result.Link != "http://example.com/message/20150301-153000" {
+		t.Errorf("The link should be the permalink %q but was %q.", "http://example.com/message/20150301-153000", result.Link)
+	}
+
+	if result.Title != "Just setting up my blog." {
+		t.Errorf("The title should be the message text %q but was %q.", "Just setting up my blog.", result.Title)
+	}
+}
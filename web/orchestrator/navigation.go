@@ -65,18 +65,16 @@ func (orchestrator *NavigationOrchestrator) GetBreadcrumbNavigation(route route.
 		return navigation
 	}
 
-	// recurse if there is a parent
-	if parent := orchestrator.getParent(item.Route()); parent != nil {
-		navigation.Entries = append(navigation.Entries, orchestrator.GetBreadcrumbNavigation(parent.Route()).Entries...)
+	// create an entry for all ancestors and the item itself
+	unmarkedEntries := make([]viewmodel.Breadcrumb, 0)
+	for _, breadcrumbItem := range append(item.GetBreadcrumbs(), item) {
+		unmarkedEntries = append(unmarkedEntries, viewmodel.Breadcrumb{
+			Title: breadcrumbItem.Title,
+			Level: breadcrumbItem.Route().Level(),
+			Path:  orchestrator.itemPather().Path(breadcrumbItem.Route().Value()),
+		})
 	}
 
-	// append a new navigation entry and return it
-	unmarkedEntries := append(navigation.Entries, viewmodel.Breadcrumb{
-		Title: item.Title,
-		Level: item.Route().Level(),
-		Path:  orchestrator.itemPather().Path(item.Route().Value()),
-	})
-
 	// mark the entries
 	markdedEntries := make([]viewmodel.Breadcrumb, 0)
 	for index, entry := range unmarkedEntries {
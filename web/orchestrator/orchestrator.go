@@ -6,6 +6,8 @@ package orchestrator
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/andreaskoch/allmark/common/config"
@@ -16,6 +18,7 @@ import (
 	"github.com/andreaskoch/allmark/model"
 	"github.com/andreaskoch/allmark/services/converter"
 	"github.com/andreaskoch/allmark/services/parser"
+	"github.com/andreaskoch/allmark/services/thumbnail"
 	"github.com/andreaskoch/allmark/web/orchestrator/index"
 	"github.com/andreaskoch/allmark/web/orchestrator/search"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
@@ -113,7 +116,7 @@ func (updateCallback *CacheUpdateCallback) Execute(route route.Route) (err error
 	return err
 }
 
-func newBaseOrchestrator(logger logger.Logger, config config.Config, repository dataaccess.Repository, parser parser.Parser, converter converter.Converter, webPathProvider webpaths.WebPathProvider) *Orchestrator {
+func newBaseOrchestrator(logger logger.Logger, config config.Config, repository dataaccess.Repository, parser parser.Parser, converter converter.Converter, webPathProvider webpaths.WebPathProvider, thumbnailIndex *thumbnail.Index) *Orchestrator {
 
 	orchestrator := &Orchestrator{
 		logger: logger,
@@ -124,9 +127,11 @@ func newBaseOrchestrator(logger logger.Logger, config config.Config, repository
 		converter:  converter,
 
 		webPathProvider: webPathProvider,
+		thumbnailIndex:  thumbnailIndex,
 
 		updateSubscribers: make([]chan Update, 0),
 		updateCallbacks:   make(map[UpdateType][]CacheUpdateCallback),
+		publicationTimers: make(map[string]*time.Timer),
 	}
 
 	return orchestrator
@@ -141,6 +146,7 @@ type Orchestrator struct {
 	converter  converter.Converter
 
 	webPathProvider webpaths.WebPathProvider
+	thumbnailIndex  *thumbnail.Index
 
 	// caches and indizes (do not initialize!)
 	fulltextIndex   *search.ItemSearch
@@ -150,6 +156,11 @@ type Orchestrator struct {
 	// update handling
 	updateCallbacks   map[UpdateType][]CacheUpdateCallback
 	updateSubscribers []chan Update
+
+	// updates receives the repository updates which publish scheduled items (see schedulePublication)
+	updates           chan<- dataaccess.Update
+	publicationTimers map[string]*time.Timer
+	publicationLock   sync.Mutex
 }
 
 // Get the full-page title for a given headline.
@@ -338,6 +349,13 @@ func (orchestrator *Orchestrator) index() *index.Index {
 			return
 		}
 
+		// remove items which have become drafts
+		if !orchestrator.isPublished(parsedItem) {
+			orchestrator.repositoryIndex.Remove(updatedRoute)
+			orchestrator.schedulePublication(parsedItem)
+			return
+		}
+
 		orchestrator.repositoryIndex.Add(parsedItem)
 	}
 
@@ -347,14 +365,27 @@ func (orchestrator *Orchestrator) index() *index.Index {
 	}
 
 	// create a new index
-	orchestrator.repositoryIndex = index.New(orchestrator.logger)
+	childOrder, err := index.ParseChildOrder(orchestrator.config.Indexing.ChildOrder)
+	if err != nil {
+		orchestrator.logger.Warn("%s Using the default child order %q.", err.Error(), childOrder)
+	}
+
+	orchestrator.repositoryIndex = index.New(orchestrator.logger, childOrder)
 
 	// parse all items
-	repositoryItems := orchestrator.repository.Items()
-	for _, repositoryItem := range repositoryItems {
+	parseItem := func(repositoryItem dataaccess.Item) *model.Item {
 		parsedItem := orchestrator.parseItem(repositoryItem)
 		if parsedItem == nil {
 			orchestrator.logger.Warn("Unable to parse item %q", repositoryItem.String())
+		}
+
+		return parsedItem
+	}
+
+	for _, parsedItem := range parseItems(orchestrator.repository.Items(), orchestrator.config.IndexingWorkers(), parseItem) {
+		if !orchestrator.isPublished(parsedItem) {
+			orchestrator.logger.Debug("Skipping draft %q", parsedItem.String())
+			orchestrator.schedulePublication(parsedItem)
 			continue
 		}
 
@@ -369,6 +400,65 @@ func (orchestrator *Orchestrator) index() *index.Index {
 	return orchestrator.repositoryIndex
 }
 
+// isPublished returns true if the supplied item is neither a draft nor scheduled for a future date
+// or if the preview is enabled. Unpublished items are not added to the index and are therefore neither
+// rendered nor listed in the sitemap, the feeds, the search index or the children of their parent.
+// Scheduled items are published when their date has passed (see schedulePublication).
+func (orchestrator *Orchestrator) isPublished(item *model.Item) bool {
+	if orchestrator.config.Web.Preview {
+		return true
+	}
+
+	return !item.IsDraft() && !item.IsScheduled(time.Now())
+}
+
+// maximumPublicationDelay is the longest time a publication timer runs. Items which are scheduled
+// further in the future are checked again when the timer expires (time.Duration cannot hold centuries).
+const maximumPublicationDelay = 24 * time.Hour
+
+// schedulePublication sends an update for the supplied scheduled item to the updates channel as soon as
+// its date has passed, so that the item is parsed again and added to the index and all caches.
+// A previous timer of the same item is replaced. Drafts and items without updates channel are not scheduled.
+func (orchestrator *Orchestrator) schedulePublication(item *model.Item) {
+	if orchestrator.updates == nil || item.IsDraft() {
+		return
+	}
+
+	date, err := item.MetaData.GetBlockDate("date")
+	if err != nil {
+		return
+	}
+
+	delay := date.Sub(time.Now())
+	if delay > maximumPublicationDelay {
+		delay = maximumPublicationDelay
+	}
+
+	itemRoute := item.Route()
+	updates := orchestrator.updates
+
+	orchestrator.publicationLock.Lock()
+	defer orchestrator.publicationLock.Unlock()
+
+	if timer, exists := orchestrator.publicationTimers[itemRoute.Value()]; exists {
+		timer.Stop()
+	}
+
+	orchestrator.logger.Debug("Scheduling the publication of %q for %s", item.String(), date)
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		orchestrator.publicationLock.Lock()
+		if orchestrator.publicationTimers[itemRoute.Value()] == timer {
+			delete(orchestrator.publicationTimers, itemRoute.Value())
+		}
+		orchestrator.publicationLock.Unlock()
+
+		updates <- dataaccess.NewUpdate([]route.Route{itemRoute}, nil, nil)
+	})
+
+	orchestrator.publicationTimers[itemRoute.Value()] = timer
+}
+
 func (orchestrator *Orchestrator) search(keywords string, maxiumNumberOfResults int) []search.Result {
 
 	if orchestrator.fulltextIndex != nil {
@@ -376,8 +466,16 @@ func (orchestrator *Orchestrator) search(keywords string, maxiumNumberOfResults
 	}
 
 	// updateFulltextIndex creates a new full-text index and replaces the existing one.
+	// Comments are not indexed because they are displayed with their item.
 	updateFulltextIndex := func(r route.Route) {
-		newFullTextIndex := search.NewItemSearch(orchestrator.logger, orchestrator.getAllItems())
+		items := make([]*model.Item, 0)
+		for _, item := range orchestrator.getAllItems() {
+			if item.Type != model.TypeComment {
+				items = append(items, item)
+			}
+		}
+
+		newFullTextIndex := search.NewItemSearch(orchestrator.logger, items)
 		orchestrator.fulltextIndex = newFullTextIndex
 	}
 
@@ -507,15 +605,26 @@ func (orchestrator *Orchestrator) getNext(currentRoute route.Route) *model.Item
 	return latestItems[previousIndex]
 }
 
-func (orchestrator *Orchestrator) getChildren(route route.Route) []*model.Item {
+// getComments returns all comments below the item with the given route, including the replies
+// which are placed in the directories of other comments.
+func (orchestrator *Orchestrator) getComments(itemRoute route.Route) []*model.Item {
+	comments := make([]*model.Item, 0)
+	for _, child := range orchestrator.getChildren(itemRoute) {
+		if child.Type != model.TypeComment {
+			continue
+		}
+
+		comments = append(comments, child)
+		comments = append(comments, orchestrator.getComments(child.Route())...)
+	}
 
-	// get all children
-	children := orchestrator.index().GetDirectChildren(route)
+	return comments
+}
 
-	// sort the children by date
-	model.SortItemsBy(sortItemsByDate).Sort(children)
+func (orchestrator *Orchestrator) getChildren(route route.Route) []*model.Item {
 
-	return children
+	// get all children (sorted by the child order of the index)
+	return orchestrator.index().GetDirectChildren(route)
 }
 
 // getAliasMap returns the map of all items by their alias.
@@ -529,6 +638,36 @@ func (orchestrator *Orchestrator) getAliasMap() ItemCache {
 	return orchestrator.itemsByAlias
 }
 
+// getItemsByTitle returns all items whose title matches the supplied title (case-insensitive).
+func (orchestrator *Orchestrator) getItemsByTitle(title string) []*model.Item {
+	title = strings.TrimSpace(title)
+
+	items := make([]*model.Item, 0)
+	for _, item := range orchestrator.index().GetAllItems() {
+		if strings.EqualFold(strings.TrimSpace(item.Title), title) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// getExcerpt returns the excerpt of the supplied item (see model.Item.Excerpt). The content of the item is only
+// converted if the item has no summary, description block or description line.
+func (orchestrator *Orchestrator) getExcerpt(item *model.Item, maxChars int) string {
+	if excerpt := item.Excerpt("", maxChars); excerpt != "" {
+		return excerpt
+	}
+
+	content, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, orchestrator.itemPather(), item)
+	if err != nil {
+		orchestrator.logger.Warn("Cannot convert the content of item %q for the excerpt. Error: %s", item, err.Error())
+		return ""
+	}
+
+	return item.Excerpt(content, maxChars)
+}
+
 // Get the item that has the specified alias. Returns nil if there is no matching item.
 func (orchestrator *Orchestrator) getItemByAlias(alias string) *model.Item {
 
@@ -643,6 +782,35 @@ func (orchestrator *Orchestrator) getAnalyticsSettings() viewmodel.Analytics {
 	}
 }
 
+// Get the presentation settings view model.
+func (orchestrator *Orchestrator) getPresentationSettings() viewmodel.PresentationSettings {
+	return viewmodel.PresentationSettings{
+		GotoKey:   orchestrator.config.PresentationGotoKey(),
+		ToggleKey: orchestrator.config.PresentationToggleKey(),
+		NotesKey:  orchestrator.config.PresentationNotesKey(),
+
+		SwipeEnabled:  !orchestrator.config.Presentation.DisableSwipe,
+		SwipeDistance: orchestrator.config.PresentationSwipeDistance(),
+
+		ShowProgress:          !orchestrator.config.Presentation.HideProgress,
+		ProgressAlwaysVisible: orchestrator.config.Presentation.ProgressAlwaysVisible,
+
+		ShowTimer:     orchestrator.config.Presentation.ShowTimer,
+		TimerPauseKey: orchestrator.config.PresentationTimerPauseKey(),
+		Duration:      orchestrator.config.PresentationDuration(),
+		WarningTime:   orchestrator.config.PresentationWarningTime(),
+	}
+}
+
+// Get the math settings view model.
+func (orchestrator *Orchestrator) getMathSettings() viewmodel.MathSettings {
+	return viewmodel.MathSettings{
+		Enabled:    !orchestrator.config.Math.Disabled,
+		Delimiters: orchestrator.config.MathDelimiters(),
+		KaTeXURL:   orchestrator.config.MathKaTeXURL(),
+	}
+}
+
 // getParentUpdate returns a new Update instance that contains the parent of each item
 // contained in the supplied update and marks them as "modified".
 func getParentUpdate(update dataaccess.Update) dataaccess.Update {
@@ -0,0 +1,111 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// ChildrenPageFolderName is the name of the folder which contains the additional pages
+// of the children of an item (e.g. "/documents/page/2/").
+const ChildrenPageFolderName = "page"
+
+// *item route*/page/*number*
+var childrenPageRoutePattern = regexp.MustCompile(`^(?:(.*)/)?` + ChildrenPageFolderName + `/([0-9]+)$`)
+
+// GetChildrenPageRoute returns the route of the item and the page number of the supplied route
+// of a children page (e.g. "documents/page/2" -> "documents", 2).
+// A flag indicates whether the route is the route of a children page.
+func GetChildrenPageRoute(pageRoute route.Route) (route.Route, int, bool) {
+	matches := childrenPageRoutePattern.FindStringSubmatch(pageRoute.Value())
+	if matches == nil {
+		return pageRoute, 0, false
+	}
+
+	page, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return pageRoute, 0, false
+	}
+
+	return route.NewFromRequest(matches[1]), page, true
+}
+
+// GetChildrenPage returns the supplied view model with the children of the given page only
+// and the pagination for all children. The page size is taken from the configuration.
+func (orchestrator *ViewModelOrchestrator) GetChildrenPage(viewModel viewmodel.Model, page int) (viewmodel.Model, bool) {
+	return getChildrenPage(orchestrator.itemPather(), viewModel, orchestrator.config.ChildrenPageSize(), page)
+}
+
+// getChildrenPage returns the supplied view model with the children of the given page only
+// and the pagination for all children. A flag indicates whether the page exists.
+// The first page is always available; it uses the URL of the item itself.
+func getChildrenPage(pathProvider paths.Pather, viewModel viewmodel.Model, pageSize, page int) (viewmodel.Model, bool) {
+
+	if pageSize < 1 || page < 1 {
+		return viewModel, false
+	}
+
+	pageCount := getPageCount(len(viewModel.Children), pageSize)
+	if page > 1 && page > pageCount {
+		return viewModel, false
+	}
+
+	// children of the page
+	startIndex := pageSize * (page - 1)
+	endIndex := startIndex + pageSize
+	if endIndex > len(viewModel.Children) {
+		endIndex = len(viewModel.Children)
+	}
+
+	viewModel.Children = viewModel.Children[startIndex:endIndex]
+
+	// pagination
+	itemURL := pathProvider.Path(viewModel.Route)
+	pagination := viewmodel.Pagination{
+		Page:      page,
+		PageCount: pageCount,
+	}
+
+	for number := 1; number <= pageCount; number++ {
+		pagination.Pages = append(pagination.Pages, viewmodel.PageLink{
+			Number:    number,
+			URL:       getPageURL(itemURL, number),
+			IsCurrent: number == page,
+		})
+	}
+
+	if page > 1 {
+		pagination.PreviousURL = getPageURL(itemURL, page-1)
+	}
+
+	if page < pageCount {
+		pagination.NextURL = getPageURL(itemURL, page+1)
+	}
+
+	viewModel.ChildrenPagination = pagination
+
+	return viewModel, true
+}
+
+// getPageURL returns the URL of the given page (e.g. "/documents/page/2/"); the first page uses the URL of the item.
+func getPageURL(itemURL string, page int) string {
+	if page == 1 {
+		return itemURL
+	}
+
+	return fmt.Sprintf("%s/%s/%d/", strings.TrimSuffix(itemURL, "/"), ChildrenPageFolderName, page)
+}
+
+// getPageCount returns the number of pages of the supplied number of children.
+func getPageCount(childCount, pageSize int) int {
+	return (childCount + pageSize - 1) / pageSize
+}
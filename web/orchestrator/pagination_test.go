@@ -0,0 +1,116 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+func Test_getChildrenPage_25ChildrenWithPageSize10_ThreePagesWithCorrectRanges(t *testing.T) {
+	// arrange
+	collection := viewmodel.Model{}
+	collection.Route = "collection"
+	for index := 1; index <= 25; index++ {
+		collection.Children = append(collection.Children, viewmodel.Base{Title: fmt.Sprintf("Child %d", index)})
+	}
+
+	expectedPages := []struct {
+		first, last string
+		count       int
+		previousURL string
+		nextURL     string
+	}{
+		{"Child 1", "Child 10", 10, "", "/collection/page/2/"},
+		{"Child 11", "Child 20", 10, "/collection", "/collection/page/3/"},
+		{"Child 21", "Child 25", 5, "/collection/page/2/", ""},
+	}
+
+	for index, expected := range expectedPages {
+		page := index + 1
+
+		// act
+		result, found := getChildrenPage(testPather{}, collection, 10, page)
+
+		// assert
+		if !found {
+			t.Fatalf("Page %d should exist.", page)
+		}
+
+		if len(result.Children) != expected.count {
+			t.Fatalf("Page %d should contain %d children but contained %d.", page, expected.count, len(result.Children))
+		}
+
+		if result.Children[0].Title != expected.first || result.Children[len(result.Children)-1].Title != expected.last {
+			t.Errorf("Page %d should contain %q to %q but contained %q to %q.", page, expected.first, expected.last, result.Children[0].Title, result.Children[len(result.Children)-1].Title)
+		}
+
+		if result.ChildrenPagination.PageCount != 3 {
+			t.Errorf("The page count should be 3 but was %d.", result.ChildrenPagination.PageCount)
+		}
+
+		if result.ChildrenPagination.PreviousURL != expected.previousURL || result.ChildrenPagination.NextURL != expected.nextURL {
+			t.Errorf("Page %d should link to %q and %q but linked to %q and %q.", page, expected.previousURL, expected.nextURL, result.ChildrenPagination.PreviousURL, result.ChildrenPagination.NextURL)
+		}
+	}
+
+	if _, found := getChildrenPage(testPather{}, collection, 10, 4); found {
+		t.Errorf("Page 4 should not exist.")
+	}
+
+	if len(collection.Children) != 25 {
+		t.Errorf("The children of the original model should not be changed.")
+	}
+}
+
+func Test_getPageURL_RootItem_PageFolderIsBelowTheRoot(t *testing.T) {
+	// arrange
+	inputs := map[int]string{
+		1: "/",
+		2: "/page/2/",
+	}
+
+	for page, expected := range inputs {
+
+		// act
+		result := getPageURL("/", page)
+
+		// assert
+		if result != expected {
+			t.Errorf("getPageURL(%q, %d) returned %q but expected %q.", "/", page, result, expected)
+		}
+	}
+}
+
+func Test_GetChildrenPageRoute(t *testing.T) {
+	// arrange
+	inputs := []struct {
+		route     string
+		itemRoute string
+		page      int
+		isPage    bool
+	}{
+		{"documents/page/2", "documents", 2, true},
+		{"page/3", "", 3, true},
+		{"documents/sub/page/12", "documents/sub", 12, true},
+		{"documents/page", "documents/page", 0, false},
+		{"documents/page/two", "documents/page/two", 0, false},
+		{"documents/homepage/2", "documents/homepage/2", 0, false},
+	}
+
+	for _, input := range inputs {
+
+		// act
+		itemRoute, page, isPage := GetChildrenPageRoute(route.NewFromRequest(input.route))
+
+		// assert
+		if itemRoute.Value() != input.itemRoute || page != input.page || isPage != input.isPage {
+			t.Errorf("GetChildrenPageRoute(%q) returned (%q, %d, %t) but expected (%q, %d, %t).", input.route, itemRoute.Value(), page, isPage, input.itemRoute, input.page, input.isPage)
+		}
+	}
+}
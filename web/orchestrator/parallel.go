@@ -0,0 +1,58 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"sync"
+
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// parseItems parses the supplied repository items with the given number of parallel workers.
+// The parsed items are returned in the order of the repository items, regardless of the number
+// of workers. Items which could not be parsed are omitted.
+func parseItems(repositoryItems []dataaccess.Item, workers int, parse func(item dataaccess.Item) *model.Item) []*model.Item {
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	// every worker writes to its own slots of the result list
+	results := make([]*model.Item, len(repositoryItems))
+
+	positions := make(chan int)
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < workers; worker++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for position := range positions {
+				results[position] = parse(repositoryItems[position])
+			}
+		}()
+	}
+
+	for position := range repositoryItems {
+		positions <- position
+	}
+
+	close(positions)
+	waitGroup.Wait()
+
+	parsedItems := make([]*model.Item, 0, len(results))
+	for _, parsedItem := range results {
+		if parsedItem == nil {
+			continue
+		}
+
+		parsedItems = append(parsedItems, parsedItem)
+	}
+
+	return parsedItems
+}
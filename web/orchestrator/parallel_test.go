@@ -0,0 +1,124 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/dataaccess/filesystem"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/services/parser"
+)
+
+// newTestRepositoryItems creates a repository with the given number of documents ("documents/document-001", ...)
+// and returns its items, a function which parses an item and the repository directory.
+func newTestRepositoryItems(tb testing.TB, numberOfDocuments int) ([]dataaccess.Item, func(item dataaccess.Item) *model.Item, string) {
+	filesystem.ClearHashCache()
+	directory, _ := ioutil.TempDir("", "allmark-repository")
+
+	ioutil.WriteFile(filepath.Join(directory, "readme.md"), []byte("# Root\n\nThe repository root."), 0600)
+	for number := 1; number <= numberOfDocuments; number++ {
+		documentDirectory := filepath.Join(directory, "documents", fmt.Sprintf("document-%03d", number))
+		os.MkdirAll(documentDirectory, 0700)
+
+		content := fmt.Sprintf("# Document %d\n\nDescription of document %d\n\n## Section\n\nSome *content* for document %d.", number, number, number)
+		ioutil.WriteFile(filepath.Join(documentDirectory, "readme.md"), []byte(content), 0600)
+	}
+
+	logger := console.New(loglevel.Fatal)
+	repository, err := filesystem.NewRepository(logger, directory, *config.New(directory))
+	if err != nil {
+		os.RemoveAll(directory)
+		tb.Fatalf("The repository could not be created. Error: %s", err)
+	}
+
+	itemParser, _ := parser.New(logger)
+	parse := func(item dataaccess.Item) *model.Item {
+		parsedItem, _ := itemParser.ParseItem(item)
+		return parsedItem
+	}
+
+	return repository.Items(), parse, directory
+}
+
+func Test_parseItems_ConcurrentAndSequential_OutputIsIdentical(t *testing.T) {
+	// arrange
+	repositoryItems, parse, directory := newTestRepositoryItems(t, 50)
+	defer os.RemoveAll(directory)
+
+	// act
+	sequentialItems := parseItems(repositoryItems, 1, parse)
+	concurrentItems := parseItems(repositoryItems, 8, parse)
+
+	// assert
+	if len(concurrentItems) != len(repositoryItems) {
+		t.Fatalf("All %d items should have been parsed but only %d were.", len(repositoryItems), len(concurrentItems))
+	}
+
+	sequential, _ := json.Marshal(sequentialItems)
+	concurrent, _ := json.Marshal(concurrentItems)
+
+	if string(sequential) != string(concurrent) {
+		t.Errorf("Concurrent and sequential parsing should produce identical output.\nSequential: %s\nConcurrent: %s", sequential, concurrent)
+	}
+}
+
+func Test_parseItems_UnparsableItems_AreOmitted(t *testing.T) {
+	// arrange
+	repositoryItems, parse, directory := newTestRepositoryItems(t, 3)
+	defer os.RemoveAll(directory)
+
+	// the first item cannot be parsed
+	firstRoute := repositoryItems[0].Route()
+	parseAllButFirst := func(item dataaccess.Item) *model.Item {
+		if item.Route().Equals(firstRoute) {
+			return nil
+		}
+
+		return parse(item)
+	}
+
+	// act
+	result := parseItems(repositoryItems, 4, parseAllButFirst)
+
+	// assert
+	if len(result) != len(repositoryItems)-1 {
+		t.Fatalf("The result should contain %d items but contained %d.", len(repositoryItems)-1, len(result))
+	}
+
+	for index, parsedItem := range result {
+		if !parsedItem.Route().Equals(repositoryItems[index+1].Route()) {
+			t.Errorf("Item %d should be %q but was %q.", index, repositoryItems[index+1].Route(), parsedItem.Route())
+		}
+	}
+}
+
+func benchmarkParseItems(b *testing.B, workers int) {
+	repositoryItems, parse, directory := newTestRepositoryItems(b, 200)
+	defer os.RemoveAll(directory)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		parseItems(repositoryItems, workers, parse)
+	}
+}
+
+func Benchmark_parseItems_Sequential(b *testing.B) {
+	benchmarkParseItems(b, 1)
+}
+
+func Benchmark_parseItems_Concurrent(b *testing.B) {
+	benchmarkParseItems(b, runtime.GOMAXPROCS(0))
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/logger"
+	"github.com/andreaskoch/allmark/common/util/slugutil"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// usesPermalinkPattern returns true if the permalink pattern applies to the supplied item.
+// Only documents, presentations and messages with a date are moved; the repository root,
+// virtual items (folders without a markdown file) and all other items keep the path of their route.
+func usesPermalinkPattern(item *model.Item) bool {
+	if item.Route().IsEmpty() || item.IsVirtual() || item.Date().IsZero() {
+		return false
+	}
+
+	switch item.Type {
+	case model.TypeDocument, model.TypePresentation, model.TypeMessage:
+		return true
+	}
+
+	return false
+}
+
+// getPermalinkFilePath returns the relative path of the file the supplied item is rendered to
+// according to the given permalink pattern (e.g. "/:year/:month/:slug/" -> "2015/03/my-post/index.html").
+// Patterns which don't end with ".html" are treated as folders.
+func getPermalinkFilePath(pattern string, item *model.Item) string {
+	date := item.Date()
+
+	replacer := strings.NewReplacer(
+		":year", fmt.Sprintf("%04d", date.Year()),
+		":month", fmt.Sprintf("%02d", date.Month()),
+		":day", fmt.Sprintf("%02d", date.Day()),
+		":slug", getPermalinkSlug(item),
+		":type", item.Type.String(),
+	)
+
+	filePath := strings.TrimPrefix(path.Clean("/"+replacer.Replace(pattern)), "/")
+	if strings.ToLower(path.Ext(filePath)) == ".html" {
+		return filePath
+	}
+
+	return path.Join(filePath, RenderFileName)
+}
+
+// getPermalinkSlug returns the slug of the item's title or, if the title does not
+// contain any letters or digits, the slug of the item's folder name.
+func getPermalinkSlug(item *model.Item) string {
+	if slug := slugutil.FromText(item.Title); slug != "" {
+		return slug
+	}
+
+	if slug := slugutil.FromText(item.Route().LastComponentName()); slug != "" {
+		return slug
+	}
+
+	return item.Route().LastComponentName()
+}
+
+// getRenderFiles returns the supplied items by the relative path of the file they are rendered to.
+// Without a permalink pattern every item is rendered to the folder of its route. If the permalink of
+// an item is already taken by another item, a numeric suffix is added (e.g. "2015/03/my-post-2/index.html").
+func getRenderFiles(logger logger.Logger, permalinkPattern string, items []*model.Item) map[string]*model.Item {
+
+	files := make(map[string]*model.Item, len(items))
+
+	// the items which keep their paths have precedence
+	var permalinkItems []*model.Item
+	for _, item := range items {
+		if permalinkPattern == "" || !usesPermalinkPattern(item) {
+			files[GetRenderFilePath(item.Route())] = item
+			continue
+		}
+
+		permalinkItems = append(permalinkItems, item)
+	}
+
+	model.SortItemsBy(sortItemsByRoute).Sort(permalinkItems)
+
+	for _, item := range permalinkItems {
+		filePath := getPermalinkFilePath(permalinkPattern, item)
+
+		uniqueFilePath := filePath
+		for suffix := 2; files[uniqueFilePath] != nil; suffix++ {
+			uniqueFilePath = getSuffixedFilePath(filePath, suffix)
+		}
+
+		if uniqueFilePath != filePath {
+			logger.Warn("The permalink %q of item %q is already taken by %q. Using %q instead.", filePath, item.Route(), files[filePath].Route(), uniqueFilePath)
+		}
+
+		files[uniqueFilePath] = item
+	}
+
+	return files
+}
+
+// getSuffixedFilePath adds the given suffix to the folder (".../my-post-2/index.html")
+// or file name (".../my-post-2.html") of the supplied file path.
+func getSuffixedFilePath(filePath string, suffix int) string {
+	if path.Base(filePath) == RenderFileName {
+		return path.Join(fmt.Sprintf("%s-%d", path.Dir(filePath), suffix), RenderFileName)
+	}
+
+	extension := path.Ext(filePath)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(filePath, extension), suffix, extension)
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+func newTestPermalinkItem(itemRoute, title, date string, itemType model.ItemType) *model.Item {
+	item := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+	item.Title = title
+	item.Type = itemType
+	item.MetaData.CreationDate, _ = time.Parse("2006-01-02", date)
+	return item
+}
+
+// getTestRenderFiles returns the rendered files of the supplied items as "file path=route" pairs sorted by file path.
+func getTestRenderFiles(permalinkPattern string, items ...*model.Item) string {
+	var files []string
+	for filePath, item := range getRenderFiles(console.New(loglevel.Fatal), permalinkPattern, items) {
+		files = append(files, filePath+"="+item.Route().Value())
+	}
+
+	sort.Strings(files)
+	return strings.Join(files, ", ")
+}
+
+func getTestPermalinkItems() []*model.Item {
+	return []*model.Item{
+		newTestPermalinkItem("", "Home", "2015-01-01", model.TypeRepository),
+		newTestPermalinkItem("posts", "Posts", "2015-01-01", model.TypeCollection),
+		newTestPermalinkItem("posts/first", "Hello World!", "2014-03-07", model.TypeDocument),
+		newTestPermalinkItem("posts/second", "Über Größe", "2015-11-21", model.TypeDocument),
+		newTestPermalinkItem("msgs/hello", "", "2015-11-22", model.TypeMessage),
+		newTestPermalinkItem("undated", "Undated", "", model.TypeDocument),
+	}
+}
+
+func Test_getRenderFiles_NoPattern_ItemsAreRenderedToTheirRoutes(t *testing.T) {
+	// act
+	result := getTestRenderFiles("", getTestPermalinkItems()...)
+
+	// assert
+	expected := "index.html=, msgs/hello/index.html=msgs/hello, posts/first/index.html=posts/first, posts/index.html=posts, posts/second/index.html=posts/second, undated/index.html=undated"
+	if result != expected {
+		t.Errorf("The rendered files should be\n%s\nbut were\n%s", expected, result)
+	}
+}
+
+func Test_getRenderFiles_DatePattern_ItemsAreRenderedToTheirPermalinks(t *testing.T) {
+	// act
+	result := getTestRenderFiles("/:year/:month/:slug/", getTestPermalinkItems()...)
+
+	// assert
+	expected := "2014/03/hello-world/index.html=posts/first, 2015/11/hello/index.html=msgs/hello, 2015/11/ueber-groesse/index.html=posts/second, index.html=, posts/index.html=posts, undated/index.html=undated"
+	if result != expected {
+		t.Errorf("The rendered files should be\n%s\nbut were\n%s", expected, result)
+	}
+}
+
+func Test_getRenderFiles_TypePatternWithFileName_ItemsAreRenderedToHTMLFiles(t *testing.T) {
+	// act
+	result := getTestRenderFiles(":type/:year-:month-:day-:slug.html", getTestPermalinkItems()...)
+
+	// assert
+	expected := "document/2014-03-07-hello-world.html=posts/first, document/2015-11-21-ueber-groesse.html=posts/second, index.html=, message/2015-11-22-hello.html=msgs/hello, posts/index.html=posts, undated/index.html=undated"
+	if result != expected {
+		t.Errorf("The rendered files should be\n%s\nbut were\n%s", expected, result)
+	}
+}
+
+func Test_getRenderFiles_SamePermalink_NumericSuffixIsAdded(t *testing.T) {
+	// arrange
+	items := []*model.Item{
+		newTestPermalinkItem("b/news", "News", "2015-03-01", model.TypeDocument),
+		newTestPermalinkItem("a/news", "News", "2015-03-02", model.TypeDocument),
+		newTestPermalinkItem("news", "News", "2015-03-03", model.TypeCollection),
+	}
+
+	// act
+	result := getTestRenderFiles("/:slug/", items...)
+
+	// assert
+	expected := "news-2/index.html=a/news, news-3/index.html=b/news, news/index.html=news"
+	if result != expected {
+		t.Errorf("The rendered files should be\n%s\nbut were\n%s", expected, result)
+	}
+}
+
+func Test_getRenderFiles_DateBlock_DateOfTheItemIsUsed(t *testing.T) {
+	// arrange
+	item := newTestPermalinkItem("posts/scheduled", "Scheduled", "", model.TypeDocument)
+	item.MetaData.AddBlock("date", "2015-06-01 10:00")
+
+	// act
+	result := getTestRenderFiles("/:year/:month/:slug/", item)
+
+	// assert
+	expected := "2015/06/scheduled/index.html=posts/scheduled"
+	if result != expected {
+		t.Errorf("The rendered files should be\n%s\nbut were\n%s", expected, result)
+	}
+}
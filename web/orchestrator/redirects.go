@@ -0,0 +1,106 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+// AliasesBlockName is the name of the meta data block which lists the previous URLs of an item
+// (e.g. "aliases: /2014/old-name, /documents/old-name").
+const AliasesBlockName = "aliases"
+
+// GetRedirects returns the target URLs of the redirect pages for the previous URLs of all items
+// by the relative path of the redirect page (e.g. "documents/old-name/index.html" -> "/documents/new-name/").
+func (orchestrator *RenderOrchestrator) GetRedirects() (map[string]string, error) {
+	return getAliasRedirects(orchestrator.getRenderFiles())
+}
+
+// getAliasRedirects returns the redirect targets for the aliases of the supplied rendered files
+// (relative path -> item). An error is returned if two items share the same alias.
+func getAliasRedirects(files map[string]*model.Item) (map[string]string, error) {
+
+	// process the files in a stable order so the errors are reproducible
+	filePaths := make([]string, 0, len(files))
+	for filePath := range files {
+		filePaths = append(filePaths, filePath)
+	}
+
+	sort.Strings(filePaths)
+
+	redirects := make(map[string]string)
+	owners := make(map[string]*model.Item)
+	for _, filePath := range filePaths {
+		item := files[filePath]
+		target := getRenderFileURL(filePath)
+
+		for _, alias := range getAliases(item) {
+			aliasFilePath := getAliasFilePath(alias)
+			if aliasFilePath == "" {
+				continue
+			}
+
+			if owner, exists := owners[aliasFilePath]; exists && owner != item {
+				return nil, fmt.Errorf("The alias %q of item %q is already used by item %q.", alias, item.Route(), owner.Route())
+			}
+
+			owners[aliasFilePath] = item
+			redirects[aliasFilePath] = target
+		}
+	}
+
+	return redirects, nil
+}
+
+// getAliases returns the values of all aliases blocks of the supplied item.
+// Every block can contain a comma-separated list of URLs.
+func getAliases(item *model.Item) []string {
+	var aliases []string
+	for _, value := range item.MetaData.GetBlockValues(AliasesBlockName) {
+		for _, alias := range strings.Split(value, ",") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				aliases = append(aliases, alias)
+			}
+		}
+	}
+
+	return aliases
+}
+
+// getAliasFilePath returns the relative path of the redirect page for the supplied alias
+// (e.g. "/2014/old-name/" -> "2014/old-name/index.html", "http://example.com/old.html" -> "old.html").
+// Aliases which have no path return an empty string.
+func getAliasFilePath(alias string) string {
+	aliasURL, err := url.Parse(alias)
+	if err != nil {
+		return ""
+	}
+
+	aliasPath := strings.TrimPrefix(path.Clean("/"+aliasURL.Path), "/")
+	if aliasPath == "" {
+		return ""
+	}
+
+	if strings.ToLower(path.Ext(aliasPath)) == ".html" {
+		return aliasPath
+	}
+
+	return path.Join(aliasPath, RenderFileName)
+}
+
+// getRenderFileURL returns the URL path of the supplied rendered file (e.g. "documents/sample/index.html" -> "/documents/sample/").
+func getRenderFileURL(filePath string) string {
+	if path.Base(filePath) == RenderFileName {
+		return "/" + strings.TrimSuffix(filePath, RenderFileName)
+	}
+
+	return "/" + filePath
+}
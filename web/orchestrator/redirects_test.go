@@ -0,0 +1,63 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/model"
+)
+
+func Test_getAliasRedirects_ItemWithTwoAliases_TwoRedirectsToTheCanonicalURL(t *testing.T) {
+	// arrange
+	item := newTestPermalinkItem("posts/new-name", "New Name", "2015-03-07", model.TypeDocument)
+	item.MetaData.AddBlock(AliasesBlockName, "/posts/old-name, /2014/old-name.html")
+
+	files := getRenderFiles(console.New(loglevel.Fatal), "/:year/:month/:slug/", []*model.Item{item})
+
+	// act
+	redirects, err := getAliasRedirects(files)
+
+	// assert
+	if err != nil {
+		t.Fatalf("getAliasRedirects should not return an error but returned %s.", err)
+	}
+
+	expected := map[string]string{
+		"posts/old-name/index.html": "/2015/03/new-name/",
+		"2014/old-name.html":        "/2015/03/new-name/",
+	}
+
+	if len(redirects) != len(expected) {
+		t.Fatalf("There should be %d redirects but there were %d: %v", len(expected), len(redirects), redirects)
+	}
+
+	for aliasFilePath, target := range expected {
+		if redirects[aliasFilePath] != target {
+			t.Errorf("The redirect %q should point to %q but pointed to %q.", aliasFilePath, target, redirects[aliasFilePath])
+		}
+	}
+}
+
+func Test_getAliasRedirects_SameAliasForTwoItems_ErrorIsReturned(t *testing.T) {
+	// arrange
+	first := newTestPermalinkItem("posts/first", "First", "2015-03-07", model.TypeDocument)
+	first.MetaData.AddBlock(AliasesBlockName, "/old")
+
+	second := newTestPermalinkItem("posts/second", "Second", "2015-03-08", model.TypeDocument)
+	second.MetaData.AddBlock(AliasesBlockName, "old/")
+
+	files := getRenderFiles(console.New(loglevel.Fatal), "", []*model.Item{first, second})
+
+	// act
+	_, err := getAliasRedirects(files)
+
+	// assert
+	if err == nil {
+		t.Errorf("getAliasRedirects should return an error if two items share an alias.")
+	}
+}
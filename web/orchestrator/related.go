@@ -0,0 +1,81 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"sort"
+
+	"github.com/andreaskoch/allmark/model"
+)
+
+var (
+
+	// the maximum number of related items per item
+	relatedItemsLimit = 5
+)
+
+// getRelatedItems returns up to limit items of the supplied candidates which share tags with the supplied item.
+// The items are ranked by the number of shared tags; items with the same number of shared tags
+// are ranked by their date (newest first). Virtual items, drafts and the item itself are never related.
+func getRelatedItems(item *model.Item, candidates []*model.Item, limit int) []*model.Item {
+
+	itemTags := make(map[string]bool)
+	for _, tag := range item.Tags() {
+		itemTags[tag] = true
+	}
+
+	related := relatedItems{}
+	for _, candidate := range candidates {
+
+		if candidate.Route().Value() == item.Route().Value() || candidate.IsVirtual() || candidate.IsDraft() {
+			continue
+		}
+
+		sharedTags := 0
+		for _, tag := range candidate.Tags() {
+			if itemTags[tag] {
+				sharedTags++
+			}
+		}
+
+		if sharedTags == 0 {
+			continue
+		}
+
+		related.items = append(related.items, candidate)
+		related.sharedTags = append(related.sharedTags, sharedTags)
+	}
+
+	sort.Stable(related)
+
+	if len(related.items) > limit {
+		return related.items[:limit]
+	}
+
+	return related.items
+}
+
+// relatedItems sorts items by the number of shared tags (descending) and by date (newest first).
+type relatedItems struct {
+	items      []*model.Item
+	sharedTags []int
+}
+
+func (related relatedItems) Len() int {
+	return len(related.items)
+}
+
+func (related relatedItems) Swap(i, j int) {
+	related.items[i], related.items[j] = related.items[j], related.items[i]
+	related.sharedTags[i], related.sharedTags[j] = related.sharedTags[j], related.sharedTags[i]
+}
+
+func (related relatedItems) Less(i, j int) bool {
+	if related.sharedTags[i] != related.sharedTags[j] {
+		return related.sharedTags[i] > related.sharedTags[j]
+	}
+
+	return related.items[i].Date().After(related.items[j].Date())
+}
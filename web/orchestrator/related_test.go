@@ -0,0 +1,62 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+func newTestRelatedItem(itemRoute, title, tags, date string) *model.Item {
+	item := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+	item.Title = title
+	if tags != "" {
+		item.MetaData.AddBlock("tags", tags)
+	}
+
+	if date != "" {
+		item.MetaData.AddBlock("date", date)
+	}
+
+	return item
+}
+
+func Test_getRelatedItems_ItemsAreRankedBySharedTagsAndDate(t *testing.T) {
+	// arrange
+	item := newTestRelatedItem("documents/allmark", "Allmark", "go, markdown, web", "")
+
+	draft := newTestRelatedItem("documents/draft", "Draft", "go, markdown, web", "")
+	draft.MetaData.AddBlock("draft", "yes")
+
+	candidates := []*model.Item{
+		item,
+		newTestRelatedItem("documents/unrelated", "Unrelated", "cooking", ""),
+		newTestRelatedItem("documents/old-go", "Old Go", "go", "2014-01-01"),
+		newTestRelatedItem("documents/markdown-web", "Markdown Web", "markdown, web", ""),
+		newTestRelatedItem("documents/new-go", "New Go", "go", "2015-01-01"),
+		newTestRelatedItem("documents/all", "All", "web, go, markdown, cooking", ""),
+		newTestRelatedItem("documents/untagged", "Untagged", "", ""),
+		draft,
+	}
+
+	// act
+	related := getRelatedItems(item, candidates, 3)
+
+	// assert
+	var titles []string
+	for _, relatedItem := range related {
+		titles = append(titles, relatedItem.Title)
+	}
+
+	result := strings.Join(titles, "|")
+	expected := "All|Markdown Web|New Go"
+	if result != expected {
+		t.Errorf("The related items should be %q but were %q.", expected, result)
+	}
+}
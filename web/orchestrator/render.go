@@ -0,0 +1,200 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/render"
+)
+
+// RenderFileName is the name of the file every item is rendered to (e.g. "documents/sample/index.html").
+const RenderFileName = "index.html"
+
+type RenderOrchestrator struct {
+	*Orchestrator
+}
+
+// GetContentHashes returns the content hashes of all items by the relative path of their rendered file.
+// Every page shows the navigation, the tags and the related items, so the hashes depend on the site structure.
+func (orchestrator *RenderOrchestrator) GetContentHashes() map[string]string {
+	getChildren := func(parent *model.Item) []*model.Item {
+		return orchestrator.getChildren(parent.Route())
+	}
+
+	getIncludes := func(item *model.Item) []*model.Item {
+		return getIncludedItems(item, orchestrator.getItem)
+	}
+
+	files := orchestrator.getRenderFiles()
+	contentHashes := getContentHashes(files, getChildren, getIncludes, orchestrator.config.Render.PermalinkPattern != "")
+	for filePath, page := range getChildrenPages(files, getChildren, orchestrator.config.ChildrenPageSize()) {
+		contentHashes[filePath] = hashutil.FromString(fmt.Sprintf("%s-page-%d", contentHashes[page.itemFilePath], page.number))
+	}
+
+	return render.AddDependency(contentHashes, getSiteStructure(orchestrator.getAllItems()))
+}
+
+// GetRequestPaths returns the request path of the item every rendered file belongs to
+// by the relative path of the file (e.g. "2015/03/my-post/index.html" -> "/documents/my-post").
+func (orchestrator *RenderOrchestrator) GetRequestPaths() map[string]string {
+	getChildren := func(parent *model.Item) []*model.Item {
+		return orchestrator.getChildren(parent.Route())
+	}
+
+	files := orchestrator.getRenderFiles()
+	requestPaths := make(map[string]string)
+	for filePath, item := range files {
+		requestPaths[filePath] = "/" + item.Route().Value()
+	}
+
+	for filePath, page := range getChildrenPages(files, getChildren, orchestrator.config.ChildrenPageSize()) {
+		requestPaths[filePath] = page.requestPath
+	}
+
+	return requestPaths
+}
+
+// GetFileRequestPaths returns the request paths of the files of all items except for drafts by the relative path
+// of their rendered file (e.g. "documents/sample/files/photo.jpg" -> "/documents/sample/files/photo.jpg").
+func (orchestrator *RenderOrchestrator) GetFileRequestPaths() map[string]string {
+	return getFileRequestPaths(orchestrator.getAllItems())
+}
+
+// getFileRequestPaths returns the request paths of the files of the supplied items except for drafts
+// by the relative path of their rendered file.
+func getFileRequestPaths(items []*model.Item) map[string]string {
+	requestPaths := make(map[string]string)
+	for _, item := range items {
+		if item.IsDraft() {
+			continue
+		}
+
+		for _, file := range item.Files() {
+			filePath := file.Route().Value()
+			requestPaths[filePath] = "/" + filePath
+		}
+	}
+
+	return requestPaths
+}
+
+// GetItemTypes returns the number of items by item type (e.g. "document" -> 12).
+func (orchestrator *RenderOrchestrator) GetItemTypes() map[string]int {
+	return getItemTypes(orchestrator.getAllItems())
+}
+
+// getItemTypes returns the number of the supplied items by item type.
+func getItemTypes(items []*model.Item) map[string]int {
+	itemTypes := make(map[string]int)
+	for _, item := range items {
+		itemTypes[item.Type.String()]++
+	}
+
+	return itemTypes
+}
+
+func (orchestrator *RenderOrchestrator) getRenderFiles() map[string]*model.Item {
+	return getRenderFiles(orchestrator.logger, orchestrator.config.Render.PermalinkPattern, orchestrator.getAllItems())
+}
+
+// getContentHashes returns the content hashes of the supplied items by the relative path of their rendered file.
+// The content hash of an item includes its files and children, so a change also marks all ancestors as stale,
+// and the content hashes of the items it includes (see getIncludes), so a change of an included item marks
+// all including items as stale.
+// If the items are rendered to their permalinks, the hashes also include the paths of all rendered files
+// because every page links to the permalinks of others.
+func getContentHashes(files map[string]*model.Item, getChildren func(parent *model.Item) []*model.Item, getIncludes func(item *model.Item) []*model.Item, includePaths bool) map[string]string {
+
+	pathsHash := ""
+	if includePaths {
+		filePaths := make([]string, 0, len(files))
+		for filePath, item := range files {
+			filePaths = append(filePaths, filePath+"="+item.Route().Value())
+		}
+
+		sort.Strings(filePaths)
+		pathsHash = hashutil.FromString(strings.Join(filePaths, "\n"))
+	}
+
+	hashes := make(map[string]string, len(files))
+	for filePath, item := range files {
+		hash := item.GetContentHash(getChildren)
+		for _, includedItem := range getIncludes(item) {
+			hash = hashutil.FromString(hash + "\n" + includedItem.GetContentHash(nil))
+		}
+		if pathsHash != "" {
+			hash = hashutil.FromString(hash + pathsHash)
+		}
+
+		hashes[filePath] = hash
+	}
+
+	return hashes
+}
+
+// A childrenPage is an additional page of the children of an item (e.g. "/documents/page/2").
+type childrenPage struct {
+	itemFilePath string
+	requestPath  string
+	number       int
+}
+
+// getChildrenPages returns the additional pages of the children of the supplied items by the relative path of
+// their rendered file (e.g. "documents/page/2/index.html"). The first page is rendered to the file of the item.
+func getChildrenPages(files map[string]*model.Item, getChildren func(parent *model.Item) []*model.Item, pageSize int) map[string]childrenPage {
+	pages := make(map[string]childrenPage)
+	for filePath, item := range files {
+
+		// comments are not listed as children
+		childCount := 0
+		for _, child := range getChildren(item) {
+			if child.Type != model.TypeComment {
+				childCount++
+			}
+		}
+
+		folder := path.Dir(filePath)
+		if path.Base(filePath) != RenderFileName {
+			folder = strings.TrimSuffix(filePath, path.Ext(filePath))
+		}
+
+		for number := 2; number <= getPageCount(childCount, pageSize); number++ {
+			pageFilePath := path.Join(folder, ChildrenPageFolderName, strconv.Itoa(number), RenderFileName)
+			pages[pageFilePath] = childrenPage{
+				itemFilePath: filePath,
+				requestPath:  strings.TrimSuffix(getPageURL("/"+item.Route().Value(), number), "/"),
+				number:       number,
+			}
+		}
+	}
+
+	return pages
+}
+
+// getSiteStructure returns the route, title, tags and draft state of every supplied item
+// (e.g. "documents/sample|Sample|go,web|false" -> ""), for use as a dependency of all rendered items.
+func getSiteStructure(items []*model.Item) map[string]string {
+	structure := make(map[string]string, len(items))
+	for _, item := range items {
+		entry := fmt.Sprintf("%s|%s|%s|%t", item.Route().Value(), item.Title, strings.Join(item.Tags(), ","), item.IsDraft())
+		structure[entry] = ""
+	}
+
+	return structure
+}
+
+// GetRenderFilePath returns the relative path of the file the item with the given route is rendered to
+// if no permalink pattern is used.
+func GetRenderFilePath(itemRoute route.Route) string {
+	return path.Join(itemRoute.Value(), RenderFileName)
+}
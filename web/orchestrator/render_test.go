@@ -0,0 +1,236 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/orchestrator/index"
+	"github.com/andreaskoch/allmark/web/render"
+)
+
+// getTestContentHashes parses the supplied repository items and returns their content hashes.
+func getTestContentHashes(repositoryItems []dataaccess.Item, parse func(item dataaccess.Item) *model.Item) map[string]string {
+	itemIndex := index.New(console.New(loglevel.Fatal), index.DefaultChildOrder)
+	for _, parsedItem := range parseItems(repositoryItems, 1, parse) {
+		itemIndex.Add(parsedItem)
+	}
+
+	getChildren := func(parent *model.Item) []*model.Item {
+		return itemIndex.GetDirectChildren(parent.Route())
+	}
+
+	getIncludes := func(item *model.Item) []*model.Item {
+		return nil
+	}
+
+	return getContentHashes(getRenderFiles(console.New(loglevel.Fatal), "", itemIndex.GetAllItems()), getChildren, getIncludes, false)
+}
+
+func Test_GetPlan_OneFileModified_ItemAndAncestorsAreStale(t *testing.T) {
+	// arrange
+	repositoryItems, parse, directory := newTestRepositoryItems(t, 3)
+	defer os.RemoveAll(directory)
+
+	manifest := render.Manifest(getTestContentHashes(repositoryItems, parse))
+
+	modifiedFile := filepath.Join(directory, "documents", "document-002", "readme.md")
+	ioutil.WriteFile(modifiedFile, []byte("# Document 2\n\nThe content has changed."), 0600)
+
+	// act
+	plan := render.NewPlan(getTestContentHashes(repositoryItems, parse), manifest)
+
+	// assert
+	expectedWrites := []string{"documents/document-002/index.html", "documents/index.html", "index.html"}
+	if strings.Join(plan.Write, ",") != strings.Join(expectedWrites, ",") {
+		t.Errorf("The plan should write %v but writes %v.", expectedWrites, plan.Write)
+	}
+
+	expectedSkips := []string{"documents/document-001/index.html", "documents/document-003/index.html"}
+	if strings.Join(plan.Skip, ",") != strings.Join(expectedSkips, ",") {
+		t.Errorf("The plan should skip %v but skips %v.", expectedSkips, plan.Skip)
+	}
+
+	if len(plan.Delete) != 0 {
+		t.Errorf("The plan should not delete anything but deletes %v.", plan.Delete)
+	}
+}
+
+func Test_GetContentHashes_OnlyTheIncludedItemChanges_ContentHashOfTheIncludingItemChanges(t *testing.T) {
+	// arrange
+	combinedOrchestrator, directory := newTestCombinedOrchestrator(t, map[string]string{
+		"page/document.md":   "# Page\n\n{{include: /shared}}",
+		"other/document.md":  "# Other\n\nNo includes.",
+		"shared/document.md": "# Shared\n\nLicense V1",
+	})
+	defer os.RemoveAll(directory)
+
+	before := (&RenderOrchestrator{combinedOrchestrator.Orchestrator}).GetContentHashes()
+
+	ioutil.WriteFile(filepath.Join(directory, "shared", "document.md"), []byte("# Shared\n\nLicense V2"), 0600)
+
+	// act
+	after := (&RenderOrchestrator{newTestCombinedOrchestratorFromDirectory(t, directory).Orchestrator}).GetContentHashes()
+
+	// assert
+	if before["page/index.html"] == after["page/index.html"] {
+		t.Errorf("The content hash of the including item should change if the included item changes but was %q before and after.", before["page/index.html"])
+	}
+
+	if before["other/index.html"] != after["other/index.html"] {
+		t.Errorf("The content hash of an item without includes should not change but was %q before and %q after.", before["other/index.html"], after["other/index.html"])
+	}
+}
+
+func Test_getIncludedRoutes_CyclicIncludes_EveryRouteIsReturnedOnce(t *testing.T) {
+	// arrange
+	first := newTestSocialItem("first", "", model.TypeDocument)
+	first.Content = "{{include: /second}}"
+
+	second := newTestSocialItem("second", "", model.TypeDocument)
+	second.Content = "{{include: /first}} {{include: /third}}"
+
+	items := map[string]*model.Item{"first": first, "second": second}
+
+	getItem := func(itemRoute route.Route) *model.Item {
+		return items[itemRoute.Value()]
+	}
+
+	// act
+	includedRoutes := getIncludedRoutes(first, getItem)
+
+	// assert
+	if len(includedRoutes) != 2 || includedRoutes[0].Value() != "second" || includedRoutes[1].Value() != "third" {
+		t.Errorf("The included routes should be [second third] but were %v.", includedRoutes)
+	}
+}
+
+func Test_getChildrenPages_CollectionWithFiveChildren_AdditionalPagesAreRendered(t *testing.T) {
+	// arrange
+	root := newTestSocialItem("", "# Root", model.TypeRepository)
+	collection := newTestSocialItem("documents", "# Documents", model.TypeCollection)
+
+	var children []*model.Item
+	for index := 1; index <= 5; index++ {
+		children = append(children, newTestSocialItem(fmt.Sprintf("documents/%d", index), "# Child", model.TypeDocument))
+	}
+
+	comment := newTestSocialItem("documents/comment", "A comment", model.TypeComment)
+
+	files := map[string]*model.Item{
+		"index.html":                root,
+		"2015/documents/index.html": collection,
+	}
+
+	getChildren := func(parent *model.Item) []*model.Item {
+		if parent == collection {
+			return append(children, comment)
+		}
+
+		return []*model.Item{collection}
+	}
+
+	// act
+	result := getChildrenPages(files, getChildren, 2)
+
+	// assert
+	expected := map[string]childrenPage{
+		"2015/documents/page/2/index.html": {"2015/documents/index.html", "/documents/page/2", 2},
+		"2015/documents/page/3/index.html": {"2015/documents/index.html", "/documents/page/3", 3},
+	}
+
+	if len(result) != len(expected) {
+		t.Errorf("The pages should be %v but were %v.", expected, result)
+	}
+
+	for filePath, page := range expected {
+		if result[filePath] != page {
+			t.Errorf("The page %q should be %v but was %v.", filePath, page, result[filePath])
+		}
+	}
+}
+
+func Test_getSiteStructure_TitleChanges_AllContentHashesChange(t *testing.T) {
+	// arrange
+	sample := newTestSocialItem("documents/sample", "# Sample", model.TypeDocument)
+	other := newTestSocialItem("documents/other", "# Other", model.TypeDocument)
+	other.Title = "Other"
+
+	contentHashes := map[string]string{
+		"documents/sample/index.html": "sample-1",
+		"documents/other/index.html":  "other-1",
+	}
+
+	before := render.AddDependency(contentHashes, getSiteStructure([]*model.Item{sample, other}))
+
+	// act
+	other.Title = "Renamed"
+	after := render.AddDependency(contentHashes, getSiteStructure([]*model.Item{sample, other}))
+
+	// assert
+	for filePath := range contentHashes {
+		if before[filePath] == after[filePath] {
+			t.Errorf("The content hash of %q should have changed after the title of another item has changed.", filePath)
+		}
+	}
+}
+
+func Test_getSiteStructure_ContentChanges_StructureIsUnchanged(t *testing.T) {
+	// arrange
+	sample := newTestSocialItem("documents/sample", "# Sample", model.TypeDocument)
+	sample.MetaData.Tags = []string{"go", "web"}
+
+	before := getSiteStructure([]*model.Item{sample})
+
+	// act
+	sample.Content = "The content has changed."
+	after := getSiteStructure([]*model.Item{sample})
+
+	// assert
+	expected := "documents/sample||go,web|false"
+	if _, exists := after[expected]; !exists || len(after) != 1 {
+		t.Errorf("The site structure should be %q but was %v.", expected, after)
+	}
+
+	if len(before) != len(after) {
+		t.Errorf("The site structure should not change with the content but was %v before and %v after.", before, after)
+	}
+}
+
+func Test_getFileRequestPaths_ItemsWithFiles_FilesOfDraftsAreExcluded(t *testing.T) {
+	// arrange
+	published := newTestSocialItem("documents/sample", "# Sample", model.TypeDocument, "documents/sample/files/photo.jpg", "documents/sample/files/notes.pdf")
+	draft := newTestSocialItem("documents/draft", "# Draft", model.TypeDocument, "documents/draft/files/secret.png")
+	draft.MetaData.Blocks = []model.Block{{Name: "draft", Value: "yes"}}
+
+	// act
+	result := getFileRequestPaths([]*model.Item{published, draft})
+
+	// assert
+	expected := map[string]string{
+		"documents/sample/files/photo.jpg": "/documents/sample/files/photo.jpg",
+		"documents/sample/files/notes.pdf": "/documents/sample/files/notes.pdf",
+	}
+
+	if len(result) != len(expected) {
+		t.Errorf("The result should contain %v but contained %v.", expected, result)
+	}
+
+	for filePath, requestPath := range expected {
+		if result[filePath] != requestPath {
+			t.Errorf("The request path of %q should be %q but was %q.", filePath, requestPath, result[filePath])
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// technicalDisallowPaths contains the paths of the alternative item representations
+// (e.g. json or docx) which are never meant to be indexed.
+var technicalDisallowPaths = []string{
+	"/thumbnails",
+	"/docx$",
+	"/json$",
+	"/markdown$",
+	"/print$",
+	"/ws$",
+	"/*.docx$",
+	"/*.json$",
+	"/*.markdown$",
+	"/*.print$",
+	"/*.ws$",
+}
+
+// A RobotsTxtOrchestrator provides the robots.txt model.
+type RobotsTxtOrchestrator struct {
+	*Orchestrator
+}
+
+// GetRobotsTxt returns the robots.txt model with the configured disallow rules
+// and a reference to the supplied sitemap URL.
+func (orchestrator *RobotsTxtOrchestrator) GetRobotsTxt(sitemapURL string) viewmodel.RobotsTxt {
+	return getRobotsTxt(orchestrator.absolutePather("/"), sitemapURL, orchestrator.config.Web.RobotsTxtDisallow)
+}
+
+// getRobotsTxt returns a robots.txt model which allows everything except for the technical paths
+// and the supplied path prefixes. The prefixes are converted to web paths with the supplied path provider
+// so they match the served URLs; a trailing slash of a prefix is preserved.
+func getRobotsTxt(pathProvider paths.Pather, sitemapURL string, disallowPrefixes []string) viewmodel.RobotsTxt {
+
+	disallowPaths := make([]string, 0, len(technicalDisallowPaths)+len(disallowPrefixes))
+	disallowPaths = append(disallowPaths, technicalDisallowPaths...)
+
+	for _, prefix := range disallowPrefixes {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			continue
+		}
+
+		disallowPath := pathProvider.Path(route.NewFromRequest(prefix).Value())
+		if strings.HasSuffix(prefix, "/") && !strings.HasSuffix(disallowPath, "/") {
+			disallowPath += "/"
+		}
+
+		disallowPaths = append(disallowPaths, disallowPath)
+	}
+
+	return viewmodel.RobotsTxt{
+		Disallows: []viewmodel.RobotsTxtDisallow{
+			viewmodel.RobotsTxtDisallow{
+				UserAgent: "*",
+				Paths:     disallowPaths,
+			},
+		},
+		SitemapURL: sitemapURL,
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/web/view/templates"
+)
+
+func Test_getRobotsTxt_OutputContainsSitemapAndConfiguredDisallowRules(t *testing.T) {
+	// arrange
+	templateProvider := templates.NewProvider("")
+	robotsTxtTemplate, _ := templateProvider.GetRobotsTxtTemplate("http://example.com")
+
+	model := getRobotsTxt(testPather{}, "http://example.com/sitemap.xml", []string{"drafts/", "/Private Notes", " "})
+
+	// act
+	buffer := new(bytes.Buffer)
+	err := robotsTxtTemplate.Execute(buffer, model)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The robots.txt could not be rendered. Error: %s", err)
+	}
+
+	robotsTxt := buffer.String()
+	expectedLines := []string{
+		"User-agent: *",
+		"Disallow: /drafts/",
+		"Disallow: /Private+Notes",
+		"Sitemap: http://example.com/sitemap.xml",
+	}
+
+	for _, expectedLine := range expectedLines {
+		if !strings.Contains(robotsTxt, expectedLine+"\n") {
+			t.Errorf("The robots.txt should contain %q:\n%s", expectedLine, robotsTxt)
+		}
+	}
+
+	if strings.Contains(robotsTxt, "Disallow: /\n") {
+		t.Errorf("Empty prefixes should not disallow everything:\n%s", robotsTxt)
+	}
+}
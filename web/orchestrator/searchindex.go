@@ -0,0 +1,80 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"html"
+
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// A SearchIndexOrchestrator provides the index for the client-side search.
+type SearchIndexOrchestrator struct {
+	*Orchestrator
+
+	// caches
+	searchIndex []viewmodel.SearchIndexEntry
+}
+
+// GetSearchIndex returns a search index entry with the title, the URL and the plain text of every published item.
+// The index is built once and rebuilt whenever an item changes.
+func (orchestrator *SearchIndexOrchestrator) GetSearchIndex() []viewmodel.SearchIndexEntry {
+
+	if orchestrator.searchIndex != nil {
+		return orchestrator.searchIndex
+	}
+
+	// updateSearchIndex converts all items and assigns the new search index to the orchestrator cache.
+	updateSearchIndex := func(route route.Route) {
+		pathProvider := orchestrator.itemPather()
+
+		getContent := func(item *model.Item) string {
+			content, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, pathProvider, item)
+			if err != nil {
+				orchestrator.logger.Warn("Unable to convert item %q. Error: %s", item, err.Error())
+				return ""
+			}
+
+			return content
+		}
+
+		orchestrator.searchIndex = getSearchIndexEntries(pathProvider, orchestrator.getAllItems(), getContent)
+	}
+
+	// register update callbacks
+	orchestrator.registerUpdateCallback("update search index", UpdateTypeNew, updateSearchIndex)
+	orchestrator.registerUpdateCallback("update search index", UpdateTypeModified, updateSearchIndex)
+	orchestrator.registerUpdateCallback("update search index", UpdateTypeDeleted, updateSearchIndex)
+
+	// build the first search index
+	updateSearchIndex(route.New())
+
+	return orchestrator.searchIndex
+}
+
+// getSearchIndexEntries returns the search index entries for all published items (no virtual items, no drafts and no comments).
+// The getContent function returns the rendered HTML of an item.
+func getSearchIndexEntries(pathProvider paths.Pather, items []*model.Item, getContent func(item *model.Item) string) []viewmodel.SearchIndexEntry {
+
+	entries := make([]viewmodel.SearchIndexEntry, 0, len(items))
+	for _, item := range items {
+
+		// skip virtual items, drafts and comments (comments are displayed with their item)
+		if item.IsVirtual() || item.IsDraft() || item.Type == model.TypeComment {
+			continue
+		}
+
+		entries = append(entries, viewmodel.SearchIndexEntry{
+			Title: item.Title,
+			URL:   pathProvider.Path(item.Route().Value()),
+			Text:  html.UnescapeString(getPlainText(getContent(item))),
+		})
+	}
+
+	return entries
+}
@@ -0,0 +1,126 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+func Test_getSearchIndexEntries_SampleDocumentContainsExpectedTokens(t *testing.T) {
+	// arrange
+	document := model.NewItem(route.NewFromRequest("documents/sample"), nil, dataaccess.TypePhysical)
+	document.Title = "Sample Document"
+
+	draft := model.NewItem(route.NewFromRequest("documents/draft"), nil, dataaccess.TypePhysical)
+	draft.MetaData.AddBlock("draft", "true")
+
+	getContent := func(item *model.Item) string {
+		return "<h1>Sample Document</h1>\n<p>Allmark renders <strong>markdown</strong> &amp; serves it.</p>"
+	}
+
+	// act
+	entries := getSearchIndexEntries(testPather{}, []*model.Item{document, draft}, getContent)
+
+	// assert
+	if len(entries) != 1 {
+		t.Fatalf("The search index should contain exactly one entry but contained %d.", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.Title != "Sample Document" {
+		t.Errorf("The title should be %q but was %q.", "Sample Document", entry.Title)
+	}
+
+	if entry.URL != "/documents/sample" {
+		t.Errorf("The URL should be %q but was %q.", "/documents/sample", entry.URL)
+	}
+
+	tokens := strings.Fields(entry.Text)
+	expectedTokens := []string{"Sample", "Document", "Allmark", "renders", "markdown", "&", "serves"}
+	for _, expectedToken := range expectedTokens {
+		if !containsString(tokens, expectedToken) {
+			t.Errorf("The text %q should contain the token %q.", entry.Text, expectedToken)
+		}
+	}
+
+	if strings.Contains(entry.Text, "<") {
+		t.Errorf("The text should not contain any HTML but was %q.", entry.Text)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Test_getSearchIndexEntries_Comments_AreSkipped(t *testing.T) {
+	// arrange
+	comment := model.NewItem(route.NewFromRequest("documents/sample/comment-20150801-153000"), nil, dataaccess.TypePhysical)
+	comment.Type = model.TypeComment
+
+	getContent := func(item *model.Item) string {
+		return "<p>Nice article!</p>"
+	}
+
+	// act
+	entries := getSearchIndexEntries(testPather{}, []*model.Item{comment}, getContent)
+
+	// assert
+	if len(entries) != 0 {
+		t.Errorf("The search index should not contain comments but contained %v.", entries)
+	}
+}
+
+func Test_GetSearchIndex_ItemIsModified_IndexIsRebuiltOnUpdate(t *testing.T) {
+	// arrange
+	combinedOrchestrator, directory := newTestCombinedOrchestrator(t, map[string]string{
+		"readme.md":      "# Root",
+		"post/readme.md": "# Post\n\nA post.\n\nThe first version.",
+	})
+	defer os.RemoveAll(directory)
+
+	searchIndexOrchestrator := &SearchIndexOrchestrator{Orchestrator: combinedOrchestrator.Orchestrator}
+	searchIndexOrchestrator.GetSearchIndex()
+
+	ioutil.WriteFile(filepath.Join(directory, "post", "readme.md"), []byte("# Post\n\nA post.\n\nThe second version."), 0600)
+	cachedIndex := searchIndexOrchestrator.GetSearchIndex()
+
+	// act
+	searchIndexOrchestrator.UpdateCache(dataaccess.NewUpdate(nil, []route.Route{route.NewFromRequest("post")}, nil))
+	updatedIndex := searchIndexOrchestrator.GetSearchIndex()
+
+	// assert
+	getText := func(entries []viewmodel.SearchIndexEntry) string {
+		for _, entry := range entries {
+			if entry.Title == "Post" {
+				return entry.Text
+			}
+		}
+
+		return ""
+	}
+
+	if !strings.Contains(getText(cachedIndex), "first version") {
+		t.Errorf("The search index should be cached until the item is updated but was %q.", getText(cachedIndex))
+	}
+
+	if !strings.Contains(getText(updatedIndex), "second version") {
+		t.Errorf("The search index should be rebuilt when the item is updated but was %q.", getText(updatedIndex))
+	}
+}
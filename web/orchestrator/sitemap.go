@@ -6,6 +6,7 @@ package orchestrator
 
 import (
 	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 )
 
@@ -55,6 +56,11 @@ func (orchestrator *SitemapOrchestrator) getSitemapEntries(startRoute route.Rout
 	children := make([]viewmodel.SitemapEntry, 0)
 	for _, child := range orchestrator.getChildren(startRoute) {
 
+		// comments are displayed with their item
+		if child.Type == model.TypeComment {
+			continue
+		}
+
 		childRoute := child.Route()
 
 		childModel := viewmodel.SitemapEntry{
@@ -0,0 +1,40 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// slideSeparatorPattern matches the horizontal rules which separate the slides of a presentation.
+	slideSeparatorPattern = regexp.MustCompile(`<hr\s*/?>`)
+
+	// speakerNotesSeparatorPattern matches the "???" paragraph after which the speaker notes of a slide start.
+	speakerNotesSeparatorPattern = regexp.MustCompile(`<p>\s*\?\?\?\s*</p>`)
+
+	// speakerNotesPattern matches <aside class="notes"> elements.
+	speakerNotesPattern = regexp.MustCompile(`(?s)<aside class="notes">.*?</aside>`)
+)
+
+// getSlides splits the supplied presentation HTML into slides at the horizontal rules
+// (like the presentation script of the theme does) and removes the speaker notes from each slide.
+func getSlides(html string) []string {
+	slides := make([]string, 0)
+
+	for _, slide := range slideSeparatorPattern.Split(html, -1) {
+
+		// content after the "???" marker
+		slide = speakerNotesSeparatorPattern.Split(slide, 2)[0]
+
+		// <aside class="notes"> elements
+		slide = speakerNotesPattern.ReplaceAllString(slide, "")
+
+		slides = append(slides, strings.TrimSpace(slide))
+	}
+
+	return slides
+}
@@ -0,0 +1,51 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_getSlides_SlidesAreSplitAtHorizontalRules(t *testing.T) {
+	// arrange
+	html := "<h1>Intro</h1>\n<hr>\n<h2>Agenda</h2>\n<hr/>\n<h2>Questions</h2>"
+
+	// act
+	result := getSlides(html)
+
+	// assert
+	expected := []string{"<h1>Intro</h1>", "<h2>Agenda</h2>", "<h2>Questions</h2>"}
+	if strings.Join(result, "|") != strings.Join(expected, "|") {
+		t.Errorf("The slides should be %q but were %q.", expected, result)
+	}
+}
+
+func Test_getSlides_SpeakerNotesAreRemoved(t *testing.T) {
+	// arrange
+	html := strings.Join([]string{
+		"<h1>Intro</h1>\n<p>???</p>\n<p>Welcome everybody</p>",
+		"<h2>Agenda</h2>\n<aside class=\"notes\">\n<p>Keep it short</p>\n</aside>\n<p>Topics</p>",
+		"<h2>Questions</h2>",
+	}, "\n<hr>\n")
+
+	// act
+	result := getSlides(html)
+
+	// assert
+	if len(result) != 3 {
+		t.Fatalf("There should be 3 slides but there were %d.", len(result))
+	}
+
+	for _, slide := range result {
+		if strings.Contains(slide, "Welcome everybody") || strings.Contains(slide, "Keep it short") || strings.Contains(slide, "???") {
+			t.Errorf("The slide %q should not contain any speaker notes.", slide)
+		}
+	}
+
+	if !strings.Contains(result[1], "<p>Topics</p>") {
+		t.Errorf("The slide content after the notes should be kept but the slide was %q.", result[1])
+	}
+}
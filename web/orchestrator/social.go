@@ -0,0 +1,87 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+	"github.com/andreaskoch/allmark/web/webpaths"
+)
+
+// The maximum number of characters of the social media description.
+const socialDescriptionLength = 200
+
+var (
+	// ![*alt text*](*path*)
+	socialMarkdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*([^)\s]+)[^)]*\)`)
+
+	// imagegallery: [*description text*](*folder path*)
+	socialImageGalleryPattern = regexp.MustCompile(`imagegallery: \[[^\]]*\]\(([^)]+)\)`)
+)
+
+// getSocialMetaData returns the OpenGraph and Twitter Card properties of the supplied item with the given description.
+// The default image is used if the item has no image of its own.
+func getSocialMetaData(item *model.Item, description, defaultImage string) viewmodel.SocialMetaData {
+	socialMetaData := viewmodel.SocialMetaData{
+		Title:       item.GetTitle(),
+		Description: description,
+		URL:         item.Route().Value(),
+		Image:       getSocialImage(item),
+		TwitterCard: "summary_large_image",
+	}
+
+	if socialMetaData.Image == "" {
+		socialMetaData.Image = strings.TrimSpace(defaultImage)
+	}
+
+	if socialMetaData.Image == "" {
+		socialMetaData.TwitterCard = "summary"
+	}
+
+	return socialMetaData
+}
+
+// getSocialImage returns the route (or URL) of the image which best represents the supplied item:
+// the first image shown on the slides of a presentation, the first image of the first image gallery
+// of the item or else its first image file. An empty string is returned if the item has no images.
+func getSocialImage(item *model.Item) string {
+
+	imageFiles := item.ImageFiles()
+
+	if item.Type == model.TypePresentation {
+		for _, match := range socialMarkdownImagePattern.FindAllStringSubmatch(item.Markdown, -1) {
+			imagePath := strings.TrimSpace(match[1])
+			if webpaths.IsAbsoluteURI(imagePath) {
+				return imagePath
+			}
+
+			imageRoute := route.NewFromRequest(imagePath)
+			for _, file := range imageFiles {
+				if strings.HasSuffix(imageRoute.Value(), file.Route().Value()) || strings.HasSuffix(file.Route().Value(), imageRoute.Value()) {
+					return file.Route().Value()
+				}
+			}
+		}
+	}
+
+	if match := socialImageGalleryPattern.FindStringSubmatch(item.Markdown); match != nil {
+		galleryRoute := route.Combine(item.Route(), route.NewFromRequest(strings.TrimSpace(match[1])))
+		for _, file := range imageFiles {
+			if strings.HasPrefix(file.Route().Value(), galleryRoute.Value()+"/") {
+				return file.Route().Value()
+			}
+		}
+	}
+
+	if len(imageFiles) > 0 {
+		return imageFiles[0].Route().Value()
+	}
+
+	return ""
+}
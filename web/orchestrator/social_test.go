@@ -0,0 +1,118 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+// A testSocialFile is a dataaccess.File which only has a route and a name.
+type testSocialFile struct {
+	dataaccess.File
+	route route.Route
+}
+
+func (file testSocialFile) Route() route.Route {
+	return file.route
+}
+
+func (file testSocialFile) Name() string {
+	return file.route.LastComponentName()
+}
+
+func newTestSocialItem(itemRoute, markdown string, itemType model.ItemType, fileRoutes ...string) *model.Item {
+	files := make([]*model.File, 0, len(fileRoutes))
+	for _, fileRoute := range fileRoutes {
+		files = append(files, &model.File{File: testSocialFile{route: route.NewFromRequest(fileRoute)}})
+	}
+
+	item := model.NewItem(route.NewFromRequest(itemRoute), files, dataaccess.TypePhysical)
+	item.Type = itemType
+	item.Markdown = markdown
+	return item
+}
+
+func Test_getSocialMetaData_DocumentWithImages_FirstImageIsUsed(t *testing.T) {
+	// arrange
+	item := newTestSocialItem("documents/sample", "# Sample\n\nSome text.", model.TypeDocument,
+		"documents/sample/files/notes.pdf",
+		"documents/sample/files/photo.jpg",
+		"documents/sample/files/second.png",
+	)
+
+	// act
+	result := getSocialMetaData(item, "Some text.", "/theme/logo.png")
+
+	// assert
+	if result.Image != "documents/sample/files/photo.jpg" {
+		t.Errorf("The image should be %q but was %q.", "documents/sample/files/photo.jpg", result.Image)
+	}
+
+	if result.TwitterCard != "summary_large_image" {
+		t.Errorf("The twitter card should be %q but was %q.", "summary_large_image", result.TwitterCard)
+	}
+
+	if result.Title != "Sample" || result.URL != "documents/sample" {
+		t.Errorf("The title and url should be %q and %q but were %q and %q.", "Sample", "documents/sample", result.Title, result.URL)
+	}
+}
+
+func Test_getSocialMetaData_DocumentWithoutImages_DefaultImageIsUsed(t *testing.T) {
+	// arrange
+	item := newTestSocialItem("documents/sample", "# Sample", model.TypeDocument, "documents/sample/files/notes.pdf")
+
+	// act
+	withDefault := getSocialMetaData(item, "Some text.", "/theme/logo.png")
+	withoutDefault := getSocialMetaData(item, "Some text.", "")
+
+	// assert
+	if withDefault.Image != "/theme/logo.png" {
+		t.Errorf("The image should be the default image %q but was %q.", "/theme/logo.png", withDefault.Image)
+	}
+
+	if withoutDefault.Image != "" || withoutDefault.TwitterCard != "summary" {
+		t.Errorf("Without a default image there should be no image and a %q card but the result was %#v.", "summary", withoutDefault)
+	}
+}
+
+func Test_getSocialImage_Presentation_FirstSlideImageIsUsed(t *testing.T) {
+	// arrange
+	markdown := "# Slides\n\n---\n\n## Intro\n\n---\n\n![Diagram](files/diagram.png)\n\n---\n\n![Photo](files/photo.jpg)"
+	item := newTestSocialItem("presentations/talk", markdown, model.TypePresentation,
+		"presentations/talk/files/background.jpg",
+		"presentations/talk/files/diagram.png",
+		"presentations/talk/files/photo.jpg",
+	)
+
+	// act
+	result := getSocialImage(item)
+
+	// assert
+	if result != "presentations/talk/files/diagram.png" {
+		t.Errorf("The image should be %q but was %q.", "presentations/talk/files/diagram.png", result)
+	}
+}
+
+func Test_getSocialImage_Gallery_FirstGalleryImageIsUsed(t *testing.T) {
+	// arrange
+	markdown := "# Holidays\n\nimagegallery: [Beach](files/beach)"
+	item := newTestSocialItem("photos/holidays", markdown, model.TypeDocument,
+		"photos/holidays/files/avatar.png",
+		"photos/holidays/files/beach/sunset.jpg",
+		"photos/holidays/files/beach/waves.jpg",
+	)
+
+	// act
+	result := getSocialImage(item)
+
+	// assert
+	if result != "photos/holidays/files/beach/sunset.jpg" {
+		t.Errorf("The image should be %q but was %q.", "photos/holidays/files/beach/sunset.jpg", result)
+	}
+}
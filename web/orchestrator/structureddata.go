@@ -0,0 +1,85 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+	"github.com/andreaskoch/allmark/web/webpaths"
+)
+
+// getStructuredData returns the schema.org data of the supplied item: a "Place" for items with
+// valid coordinates and an "Article" for documents and presentations. Other items have no structured data (nil).
+// The URLs are only included if a base URL is given, because JSON-LD requires absolute URLs.
+func getStructuredData(item *model.Item, description string, author viewmodel.Author, baseURL string) *viewmodel.StructuredData {
+
+	baseURL = strings.TrimSuffix(strings.TrimSpace(baseURL), "/")
+	getURL := func(path string) string {
+		if webpaths.IsAbsoluteURI(path) {
+			return path
+		}
+
+		if path == "" || baseURL == "" {
+			return ""
+		}
+
+		return baseURL + "/" + strings.TrimPrefix(path, "/")
+	}
+
+	url := getURL(item.Route().Value())
+
+	if hasCoordinates(item) {
+		geoLocation := getGeoLocation(item)
+		latitude, latitudeErr := strconv.ParseFloat(geoLocation.Latitude, 64)
+		longitude, longitudeErr := strconv.ParseFloat(geoLocation.Longitude, 64)
+		if latitudeErr == nil && longitudeErr == nil {
+			return &viewmodel.StructuredData{
+				Context:     "http://schema.org",
+				Type:        "Place",
+				Name:        item.GetTitle(),
+				Description: description,
+				Address:     geoLocation.Address,
+				Geo: &viewmodel.StructuredDataGeoCoordinates{
+					Type:      "GeoCoordinates",
+					Latitude:  latitude,
+					Longitude: longitude,
+				},
+				URL:   url,
+				Image: getURL(getSocialImage(item)),
+			}
+		}
+	}
+
+	if item.Type != model.TypeDocument && item.Type != model.TypePresentation {
+		return nil
+	}
+
+	article := &viewmodel.StructuredData{
+		Context:     "http://schema.org",
+		Type:        "Article",
+		Headline:    item.GetTitle(),
+		Description: description,
+		URL:         url,
+		Image:       getURL(getSocialImage(item)),
+	}
+
+	if date, err := item.MetaData.GetBlockDate("date"); err == nil {
+		article.DatePublished = date.Format(time.RFC3339)
+	}
+
+	if author.Name != "" {
+		article.Author = &viewmodel.StructuredDataPerson{
+			Type: "Person",
+			Name: author.Name,
+			URL:  author.URL,
+		}
+	}
+
+	return article
+}
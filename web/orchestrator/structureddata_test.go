@@ -0,0 +1,88 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+func Test_getStructuredData_Document_ArticleWithHeadlineDateAndAuthor(t *testing.T) {
+	// arrange
+	item := newTestSocialItem("documents/sample", "# Sample\n\nSome text.", model.TypeDocument)
+	item.Content = "<p>Some text.</p>"
+	item.MetaData.AddBlock("date", "2015-03-01")
+	author := viewmodel.Author{Name: "John Doe", URL: "http://example.com/john"}
+
+	// act
+	result := getStructuredData(item, "Some text.", author, "http://example.com/")
+
+	// assert
+	if result == nil || result.Type != "Article" {
+		t.Fatalf("The structured data should be an %q but was %#v.", "Article", result)
+	}
+
+	if result.Headline != "Sample" {
+		t.Errorf("The headline should be %q but was %q.", "Sample", result.Headline)
+	}
+
+	if result.DatePublished != "2015-03-01T00:00:00Z" {
+		t.Errorf("The publication date should be %q but was %q.", "2015-03-01T00:00:00Z", result.DatePublished)
+	}
+
+	if result.Author == nil || result.Author.Name != "John Doe" || result.Author.URL != "http://example.com/john" {
+		t.Errorf("The author should be %q but was %#v.", "John Doe", result.Author)
+	}
+
+	if result.URL != "http://example.com/documents/sample" {
+		t.Errorf("The url should be %q but was %q.", "http://example.com/documents/sample", result.URL)
+	}
+}
+
+func Test_getStructuredData_DocumentWithoutMetaData_MissingFieldsAreOmitted(t *testing.T) {
+	// arrange
+	item := newTestSocialItem("documents/sample", "# Sample", model.TypeDocument)
+
+	// act
+	result := getStructuredData(item, "", viewmodel.Author{}, "")
+
+	// assert
+	if result == nil {
+		t.Fatalf("The structured data should not be nil.")
+	}
+
+	if result.DatePublished != "" || result.Author != nil || result.URL != "" || result.Image != "" {
+		t.Errorf("Unavailable fields should be empty but the result was %#v.", result)
+	}
+}
+
+func Test_getStructuredData_ItemWithCoordinates_PlaceWithGeoCoordinates(t *testing.T) {
+	// arrange
+	item := newTestSocialItem("places/alexanderplatz", "# Alexanderplatz", model.TypeDocument)
+	item.MetaData.AddBlock("latitude", "52.5219")
+	item.MetaData.AddBlock("longitude", "13.4132")
+
+	// act
+	result := getStructuredData(item, "", viewmodel.Author{Name: "John Doe"}, "")
+
+	// assert
+	if result == nil || result.Type != "Place" {
+		t.Fatalf("The structured data should be a %q but was %#v.", "Place", result)
+	}
+
+	if result.Name != "Alexanderplatz" {
+		t.Errorf("The name should be %q but was %q.", "Alexanderplatz", result.Name)
+	}
+
+	if result.Geo == nil || result.Geo.Latitude != 52.5219 || result.Geo.Longitude != 13.4132 {
+		t.Errorf("The geo coordinates should be 52.5219, 13.4132 but were %#v.", result.Geo)
+	}
+
+	if result.Author != nil {
+		t.Errorf("A place should not have an author.")
+	}
+}
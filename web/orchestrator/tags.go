@@ -5,11 +5,17 @@
 package orchestrator
 
 import (
-	"github.com/andreaskoch/allmark/common/route"
-	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"fmt"
 	"math"
 	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/paths"
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
 )
 
 var (
@@ -26,7 +32,7 @@ type TagsOrchestrator struct {
 	tagCloud viewmodel.TagCloud
 }
 
-// GetTags returns a list of all known tag models.
+// GetTags returns a list of all known tag models ordered by the number of items (most used tags first).
 func (orchestrator *TagsOrchestrator) GetTags() []viewmodel.Tag {
 
 	if orchestrator.tags != nil {
@@ -42,42 +48,31 @@ func (orchestrator *TagsOrchestrator) GetTags() []viewmodel.Tag {
 		}
 
 		// items by tag
-		itemsByTag := make(map[string][]viewmodel.Model)
-		for _, item := range orchestrator.getAllItems() {
-
-			itemViewModel := viewmodel.Model{
-				Base: getBaseModel(rootItem, item, orchestrator.config),
-			}
-
-			for _, tag := range item.MetaData.Tags {
-				if items, exists := itemsByTag[tag]; exists {
-					itemsByTag[tag] = append(items, itemViewModel)
-				} else {
-					itemsByTag[tag] = []viewmodel.Model{itemViewModel}
-				}
-			}
-
-		}
+		itemsByTag := getItemsByTag(orchestrator.getAllItems())
 
 		// create tag models
-		tags := make([]viewmodel.Tag, 0)
-		for tag, items := range itemsByTag {
+		tags := make([]viewmodel.Tag, 0, len(itemsByTag))
+		for _, tag := range getTagNamesByFrequency(itemsByTag) {
+
+			var children []viewmodel.Model
+			for _, item := range itemsByTag[tag] {
+				children = append(children, viewmodel.Model{
+					Base: getBaseModel(rootItem, item, orchestrator.config),
+				})
+			}
 
 			// create view model
 			tagModel := viewmodel.Tag{
 				Name:     tag,
-				Anchor:   url.QueryEscape(tag),
-				Route:    orchestrator.tagPather().Path(url.QueryEscape(tag)),
-				Children: items,
+				Anchor:   url.PathEscape(tag),
+				Route:    orchestrator.tagPather().Path(url.PathEscape(tag)),
+				Children: children,
 			}
 
 			// append to list
 			tags = append(tags, tagModel)
 		}
 
-		// sort the tags
-		viewmodel.SortTagBy(tagsByName).Sort(tags)
-
 		orchestrator.tags = tags
 	}
 
@@ -96,6 +91,18 @@ func (orchestrator *TagsOrchestrator) GetTags() []viewmodel.Tag {
 	return orchestrator.tags
 }
 
+// GetTag returns the tag model with the given name and all items carrying it.
+func (orchestrator *TagsOrchestrator) GetTag(name string) (tag viewmodel.Tag, found bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, tag := range orchestrator.GetTags() {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+
+	return viewmodel.Tag{}, false
+}
+
 // GetTagCloud returns the latest tag cloud viewmodel.
 func (orchestrator *TagsOrchestrator) GetTagCloud() viewmodel.TagCloud {
 
@@ -128,8 +135,8 @@ func (orchestrator *TagsOrchestrator) GetTagCloud() viewmodel.TagCloud {
 			// create a new tag cloud entry
 			tagCloudEntry := viewmodel.TagCloudEntry{
 				Name:             tag.Name,
-				Anchor:           url.QueryEscape(tag.Name),
-				Route:            orchestrator.tagPather().Path(url.QueryEscape(tag.Name)),
+				Anchor:           url.PathEscape(tag.Name),
+				Route:            orchestrator.tagPather().Path(url.PathEscape(tag.Name)),
 				NumberOfChildren: numberItemsPerTag,
 			}
 
@@ -174,13 +181,13 @@ func (orchestrator *TagsOrchestrator) getItemTags(route route.Route) []viewmodel
 		return tags
 	}
 
-	for _, tag := range item.MetaData.Tags {
+	for _, tag := range item.Tags() {
 
 		// create view model
 		tagModel := viewmodel.Tag{
 			Name:   tag,
-			Anchor: url.QueryEscape(tag),
-			Route:  orchestrator.tagPather().Path(url.QueryEscape(tag)),
+			Anchor: url.PathEscape(tag),
+			Route:  orchestrator.tagPather().Path(url.PathEscape(tag)),
 		}
 
 		// append to list
@@ -190,6 +197,108 @@ func (orchestrator *TagsOrchestrator) getItemTags(route route.Route) []viewmodel
 	return tags
 }
 
+// GetTagsOPML returns the OPML outline of all tags (most used tags first) with the items carrying them as child outlines.
+// The URLs are prefixed with the supplied hostname.
+func (orchestrator *TagsOrchestrator) GetTagsOPML(hostname string) viewmodel.TagsOPML {
+
+	rootItem := orchestrator.rootItem()
+	if rootItem == nil {
+		orchestrator.logger.Fatal("No root item found")
+	}
+
+	itemPathProvider := orchestrator.absolutePather(fmt.Sprintf("%s/", hostname))
+	getTagURL := func(tag string) string {
+		return hostname + orchestrator.tagPather().Path(url.PathEscape(tag))
+	}
+
+	return viewmodel.TagsOPML{
+		Title:        fmt.Sprintf("%s - Tags", rootItem.Title),
+		DateModified: time.Now().Format(time.RFC1123Z),
+		Tags:         getTagOutlines(itemPathProvider, getTagURL, orchestrator.getAllItems()),
+	}
+}
+
+// getTagOutlines returns an outline for every tag of the supplied items (ordered by frequency)
+// which contains an outline for every item carrying the tag.
+func getTagOutlines(itemPathProvider paths.Pather, getTagURL func(tag string) string, items []*model.Item) []viewmodel.OPMLOutline {
+
+	itemsByTag := getItemsByTag(items)
+
+	outlines := make([]viewmodel.OPMLOutline, 0, len(itemsByTag))
+	for _, tag := range getTagNamesByFrequency(itemsByTag) {
+
+		tagOutline := viewmodel.OPMLOutline{
+			Text: tag,
+			URL:  getTagURL(tag),
+		}
+
+		for _, item := range itemsByTag[tag] {
+			tagOutline.Children = append(tagOutline.Children, viewmodel.OPMLOutline{
+				Text: item.Title,
+				URL:  itemPathProvider.Path(item.Route().Value()),
+			})
+		}
+
+		outlines = append(outlines, tagOutline)
+	}
+
+	return outlines
+}
+
+// getItemsByTag returns a map of all tags of the supplied items (except for virtual items and drafts)
+// and the items carrying each tag.
+func getItemsByTag(items []*model.Item) map[string][]*model.Item {
+	itemsByTag := make(map[string][]*model.Item)
+	for _, item := range items {
+
+		// skip virtual items and drafts
+		if item.IsVirtual() || item.IsDraft() {
+			continue
+		}
+
+		for _, tag := range item.Tags() {
+			itemsByTag[tag] = append(itemsByTag[tag], item)
+		}
+	}
+
+	return itemsByTag
+}
+
+// getTagNamesByFrequency returns the tags of the supplied map ordered by the number of items (descending).
+// Tags with the same number of items are ordered by name.
+func getTagNamesByFrequency(itemsByTag map[string][]*model.Item) []string {
+	tags := make([]string, 0, len(itemsByTag))
+	for tag := range itemsByTag {
+		tags = append(tags, tag)
+	}
+
+	sort.Sort(tagsByFrequency{tags, itemsByTag})
+	return tags
+}
+
+// tagsByFrequency sorts tag names by the number of items (descending) and by name.
+type tagsByFrequency struct {
+	tags       []string
+	itemsByTag map[string][]*model.Item
+}
+
+func (sorter tagsByFrequency) Len() int {
+	return len(sorter.tags)
+}
+
+func (sorter tagsByFrequency) Swap(i, j int) {
+	sorter.tags[i], sorter.tags[j] = sorter.tags[j], sorter.tags[i]
+}
+
+func (sorter tagsByFrequency) Less(i, j int) bool {
+	count1, count2 := len(sorter.itemsByTag[sorter.tags[i]]), len(sorter.itemsByTag[sorter.tags[j]])
+	if count1 != count2 {
+		return count1 > count2
+	}
+
+	return sorter.tags[i] < sorter.tags[j]
+}
+
 func getTagCloudEntryLevel(numberOfChildren, minNumberOfChildren, maxNumberOfChildren, levelCount int) int {
 
 	// check the number of children for negative numbers
@@ -222,11 +331,6 @@ func getTagCloudEntryLevel(numberOfChildren, minNumberOfChildren, maxNumberOfChi
 	return level
 }
 
-// sort tags by nametag
-func tagsByName(tag1, tag2 viewmodel.Tag) bool {
-	return tag1.Name < tag2.Name
-}
-
 // sort tag cloud entries by name
 func tagCloudEntriesByName(tagCloudEntry1, tagCloudEntry2 viewmodel.TagCloudEntry) bool {
 	return tagCloudEntry1.Name < tagCloudEntry2.Name
@@ -0,0 +1,101 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+)
+
+func newTestTaggedItem(itemRoute, title, tags string) *model.Item {
+	item := model.NewItem(route.NewFromRequest(itemRoute), nil, dataaccess.TypePhysical)
+	item.Title = title
+	if tags != "" {
+		item.MetaData.AddBlock("tags", tags)
+	}
+
+	return item
+}
+
+func Test_getItemsByTag_TagsAreCountedAndOrderedByFrequency(t *testing.T) {
+	// arrange
+	draft := newTestTaggedItem("documents/draft", "Draft", "web, draft")
+	draft.MetaData.AddBlock("draft", "true")
+
+	items := []*model.Item{
+		newTestTaggedItem("", "Home", ""),
+		newTestTaggedItem("documents/go", "Go", "go, web"),
+		newTestTaggedItem("documents/markdown", "Markdown", "markdown, Web"),
+		newTestTaggedItem("documents/allmark", "Allmark", "go, web, markdown"),
+		newTestTaggedItem("documents/single", "Single", "single"),
+		draft,
+	}
+
+	// act
+	itemsByTag := getItemsByTag(items)
+	tags := getTagNamesByFrequency(itemsByTag)
+
+	// assert
+	expectedCounts := map[string]int{"web": 3, "go": 2, "markdown": 2, "single": 1}
+	if len(itemsByTag) != len(expectedCounts) {
+		t.Errorf("There should be %d tags but there were %d: %q", len(expectedCounts), len(itemsByTag), tags)
+	}
+
+	for tag, expectedCount := range expectedCounts {
+		if count := len(itemsByTag[tag]); count != expectedCount {
+			t.Errorf("The tag %q should have %d items but had %d.", tag, expectedCount, count)
+		}
+	}
+
+	if result := strings.Join(tags, "|"); result != "web|go|markdown|single" {
+		t.Errorf("The tags should be ordered as %q but were ordered as %q.", "web|go|markdown|single", result)
+	}
+}
+
+func Test_getTagOutlines_TagsContainTheItemsCarryingThem(t *testing.T) {
+	// arrange
+	draft := newTestTaggedItem("documents/draft", "Draft", "draft")
+	draft.MetaData.AddBlock("draft", "true")
+
+	items := []*model.Item{
+		newTestTaggedItem("", "Home", ""),
+		newTestTaggedItem("documents/go", "Go", "go, web"),
+		newTestTaggedItem("documents/allmark", "Allmark", "go, web, markdown"),
+		newTestTaggedItem("documents/untagged", "Untagged", ""),
+		draft,
+	}
+
+	getTagURL := func(tag string) string {
+		return "/tags/" + tag
+	}
+
+	// act
+	outlines := getTagOutlines(testPather{}, getTagURL, items)
+
+	// assert
+	var result []string
+	for _, tagOutline := range outlines {
+		var children []string
+		for _, itemOutline := range tagOutline.Children {
+			children = append(children, itemOutline.Text+" "+itemOutline.URL)
+		}
+
+		result = append(result, tagOutline.Text+" "+tagOutline.URL+" ["+strings.Join(children, ", ")+"]")
+	}
+
+	expected := []string{
+		"go /tags/go [Go /documents/go, Allmark /documents/allmark]",
+		"web /tags/web [Go /documents/go, Allmark /documents/allmark]",
+		"markdown /tags/markdown [Allmark /documents/allmark]",
+	}
+
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("The outlines should be\n%s\nbut were\n%s", strings.Join(expected, "\n"), strings.Join(result, "\n"))
+	}
+}
@@ -5,6 +5,7 @@
 package orchestrator
 
 import (
+	"github.com/andreaskoch/allmark/model"
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 	"strings"
 )
@@ -23,6 +24,11 @@ func (orchestrator *TitlesOrchestrator) GetTitles() []viewmodel.Title {
 	titleModels := make([]viewmodel.Title, 0)
 	for _, item := range orchestrator.getAllItems() {
 
+		// comments are displayed with their item
+		if item.Type == model.TypeComment {
+			continue
+		}
+
 		titleModels = append(titleModels, viewmodel.Title{
 			Value:  item.Title,
 			Tokens: strings.Split(item.Title, " "),
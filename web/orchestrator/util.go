@@ -6,6 +6,7 @@ package orchestrator
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,6 +16,17 @@ import (
 	"github.com/andreaskoch/allmark/web/view/viewmodel"
 )
 
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// getPlainText removes all tags from the supplied HTML and collapses the whitespace.
+func getPlainText(html string) string {
+	text := htmlTagPattern.ReplaceAllString(html, " ")
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+}
+
 func getBaseModel(root, item *model.Item, config config.Config) viewmodel.Base {
 
 	baseModel := viewmodel.Base{
@@ -99,16 +111,16 @@ func GetTypedItemURL(route route.Route, urlType string) string {
 }
 
 // sort the models by date and name
-func sortBaseModelsByDate(model1, model2 viewmodel.Base) bool {
+func sortItemsByDate(model1, model2 *model.Item) bool {
 
-	return model1.CreationDate > model2.CreationDate
+	return model1.MetaData.CreationDate.After(model2.MetaData.CreationDate)
 
 }
 
-// sort the models by date and name
-func sortItemsByDate(model1, model2 *model.Item) bool {
+// sort the models by route
+func sortItemsByRoute(model1, model2 *model.Item) bool {
 
-	return model1.MetaData.CreationDate.After(model2.MetaData.CreationDate)
+	return model1.Route().Value() < model2.Route().Value()
 
 }
 
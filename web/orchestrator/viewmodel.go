@@ -5,6 +5,7 @@
 package orchestrator
 
 import (
+	"strings"
 	"time"
 
 	"github.com/andreaskoch/allmark/common/paths"
@@ -40,69 +41,23 @@ func (orchestrator *ViewModelOrchestrator) GetFullViewModel(itemRoute route.Rout
 			return viewModel, true
 		}
 
+		// the cache is built asynchronously; create the missing viewmodel directly
+		orchestrator.updateFullViewModel(itemRoute)
+		if viewModel, exists := orchestrator.fullViewmodelsByRoute.Get(itemRoute.String()); exists {
+			viewModel.Content = orchestrator.getHTMLFromRoute(orchestrator.relativePather(itemRoute), itemRoute)
+			return viewModel, true
+		}
+
 		return viewmodel.Model{}, false
 	}
 
 	// initialize the cache
 	orchestrator.fullViewmodelsByRoute = newViewmodelCache()
 
-	// updateViewModel update the viewmodel cache for the given route.
-	updateViewModel := func(route route.Route) {
-
-		// get the requested item
-		item := orchestrator.getItem(route)
-		if item == nil {
-			return
-		}
-
-		// get the base view model
-		viewModel, found := orchestrator.getViewModel(route)
-		if !found {
-			return
-		}
-
-		// navigation
-		viewModel.ToplevelNavigation = orchestrator.navigationOrchestrator.GetToplevelNavigation()
-		viewModel.BreadcrumbNavigation = orchestrator.navigationOrchestrator.GetBreadcrumbNavigation(route)
-		viewModel.ItemNavigation = orchestrator.navigationOrchestrator.GetItemNavigation(route)
-
-		// children
-		viewModel.Children = orchestrator.getChildModels(route)
-
-		// tags
-		viewModel.Tags = orchestrator.tagOrchestrator.getItemTags(route)
-
-		// Geo Coordinates
-		viewModel.GeoLocation = getGeoLocation(item)
-
-		// Analytics Settings
-		viewModel.Analytics = orchestrator.getAnalyticsSettings()
-
-		// Hash / ETag
-		viewModel.Hash = item.Hash
-
-		// special viewmodel attributes
-		isRepositoryItem := item.Type == model.TypeRepository
-		if isRepositoryItem {
-
-			// tag cloud
-			repositoryIsNotEmpty := orchestrator.index().Size() >= 5 // don't bother to create a tag cloud if there aren't enough documents
-			if repositoryIsNotEmpty {
-
-				tagCloud := orchestrator.tagOrchestrator.GetTagCloud()
-				viewModel.TagCloud = tagCloud
-
-			}
-
-		}
-
-		orchestrator.fullViewmodelsByRoute.Set(route.String(), viewModel)
-	}
-
 	// buildCache writes the cache for all routes
 	buildCache := func(route route.Route) {
 		for _, childRoute := range orchestrator.repository.Routes() {
-			updateViewModel(childRoute)
+			orchestrator.updateFullViewModel(childRoute)
 		}
 	}
 
@@ -118,19 +73,97 @@ func (orchestrator *ViewModelOrchestrator) GetFullViewModel(itemRoute route.Rout
 	}
 
 	// write the cache for the requested route directly
-	updateViewModel(itemRoute)
+	orchestrator.updateFullViewModel(itemRoute)
 
 	// write cache for all other routes async
 	go buildCache(route.New())
 
 	// register update callbacks
-	orchestrator.registerUpdateCallback("update full viewmodel", UpdateTypeNew, updateViewModel)
-	orchestrator.registerUpdateCallback("update full viewmodel", UpdateTypeModified, updateViewModel)
+	orchestrator.registerUpdateCallback("update full viewmodel", UpdateTypeNew, orchestrator.updateFullViewModel)
+	orchestrator.registerUpdateCallback("update full viewmodel", UpdateTypeModified, orchestrator.updateFullViewModel)
 	orchestrator.registerUpdateCallback("update full viewmodel", UpdateTypeDeleted, deleteRouteFromCache)
 
+	// the view models of items which include the changed item contain its content
+	updateIncludingViewModels := func(route route.Route) {
+		for _, includingRoute := range getIncludingRoutes(route, orchestrator.getAllItems(), orchestrator.getItem) {
+			orchestrator.updateFullViewModel(includingRoute)
+		}
+	}
+
+	orchestrator.registerUpdateCallback("update including viewmodels", UpdateTypeNew, updateIncludingViewModels)
+	orchestrator.registerUpdateCallback("update including viewmodels", UpdateTypeModified, updateIncludingViewModels)
+	orchestrator.registerUpdateCallback("update including viewmodels", UpdateTypeDeleted, updateIncludingViewModels)
+
 	return orchestrator.GetFullViewModel(itemRoute)
 }
 
+// updateFullViewModel updates the full viewmodel cache for the given route.
+func (orchestrator *ViewModelOrchestrator) updateFullViewModel(route route.Route) {
+
+	// get the requested item
+	item := orchestrator.getItem(route)
+	if item == nil {
+		return
+	}
+
+	// get the base view model
+	viewModel, found := orchestrator.getViewModel(route)
+	if !found {
+		return
+	}
+
+	// navigation
+	viewModel.ToplevelNavigation = orchestrator.navigationOrchestrator.GetToplevelNavigation()
+	viewModel.BreadcrumbNavigation = orchestrator.navigationOrchestrator.GetBreadcrumbNavigation(route)
+	viewModel.ItemNavigation = orchestrator.navigationOrchestrator.GetItemNavigation(route)
+
+	// children
+	viewModel.Children = orchestrator.getChildModels(route)
+
+	// tags
+	viewModel.Tags = orchestrator.tagOrchestrator.getItemTags(route)
+
+	// related items
+	viewModel.Related = orchestrator.getRelatedModels(item)
+
+	// comments
+	viewModel.Comments = orchestrator.getCommentModels(route)
+
+	// Geo Coordinates
+	viewModel.GeoLocation = getGeoLocation(item)
+
+	// Analytics Settings
+	viewModel.Analytics = orchestrator.getAnalyticsSettings()
+
+	// Presentation Settings
+	viewModel.Presentation = orchestrator.getPresentationSettings()
+
+	// Math Settings
+	viewModel.Math = orchestrator.getMathSettings()
+
+	// Hash / ETag: include the children and files because they are part of the rendered page
+	viewModel.Hash = item.GetContentHash(func(parent *model.Item) []*model.Item {
+		return orchestrator.getChildren(parent.Route())
+	})
+
+	// special viewmodel attributes
+	isRepositoryItem := item.Type == model.TypeRepository
+	if isRepositoryItem {
+
+		// tag cloud
+		repositoryIsNotEmpty := orchestrator.index().Size() >= 5 // don't bother to create a tag cloud if there aren't enough documents
+		if repositoryIsNotEmpty {
+
+			tagCloud := orchestrator.tagOrchestrator.GetTagCloud()
+			viewModel.TagCloud = tagCloud
+
+		}
+
+	}
+
+	orchestrator.fullViewmodelsByRoute.Set(route.String(), viewModel)
+}
+
 func (orchestrator *ViewModelOrchestrator) GetViewModel(itemRoute route.Route) (viewModel viewmodel.Model, found bool) {
 
 	vm, found := orchestrator.getViewModel(itemRoute)
@@ -282,6 +315,7 @@ func (orchestrator *ViewModelOrchestrator) getViewModel(itemRoute route.Route) (
 		}
 
 		root := orchestrator.rootItem()
+		description := orchestrator.getExcerpt(item, socialDescriptionLength)
 
 		viewModel := viewmodel.Model{
 			Base:             getBaseModel(root, item, orchestrator.config),
@@ -291,14 +325,26 @@ func (orchestrator *ViewModelOrchestrator) getViewModel(itemRoute route.Route) (
 			Author:           orchestrator.getAuthorInformation(item.MetaData.Author),
 			Files:            orchestrator.fileOrchestrator.GetFiles(route),
 			Images:           orchestrator.fileOrchestrator.GetImages(route),
+			Social:           getSocialMetaData(item, description, orchestrator.config.Web.SocialImage),
 			IsRepositoryItem: true,
 		}
 
+		viewModel.StructuredData = getStructuredData(item, description, viewModel.Author, orchestrator.config.Web.BaseURL)
+
+		if item.Type == model.TypeMessage {
+			viewModel.Permalink = getMessagePermalink(item)
+		}
+
 		// add docx url if docx conversion is enabled
 		if orchestrator.config.Conversion.DOCX.IsEnabled() {
 			viewModel.DOCXURL = GetTypedItemURL(route, "docx")
 		}
 
+		// add pdf url for presentations if pdf conversion is enabled
+		if item.Type == model.TypePresentation && orchestrator.config.Conversion.PDF.IsEnabled() {
+			viewModel.PDFURL = GetTypedItemURL(route, "pdf")
+		}
+
 		orchestrator.viewmodelsByRoute.Set(route.String(), viewModel)
 	}
 
@@ -335,17 +381,60 @@ func (orchestrator *ViewModelOrchestrator) getChildModels(itemRoute route.Route)
 	childModels := make([]viewmodel.Base, 0)
 	childItems := orchestrator.getChildren(itemRoute)
 	for _, childItem := range childItems {
+
+		// comments are displayed in the comment thread
+		if childItem.Type == model.TypeComment {
+			continue
+		}
+
 		baseModel := getBaseModel(rootItem, childItem, orchestrator.config)
 		baseModel.Route = orchestrator.relativePather(itemRoute).Path(baseModel.Route)
 		childModels = append(childModels, baseModel)
 	}
 
-	// sort the models
-	viewmodel.SortBaseModelBy(sortBaseModelsByDate).Sort(childModels)
-
 	return childModels
 }
 
+// getCommentModels returns the threaded comments on the item with the given route.
+func (orchestrator *ViewModelOrchestrator) getCommentModels(itemRoute route.Route) []viewmodel.Comment {
+	thread := getCommentThread(orchestrator.logger, itemRoute, orchestrator.getComments(itemRoute))
+	return orchestrator.getCommentModelsFromThread(orchestrator.relativePather(itemRoute), thread, 0)
+}
+
+func (orchestrator *ViewModelOrchestrator) getCommentModelsFromThread(pathProvider paths.Pather, nodes []*commentNode, level int) []viewmodel.Comment {
+	commentModels := make([]viewmodel.Comment, 0, len(nodes))
+	for _, node := range nodes {
+		commentModels = append(commentModels, viewmodel.Comment{
+			Route:   pathProvider.Path(node.item.Route().Value()),
+			Author:  node.item.MetaData.Author,
+			Date:    getFormattedDate(node.item.Date()),
+			Content: orchestrator.getHTMLFromItem(pathProvider, node.item),
+			Level:   level,
+			Replies: orchestrator.getCommentModelsFromThread(pathProvider, node.replies, level+1),
+		})
+	}
+
+	return commentModels
+}
+
+// getRelatedModels returns the base models for the items which share the most tags with the supplied item.
+func (orchestrator *ViewModelOrchestrator) getRelatedModels(item *model.Item) []viewmodel.Base {
+
+	rootItem := orchestrator.rootItem()
+	if rootItem == nil {
+		orchestrator.logger.Fatal("No root item found")
+	}
+
+	relatedModels := make([]viewmodel.Base, 0)
+	for _, relatedItem := range getRelatedItems(item, orchestrator.getAllItems(), relatedItemsLimit) {
+		baseModel := getBaseModel(rootItem, relatedItem, orchestrator.config)
+		baseModel.Route = orchestrator.relativePather(item.Route()).Path(baseModel.Route)
+		relatedModels = append(relatedModels, baseModel)
+	}
+
+	return relatedModels
+}
+
 // getHTMLFromRoute returns the converted HTML code for the item with the given route.
 func (orchestrator *ViewModelOrchestrator) getHTMLFromRoute(pathProvider paths.Pather, route route.Route) string {
 	item := orchestrator.getItem(route)
@@ -362,11 +451,22 @@ func (orchestrator *ViewModelOrchestrator) getHTMLFromItem(pathProvider paths.Pa
 		return ""
 	}
 
-	convertedContent, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, pathProvider, item)
+	convertedContent, err := orchestrator.converter.Convert(orchestrator.getItemByAlias, orchestrator.getItemsByTitle, orchestrator.getItem, pathProvider, item)
 	if err != nil {
 		orchestrator.logger.Warn("Cannot convert content for route %q. Error: %s.", item.Route(), err.Error())
 		return "<!-- Conversion Error -->"
 	}
 
+	// collections list their children
+	if item.Type == model.TypeCollection {
+		getExcerpt := func(child *model.Item) string {
+			return orchestrator.getExcerpt(child, collectionExcerptLength)
+		}
+
+		listing := getCollectionListing(orchestrator.relativePather(item.Route()), orchestrator.getChildren(item.Route()), getExcerpt)
+		bodyIsEmpty := strings.TrimSpace(item.Content) == ""
+		convertedContent = insertCollectionListing(convertedContent, listing, bodyIsEmpty)
+	}
+
 	return convertedContent
 }
@@ -23,19 +23,26 @@ func (orchestrator *XmlSitemapOrchestrator) GetSitemapEntires(hostname string) [
 		orchestrator.logger.Fatal("No root item found")
 	}
 
+	addressPrefix := fmt.Sprintf("%s/", hostname)
+	pathProvider := orchestrator.absolutePather(addressPrefix)
+
+	return getXMLSitemapEntries(pathProvider, orchestrator.getAllItems())
+}
+
+// getXMLSitemapEntries returns the sitemap entries for all published items (no virtual items, no drafts and no comments).
+func getXMLSitemapEntries(pathProvider paths.Pather, items []*model.Item) []viewmodel.XmlSitemapEntry {
+
 	zeroTime := time.Time{}
 
 	children := make([]viewmodel.XmlSitemapEntry, 0)
-	for _, item := range orchestrator.getAllItems() {
+	for _, item := range items {
 
-		// skip virtual items
-		if item.IsVirtual() {
+		// skip virtual items, drafts and comments (comments are displayed with their item)
+		if item.IsVirtual() || item.IsDraft() || item.Type == model.TypeComment {
 			continue
 		}
 
 		// item location
-		addressPrefix := fmt.Sprintf("%s/", hostname)
-		pathProvider := orchestrator.absolutePather(addressPrefix)
 		location := pathProvider.Path(item.Route().Value())
 
 		// last modified date
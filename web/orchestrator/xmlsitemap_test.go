@@ -0,0 +1,101 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/route"
+	"github.com/andreaskoch/allmark/dataaccess"
+	"github.com/andreaskoch/allmark/model"
+	"github.com/andreaskoch/allmark/web/view/templates"
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// xmlSitemap is the structure of a sitemap according to the sitemaps.org schema.
+type xmlSitemap struct {
+	XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+}
+
+func Test_getXMLSitemapEntries_PublishedItemsAreRenderedAsValidSitemap(t *testing.T) {
+	// arrange
+	modified := time.Date(2015, time.March, 1, 21, 13, 0, 0, time.UTC)
+
+	published := model.NewItem(route.NewFromRequest("documents/published"), nil, dataaccess.TypePhysical)
+	published.MetaData.LastModifiedDate = modified
+
+	draft := model.NewItem(route.NewFromRequest("documents/draft"), nil, dataaccess.TypePhysical)
+	draft.MetaData.AddBlock("draft", "true")
+	virtual := model.NewItem(route.NewFromRequest("documents"), nil, dataaccess.TypeVirtual)
+
+	pathProvider := prefixPather{"http://example.com/"}
+	entries := getXMLSitemapEntries(pathProvider, []*model.Item{published, draft, virtual})
+
+	templateProvider := templates.NewProvider("")
+	sitemapTemplate, _ := templateProvider.GetXMLSitemapTemplate("http://example.com")
+
+	// act
+	buffer := new(bytes.Buffer)
+	err := sitemapTemplate.Execute(buffer, viewmodel.XMLSitemap{Entries: entries})
+
+	// assert
+	if err != nil {
+		t.Fatalf("The sitemap could not be rendered. Error: %s", err)
+	}
+
+	var sitemap xmlSitemap
+	if err := xml.Unmarshal(buffer.Bytes(), &sitemap); err != nil {
+		t.Fatalf("The sitemap is not a valid sitemap. Error: %s\n%s", err, buffer.String())
+	}
+
+	if len(sitemap.URLs) != 1 {
+		t.Fatalf("The sitemap should contain exactly one URL but contained %d:\n%s", len(sitemap.URLs), buffer.String())
+	}
+
+	if sitemap.URLs[0].Loc != "http://example.com/documents/published" {
+		t.Errorf("The location should be %q but was %q.", "http://example.com/documents/published", sitemap.URLs[0].Loc)
+	}
+
+	if sitemap.URLs[0].LastMod != "2015-03-01" {
+		t.Errorf("The last modified date should be %q but was %q.", "2015-03-01", sitemap.URLs[0].LastMod)
+	}
+}
+
+// A prefixPather prefixes the item paths with the given prefix.
+type prefixPather struct {
+	prefix string
+}
+
+func (pather prefixPather) Path(itemPath string) string {
+	return pather.prefix + itemPath
+}
+
+func (pather prefixPather) Base() route.Route {
+	return route.New()
+}
+
+func Test_getXMLSitemapEntries_Comments_AreSkipped(t *testing.T) {
+	// arrange
+	document := model.NewItem(route.NewFromRequest("documents/sample"), nil, dataaccess.TypePhysical)
+	document.Type = model.TypeDocument
+
+	comment := model.NewItem(route.NewFromRequest("documents/sample/comment-20150801-153000"), nil, dataaccess.TypePhysical)
+	comment.Type = model.TypeComment
+
+	// act
+	entries := getXMLSitemapEntries(prefixPather{"http://example.com/"}, []*model.Item{document, comment})
+
+	// assert
+	if len(entries) != 1 || entries[0].Loc != "http://example.com/documents/sample" {
+		t.Errorf("The sitemap should only contain the document but contained %v.", entries)
+	}
+}
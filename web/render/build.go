@@ -0,0 +1,203 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+)
+
+// A Site contains all files of a repository which are written by a build.
+type Site struct {
+	// ItemTypes contains the number of items by item type (e.g. "document" -> 12).
+	ItemTypes map[string]int
+
+	// ContentHashes, RequestPaths and Redirects describe the rendered items (see Render).
+	ContentHashes map[string]string
+	RequestPaths  map[string]string
+	Redirects     map[string]string
+
+	// Feeds (including the sitemap and the combined page) and Assets contain the request paths of the files which are
+	// written on every build by their relative path (e.g. "feed.rss" -> "/feed.rss").
+	Feeds  map[string]string
+	Assets map[string]string
+}
+
+// A Report contains the statistics and timings of a build.
+type Report struct {
+	// Items contains the number of items by item type.
+	Items map[string]int `json:"items"`
+
+	Rendered     int   `json:"rendered"`
+	Skipped      int   `json:"skipped"`
+	Deleted      int   `json:"deleted"`
+	Redirects    int   `json:"redirects"`
+	Feeds        int   `json:"feeds"`
+	Assets       int   `json:"assets"`
+	BytesWritten int64 `json:"bytesWritten"`
+
+	Timings ReportTimings `json:"timings"`
+
+	// Plan lists the item files which have been written, skipped or deleted.
+	Plan Plan `json:"plan"`
+}
+
+// ReportTimings contains the wall-clock duration of each phase of a build in milliseconds.
+type ReportTimings struct {
+	Index  int64 `json:"index"`
+	Render int64 `json:"render"`
+	Feeds  int64 `json:"feeds"`
+	Assets int64 `json:"assets"`
+	Total  int64 `json:"total"`
+}
+
+// Build renders the site returned by the index function into the target folder and returns the report of the build.
+// Changed items are rendered incrementally (see Render); feeds and assets are written on every build.
+func Build(handler http.Handler, domainName string, index func() (Site, error), targetFolder, manifestFilePath string) (Report, error) {
+
+	buildStart := time.Now()
+	report := Report{}
+
+	// index
+	phaseStart := time.Now()
+	site, err := index()
+	if err != nil {
+		return report, err
+	}
+
+	report.Items = site.ItemTypes
+	report.Timings.Index = getMilliseconds(time.Since(phaseStart))
+
+	// render
+	phaseStart = time.Now()
+	plan, err := Render(handler, domainName, site.ContentHashes, site.RequestPaths, site.Redirects, targetFolder, manifestFilePath)
+	report.Plan = plan
+	if err != nil {
+		return report, err
+	}
+
+	for _, relativePath := range plan.Write {
+		if _, isRedirect := site.Redirects[relativePath]; isRedirect {
+			report.Redirects++
+			continue
+		}
+
+		report.Rendered++
+	}
+
+	report.Skipped = plan.Summary.Skip
+	report.Deleted = plan.Summary.Delete
+	report.BytesWritten += getFileSizes(targetFolder, plan.Write)
+	report.Timings.Render = getMilliseconds(time.Since(phaseStart))
+
+	// feeds (the links of the feeds and the sitemap point to the permalinks of the items)
+	phaseStart = time.Now()
+	feeds, err := renderFiles(handler, domainName, targetFolder, site.Feeds, getPermalinks(site.RequestPaths))
+	if err != nil {
+		return report, err
+	}
+
+	report.Feeds = len(feeds)
+	report.BytesWritten += getFileSizes(targetFolder, feeds)
+	report.Timings.Feeds = getMilliseconds(time.Since(phaseStart))
+
+	// assets
+	phaseStart = time.Now()
+	assets, err := renderFiles(handler, domainName, targetFolder, site.Assets, nil)
+	if err != nil {
+		return report, err
+	}
+
+	report.Assets = len(assets)
+	report.BytesWritten += getFileSizes(targetFolder, assets)
+	report.Timings.Assets = getMilliseconds(time.Since(phaseStart))
+
+	report.Timings.Total = getMilliseconds(time.Since(buildStart))
+
+	return report, nil
+}
+
+// AddDependency returns the supplied content hashes combined with the hash of the given files
+// every rendered item depends on (e.g. the fingerprinted theme files which are referenced by every page),
+// so all items are rendered again when one of these files changes.
+func AddDependency(contentHashes map[string]string, files map[string]string) map[string]string {
+	relativePaths := make([]string, 0, len(files))
+	for relativePath := range files {
+		relativePaths = append(relativePaths, relativePath)
+	}
+
+	sort.Strings(relativePaths)
+	dependencyHash := hashutil.FromString(strings.Join(relativePaths, "\n"))
+
+	hashes := make(map[string]string, len(contentHashes))
+	for relativePath, hash := range contentHashes {
+		hashes[relativePath] = hashutil.FromString(hash + dependencyHash)
+	}
+
+	return hashes
+}
+
+// renderFiles requests the given files (relative path -> request path) from the handler,
+// replaces the links to items with a permalink, writes them to the target folder and returns their relative paths in alphabetical order.
+func renderFiles(handler http.Handler, domainName, targetFolder string, files map[string]string, permalinks map[string]string) ([]string, error) {
+	relativePaths := make([]string, 0, len(files))
+	for relativePath := range files {
+		relativePaths = append(relativePaths, relativePath)
+	}
+
+	sort.Strings(relativePaths)
+
+	for _, relativePath := range relativePaths {
+		if err := renderFile(handler, domainName, targetFolder, relativePath, files[relativePath], permalinks); err != nil {
+			return nil, err
+		}
+	}
+
+	return relativePaths, nil
+}
+
+// getFileSizes returns the total size of the given files in the target folder.
+func getFileSizes(targetFolder string, relativePaths []string) int64 {
+	var size int64
+	for _, relativePath := range relativePaths {
+		if info, err := os.Stat(filepath.Join(targetFolder, filepath.FromSlash(relativePath))); err == nil {
+			size += info.Size()
+		}
+	}
+
+	return size
+}
+
+func getMilliseconds(duration time.Duration) int64 {
+	return int64(duration / time.Millisecond)
+}
+
+// JSON returns the report as indented JSON.
+func (report Report) JSON() string {
+	bytes, _ := json.MarshalIndent(report, "", "\t")
+	return string(bytes)
+}
+
+// Save writes the report to the given file.
+func (report Report) Save(reportFilePath string) error {
+	if err := os.MkdirAll(filepath.Dir(reportFilePath), 0700); err != nil {
+		return fmt.Errorf("Cannot create the folder for the render report %q. Error: %s", reportFilePath, err.Error())
+	}
+
+	if err := ioutil.WriteFile(reportFilePath, []byte(report.JSON()), 0600); err != nil {
+		return fmt.Errorf("Cannot write the render report %q. Error: %s", reportFilePath, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// getTestSite returns a site with three items, one redirect, two feeds and three assets.
+func getTestSite() Site {
+	return Site{
+		ItemTypes: map[string]int{
+			"repository": 1,
+			"document":   2,
+		},
+		ContentHashes: map[string]string{
+			"index.html":                  "root-1",
+			"documents/first/index.html":  "first-1",
+			"documents/second/index.html": "second-1",
+		},
+		Redirects: map[string]string{
+			"documents/old/index.html": "/documents/first/",
+		},
+		Feeds: map[string]string{
+			"feed.rss":  "/feed.rss",
+			"feed.atom": "/feed.atom",
+		},
+		Assets: map[string]string{
+			"theme/screen.css": "/theme/screen.css",
+			"theme/print.css":  "/theme/print.css",
+			"theme/site.js":    "/theme/site.js",
+		},
+	}
+}
+
+func Test_Build_SyntheticSite_ReportCountsMatch(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	var requests []string
+	index := func() (Site, error) {
+		return getTestSite(), nil
+	}
+
+	// act
+	report, err := Build(newTestHandler(&requests), "localhost", index, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Build should not return an error but returned %s.", err)
+	}
+
+	if report.Items["document"] != 2 || report.Items["repository"] != 1 {
+		t.Errorf("The report should contain 2 documents and 1 repository but contained %v.", report.Items)
+	}
+
+	counts := map[string][]int{
+		"rendered":  {3, report.Rendered},
+		"skipped":   {0, report.Skipped},
+		"deleted":   {0, report.Deleted},
+		"redirects": {1, report.Redirects},
+		"feeds":     {2, report.Feeds},
+		"assets":    {3, report.Assets},
+	}
+
+	for name, count := range counts {
+		if count[0] != count[1] {
+			t.Errorf("The number of %s files should be %d but was %d.", name, count[0], count[1])
+		}
+	}
+
+	// every file except for the redirect contains "<html>" + request path + "</html>"
+	var expectedBytes int64
+	for _, requestPath := range []string{"/", "/documents/first", "/documents/second", "/feed.rss", "/feed.atom", "/theme/screen.css", "/theme/print.css", "/theme/site.js"} {
+		expectedBytes += int64(len("<html></html>") + len(requestPath))
+	}
+
+	expectedBytes += int64(len(getRedirectPage("/documents/first/")))
+	if report.BytesWritten != expectedBytes {
+		t.Errorf("The report should contain %d written bytes but contained %d.", expectedBytes, report.BytesWritten)
+	}
+}
+
+func Test_Build_UnchangedSite_ItemsAreSkippedAndFeedsAndAssetsAreWritten(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	var requests []string
+	index := func() (Site, error) {
+		return getTestSite(), nil
+	}
+
+	Build(newTestHandler(&requests), "localhost", index, targetFolder, manifestFilePath)
+	requests = nil
+
+	// act
+	report, err := Build(newTestHandler(&requests), "localhost", index, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Build should not return an error but returned %s.", err)
+	}
+
+	if report.Rendered != 0 || report.Redirects != 0 || report.Skipped != 4 {
+		t.Errorf("All items and redirects should have been skipped but the report was %s.", report.JSON())
+	}
+
+	if report.Feeds != 2 || report.Assets != 3 || len(requests) != 5 {
+		t.Errorf("The feeds and assets should have been written again but %v were requested.", requests)
+	}
+}
+
+func Test_Build_Permalinks_FeedsPointToThePermalinksAndAssetsAreNotChanged(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<url><loc>http://localhost/documents/first</loc></url><link>http://localhost/documents/first?utm=rss</link>`)
+	})
+
+	site := getTestSite()
+	site.RequestPaths = map[string]string{
+		"2015/first/index.html": "/documents/first",
+	}
+
+	index := func() (Site, error) {
+		return site, nil
+	}
+
+	// act
+	_, err := Build(handler, "localhost", index, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Build should not return an error but returned %s.", err)
+	}
+
+	expectedContents := map[string]string{
+		"feed.rss":         `<url><loc>http://localhost/2015/first/</loc></url><link>http://localhost/2015/first/?utm=rss</link>`,
+		"theme/screen.css": `<url><loc>http://localhost/documents/first</loc></url><link>http://localhost/documents/first?utm=rss</link>`,
+	}
+
+	for relativePath, expected := range expectedContents {
+		content, err := ioutil.ReadFile(filepath.Join(targetFolder, filepath.FromSlash(relativePath)))
+		if err != nil {
+			t.Errorf("The file %q should have been written. Error: %s", relativePath, err)
+			continue
+		}
+
+		if string(content) != expected {
+			t.Errorf("The file %q should be\n%s\nbut was\n%s", relativePath, expected, content)
+		}
+	}
+}
+
+func Test_Report_Save_ReportIsWrittenAsJSON(t *testing.T) {
+	// arrange
+	targetFolder, _, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	reportFilePath := filepath.Join(targetFolder, "render.report.json")
+	report := Report{Items: map[string]int{"document": 2}, Rendered: 2, BytesWritten: 1024}
+
+	// act
+	err := report.Save(reportFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Save should not return an error but returned %s.", err)
+	}
+
+	data, _ := ioutil.ReadFile(reportFilePath)
+
+	var savedReport Report
+	if err := json.Unmarshal(data, &savedReport); err != nil {
+		t.Fatalf("The report should be valid JSON. Error: %s", err)
+	}
+
+	if savedReport.Rendered != 2 || savedReport.BytesWritten != 1024 || savedReport.Items["document"] != 2 {
+		t.Errorf("The saved report should match the original report but was %s.", data)
+	}
+}
+
+func Test_AddDependency_DependencyChanges_AllContentHashesChange(t *testing.T) {
+	// arrange
+	contentHashes := map[string]string{"index.html": "1", "documents/index.html": "2"}
+
+	// act
+	first := AddDependency(contentHashes, map[string]string{"theme/screen.aaaa.css": "/theme/screen.aaaa.css"})
+	same := AddDependency(contentHashes, map[string]string{"theme/screen.aaaa.css": "/theme/screen.aaaa.css"})
+	changed := AddDependency(contentHashes, map[string]string{"theme/screen.bbbb.css": "/theme/screen.bbbb.css"})
+
+	// assert
+	for relativePath := range contentHashes {
+		if first[relativePath] != same[relativePath] {
+			t.Errorf("The hash of %q should not change if the dependencies do not change.", relativePath)
+		}
+
+		if first[relativePath] == changed[relativePath] {
+			t.Errorf("The hash of %q should change if the dependencies change.", relativePath)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sourceFileExtensions contains the extensions of the source files which are never exported.
+var sourceFileExtensions = map[string]bool{
+	".md":       true,
+	".markdown": true,
+	".mdown":    true,
+}
+
+// Export renders the complete site returned by the index function into a temporary folder
+// and writes all rendered files as a ZIP archive to the supplied writer.
+// The paths in the archive are relative to the root of the site (e.g. "documents/sample/index.html").
+func Export(writer io.Writer, handler http.Handler, domainName string, index func() (Site, error)) (Report, error) {
+	directory, err := ioutil.TempDir("", "allmark-export")
+	if err != nil {
+		return Report{}, fmt.Errorf("Cannot create the export folder. Error: %s", err.Error())
+	}
+
+	defer os.RemoveAll(directory)
+
+	// without a previous manifest every file is rendered
+	targetFolder := filepath.Join(directory, "site")
+	report, err := Build(handler, domainName, index, targetFolder, filepath.Join(directory, "render.manifest"))
+	if err != nil {
+		return report, err
+	}
+
+	return report, writeZip(writer, targetFolder)
+}
+
+// writeZip writes all files of the supplied folder (except for source files) to a ZIP archive.
+func writeZip(writer io.Writer, folder string) error {
+	archive := zip.NewWriter(writer)
+
+	err := filepath.Walk(folder, func(filePath string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && filePath == folder {
+			return filepath.SkipDir
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || sourceFileExtensions[strings.ToLower(filepath.Ext(filePath))] {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(folder, filePath)
+		if err != nil {
+			return err
+		}
+
+		return addZipEntry(archive, filepath.ToSlash(relativePath), filePath, info)
+	})
+
+	if err != nil {
+		archive.Close()
+		return fmt.Errorf("Cannot create the archive of %q. Error: %s", folder, err.Error())
+	}
+
+	return archive.Close()
+}
+
+// addZipEntry adds the file with the given path to the supplied archive.
+func addZipEntry(archive *zip.Writer, name, filePath string, info os.FileInfo) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+
+	header.Name = name
+	header.Method = zip.Deflate
+
+	entry, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = io.Copy(entry, file)
+	return err
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// getZipEntries returns the names of all entries of the supplied ZIP archive in alphabetical order.
+func getZipEntries(t *testing.T, archive []byte) []string {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("The archive cannot be read. Error: %s", err)
+	}
+
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func Test_Export_SmallSite_ArchiveContainsAllRenderedFiles(t *testing.T) {
+	// arrange
+	var archive bytes.Buffer
+	var requests []string
+	index := func() (Site, error) {
+		site := getTestSite()
+		site.Assets["documents/first/files/photo.jpg"] = "/documents/first/files/photo.jpg"
+		return site, nil
+	}
+
+	expectedEntries := []string{
+		"documents/first/files/photo.jpg",
+		"documents/first/index.html",
+		"documents/old/index.html",
+		"documents/second/index.html",
+		"feed.atom",
+		"feed.rss",
+		"index.html",
+		"theme/print.css",
+		"theme/screen.css",
+		"theme/site.js",
+	}
+
+	// act
+	_, err := Export(&archive, newTestHandler(&requests), "localhost", index)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Export should not return an error but returned %s.", err)
+	}
+
+	if entries := getZipEntries(t, archive.Bytes()); !reflect.DeepEqual(entries, expectedEntries) {
+		t.Errorf("The archive should contain %v but contained %v.", expectedEntries, entries)
+	}
+}
+
+func Test_writeZip_SourceFiles_AreExcluded(t *testing.T) {
+	// arrange
+	folder, err := ioutil.TempDir("", "allmark-export-test")
+	if err != nil {
+		t.Fatalf("The temp folder could not be created. Error: %s", err)
+	}
+
+	defer os.RemoveAll(folder)
+
+	for _, relativePath := range []string{"index.html", "documents/readme.md", "documents/notes.markdown", "documents/index.html"} {
+		filePath := filepath.Join(folder, filepath.FromSlash(relativePath))
+		os.MkdirAll(filepath.Dir(filePath), 0700)
+		ioutil.WriteFile(filePath, []byte(relativePath), 0600)
+	}
+
+	var archive bytes.Buffer
+
+	// act
+	err = writeZip(&archive, folder)
+
+	// assert
+	if err != nil {
+		t.Fatalf("writeZip should not return an error but returned %s.", err)
+	}
+
+	expectedEntries := []string{"documents/index.html", "index.html"}
+	if entries := getZipEntries(t, archive.Bytes()); !reflect.DeepEqual(entries, expectedEntries) {
+		t.Errorf("The archive should contain %v but contained %v.", expectedEntries, entries)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// <base href="*base url*">
+	baseHrefPattern = regexp.MustCompile(`<base[^>]+href="([^"]*)"`)
+
+	// <a href="*link target*">
+	hrefPattern = regexp.MustCompile(`<a\s[^>]*href="([^"]*)"`)
+)
+
+// A BrokenLink is an internal link of a rendered item whose target does not exist.
+type BrokenLink struct {
+	// Source is the route of the item which contains the link (e.g. "/documents/sample").
+	Source string `json:"source"`
+
+	// Target is the path the link points to (e.g. "/documents/missing").
+	Target string `json:"target"`
+}
+
+func (brokenLink BrokenLink) String() string {
+	return fmt.Sprintf("%s -> %s", brokenLink.Source, brokenLink.Target)
+}
+
+// CheckLinks extracts the internal links from all HTML files in the target folder and checks if
+// the given handler can serve their targets (items, files, theme assets and so on) for the given domain name.
+// Links to other domains are not checked. The broken links are returned ordered by source and target.
+func CheckLinks(handler http.Handler, domainName, targetFolder string) ([]BrokenLink, error) {
+
+	targetStatus := make(map[string]bool)
+	brokenLinks := make([]BrokenLink, 0)
+
+	err := filepath.Walk(targetFolder, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || strings.ToLower(filepath.Ext(filePath)) != ".html" {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("Cannot read %q. Error: %s", filePath, err.Error())
+		}
+
+		relativePath, _ := filepath.Rel(targetFolder, filePath)
+		source := "/" + strings.TrimPrefix(path.Dir(filepath.ToSlash(relativePath)), ".")
+
+		for _, target := range getInternalLinks(domainName, source, string(content)) {
+			exists, checked := targetStatus[target]
+			if !checked {
+				exists = targetExists(handler, domainName, target) || renderedFileExists(targetFolder, target)
+				targetStatus[target] = exists
+			}
+
+			if !exists {
+				brokenLinks = append(brokenLinks, BrokenLink{Source: source, Target: target})
+			}
+		}
+
+		return nil
+	})
+
+	sort.Sort(brokenLinksBySource(brokenLinks))
+
+	return brokenLinks, err
+}
+
+// getInternalLinks returns the distinct paths (including the query) of all links in the supplied HTML code
+// which point to the given domain. Relative links are resolved against the base href of the document
+// or else against the source path.
+func getInternalLinks(domainName, source, html string) []string {
+
+	base := getBaseURL(domainName, source, html)
+
+	links := make([]string, 0)
+	distinctLinks := make(map[string]bool)
+	for _, match := range hrefPattern.FindAllStringSubmatch(html, -1) {
+		link, err := url.Parse(strings.TrimSpace(strings.Replace(match[1], "&amp;", "&", -1)))
+		if err != nil {
+			continue
+		}
+
+		target := base.ResolveReference(link)
+		if (target.Scheme != "http" && target.Scheme != "https") || target.Host != domainName {
+			continue
+		}
+
+		targetPath := target.EscapedPath()
+		if targetPath == "" {
+			targetPath = "/"
+		}
+
+		if target.RawQuery != "" {
+			targetPath += "?" + target.RawQuery
+		}
+
+		if distinctLinks[targetPath] {
+			continue
+		}
+
+		distinctLinks[targetPath] = true
+		links = append(links, targetPath)
+	}
+
+	return links
+}
+
+// getBaseURL returns the URL relative links of the supplied HTML code are resolved against:
+// the base href of the document or else the source path.
+func getBaseURL(domainName, source, html string) *url.URL {
+	base := &url.URL{Scheme: "http", Host: domainName, Path: source}
+	if match := baseHrefPattern.FindStringSubmatch(html); match != nil {
+		if baseHref, err := url.Parse(strings.TrimSpace(match[1])); err == nil {
+			base = base.ResolveReference(baseHref)
+		}
+	}
+
+	return base
+}
+
+// targetExists returns true if the handler answers a request for the given target
+// with a success or a redirect status code.
+func targetExists(handler http.Handler, domainName, target string) bool {
+	request := httptest.NewRequest("GET", target, nil)
+	request.Host = domainName
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	return response.Code >= 200 && response.Code < 400
+}
+
+// renderedFileExists returns true if the given target is a file in the target folder
+// or a folder which contains a rendered index file (e.g. an item rendered to its permalink).
+func renderedFileExists(targetFolder, target string) bool {
+	targetPath, err := url.PathUnescape(strings.SplitN(target, "?", 2)[0])
+	if err != nil {
+		return false
+	}
+
+	filePath := filepath.Join(targetFolder, filepath.FromSlash(path.Clean("/"+targetPath)))
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return false
+	}
+
+	if !info.IsDir() {
+		return true
+	}
+
+	_, err = os.Stat(filepath.Join(filePath, indexFileName))
+	return err == nil
+}
+
+// brokenLinksBySource sorts broken links by their source and target.
+type brokenLinksBySource []BrokenLink
+
+func (links brokenLinksBySource) Len() int {
+	return len(links)
+}
+
+func (links brokenLinksBySource) Swap(i, j int) {
+	links[i], links[j] = links[j], links[i]
+}
+
+func (links brokenLinksBySource) Less(i, j int) bool {
+	if links[i].Source != links[j].Source {
+		return links[i].Source < links[j].Source
+	}
+
+	return links[i].Target < links[j].Target
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestSiteHandler returns a handler which serves the given paths and answers all other requests with 404.
+func newTestSiteHandler(paths ...string) http.Handler {
+	existingPaths := make(map[string]bool)
+	for _, path := range paths {
+		existingPaths[path] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !existingPaths[r.URL.Path] {
+			http.NotFound(w, r)
+			return
+		}
+
+		fmt.Fprintf(w, "<html>%s</html>", r.URL.Path)
+	})
+}
+
+func writeTestRenderedFile(t *testing.T, targetFolder, relativePath, content string) {
+	filePath := filepath.Join(targetFolder, filepath.FromSlash(relativePath))
+	os.MkdirAll(filepath.Dir(filePath), 0700)
+	if err := ioutil.WriteFile(filePath, []byte(content), 0600); err != nil {
+		t.Fatalf("The file %q could not be written. Error: %s", filePath, err)
+	}
+}
+
+func Test_CheckLinks_LinkToNonexistentPage_IsReported(t *testing.T) {
+	// arrange
+	targetFolder, _, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	writeTestRenderedFile(t, targetFolder, "documents/sample/index.html", `<html><head><base href="http://localhost/documents/sample/"></head><body>
+		<a href="/documents">Documents</a>
+		<a href="files/handout.pdf">Handout</a>
+		<a href="http://localhost/documents/missing">Missing</a>
+		<a href="../deleted">Deleted</a>
+		<a href="#section">Section</a>
+		<a href="https://example.com/elsewhere">External</a>
+		<a href="mailto:john@example.com">Mail</a>
+	</body></html>`)
+
+	writeTestRenderedFile(t, targetFolder, "documents/index.html", `<html><body><a href="/documents/sample">Sample</a></body></html>`)
+
+	handler := newTestSiteHandler("/documents", "/documents/sample/", "/documents/sample", "/documents/sample/files/handout.pdf")
+
+	// act
+	brokenLinks, err := CheckLinks(handler, "localhost", targetFolder)
+
+	// assert
+	if err != nil {
+		t.Fatalf("CheckLinks should not return an error but returned %s.", err)
+	}
+
+	expected := []BrokenLink{
+		{Source: "/documents/sample", Target: "/documents/deleted"},
+		{Source: "/documents/sample", Target: "/documents/missing"},
+	}
+
+	if len(brokenLinks) != len(expected) {
+		t.Fatalf("The broken links should be %v but were %v.", expected, brokenLinks)
+	}
+
+	for index, brokenLink := range brokenLinks {
+		if brokenLink != expected[index] {
+			t.Errorf("Broken link %d should be %q but was %q.", index, expected[index], brokenLink)
+		}
+	}
+}
+
+func Test_CheckLinks_NoBrokenLinks_ResultIsEmpty(t *testing.T) {
+	// arrange
+	targetFolder, _, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	writeTestRenderedFile(t, targetFolder, "index.html", `<html><body><a href="/documents">Documents</a> <a href="/documents?page=2">Page 2</a></body></html>`)
+
+	// act
+	brokenLinks, err := CheckLinks(newTestSiteHandler("/documents"), "localhost", targetFolder)
+
+	// assert
+	if err != nil || len(brokenLinks) != 0 {
+		t.Errorf("There should be no broken links but the result was %v (error: %v).", brokenLinks, err)
+	}
+}
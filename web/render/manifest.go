@@ -0,0 +1,57 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package render contains the bookkeeping for rendering a repository into static files.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// A Manifest maps the relative path of every rendered file (e.g. "documents/sample/index.html")
+// to the content hash of the item it was rendered from.
+type Manifest map[string]string
+
+// LoadManifest reads the manifest from the given file.
+// If the file does not exist an empty manifest is returned.
+func LoadManifest(manifestFilePath string) (Manifest, error) {
+	manifest := make(Manifest)
+
+	data, err := ioutil.ReadFile(manifestFilePath)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read the render manifest %q. Error: %s", manifestFilePath, err.Error())
+	}
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("Cannot parse the render manifest %q. Error: %s", manifestFilePath, err.Error())
+	}
+
+	return manifest, nil
+}
+
+// Save writes the manifest to the given file.
+func (manifest Manifest) Save(manifestFilePath string) error {
+	data, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return fmt.Errorf("Cannot serialize the render manifest. Error: %s", err.Error())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(manifestFilePath), 0700); err != nil {
+		return fmt.Errorf("Cannot create the folder for the render manifest %q. Error: %s", manifestFilePath, err.Error())
+	}
+
+	if err := ioutil.WriteFile(manifestFilePath, data, 0600); err != nil {
+		return fmt.Errorf("Cannot write the render manifest %q. Error: %s", manifestFilePath, err.Error())
+	}
+
+	return nil
+}
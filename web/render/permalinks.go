@@ -0,0 +1,102 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// indexFileName is the name of the file which is served for a folder.
+const indexFileName = "index.html"
+
+// getPermalinks returns the URL path of every rendered file by the request path of its item
+// (e.g. "/documents/my-post" -> "/2015/03/my-post/") for all files which are not rendered
+// to the folder of their item's route.
+func getPermalinks(requestPaths map[string]string) map[string]string {
+	permalinks := make(map[string]string)
+	for relativePath, requestPath := range requestPaths {
+		if requestPath == getDefaultRequestPath(relativePath) {
+			continue
+		}
+
+		permalink := "/" + relativePath
+		if path.Base(relativePath) == indexFileName {
+			permalink = strings.TrimSuffix(permalink, indexFileName)
+		}
+
+		permalinks[normalizeRequestPath(requestPath)] = permalink
+	}
+
+	return permalinks
+}
+
+// <a href="*link target*"> and <link href="*link target*"> (e.g. the canonical and alternate links)
+var linkHrefPattern = regexp.MustCompile(`<(?:a|link)\s[^>]*href="([^"]*)"`)
+
+// rewritePermalinks replaces all links in the supplied HTML (or XML) code which point to the request path
+// of an item with a permalink by the permalink. Relative links of anchors and link tags are replaced
+// by the permalink path; absolute URLs of the domain anywhere in the code (e.g. in the meta data,
+// the structured data, the feeds and the sitemap) are replaced by the absolute URL of the permalink.
+// Query and fragment of the links are kept.
+func rewritePermalinks(domainName, requestPath, html string, permalinks map[string]string) string {
+	if len(permalinks) == 0 {
+		return html
+	}
+
+	base := getBaseURL(domainName, requestPath, html)
+
+	html = linkHrefPattern.ReplaceAllStringFunc(html, func(tag string) string {
+		href := linkHrefPattern.FindStringSubmatch(tag)[1]
+		link, err := url.Parse(strings.TrimSpace(strings.Replace(href, "&amp;", "&", -1)))
+		if err != nil || link.IsAbs() || link.Host != "" {
+			// absolute URLs are rewritten below
+			return tag
+		}
+
+		target := base.ResolveReference(link)
+		if (target.Scheme != "http" && target.Scheme != "https") || target.Host != domainName {
+			return tag
+		}
+
+		permalink, exists := permalinks[normalizeRequestPath(target.Path)]
+		if !exists {
+			return tag
+		}
+
+		rewrittenLink := &url.URL{Path: permalink, RawQuery: target.RawQuery, Fragment: target.Fragment}
+		rewrittenHref := strings.Replace(rewrittenLink.String(), "&", "&amp;", -1)
+		return strings.Replace(tag, `href="`+href+`"`, `href="`+rewrittenHref+`"`, 1)
+	})
+
+	// http://*domain name**path*
+	absoluteURLPattern := regexp.MustCompile(`(https?://` + regexp.QuoteMeta(domainName) + `)(/[^\s"'<>?#\\]*)`)
+
+	return absoluteURLPattern.ReplaceAllStringFunc(html, func(absoluteURL string) string {
+		components := absoluteURLPattern.FindStringSubmatch(absoluteURL)
+		urlPath, err := url.PathUnescape(components[2])
+		if err != nil {
+			return absoluteURL
+		}
+
+		permalink, exists := permalinks[normalizeRequestPath(urlPath)]
+		if !exists {
+			return absoluteURL
+		}
+
+		return components[1] + (&url.URL{Path: permalink}).EscapedPath()
+	})
+}
+
+// normalizeRequestPath removes the trailing slash of the supplied request path (e.g. "/documents/" -> "/documents").
+func normalizeRequestPath(requestPath string) string {
+	if requestPath == "/" || requestPath == "" {
+		return "/"
+	}
+
+	return strings.TrimSuffix(requestPath, "/")
+}
@@ -0,0 +1,74 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// A Plan lists which files a render would write, skip or delete.
+type Plan struct {
+	Write  []string `json:"write"`
+	Skip   []string `json:"skip"`
+	Delete []string `json:"delete"`
+
+	Summary PlanSummary `json:"summary"`
+}
+
+// PlanSummary contains the number of files per action of a Plan.
+type PlanSummary struct {
+	Write  int `json:"write"`
+	Skip   int `json:"skip"`
+	Delete int `json:"delete"`
+}
+
+// NewPlan compares the current content hashes (relative path -> hash) with the hashes
+// of the previous render and returns the resulting plan. All lists are sorted by path.
+func NewPlan(currentHashes map[string]string, previous Manifest) Plan {
+	plan := Plan{
+		Write:  make([]string, 0),
+		Skip:   make([]string, 0),
+		Delete: make([]string, 0),
+	}
+
+	for path, hash := range currentHashes {
+		if previousHash, exists := previous[path]; exists && previousHash == hash {
+			plan.Skip = append(plan.Skip, path)
+			continue
+		}
+
+		plan.Write = append(plan.Write, path)
+	}
+
+	for path := range previous {
+		if _, exists := currentHashes[path]; !exists {
+			plan.Delete = append(plan.Delete, path)
+		}
+	}
+
+	sort.Strings(plan.Write)
+	sort.Strings(plan.Skip)
+	sort.Strings(plan.Delete)
+
+	plan.Summary = PlanSummary{
+		Write:  len(plan.Write),
+		Skip:   len(plan.Skip),
+		Delete: len(plan.Delete),
+	}
+
+	return plan
+}
+
+// IsEmpty returns true if the plan neither writes nor deletes any files.
+func (plan Plan) IsEmpty() bool {
+	return len(plan.Write) == 0 && len(plan.Delete) == 0
+}
+
+// JSON returns the plan as indented JSON.
+func (plan Plan) JSON() string {
+	bytes, _ := json.MarshalIndent(plan, "", "\t")
+	return string(bytes)
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_NewPlan_NewChangedUnchangedAndRemovedFiles(t *testing.T) {
+	// arrange
+	previous := Manifest{
+		"index.html":           "a1",
+		"unchanged/index.html": "b2",
+		"changed/index.html":   "c3",
+		"removed/index.html":   "d4",
+	}
+
+	current := map[string]string{
+		"index.html":           "a1",
+		"unchanged/index.html": "b2",
+		"changed/index.html":   "c4",
+		"new/index.html":       "e5",
+	}
+
+	// act
+	plan := NewPlan(current, previous)
+
+	// assert
+	if write := strings.Join(plan.Write, ","); write != "changed/index.html,new/index.html" {
+		t.Errorf("The plan should write the changed and the new file but writes %q.", write)
+	}
+
+	if skip := strings.Join(plan.Skip, ","); skip != "index.html,unchanged/index.html" {
+		t.Errorf("The plan should skip the unchanged files but skips %q.", skip)
+	}
+
+	if remove := strings.Join(plan.Delete, ","); remove != "removed/index.html" {
+		t.Errorf("The plan should delete the removed file but deletes %q.", remove)
+	}
+
+	if plan.Summary != (PlanSummary{Write: 2, Skip: 2, Delete: 1}) {
+		t.Errorf("The summary does not match the plan: %#v", plan.Summary)
+	}
+}
+
+func Test_Plan_JSON_CanBeParsed(t *testing.T) {
+	// arrange
+	plan := NewPlan(map[string]string{"index.html": "a1"}, Manifest{})
+
+	// act
+	var result Plan
+	err := json.Unmarshal([]byte(plan.JSON()), &result)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The plan JSON should be valid. Error: %s", err)
+	}
+
+	if result.Summary.Write != 1 || len(result.Skip) != 0 || len(result.Delete) != 0 {
+		t.Errorf("The parsed plan should contain one write but was %#v.", result)
+	}
+}
+
+func Test_LoadManifest_FileDoesNotExist_ManifestIsEmpty(t *testing.T) {
+	// arrange
+	directory, _ := ioutil.TempDir("", "allmark-render")
+	defer os.RemoveAll(directory)
+
+	// act
+	manifest, err := LoadManifest(filepath.Join(directory, "render.manifest"))
+
+	// assert
+	if err != nil {
+		t.Fatalf("A missing manifest should not be an error. Error: %s", err)
+	}
+
+	if len(manifest) != 0 {
+		t.Errorf("The manifest should be empty but contained %d entries.", len(manifest))
+	}
+}
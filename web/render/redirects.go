@@ -0,0 +1,69 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/andreaskoch/allmark/common/util/hashutil"
+)
+
+// AddRedirects returns the supplied content hashes (relative path -> hash) together with the hashes
+// of the redirect pages (relative path -> target URL). An error is returned if a redirect
+// page would replace the file of an item.
+func AddRedirects(currentHashes, redirects map[string]string) (map[string]string, error) {
+	hashes := make(map[string]string, len(currentHashes)+len(redirects))
+	for relativePath, hash := range currentHashes {
+		hashes[relativePath] = hash
+	}
+
+	for relativePath, target := range redirects {
+		if _, exists := currentHashes[relativePath]; exists {
+			return nil, fmt.Errorf("Cannot write a redirect to %q to %q because the file belongs to an item.", target, relativePath)
+		}
+
+		hashes[relativePath] = hashutil.FromString(getRedirectPage(target))
+	}
+
+	return hashes, nil
+}
+
+// writeRedirect writes a page which redirects to the given target URL to the given file in the target folder.
+func writeRedirect(targetFolder, relativePath, target string) error {
+	targetFile := filepath.Join(targetFolder, filepath.FromSlash(relativePath))
+	if err := os.MkdirAll(filepath.Dir(targetFile), 0700); err != nil {
+		return fmt.Errorf("Cannot create the folder for %q. Error: %s", targetFile, err.Error())
+	}
+
+	if err := ioutil.WriteFile(targetFile, []byte(getRedirectPage(target)), 0600); err != nil {
+		return fmt.Errorf("Cannot write %q. Error: %s", targetFile, err.Error())
+	}
+
+	return nil
+}
+
+// getRedirectPage returns a HTML page which immediately redirects to the given target URL
+// and names it as the canonical URL of the page.
+func getRedirectPage(target string) string {
+	escapedTarget := html.EscapeString(target)
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Redirecting to %s</title>
+<link rel="canonical" href="%s">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url=%s">
+</head>
+<body>
+<p>This page has moved to <a href="%s">%s</a>.</p>
+</body>
+</html>
+`, escapedTarget, escapedTarget, escapedTarget, escapedTarget, escapedTarget)
+}
@@ -0,0 +1,133 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Render writes all files whose content hash (relative path -> hash) has changed since the last render
+// to the target folder, removes the files of items which no longer exist and saves the current hashes
+// as the new manifest. The files are rendered by requesting them from the given handler
+// for the given domain name. Deleting the manifest file forces a full render.
+//
+// The request paths (relative path -> request path of the item) are only needed for files which are
+// not rendered to the folder of their item's route (e.g. "2015/03/my-post/index.html" -> "/documents/my-post").
+// All links to the request paths of these items are replaced with the paths of their rendered files.
+// The redirects (relative path -> target URL) are written as pages which redirect to their target.
+func Render(handler http.Handler, domainName string, currentHashes, requestPaths, redirects map[string]string, targetFolder, manifestFilePath string) (Plan, error) {
+
+	currentHashes, err := AddRedirects(currentHashes, redirects)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	previous, err := LoadManifest(manifestFilePath)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	// files which have been removed from the target folder must be written again
+	for relativePath := range previous {
+		if _, err := os.Stat(filepath.Join(targetFolder, filepath.FromSlash(relativePath))); os.IsNotExist(err) {
+			delete(previous, relativePath)
+		}
+	}
+
+	plan := NewPlan(currentHashes, previous)
+	permalinks := getPermalinks(requestPaths)
+
+	for _, relativePath := range plan.Write {
+		if target, isRedirect := redirects[relativePath]; isRedirect {
+			if err := writeRedirect(targetFolder, relativePath, target); err != nil {
+				return plan, err
+			}
+
+			continue
+		}
+
+		if err := renderFile(handler, domainName, targetFolder, relativePath, getRequestPath(requestPaths, relativePath), permalinks); err != nil {
+			return plan, err
+		}
+	}
+
+	for _, relativePath := range plan.Delete {
+		if err := removeFile(targetFolder, relativePath); err != nil {
+			return plan, err
+		}
+	}
+
+	if err := Manifest(currentHashes).Save(manifestFilePath); err != nil {
+		return plan, err
+	}
+
+	return plan, nil
+}
+
+// renderFile requests the given request path from the handler, replaces all links to items
+// with their permalinks and writes the response to the given file in the target folder.
+func renderFile(handler http.Handler, domainName, targetFolder, relativePath, requestPath string, permalinks map[string]string) error {
+
+	request := httptest.NewRequest("GET", requestPath, nil)
+	request.Host = domainName
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	if response.Code != http.StatusOK {
+		return fmt.Errorf("Cannot render %q. The request for %q returned status code %d.", relativePath, requestPath, response.Code)
+	}
+
+	targetFile := filepath.Join(targetFolder, filepath.FromSlash(relativePath))
+	if err := os.MkdirAll(filepath.Dir(targetFile), 0700); err != nil {
+		return fmt.Errorf("Cannot create the folder for %q. Error: %s", targetFile, err.Error())
+	}
+
+	content := rewritePermalinks(domainName, requestPath, response.Body.String(), permalinks)
+	if err := ioutil.WriteFile(targetFile, []byte(content), 0600); err != nil {
+		return fmt.Errorf("Cannot write %q. Error: %s", targetFile, err.Error())
+	}
+
+	return nil
+}
+
+// getRequestPath returns the request path for the supplied file or,
+// if there is none, the path of the folder the file is located in.
+func getRequestPath(requestPaths map[string]string, relativePath string) string {
+	if requestPath, exists := requestPaths[relativePath]; exists {
+		return requestPath
+	}
+
+	return getDefaultRequestPath(relativePath)
+}
+
+// getDefaultRequestPath returns the path of the folder the given file is located in (e.g. "/documents/sample").
+func getDefaultRequestPath(relativePath string) string {
+	return "/" + strings.TrimPrefix(path.Dir(relativePath), ".")
+}
+
+// removeFile removes the given file and all of its parent folders which are empty afterwards.
+func removeFile(targetFolder, relativePath string) error {
+
+	targetFile := filepath.Join(targetFolder, filepath.FromSlash(relativePath))
+	if err := os.Remove(targetFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Cannot remove %q. Error: %s", targetFile, err.Error())
+	}
+
+	for folder := filepath.Dir(targetFile); strings.HasPrefix(folder, targetFolder) && folder != filepath.Clean(targetFolder); folder = filepath.Dir(folder) {
+		if os.Remove(folder) != nil {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestHandler returns a handler which renders the request path and records every request.
+func newTestHandler(requests *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.Path)
+		fmt.Fprintf(w, "<html>%s</html>", r.URL.Path)
+	})
+}
+
+func newTestRenderFolder(t *testing.T) (targetFolder, manifestFilePath string, cleanup func()) {
+	directory, err := ioutil.TempDir("", "allmark-render")
+	if err != nil {
+		t.Fatalf("The temp folder could not be created. Error: %s", err)
+	}
+
+	return filepath.Join(directory, "render"), filepath.Join(directory, "render.manifest"), func() { os.RemoveAll(directory) }
+}
+
+func Test_Render_UnchangedItems_AreSkipped(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	hashes := map[string]string{
+		"index.html":           "root-1",
+		"documents/index.html": "documents-1",
+	}
+
+	var requests []string
+	Render(newTestHandler(&requests), "localhost", hashes, nil, nil, targetFolder, manifestFilePath)
+	requests = nil
+
+	// act
+	plan, err := Render(newTestHandler(&requests), "localhost", hashes, nil, nil, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Render should not return an error but returned %s.", err)
+	}
+
+	if len(requests) != 0 {
+		t.Errorf("No file should have been rendered but %v were.", requests)
+	}
+
+	if plan.Summary.Skip != 2 {
+		t.Errorf("%d files should have been skipped but %d were.", 2, plan.Summary.Skip)
+	}
+}
+
+func Test_Render_ChangedItem_IsRenderedAgain(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	var requests []string
+	Render(newTestHandler(&requests), "localhost", map[string]string{
+		"index.html":           "root-1",
+		"documents/index.html": "documents-1",
+	}, nil, nil, targetFolder, manifestFilePath)
+	requests = nil
+
+	// act
+	_, err := Render(newTestHandler(&requests), "localhost", map[string]string{
+		"index.html":           "root-1",
+		"documents/index.html": "documents-2",
+	}, nil, nil, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Render should not return an error but returned %s.", err)
+	}
+
+	if len(requests) != 1 || requests[0] != "/documents" {
+		t.Errorf("Only %q should have been requested but %v were.", "/documents", requests)
+	}
+
+	content, _ := ioutil.ReadFile(filepath.Join(targetFolder, "documents", "index.html"))
+	if string(content) != "<html>/documents</html>" {
+		t.Errorf("The rendered file should contain the response but contained %q.", content)
+	}
+}
+
+func Test_Render_DeletedItem_OutputIsRemoved(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	var requests []string
+	Render(newTestHandler(&requests), "localhost", map[string]string{
+		"index.html":                  "root-1",
+		"documents/sample/index.html": "sample-1",
+	}, nil, nil, targetFolder, manifestFilePath)
+
+	// act
+	plan, err := Render(newTestHandler(&requests), "localhost", map[string]string{
+		"index.html": "root-1",
+	}, nil, nil, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Render should not return an error but returned %s.", err)
+	}
+
+	if len(plan.Delete) != 1 || plan.Delete[0] != "documents/sample/index.html" {
+		t.Errorf("The plan should delete %q but deleted %v.", "documents/sample/index.html", plan.Delete)
+	}
+
+	if _, err := os.Stat(filepath.Join(targetFolder, "documents")); !os.IsNotExist(err) {
+		t.Errorf("The rendered file and its empty folders should have been removed.")
+	}
+
+	if _, err := os.Stat(filepath.Join(targetFolder, "index.html")); err != nil {
+		t.Errorf("The file of the remaining item should still exist.")
+	}
+}
+
+func Test_Render_ManifestDeleted_AllItemsAreRendered(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	hashes := map[string]string{
+		"index.html":           "root-1",
+		"documents/index.html": "documents-1",
+	}
+
+	var requests []string
+	Render(newTestHandler(&requests), "localhost", hashes, nil, nil, targetFolder, manifestFilePath)
+	requests = nil
+
+	os.Remove(manifestFilePath)
+
+	// act
+	Render(newTestHandler(&requests), "localhost", hashes, nil, nil, targetFolder, manifestFilePath)
+
+	// assert
+	if len(requests) != 2 {
+		t.Errorf("All %d files should have been rendered but %v were.", 2, requests)
+	}
+}
+
+func Test_Render_RequestPaths_FilesAreRenderedToTheirPermalinksAndLinksAreRewritten(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><base href="/"><a href="documents/post#intro">Post</a> <a href="/documents/post/">Post</a> <a href="/documents">Documents</a></html>`)
+	})
+
+	hashes := map[string]string{
+		"index.html":              "root-1",
+		"documents/index.html":    "documents-1",
+		"2015/03/post/index.html": "post-1",
+	}
+
+	requestPaths := map[string]string{
+		"index.html":              "/",
+		"documents/index.html":    "/documents",
+		"2015/03/post/index.html": "/documents/post",
+	}
+
+	// act
+	_, err := Render(handler, "localhost", hashes, requestPaths, nil, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Render should not return an error but returned %s.", err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(targetFolder, "2015", "03", "post", "index.html"))
+	if err != nil {
+		t.Fatalf("The item should have been rendered to its permalink. Error: %s", err)
+	}
+
+	expected := `<html><base href="/"><a href="/2015/03/post/#intro">Post</a> <a href="/2015/03/post/">Post</a> <a href="/documents">Documents</a></html>`
+	if string(content) != expected {
+		t.Errorf("The rendered file should be\n%s\nbut was\n%s", expected, content)
+	}
+}
+
+func Test_Render_RequestPaths_CanonicalLinksAndMetaDataPointToThePermalinks(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><head><meta property="og:url" content="http://localhost/documents/post" /><script type="application/ld+json">{"url":"http://localhost/documents/post","publisher":{"url":"http://localhost/documents"}}</script><link rel="canonical" href="http://localhost/documents/post"><link rel="alternate" hreflang="en" href="/documents/post"></head><a href="http://localhost/documents/posts">Posts</a> <a href="http://example.com/documents/post">Elsewhere</a></html>`)
+	})
+
+	hashes := map[string]string{
+		"2015/03/post/index.html": "post-1",
+	}
+
+	requestPaths := map[string]string{
+		"2015/03/post/index.html": "/documents/post",
+	}
+
+	// act
+	_, err := Render(handler, "localhost", hashes, requestPaths, nil, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Render should not return an error but returned %s.", err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(targetFolder, "2015", "03", "post", "index.html"))
+	if err != nil {
+		t.Fatalf("The item should have been rendered to its permalink. Error: %s", err)
+	}
+
+	expected := `<html><head><meta property="og:url" content="http://localhost/2015/03/post/" /><script type="application/ld+json">{"url":"http://localhost/2015/03/post/","publisher":{"url":"http://localhost/documents"}}</script><link rel="canonical" href="http://localhost/2015/03/post/"><link rel="alternate" hreflang="en" href="/2015/03/post/"></head><a href="http://localhost/documents/posts">Posts</a> <a href="http://example.com/documents/post">Elsewhere</a></html>`
+	if string(content) != expected {
+		t.Errorf("The rendered file should be\n%s\nbut was\n%s", expected, content)
+	}
+}
+
+func Test_Render_Redirects_RedirectPagesAreWritten(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	hashes := map[string]string{
+		"documents/new/index.html": "new-1",
+	}
+
+	redirects := map[string]string{
+		"documents/old/index.html": "/documents/new/",
+		"2014/old.html":            "/documents/new/",
+	}
+
+	var requests []string
+
+	// act
+	_, err := Render(newTestHandler(&requests), "localhost", hashes, nil, redirects, targetFolder, manifestFilePath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Render should not return an error but returned %s.", err)
+	}
+
+	if len(requests) != 1 {
+		t.Errorf("Only the item should have been requested but %v were.", requests)
+	}
+
+	for relativePath := range redirects {
+		content, err := ioutil.ReadFile(filepath.Join(targetFolder, filepath.FromSlash(relativePath)))
+		if err != nil {
+			t.Errorf("The redirect page %q should have been written. Error: %s", relativePath, err)
+			continue
+		}
+
+		for _, expected := range []string{`<link rel="canonical" href="/documents/new/">`, `<meta http-equiv="refresh" content="0; url=/documents/new/">`} {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("The redirect page %q should contain %q but was %q.", relativePath, expected, content)
+			}
+		}
+	}
+}
+
+func Test_Render_RedirectToTheFileOfAnItem_ErrorIsReturned(t *testing.T) {
+	// arrange
+	targetFolder, manifestFilePath, cleanup := newTestRenderFolder(t)
+	defer cleanup()
+
+	hashes := map[string]string{
+		"documents/new/index.html": "new-1",
+		"documents/old/index.html": "old-1",
+	}
+
+	redirects := map[string]string{
+		"documents/old/index.html": "/documents/new/",
+	}
+
+	var requests []string
+
+	// act
+	_, err := Render(newTestHandler(&requests), "localhost", hashes, nil, redirects, targetFolder, manifestFilePath)
+
+	// assert
+	if err == nil {
+		t.Errorf("Render should return an error if a redirect collides with an item.")
+	}
+
+	if len(requests) != 0 {
+		t.Errorf("Nothing should have been rendered but %v were.", requests)
+	}
+}
@@ -17,13 +17,18 @@ import (
 	"github.com/andreaskoch/allmark/web/handlers"
 	"github.com/andreaskoch/allmark/web/header"
 	"github.com/andreaskoch/allmark/web/orchestrator"
+	"github.com/andreaskoch/allmark/web/render"
 	"github.com/andreaskoch/allmark/web/view/templates"
+	"github.com/andreaskoch/allmark/web/view/themes"
 	"github.com/andreaskoch/allmark/web/webpaths"
+	"crypto/tls"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/skratchdot/open-golang/open"
+	"io"
 	"net"
 	"net/http"
+	"path/filepath"
 	"strings"
 )
 
@@ -37,20 +42,33 @@ func New(logger logger.Logger, config config.Config, repository dataaccess.Repos
 	imageProvider := imageprovider.NewImageProvider(webPathProvider.AbsolutePather("/"), thumbnailIndex)
 
 	// converter
-	converter := markdowntohtml.New(logger, imageProvider)
+	converter := markdowntohtml.New(logger, config.Markdown, imageProvider)
 
-	orchestratorFactory := orchestrator.NewFactory(logger, config, repository, parser, converter, webPathProvider)
+	orchestratorFactory := orchestrator.NewFactory(logger, config, repository, parser, converter, webPathProvider, thumbnailIndex)
 	reindexInterval := config.Indexing.IntervalInSeconds
 	headerWriterFactory := header.NewHeaderWriterFactory(reindexInterval)
 	templateProvider := templates.NewProvider(config.TemplatesFolder())
-	requestHandlers := handlers.GetBaseHandlers(logger, config, templateProvider, *orchestratorFactory, headerWriterFactory)
+
+	// theme
+	theme, err := themes.GetThemeByName(config.ThemesFolder(), config.ThemeName())
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Theme: %s", theme.Name)
+
+	requestHandlers := handlers.GetBaseHandlers(logger, config, templateProvider, theme, *orchestratorFactory, headerWriterFactory)
+	assetHashes := handlers.NewAssetHashProvider(config.ThemeOverrideFolder(), theme, orchestratorFactory.NewFileOrchestrator())
 
 	return &Server{
 		logger: logger,
 		config: config,
 
 		headerWriterFactory: headerWriterFactory,
+		orchestratorFactory: orchestratorFactory,
 		requestHandlers:     requestHandlers,
+		theme:               theme,
+		assetHashes:         assetHashes,
 	}, nil
 
 }
@@ -61,16 +79,143 @@ type Server struct {
 	config config.Config
 
 	headerWriterFactory header.WriterFactory
+	orchestratorFactory *orchestrator.Factory
 
 	requestHandlers handlers.HandlerList
+	theme           *themes.Theme
+	assetHashes     handlers.AssetHashProvider
+}
+
+// RenderPlan returns which files a render of the repository would write, skip or delete
+// compared to the last render. The file system is not modified.
+func (server *Server) RenderPlan() (render.Plan, error) {
+	manifest, err := render.LoadManifest(server.config.RenderManifestFilePath())
+	if err != nil {
+		return render.Plan{}, err
+	}
+
+	// the plan must use the same content hashes as the render (including the theme and markdown dependencies)
+	site, err := server.getRenderSite()
+	if err != nil {
+		return render.Plan{}, err
+	}
+
+	currentHashes, err := render.AddRedirects(site.ContentHashes, site.Redirects)
+	if err != nil {
+		return render.Plan{}, err
+	}
+
+	return render.NewPlan(currentHashes, manifest), nil
+}
+
+// Render writes all items which have changed since the last render, the feeds and the theme files to the
+// render target folder, removes the files of deleted items and returns the report of the render.
+// The report is also written to the render report file.
+func (server *Server) Render() (render.Report, error) {
+	report, err := render.Build(server.getLocalRequestRouter(), server.getRenderDomainName(), server.getRenderSite, server.config.RenderTargetFolder(), server.config.RenderManifestFilePath())
+	if err != nil {
+		return report, err
+	}
+
+	return report, report.Save(server.config.RenderReportFilePath())
+}
+
+// Export renders the complete repository into a temporary folder and writes the rendered files
+// as a ZIP archive to the supplied writer. The render target folder and the render manifest are not changed.
+func (server *Server) Export(writer io.Writer) (render.Report, error) {
+	return render.Export(writer, server.getLocalRequestRouter(), server.getRenderDomainName(), server.getRenderSite)
+}
+
+// getRenderSite returns all files which are written by a render of the repository.
+func (server *Server) getRenderSite() (render.Site, error) {
+	renderOrchestrator := server.orchestratorFactory.NewRenderOrchestrator()
+
+	redirects, err := renderOrchestrator.GetRedirects()
+	if err != nil {
+		return render.Site{}, err
+	}
+
+	// the pages reference the fingerprinted theme files, so they change whenever a theme file changes
+	assets := server.getRenderAssets()
+	contentHashes := renderOrchestrator.GetContentHashes()
+	if server.config.Server.FingerprintAssets {
+		contentHashes = render.AddDependency(contentHashes, assets)
+	}
+
+	// the markdown extensions change the HTML of every item
+	if server.config.Markdown != (config.Markdown{}) {
+		contentHashes = render.AddDependency(contentHashes, map[string]string{fmt.Sprintf("markdown=%+v", server.config.Markdown): ""})
+	}
+
+	return render.Site{
+		ItemTypes:     renderOrchestrator.GetItemTypes(),
+		ContentHashes: contentHashes,
+		RequestPaths:  renderOrchestrator.GetRequestPaths(),
+		Redirects:     redirects,
+		Feeds:         server.getRenderFeeds(),
+		Assets:        assets,
+	}, nil
+}
+
+// getRenderFeeds returns the request paths of the feeds, the sitemap, the tag outline and the combined page of all documents
+// by the relative path of their rendered file. They depend on many items, so they are written on every render.
+func (server *Server) getRenderFeeds() map[string]string {
+	feeds := make(map[string]string)
+	for _, requestPath := range []string{handlers.RSSHandlerRoute, handlers.AtomHandlerRoute, handlers.XMLSitemapHandlerRoute, handlers.TagsOPMLHandlerRoute, handlers.CombinedHandlerRoute} {
+		feeds[strings.TrimPrefix(requestPath, "/")] = requestPath
+	}
+
+	return feeds
+}
+
+// getRenderAssets returns the request paths of all theme files and of the files of all items (except for drafts)
+// by the relative path of their rendered file.
+// If fingerprinting is enabled the fingerprinted copies of the files are included too.
+func (server *Server) getRenderAssets() map[string]string {
+	var requestPaths []string
+	for _, themeFilePath := range server.theme.Paths() {
+		requestPaths = append(requestPaths, handlers.ThemeRoutePrefix+"/"+themeFilePath)
+	}
+
+	for _, requestPath := range server.orchestratorFactory.NewRenderOrchestrator().GetFileRequestPaths() {
+		requestPaths = append(requestPaths, requestPath)
+	}
+
+	assets := make(map[string]string)
+	for _, requestPath := range requestPaths {
+		assets[strings.TrimPrefix(requestPath, "/")] = requestPath
+
+		if !server.config.Server.FingerprintAssets {
+			continue
+		}
+
+		if hash, found := server.assetHashes(requestPath); found {
+			fingerprintedRequestPath := handlers.GetFingerprintedPath(requestPath, hash)
+			assets[strings.TrimPrefix(fingerprintedRequestPath, "/")] = fingerprintedRequestPath
+		}
+	}
+
+	return assets
+}
+
+// CheckLinks returns all internal links of the rendered items whose targets do not exist.
+func (server *Server) CheckLinks() ([]render.BrokenLink, error) {
+	return render.CheckLinks(server.getLocalRequestRouter(), server.getRenderDomainName(), server.config.RenderTargetFolder())
+}
+
+// getRenderDomainName returns the domain name which is used for rendering the items.
+func (server *Server) getRenderDomainName() string {
+	if domainName := server.config.Server.DomainName; domainName != "" {
+		return domainName
+	}
+
+	return config.DefaultDomainName
 }
 
 // Start starts the current web server.
 func (server *Server) Start() chan error {
 
-	result := make(chan error)
-
-	standardRequestRouter := server.getStandardRequestRouter()
+	result := make(chan error, 1)
 
 	// bindings
 	httpEndpoint, httpEnabled := server.httpEndpoint()
@@ -82,6 +227,36 @@ func (server *Server) Start() chan error {
 		return result
 	}
 
+	// load the certificate; a configured certificate must be usable,
+	// only if the generated certificate cannot be loaded the server falls back to HTTP-only
+	var tlsConfig *tls.Config
+	if httpsEnabled {
+		var err error
+		tlsConfig, err = getTLSConfig(httpsEndpoint.CertFilePath(), httpsEndpoint.KeyFilePath())
+		if err != nil && httpsEndpoint.IsConfiguredCertificate() {
+			result <- fmt.Errorf("Cannot load the certificate %q and key %q. Error: %s", httpsEndpoint.CertFilePath(), httpsEndpoint.KeyFilePath(), err.Error())
+			return result
+		}
+
+		if err != nil {
+			server.logger.Error("HTTPS is disabled because the generated certificate cannot be loaded. Error: %s", err.Error())
+			httpsEnabled = false
+		}
+	}
+
+	if !httpEnabled && !httpsEnabled {
+		result <- fmt.Errorf("Neither HTTP nor HTTPS can be served")
+		return result
+	}
+
+	// basic authentication is only available over HTTPS; the repository must not be served without it
+	if server.config.Server.Authentication.Enabled && !httpsEnabled {
+		result <- fmt.Errorf("Authentication is enabled but HTTPS is not available. Please enable HTTPS in order to use basic-authentication.")
+		return result
+	}
+
+	standardRequestRouter := server.getStandardRequestRouter()
+
 	uniqueURLs := make(map[string]string)
 
 	// http
@@ -98,7 +273,7 @@ func (server *Server) Start() chan error {
 			go func() {
 				server.logger.Info("HTTP Endpoint: %s", address)
 
-				if httpEndpoint.ForceHTTPS() {
+				if httpsEnabled && httpEndpoint.ForceHTTPS() {
 
 					// Redirect HTTP → HTTPS
 					redirectTarget := httpsEndpoint.DefaultURL()
@@ -147,7 +322,13 @@ func (server *Server) Start() chan error {
 				server.logger.Info("HTTPS Endpoint: %s", address)
 
 				// Standard HTTPS Request Router
-				if err := http.ListenAndServeTLS(address, httpsEndpoint.CertFilePath(), httpsEndpoint.KeyFilePath(), standardRequestRouter); err != nil {
+				tlsServer := &http.Server{
+					Addr:      address,
+					Handler:   standardRequestRouter,
+					TLSConfig: tlsConfig,
+				}
+
+				if err := tlsServer.ListenAndServeTLS("", ""); err != nil {
 					result <- fmt.Errorf("Server failed with error: %v", err)
 				} else {
 					result <- nil
@@ -162,19 +343,19 @@ func (server *Server) Start() chan error {
 
 	}
 
-	// docx conversion endpoint (unencrypted, no authentication)
-	if server.config.Conversion.DOCX.IsEnabled() {
+	// docx and pdf conversion endpoint (unencrypted, no authentication)
+	if server.config.Conversion.EndpointIsRequired() {
 
 		// start listening
 		go func() {
 			conversionEndpointTCPAddress := server.config.Conversion.EndpointBinding().GetTCPAddress()
 			conversionEndpointAddress := conversionEndpointTCPAddress.String()
 
-			server.logger.Info("Docx Conversion Endpoint: %s", conversionEndpointAddress)
+			server.logger.Info("Conversion Endpoint: %s", conversionEndpointAddress)
 
 			// Standard HTTPS Request Router
 			if err := http.ListenAndServe(conversionEndpointAddress, server.getLocalRequestRouter()); err != nil {
-				result <- fmt.Errorf("Docx Conversion endpoint failed with error: %v", err)
+				result <- fmt.Errorf("Conversion endpoint failed with error: %v", err)
 			} else {
 				result <- nil
 			}
@@ -192,6 +373,20 @@ func (server *Server) Start() chan error {
 	return result
 }
 
+// getTLSConfig returns a TLS configuration for the supplied certificate and key file
+// or an error if the certificate/key pair cannot be loaded.
+func getTLSConfig(certFilePath, keyFilePath string) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(certFilePath, keyFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
 // getRedirectRouter returns a router which redirects all requests to the url with the given base.
 func (server *Server) getRedirectRouter(baseURITarget string, baseHandler http.Handler) *mux.Router {
 	redirectRouter := mux.NewRouter()
@@ -212,6 +407,8 @@ func (server *Server) getStandardRequestRouter() *mux.Router {
 	// register requst routers
 	requestRouter := mux.NewRouter()
 
+	credentialsChecker := server.getCredentialsChecker()
+
 	for _, requestHandler := range server.requestHandlers {
 		requestRoute := requestHandler.Route
 		requestHandler := requestHandler.Handler
@@ -219,17 +416,22 @@ func (server *Server) getStandardRequestRouter() *mux.Router {
 		// add logging
 		requestHandler = handlers.LogRequests(requestHandler)
 
+		// add asset fingerprints
+		if server.config.Server.FingerprintAssets {
+			requestHandler = handlers.FingerprintAssetURLs(requestHandler, server.assetHashes)
+		}
+
+		// add minification
+		if server.config.Server.Minify {
+			requestHandler = handlers.MinifyResponses(requestHandler)
+		}
+
 		// add compression
-		requestHandler = handlers.CompressResponses(requestHandler)
+		requestHandler = handlers.CompressResponses(requestHandler, server.config.CompressionMinimumSize())
 
 		// add authentication
-		if _, httpsEnabled := server.httpsEndpoint(); httpsEnabled && server.config.AuthenticationIsEnabled() {
-			secretProvider := server.config.GetAuthenticationUserStore()
-			if secretProvider == nil {
-				panic("Authentication is enabled but the supplied secret provider is nil.")
-			}
-
-			requestHandler = handlers.RequireDigestAuthentication(server.logger, requestHandler, secretProvider)
+		if credentialsChecker != nil {
+			requestHandler = handlers.RequireBasicAuthentication(server.logger, requestHandler, server.config.Server.DomainName, credentialsChecker)
 		}
 
 		requestRouter.Handle(requestRoute, requestHandler)
@@ -238,7 +440,28 @@ func (server *Server) getStandardRequestRouter() *mux.Router {
 	return requestRouter
 }
 
+// getCredentialsChecker returns the checker for the configured credentials (username and password or
+// the htpasswd user store) or nil if authentication is disabled.
+// Start fails if authentication is enabled but HTTPS is not available, so the checker is never skipped silently.
+func (server *Server) getCredentialsChecker() handlers.CredentialsChecker {
+	if !server.config.AuthenticationIsEnabled() {
+		return nil
+	}
+
+	if username, password, configured := server.config.AuthenticationCredentials(); configured {
+		return handlers.StaticCredentials(username, password)
+	}
+
+	secretProvider := server.config.GetAuthenticationUserStore()
+	if secretProvider == nil {
+		panic("Authentication is enabled but the supplied secret provider is nil.")
+	}
+
+	return handlers.HtpasswdCredentials(secretProvider)
+}
+
 // getLocalRequestRouter returns a local request router without compression and without authentication.
+// Asset fingerprints and minification are applied if they are enabled.
 func (server *Server) getLocalRequestRouter() *mux.Router {
 
 	// register requst routers
@@ -251,6 +474,16 @@ func (server *Server) getLocalRequestRouter() *mux.Router {
 		// add logging
 		requestHandler = handlers.LogRequests(requestHandler)
 
+		// add asset fingerprints
+		if server.config.Server.FingerprintAssets {
+			requestHandler = handlers.FingerprintAssetURLs(requestHandler, server.assetHashes)
+		}
+
+		// add minification
+		if server.config.Server.Minify {
+			requestHandler = handlers.MinifyResponses(requestHandler)
+		}
+
 		requestRouter.Handle(requestRoute, requestHandler)
 	}
 
@@ -285,14 +518,24 @@ func (server *Server) httpsEndpoint() (httpsEndpoint HTTPSEndpoint, enabled bool
 		tcpBindings: server.config.Server.HTTPS.Bindings,
 	}
 
-	certFilePath, keyFilePath, _ := server.config.CertificateFilePaths()
-
 	httpsEndpoint = HTTPSEndpoint{
 		HTTPEndpoint: httpEndpoint,
-		certFilePath: certFilePath,
-		keyFilePath:  keyFilePath,
 	}
 
+	// a custom certificate must be supplied; only the default certificate is generated if it does not exist
+	httpsConfig := server.config.Server.HTTPS
+	if isCustomCertificate(httpsConfig.CertFileName, httpsConfig.KeyFileName) {
+		httpsEndpoint.certFilePath = filepath.Join(server.config.CertificateDirectory(), getValueOrDefault(httpsConfig.CertFileName, config.DefaultHTTPSCertName))
+		httpsEndpoint.keyFilePath = filepath.Join(server.config.CertificateDirectory(), getValueOrDefault(httpsConfig.KeyFileName, config.DefaultHTTPSKeyName))
+		httpsEndpoint.isConfiguredCertificate = true
+		return httpsEndpoint, true
+	}
+
+	certFilePath, keyFilePath, created := server.config.CertificateFilePaths()
+	httpsEndpoint.certFilePath = certFilePath
+	httpsEndpoint.keyFilePath = keyFilePath
+	httpsEndpoint.isConfiguredCertificate = !created
+
 	return httpsEndpoint, true
 
 }
@@ -366,6 +609,9 @@ type HTTPSEndpoint struct {
 
 	certFilePath string
 	keyFilePath  string
+
+	// isConfiguredCertificate indicates whether the certificate has been supplied by the user (and not generated)
+	isConfiguredCertificate bool
 }
 
 // CertFilePath returns the SSL certificate file (e.g. "cert.pem") name of this HTTPSEndpoint.
@@ -378,6 +624,27 @@ func (endpoint *HTTPSEndpoint) KeyFilePath() string {
 	return endpoint.keyFilePath
 }
 
+// IsConfiguredCertificate returns true if the certificate of this HTTPSEndpoint has been supplied by the user
+// and false if it is a generated self-signed certificate.
+func (endpoint *HTTPSEndpoint) IsConfiguredCertificate() bool {
+	return endpoint.isConfiguredCertificate
+}
+
+// isCustomCertificate returns true if the supplied certificate or key file name differs from the default names.
+func isCustomCertificate(certFileName, keyFileName string) bool {
+	return getValueOrDefault(certFileName, config.DefaultHTTPSCertName) != config.DefaultHTTPSCertName ||
+		getValueOrDefault(keyFileName, config.DefaultHTTPSKeyName) != config.DefaultHTTPSKeyName
+}
+
+// getValueOrDefault returns the supplied value or the default value if the value is empty.
+func getValueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
 // getURL returns the formatted URL (e.g. "https://localhost:8080") for the given TCP binding,
 // using the IP address as the hostname.
 func getURL(endpoint HTTPEndpoint, tcpBinding config.TCPBinding) string {
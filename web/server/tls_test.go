@@ -0,0 +1,187 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/common/certificates"
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/logger/console"
+	"github.com/andreaskoch/allmark/common/logger/loglevel"
+)
+
+func Test_getTLSConfig_SelfSignedCertificate_RequestOverTLSSucceeds(t *testing.T) {
+	// arrange
+	certificateDirectory, err := ioutil.TempDir("", "allmark-tls-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory: %s", err)
+	}
+	defer os.RemoveAll(certificateDirectory)
+
+	certFilePath := filepath.Join(certificateDirectory, "cert.pem")
+	keyFilePath := filepath.Join(certificateDirectory, "cert.key")
+	if err := certificates.GenerateDummyCert(certFilePath, keyFilePath, "localhost"); err != nil {
+		t.Fatalf("Unable to create a self-signed certificate: %s", err)
+	}
+
+	tlsConfig, err := getTLSConfig(certFilePath, keyFilePath)
+	if err != nil {
+		t.Fatalf("getTLSConfig should not return an error for a valid certificate but returned: %s", err)
+	}
+
+	tlsServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "secure")
+	}))
+	tlsServer.TLS = tlsConfig
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	// act
+	response, err := client.Get(tlsServer.URL)
+
+	// assert
+	if err != nil {
+		t.Fatalf("The request over TLS should succeed but failed with: %s", err)
+	}
+	defer response.Body.Close()
+
+	if response.TLS == nil {
+		t.Errorf("The response should have been received over TLS.")
+	}
+
+	body, _ := ioutil.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || string(body) != "secure" {
+		t.Errorf("The response should be %d %q but was %d %q.", http.StatusOK, "secure", response.StatusCode, string(body))
+	}
+}
+
+func Test_getTLSConfig_MissingCertificate_ErrorIsReturned(t *testing.T) {
+	// act
+	_, err := getTLSConfig("/does/not/exist/cert.pem", "/does/not/exist/cert.key")
+
+	// assert
+	if err == nil {
+		t.Errorf("getTLSConfig should return an error if the certificate does not exist.")
+	}
+}
+
+// newTestHTTPSServer creates a server for a repository in a temporary directory which serves HTTPS only.
+func newTestHTTPSServer(t *testing.T, certFileName, keyFileName string) (server *Server, certificateDirectory string) {
+	baseFolder, err := ioutil.TempDir("", "allmark-https-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory: %s", err)
+	}
+
+	serverConfig := config.Default(baseFolder)
+	serverConfig.Server.HTTP.Enabled = false
+	serverConfig.Server.HTTPS.Enabled = true
+	serverConfig.Server.HTTPS.CertFileName = certFileName
+	serverConfig.Server.HTTPS.KeyFileName = keyFileName
+
+	certificateDirectory = serverConfig.CertificateDirectory()
+	if err := os.MkdirAll(certificateDirectory, 0700); err != nil {
+		t.Fatalf("Unable to create the certificate directory: %s", err)
+	}
+
+	return &Server{logger: console.New(loglevel.Off), config: *serverConfig}, certificateDirectory
+}
+
+func Test_Start_ConfiguredCertificateIsInvalid_StartupFails(t *testing.T) {
+	// arrange
+	server, certificateDirectory := newTestHTTPSServer(t, config.DefaultHTTPSCertName, config.DefaultHTTPSKeyName)
+	defer os.RemoveAll(server.config.BaseFolder())
+
+	ioutil.WriteFile(filepath.Join(certificateDirectory, config.DefaultHTTPSCertName), []byte("not a certificate"), 0600)
+	ioutil.WriteFile(filepath.Join(certificateDirectory, config.DefaultHTTPSKeyName), []byte("not a key"), 0600)
+
+	// act
+	err := <-server.Start()
+
+	// assert
+	if err == nil {
+		t.Errorf("The server should not start if the configured certificate cannot be loaded.")
+	}
+}
+
+func Test_Start_ConfiguredCertificateIsMissing_StartupFails(t *testing.T) {
+	// arrange
+	server, certificateDirectory := newTestHTTPSServer(t, "example.org.pem", "example.org.key")
+	defer os.RemoveAll(server.config.BaseFolder())
+
+	// act
+	err := <-server.Start()
+
+	// assert
+	if err == nil {
+		t.Errorf("The server should not start if the configured certificate does not exist.")
+	}
+
+	if _, statErr := os.Stat(filepath.Join(certificateDirectory, "example.org.pem")); statErr == nil {
+		t.Errorf("No certificate should have been generated for the configured certificate name.")
+	}
+}
+
+func Test_httpsEndpoint_NoCertificateConfigured_CertificateIsGenerated(t *testing.T) {
+	// arrange
+	server, _ := newTestHTTPSServer(t, "", "")
+	defer os.RemoveAll(server.config.BaseFolder())
+
+	// act
+	httpsEndpoint, enabled := server.httpsEndpoint()
+
+	// assert
+	if !enabled {
+		t.Fatalf("HTTPS should be enabled.")
+	}
+
+	if httpsEndpoint.IsConfiguredCertificate() {
+		t.Errorf("The generated certificate should not be treated as a configured certificate.")
+	}
+
+	if _, err := getTLSConfig(httpsEndpoint.CertFilePath(), httpsEndpoint.KeyFilePath()); err != nil {
+		t.Errorf("The generated certificate should be usable but returned: %s", err)
+	}
+}
+
+func Test_Start_AuthenticationEnabledWithoutHTTPS_StartupFails(t *testing.T) {
+	// arrange
+	baseFolder, err := ioutil.TempDir("", "allmark-authentication-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory: %s", err)
+	}
+	defer os.RemoveAll(baseFolder)
+
+	serverConfig := config.Default(baseFolder)
+	serverConfig.Server.HTTP.Enabled = true
+	serverConfig.Server.HTTPS.Enabled = false
+	serverConfig.Server.Authentication.Enabled = true
+	serverConfig.Server.Authentication.Username = "admin"
+	serverConfig.Server.Authentication.Password = "secret"
+
+	server := &Server{logger: console.New(loglevel.Off), config: *serverConfig}
+
+	// act
+	err = <-server.Start()
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "HTTPS") {
+		t.Errorf("The server should not start without HTTPS if authentication is enabled but returned %v.", err)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+func Test_DocumentTemplate_CommentWithHTMLAuthor_AuthorIsEscaped(t *testing.T) {
+	// arrange
+	document := newTestSocialDocument("")
+	document.Comments = []viewmodel.Comment{
+		{
+			Route:   "documents/sample/comment-1",
+			Author:  `<script>alert("author")</script>`,
+			Date:    "2015-06-01",
+			Content: "<p>Nice!</p>",
+			Replies: []viewmodel.Comment{
+				{Route: "documents/sample/comment-2", Author: "<b>Mallory</b>", Level: 1, Content: "<p>Thanks</p>"},
+			},
+		},
+	}
+
+	// act
+	html := renderTestDocument(t, document)
+
+	// assert
+	if strings.Contains(html, `<script>alert("author")`) || strings.Contains(html, "<b>Mallory</b>") {
+		t.Errorf("The comment authors should have been escaped: %s", html)
+	}
+
+	expected := []string{
+		`<span class="comment-author">&lt;script&gt;alert(&#34;author&#34;)&lt;/script&gt;</span>`,
+		`<span class="comment-author">&lt;b&gt;Mallory&lt;/b&gt;</span>`,
+	}
+
+	for _, fragment := range expected {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("The document should contain %q: %s", fragment, html)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package defaulttheme
+
+import (
+	"github.com/andreaskoch/allmark/web/view/templates/templatenames"
+)
+
+func init() {
+	templates[templatenames.AtomFeed] = atomFeedTemplate
+}
+
+var atomFeedTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+
+<id>{{.ID}}</id>
+<title><![CDATA[ {{.Title}} ]]></title>
+<subtitle><![CDATA[ {{.Description}} ]]></subtitle>
+<link href="{{.Link}}"/>
+<updated>{{.Updated}}</updated>
+
+{{ range .Items }}
+<entry>
+	<id>{{.ID}}</id>
+	<title><![CDATA[ {{.Title}} ]]></title>
+	<link href="{{.Link}}"/>
+	<updated>{{.Updated}}</updated>
+	<summary type="html"><![CDATA[ {{.Description}} ]]></summary>
+	<content type="html"><![CDATA[ {{.Content}} ]]></content>
+</entry>
+{{ end}}
+
+</feed>`
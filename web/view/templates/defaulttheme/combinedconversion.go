@@ -0,0 +1,54 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package defaulttheme
+
+import (
+	"github.com/andreaskoch/allmark/web/view/templates/templatenames"
+)
+
+func init() {
+	templates[templatenames.CombinedConversion] = combinedConverterTemplate
+}
+
+const combinedConverterTemplate = `
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex,nofollow">
+	<title>{{.Title}}</title>
+	<link rel="stylesheet" href="/theme/print.css">
+	<style type="text/css">
+		nav.toc ol {
+			list-style: none;
+		}
+
+		nav.toc li.level-2 { margin-left: 1em; }
+		nav.toc li.level-3 { margin-left: 2em; }
+		nav.toc li.level-4 { margin-left: 3em; }
+		nav.toc li.level-5 { margin-left: 4em; }
+
+		section.item {
+			page-break-before: always;
+		}
+	</style>
+</head>
+<body>
+<nav class="toc">
+<h1>{{.Title}}</h1>
+<ol>
+{{range .Sections}}<li class="level-{{.Level}}"><a href="#{{.ID}}">{{.Title}}</a></li>
+{{end}}
+</ol>
+</nav>
+
+{{range .Sections}}<section class="item {{.Type}}" id="{{.ID}}">
+<h1>{{.Title}}</h1>
+{{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+{{if .Linked}}<p><a href="{{.Route | absolute}}">{{.Route | absolute}}</a></p>{{else}}{{.Content}}{{end}}
+</section>
+{{end}}
+</body>
+</html>
+`
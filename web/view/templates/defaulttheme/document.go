@@ -38,6 +38,22 @@ const documentTemplate = `
 </section>
 {{end}}
 
+{{ if .Related }}
+<section class="related">
+	<h1>Related</h1>
+
+	<ol class="list">
+	{{range .Related}}
+	<li class="child">
+		<a href="{{.Route}}" class="child-title child-link">{{.Title}}</a>
+		<p class="child-description">{{.Description}}</p>
+	</li>
+	{{end}}
+	</ol>
+</section>
+{{end}}
+
 {{template "aliases-snippet" .}}
 {{template "tags-snippet" .}}
+{{template "comments-snippet" .}}
 `
@@ -17,7 +17,8 @@ func init() {
 		tagcloudSnippet +
 		tagsSnippet +
 		publisherSnippet +
-		aliasesSnippet
+		aliasesSnippet +
+		commentsSnippet
 
 	templates[templatenames.ToplevelNavigation] = toplevelNavigationSnippet
 	templates[templatenames.BreadcrumbNavigation] = breadcrumbNavigationSnippet
@@ -27,55 +28,73 @@ func init() {
 	templates[templatenames.Tags] = tagsSnippet
 	templates[templatenames.Publisher] = publisherSnippet
 	templates[templatenames.Aliases] = aliasesSnippet
+	templates[templatenames.Comments] = commentsSnippet
 }
 
 const masterTemplate = `<!DOCTYPE HTML>
-<html lang="{{.LanguageTag}}" itemscope itemtype="http://schema.org/WebPage" prefix="og: http://ogp.me/ns#" prefix="article: http://ogp.me/ns/article#">
+<html lang="{{html .LanguageTag}}" itemscope itemtype="http://schema.org/WebPage" prefix="og: http://ogp.me/ns#" prefix="article: http://ogp.me/ns/article#">
 <head>
-	<base href="{{ .BaseURL }}">
+	<base href="{{ html .BaseURL }}">
 
 	<title>{{.PageTitle}}</title>
-	<meta name="description" content="{{.Description}}">
+	<meta name="description" content="{{html .Description}}">
 
-	<link rel="search" type="application/opensearchdescription+xml" title="{{.RepositoryName}}" href="/opensearch.xml" />
+	<link rel="search" type="application/opensearchdescription+xml" title="{{html .RepositoryName}}" href="/opensearch.xml" />
 
 	{{if .Publisher.Name }}
-	<meta name="publisher" content="{{.Publisher.Name}}">
+	<meta name="publisher" content="{{html .Publisher.Name}}">
 	{{end}}
 
 	{{if .GeoLocation }}
 	{{if .GeoLocation.Coordinates}}
-	<meta name="geo.position" content="{{.GeoLocation.Coordinates}}">
+	<meta name="geo.position" content="{{html .GeoLocation.Coordinates}}">
 	{{end}}
 
 	{{if .GeoLocation.PlaceName}}
-	<meta name="geo.placename" content="{{.GeoLocation.PlaceName}}">
+	<meta name="geo.placename" content="{{html .GeoLocation.PlaceName}}">
 	{{end}}
 	{{end}}
 
-	<meta property="og:site_name" content="{{ .RepositoryName }}" />
+	<meta property="og:site_name" content="{{ html .RepositoryName }}" />
 	<meta property="og:type" content="article" />
-	<meta property="og:title" content="{{.PageTitle}}" />
-	<meta property="og:description" content="{{.Description}}" />
-	<meta property="og:url" content="{{ .Route | absolute }}" />
-	{{if .LanguageTag}}<meta property="og:locale" content="{{ replace .LanguageTag "-" "_" }}" />{{end}}
-	{{if .Images}}{{range .Images}}
-	<meta property="og:image" content="{{ .Route | absolute }}" />{{end}}{{end}}
-	{{if .CreationDate}}<meta property="article:published_time" content="{{.CreationDate}}" />{{end}}
-	{{if .LastModifiedDate}}<meta property="article:modified_time" content="{{.LastModifiedDate}}" />{{end}}
+	{{if .Social.Title}}
+	<meta property="og:title" content="{{html .Social.Title}}" />
+	<meta property="og:description" content="{{html .Social.Description}}" />
+	<meta property="og:url" content="{{ .Social.URL | absolute | html }}" />
+	{{if .Social.Image}}<meta property="og:image" content="{{ .Social.Image | absolute | html }}" />{{end}}
+
+	<meta name="twitter:card" content="{{html .Social.TwitterCard}}" />
+	<meta name="twitter:title" content="{{html .Social.Title}}" />
+	<meta name="twitter:description" content="{{html .Social.Description}}" />
+	{{if .Social.Image}}<meta name="twitter:image" content="{{ .Social.Image | absolute | html }}" />{{end}}
+	{{if .Publisher.TwitterHandle}}<meta name="twitter:site" content="@{{html .Publisher.TwitterHandle}}" />{{end}}
+	{{else}}
+	<meta property="og:title" content="{{html .PageTitle}}" />
+	<meta property="og:description" content="{{html .Description}}" />
+	<meta property="og:url" content="{{ .Route | absolute | html }}" />
+	{{end}}
+	{{if .LanguageTag}}<meta property="og:locale" content="{{ replace .LanguageTag "-" "_" | html }}" />{{end}}
+	{{if .CreationDate}}<meta property="article:published_time" content="{{html .CreationDate}}" />{{end}}
+	{{if .LastModifiedDate}}<meta property="article:modified_time" content="{{html .LastModifiedDate}}" />{{end}}
 	{{if .Tags}}{{range .Tags}}
-	<meta property="article:tag" content="{{ .Name }}" />{{end}}{{end}}
+	<meta property="article:tag" content="{{ html .Name }}" />{{end}}{{end}}
+
+	{{if .StructuredData}}
+	<script type="application/ld+json">{{ .StructuredData | json }}</script>
+	{{end}}
 
-	<link rel="canonical" href="{{ .Route | absolute }}">
-	<link rel="alternate" hreflang="{{.LanguageTag}}" href="{{.Route}}">
+	<link rel="canonical" href="{{ .Route | absolute | html }}">
+	<link rel="alternate" hreflang="{{html .LanguageTag}}" href="{{html .Route}}">
 	<link rel="alternate" type="application/rss+xml" title="RSS" href="/feed.rss">
+	<link rel="alternate" type="application/atom+xml" title="Atom" href="/feed.atom">
 	<link rel="shortcut icon" href="/theme/favicon.ico">
 
 	<link rel="stylesheet" href="/theme/screen.css" media="screen">
 	<link rel="stylesheet" href="/theme/print.css" media="print">
-	<link rel="stylesheet" href="/theme/codehighlighting/highlight.css" media="screen, print">
+	<link rel="stylesheet" href="/theme/darkmode.css" media="screen">
 
 	<script src="/theme/modernizr.js"></script>
+	<script src="/theme/darkmode.js"></script>
 </head>
 <body>
 
@@ -92,6 +111,9 @@ const masterTemplate = `<!DOCTYPE HTML>
 
 <article class="{{.Type}} level-{{.Level}}" itemprop="mainContentOfPage" itemscope itemtype=http://schema.org/BlogPosting>
 {{template "content" .}}
+{{if .GeoLocation.Coordinates}}
+<div class="map" data-latitude="{{.GeoLocation.Latitude}}" data-longitude="{{.GeoLocation.Longitude}}" data-zoom="{{.GeoLocation.Zoom}}" data-title="{{html .Title}}" style="display: none; height: 400px;"></div>
+{{end}}
 </article>
 
 <aside class="sidebar">
@@ -106,13 +128,14 @@ const masterTemplate = `<!DOCTYPE HTML>
 
 <div class="cleaner"></div>
 
-{{if or .PrintURL .JSONURL .MarkdownURL .DOCXURL}}
+{{if or .PrintURL .JSONURL .MarkdownURL .DOCXURL .PDFURL}}
 <aside class="export">
 <ul>
 	{{if .PrintURL}}<li><a href="{{.PrintURL}}">Print</a></li>{{end}}
 	{{if .JSONURL}}<li><a href="{{.JSONURL}}">JSON</a></li>{{end}}
 	{{if .MarkdownURL}}<li><a href="{{.MarkdownURL}}">Markdown</a></li>{{end}}
 	{{if .DOCXURL}}<li><a href="{{.DOCXURL}}">DOCX</a></li>{{end}}
+	{{if .PDFURL}}<li><a href="{{.PDFURL}}">PDF</a></li>{{end}}
 </ul>
 </aside>
 {{end}}
@@ -124,6 +147,7 @@ const masterTemplate = `<!DOCTYPE HTML>
 			<li><a href="/tags.html">Tags</a></li>
 			<li><a href="/sitemap.html">Sitemap</a></li>
 			<li><a href="/feed.rss">RSS Feed</a></li>
+			<li><a href="/feed.atom">Atom Feed</a></li>
 			<li><a href="/!">Shortlinks</a></li>
 		</ul>
 	</nav>
@@ -139,33 +163,25 @@ const masterTemplate = `<!DOCTYPE HTML>
 <script src="/theme/site.js"></script>
 <script src="/theme/typeahead.js"></script>
 <script src="/theme/search.js"></script>
+<script src="/theme/searchindex.js"></script>
 
 {{ if .IsRepositoryItem }}
 {{ if .LiveReloadEnabled }}<script src="/theme/autoupdate.js"></script>{{ end }}
+<script type="text/javascript">var presentationSettings = {{.Presentation.JSON}};</script>
 <script src="/theme/presentation.js"></script>
 <script src="/theme/latest.js"></script>
-<script src="/theme/codehighlighting/highlight.js"></script>
+{{if .GeoLocation.Coordinates}}<script src="/theme/map.js"></script>{{end}}
+<script src="/theme/codehighlighting/codehighlighting.js"></script>
+<script src="/theme/copycode.js"></script>
+<script src="/theme/toc.js"></script>
+<script src="/theme/lightbox.js"></script>
+<script type="text/javascript">var mathSettings = {{.Math.JSON}};</script>
+<script src="/theme/math.js"></script>
+<script src="/theme/mermaid.js"></script>
 <script type="text/javascript">
 $(function() {
-	// code highligting
-	$('pre code').each(function(i, block) {
-		hljs.highlightBlock(block);
-	});
-
 	// deep linking
 	addDeepLinksToElements('section.content > h1, h2, h3, h4, h5, h6');
-
-	// register a on change listener
-	if (typeof(autoupdate) === 'object' && typeof(autoupdate.onchange) === 'function') {
-		autoupdate.onchange(
-			"Code Highlighting",
-			function() {
-				$('pre code').each(function(i, block) {
-					hljs.highlightBlock(block);
-				});
-			}
-		);
-	}
 });
 </script>
 {{ end }}
@@ -222,19 +238,19 @@ const itemNavigationSnippet = `{{define "itemnavigation-snippet"}}
 {{if .ItemNavigation.IsAvailable}}
 	<div class="navelement parent">
 		{{if .ItemNavigation.Parent.Path}}
-		<a href="{{.ItemNavigation.Parent.Path}}" title="{{.ItemNavigation.Parent.Title}}">↑ Parent</a>
+		<a href="{{.ItemNavigation.Parent.Path}}" title="{{html .ItemNavigation.Parent.Title}}">↑ Parent</a>
 		{{end}}
 	</div>
 
 	<div class="navelement previous">
 		{{if .ItemNavigation.Previous.Path}}
-		<a class="previous" href="{{.ItemNavigation.Previous.Path}}" title="{{.ItemNavigation.Previous.Title}}">← Previous</a>
+		<a class="previous" href="{{.ItemNavigation.Previous.Path}}" title="{{html .ItemNavigation.Previous.Title}}">← Previous</a>
 		{{end}}
 	</div>
 
 	<div class="navelement next">
 		{{if .ItemNavigation.Next.Path}}
-		<a class="next" href="{{.ItemNavigation.Next.Path}}" title="{{.ItemNavigation.Next.Title}}">Next →</a>
+		<a class="next" href="{{.ItemNavigation.Next.Path}}" title="{{html .ItemNavigation.Next.Title}}">Next →</a>
 		{{end}}
 	</div>
 {{end}}
@@ -256,6 +272,19 @@ const childrenSnippet = `{{define "children-snippet"}}
 {{end}}
 </ol>
 {{end}}
+{{ with .ChildrenPagination }}
+{{ if gt .PageCount 1 }}
+<nav class="pagination">
+	{{ if .PreviousURL }}<a class="previous" href="{{.PreviousURL}}" rel="prev">Previous</a>{{ end }}
+	<ol>
+	{{ range .Pages }}
+	<li>{{ if .IsCurrent }}<span class="current">{{.Number}}</span>{{ else }}<a href="{{.URL}}">{{.Number}}</a>{{ end }}</li>
+	{{ end }}
+	</ol>
+	{{ if .NextURL }}<a class="next" href="{{.NextURL}}" rel="next">Next</a>{{ end }}
+</nav>
+{{ end }}
+{{ end }}
 </section>
 {{end}}
 `
@@ -302,14 +331,14 @@ const publisherSnippet = `{{define "publisher-snippet"}}
 {{if and .Author.Name .Author.URL}}
 
 	created by <span class="author" itemprop="author" rel="author">
-	<a href="{{ .Author.URL }}" title="{{ .Author.Name }}" target="_blank">
-	{{ .Author.Name }}
+	<a href="{{ html .Author.URL }}" title="{{ html .Author.Name }}" target="_blank">
+	{{html .Author.Name}}
 	</a>
 	</span>
 
 {{else if .Author.Name}}
 
-	created by <span class="author" itemprop="author" rel="author">{{ .Author.Name }}</span>
+	created by <span class="author" itemprop="author" rel="author">{{html .Author.Name}}</span>
 
 {{end}}
 {{if .CreationDate}}
@@ -349,3 +378,36 @@ const aliasesSnippet = `{{define "aliases-snippet"}}
 {{end}}
 </section>
 {{end}}`
+
+// commentsSnippet defines the templates for the comment thread of an item.
+// Replies are nested in the list of their parent comment and carry the CSS class
+// of their thread level ("level-0", "level-1", ...) so themes can indent them.
+const commentsSnippet = `{{define "comments-snippet"}}
+<section class="comments">
+{{ if .Comments }}
+	<h1>Comments</h1>
+
+	{{template "comment-thread" .Comments}}
+{{end}}
+</section>
+{{end}}
+
+{{define "comment-thread"}}
+<ol class="comment-thread">
+{{range .}}
+<li class="comment level-{{.Level}}">
+	<article>
+		<header class="comment-header">
+			{{if .Author}}<span class="comment-author">{{html .Author}}</span>{{end}}
+			{{if .Date}}<span class="comment-date">{{html .Date}}</span>{{end}}
+		</header>
+		<div class="comment-content">
+		{{.Content}}
+		</div>
+	</article>
+	{{if .Replies}}{{template "comment-thread" .Replies}}{{end}}
+</li>
+{{end}}
+</ol>
+{{end}}
+`
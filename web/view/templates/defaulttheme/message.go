@@ -0,0 +1,64 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package defaulttheme
+
+import (
+	"github.com/andreaskoch/allmark/web/view/templates/templatenames"
+)
+
+func init() {
+	templates[templatenames.Message] = messageTemplate
+	templates[templatenames.Timeline] = timelineTemplate
+}
+
+// messageTemplate is the compact layout for short posts: no headline, just the message and its timestamp.
+const messageTemplate = `
+<section class="content message" itemprop="articleBody">
+{{.Content}}
+</section>
+
+<footer class="message-footer">
+	{{if .Author.Name}}<span class="author" itemprop="author" rel="author">{{html .Author.Name}}</span>{{end}}
+	<a class="permalink" href="{{ .Permalink | absolute }}" rel="bookmark" title="Permalink">
+		<time class="creationdate" itemprop="dateCreated">{{ .CreationDate }}</time>
+	</a>
+</footer>
+
+{{template "tags-snippet" .}}
+{{template "comments-snippet" .}}
+`
+
+const timelineTemplate = `
+<header>
+<h1 class="title">
+{{.Title}}
+</h1>
+</header>
+
+<section class="description">
+{{.Description}}
+</section>
+
+<section class="content">
+
+<ol class="timeline">
+
+{{ if eq (len .Messages) 0 }}
+-- There are currently no messages in this repository --
+{{ else }}
+{{ range .Messages }}
+<li class="message">
+	<div class="message-content">
+	{{.Content}}
+	</div>
+	<a class="permalink" href="{{.Permalink}}" rel="bookmark" title="Permalink"><time>{{.Date}}</time></a>
+</li>
+{{ end }}
+{{ end }}
+
+</ol>
+
+</section>
+`
@@ -0,0 +1,45 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package defaulttheme
+
+import (
+	"github.com/andreaskoch/allmark/web/view/templates/templatenames"
+)
+
+func init() {
+	templates[templatenames.PresentationConversion] = presentationConverterTemplate
+}
+
+const presentationConverterTemplate = `
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex,nofollow">
+	<link rel="canonical" href="{{ .Route | absolute }}">
+	<link rel="stylesheet" href="/theme/print.css">
+	<style type="text/css">
+		section.slide {
+			page-break-after: always;
+			page-break-inside: avoid;
+		}
+
+		section.slide:last-child {
+			page-break-after: auto;
+		}
+
+		section.slide img {
+			max-width: 100%;
+			max-height: 90%;
+		}
+	</style>
+</head>
+<body>
+{{range .Slides}}<section class="slide">
+{{.}}
+</section>
+{{end}}
+</body>
+</html>
+`
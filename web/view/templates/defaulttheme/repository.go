@@ -28,14 +28,14 @@ const repositoryTemplate = `
 {{if and .Author.Name .Author.URL}}
 
 	by <span class="author" itemprop="author" rel="author">
-	<a href="{{ .Author.URL }}" title="{{ .Author.Name }}" target="_blank">
-	{{ .Author.Name }}
+	<a href="{{ html .Author.URL }}" title="{{ html .Author.Name }}" target="_blank">
+	{{html .Author.Name}}
 	</a>
 	</span>
 
 {{else if .Author.Name}}
 
-	created by <span class="author" itemprop="author" rel="author">{{ .Author.Name }}</span>
+	created by <span class="author" itemprop="author" rel="author">{{html .Author.Name}}</span>
 
 {{end}}
 </section>
@@ -29,9 +29,10 @@ const searchTemplate = `
 <section class="content">
 <nav>
 	<form action="/search" method="GET">
-		<input type="text" name="q" placeholder="search" value="{{.Query}}" autocomplete="off">
+		<input class="instant-search" type="text" name="q" placeholder="search" value="{{html .Query}}" autocomplete="off">
 		<input type="submit" value="Search">
 	</form>
+	<ol class="instant-search-results"></ol>
 </nav>
 
 {{if .ResultCount}}
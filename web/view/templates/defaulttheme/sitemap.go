@@ -35,7 +35,7 @@ const sitemapTemplate = `
 `
 
 var sitemapContentTemplate = fmt.Sprintf(`<li>
-	<a href="{{.Path}}" {{ if .Description }}title="{{.Description}}"{{ end }}>{{.Title}}</a>
+	<a href="{{.Path}}" {{ if .Description }}title="{{html .Description}}"{{ end }}>{{.Title}}</a>
 
 	{{ if .Children }}
 	<ul>
@@ -0,0 +1,30 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package defaulttheme
+
+import (
+	"github.com/andreaskoch/allmark/web/view/templates/templatenames"
+)
+
+func init() {
+	templates[templatenames.TagsOPML] = tagsOPMLTemplate
+}
+
+var tagsOPMLTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+<head>
+	<title>{{html .Title}}</title>
+	<dateModified>{{.DateModified}}</dateModified>
+</head>
+<body>
+{{ range .Tags }}
+	<outline text="{{html .Text}}" type="link" url="{{html .URL}}">
+	{{ range .Children }}
+		<outline text="{{html .Text}}" type="link" url="{{html .URL}}"/>
+	{{ end }}
+	</outline>
+{{ end }}
+</body>
+</opml>`
@@ -0,0 +1,84 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package templates
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+// testOPMLOutline is a node of a parsed OPML document.
+type testOPMLOutline struct {
+	Text     string            `xml:"text,attr"`
+	URL      string            `xml:"url,attr"`
+	Children []testOPMLOutline `xml:"outline"`
+}
+
+func Test_TagsOPMLTemplate_TagsAndItemsAreNestedOutlines(t *testing.T) {
+	// arrange
+	provider := NewProvider("")
+	template, err := provider.GetTagsOPMLTemplate("http://example.com")
+	if err != nil {
+		t.Fatalf("The OPML template could not be created. Error: %s", err)
+	}
+
+	opml := viewmodel.TagsOPML{
+		Title: "Docs & More - Tags",
+		Tags: []viewmodel.OPMLOutline{
+			{Text: "go", URL: "http://example.com/tags/go", Children: []viewmodel.OPMLOutline{
+				{Text: "Go \"Basics\"", URL: "http://example.com/documents/go"},
+				{Text: "Allmark", URL: "http://example.com/documents/allmark"},
+			}},
+			{Text: "c++", URL: "http://example.com/tags/c++", Children: []viewmodel.OPMLOutline{
+				{Text: "Templates <T>", URL: "http://example.com/documents/templates?a=1&b=2"},
+			}},
+		},
+	}
+
+	// act
+	buffer := new(bytes.Buffer)
+	if err := template.Execute(buffer, opml); err != nil {
+		t.Fatalf("The OPML could not be rendered. Error: %s", err)
+	}
+
+	// assert
+	var document struct {
+		Title    string            `xml:"head>title"`
+		Outlines []testOPMLOutline `xml:"body>outline"`
+	}
+
+	if err := xml.Unmarshal(buffer.Bytes(), &document); err != nil {
+		t.Fatalf("The OPML is not valid XML. Error: %s\n%s", err, buffer.String())
+	}
+
+	if document.Title != opml.Title {
+		t.Errorf("The title should be %q but was %q.", opml.Title, document.Title)
+	}
+
+	if len(document.Outlines) != len(opml.Tags) {
+		t.Fatalf("There should be %d tag outlines but there were %d.", len(opml.Tags), len(document.Outlines))
+	}
+
+	for tagIndex, tag := range opml.Tags {
+		tagOutline := document.Outlines[tagIndex]
+		if tagOutline.Text != tag.Text || tagOutline.URL != tag.URL {
+			t.Errorf("The tag outline %d should be %q (%s) but was %q (%s).", tagIndex, tag.Text, tag.URL, tagOutline.Text, tagOutline.URL)
+		}
+
+		if len(tagOutline.Children) != len(tag.Children) {
+			t.Errorf("The tag %q should have %d item outlines but had %d.", tag.Text, len(tag.Children), len(tagOutline.Children))
+			continue
+		}
+
+		for itemIndex, item := range tag.Children {
+			if itemOutline := tagOutline.Children[itemIndex]; itemOutline.Text != item.Text || itemOutline.URL != item.URL {
+				t.Errorf("The item outline should be %q (%s) but was %q (%s).", item.Text, item.URL, itemOutline.Text, itemOutline.URL)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package templates
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"text/template"
@@ -50,6 +51,11 @@ func (provider *Provider) GetAliasIndexTemplate(hostname string) (*template.Temp
 	return provider.getWrappedTemplate(templatenames.AliasIndex, hostname)
 }
 
+// GetTimelineTemplate returns the template for the message timeline.
+func (provider *Provider) GetTimelineTemplate(hostname string) (*template.Template, error) {
+	return provider.getWrappedTemplate(templatenames.Timeline, hostname)
+}
+
 // GetSitemapTemplate returns the sitemap template.
 func (provider *Provider) GetSitemapTemplate(hostname string) (*template.Template, error) {
 	return provider.getWrappedTemplate(templatenames.Sitemap, hostname)
@@ -67,7 +73,19 @@ func (provider *Provider) GetSearchTemplate(hostname string) (*template.Template
 }
 
 // GetItemTemplate returns the item template for the given item type (e.g. document, presentation).
+// Custom item types (e.g. recipe) use the template with the type name from the templates folder
+// and fall back to the document template if there is none.
 func (provider *Provider) GetItemTemplate(itemType, hostname string) (*template.Template, error) {
+	if _, exists := provider.templatedefinitions[itemType]; !exists {
+		documentTemplateCode, err := provider.getTemplateText(templatenames.Document)
+		if err != nil {
+			return nil, err
+		}
+
+		customTemplate := newTemplateDefinition(provider.folder, itemType, documentTemplateCode)
+		return provider.wrapTemplate(itemType, customTemplate.Text(), hostname)
+	}
+
 	return provider.getWrappedTemplate(itemType, hostname)
 }
 
@@ -81,11 +99,21 @@ func (provider *Provider) GetRSSTemplate(hostname string) (*template.Template, e
 	return provider.GetSimpleTemplate(templatenames.RSSFeed, hostname)
 }
 
+// GetAtomTemplate returns the template for Atom feeds.
+func (provider *Provider) GetAtomTemplate(hostname string) (*template.Template, error) {
+	return provider.GetSimpleTemplate(templatenames.AtomFeed, hostname)
+}
+
 // GetXMLSitemapTemplate returns the template for XML sitemaps.
 func (provider *Provider) GetXMLSitemapTemplate(hostname string) (*template.Template, error) {
 	return provider.GetSimpleTemplate(templatenames.XMLSitemap, hostname)
 }
 
+// GetTagsOPMLTemplate returns the template for the OPML outline of all tags.
+func (provider *Provider) GetTagsOPMLTemplate(hostname string) (*template.Template, error) {
+	return provider.GetSimpleTemplate(templatenames.TagsOPML, hostname)
+}
+
 // GetRobotsTxtTemplate returns the template for robots.txt.
 func (provider *Provider) GetRobotsTxtTemplate(hostname string) (*template.Template, error) {
 	return provider.GetSimpleTemplate(templatenames.RobotsTxt, hostname)
@@ -96,6 +124,16 @@ func (provider *Provider) GetConversionTemplate(hostname string) (*template.Temp
 	return provider.GetSimpleTemplate(templatenames.Conversion, hostname)
 }
 
+// GetPresentationConversionTemplate returns the template for the PDF conversion of presentations.
+func (provider *Provider) GetPresentationConversionTemplate(hostname string) (*template.Template, error) {
+	return provider.GetSimpleTemplate(templatenames.PresentationConversion, hostname)
+}
+
+// GetCombinedConversionTemplate returns the template for the single-page view of all documents.
+func (provider *Provider) GetCombinedConversionTemplate(hostname string) (*template.Template, error) {
+	return provider.GetSimpleTemplate(templatenames.CombinedConversion, hostname)
+}
+
 // GetOpenSearchDescriptionTemplate returns the template for conversion.
 func (provider *Provider) GetOpenSearchDescriptionTemplate(hostname string) (*template.Template, error) {
 	return provider.GetSimpleTemplate(templatenames.OpenSearchDescription, hostname)
@@ -161,14 +199,20 @@ func (provider *Provider) StoreTemplatesOnDisc() (success bool, err error) {
 // getWrappedTemplate returns the supplied template wrapped by the master template
 func (provider *Provider) getWrappedTemplate(subTemplate, hostname string) (*template.Template, error) {
 
-	// get the master template code
-	masterTemplateCode, err := provider.getTemplateText(templatenames.Master)
+	// get the sub-template code
+	subTemplateCode, err := provider.getTemplateText(subTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	// get the sub-template code
-	subTemplateCode, err := provider.getTemplateText(subTemplate)
+	return provider.wrapTemplate(subTemplate, subTemplateCode, hostname)
+}
+
+// wrapTemplate returns the supplied template code wrapped by the master template
+func (provider *Provider) wrapTemplate(subTemplate, subTemplateCode, hostname string) (*template.Template, error) {
+
+	// get the master template code
+	masterTemplateCode, err := provider.getTemplateText(templatenames.Master)
 	if err != nil {
 		return nil, err
 	}
@@ -242,9 +286,21 @@ func getTemplateHelpers(hostname string) map[string]interface{} {
 		"hostname": getHostname,
 		"absolute": getAbsoluteURL,
 		"replace":  replace,
+		"json":     toJSON,
 	}
 }
 
+// toJSON returns the JSON representation of the supplied value or an empty string if it cannot be serialized.
+// The characters <, > and & are escaped so the result can be embedded into script tags.
+func toJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
 // Replace all occurances of `textToReplace` in `text` with `replacement`.
 func replace(text, textToReplace, replacement string) string {
 	return strings.Replace(text, textToReplace, replacement, -1)
@@ -0,0 +1,185 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andreaskoch/allmark/web/view/viewmodel"
+)
+
+func renderTestDocument(t *testing.T, viewModel viewmodel.Model) string {
+	provider := NewProvider("")
+	template, err := provider.GetItemTemplate("document", "http://example.com")
+	if err != nil {
+		t.Fatalf("The document template could not be created. Error: %s", err)
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := template.Execute(buffer, viewModel); err != nil {
+		t.Fatalf("The document could not be rendered. Error: %s", err)
+	}
+
+	return buffer.String()
+}
+
+func newTestSocialDocument(image string) viewmodel.Model {
+	var document viewmodel.Model
+	document.Type = "document"
+	document.Title = "Sample Document"
+	document.Route = "documents/sample"
+	document.Social = viewmodel.SocialMetaData{
+		Title:       "Sample Document",
+		Description: "A short excerpt of the sample document.",
+		URL:         "documents/sample",
+		Image:       image,
+		TwitterCard: "summary",
+	}
+
+	if image != "" {
+		document.Social.TwitterCard = "summary_large_image"
+	}
+
+	return document
+}
+
+func Test_DocumentTemplate_DocumentWithImage_SocialMetaTagsAreRendered(t *testing.T) {
+	// arrange
+	document := newTestSocialDocument("documents/sample/files/photo.jpg")
+
+	// act
+	result := renderTestDocument(t, document)
+
+	// assert
+	expectedTags := []string{
+		`<meta property="og:title" content="Sample Document" />`,
+		`<meta property="og:description" content="A short excerpt of the sample document." />`,
+		`<meta property="og:url" content="http://example.com/documents/sample" />`,
+		`<meta property="og:image" content="http://example.com/documents/sample/files/photo.jpg" />`,
+		`<meta name="twitter:card" content="summary_large_image" />`,
+		`<meta name="twitter:title" content="Sample Document" />`,
+		`<meta name="twitter:description" content="A short excerpt of the sample document." />`,
+		`<meta name="twitter:image" content="http://example.com/documents/sample/files/photo.jpg" />`,
+	}
+
+	for _, expectedTag := range expectedTags {
+		if !strings.Contains(result, expectedTag) {
+			t.Errorf("The rendered document should contain %q.", expectedTag)
+		}
+	}
+}
+
+func Test_DocumentTemplate_DocumentWithoutImage_NoImageTagsAreRendered(t *testing.T) {
+	// arrange
+	document := newTestSocialDocument("")
+
+	// act
+	result := renderTestDocument(t, document)
+
+	// assert
+	expectedTags := []string{
+		`<meta property="og:title" content="Sample Document" />`,
+		`<meta property="og:url" content="http://example.com/documents/sample" />`,
+		`<meta name="twitter:card" content="summary" />`,
+	}
+
+	for _, expectedTag := range expectedTags {
+		if !strings.Contains(result, expectedTag) {
+			t.Errorf("The rendered document should contain %q.", expectedTag)
+		}
+	}
+
+	for _, unexpectedTag := range []string{`property="og:image"`, `name="twitter:image"`} {
+		if strings.Contains(result, unexpectedTag) {
+			t.Errorf("The rendered document should not contain %q.", unexpectedTag)
+		}
+	}
+}
+
+func Test_DocumentTemplate_QuotesAndTagsInMetaData_AttributeValuesAreEscaped(t *testing.T) {
+	// arrange
+	document := newTestSocialDocument("")
+	document.Description = `A "quoted" <description>`
+	document.Social.Title = `Fish & "Chips"`
+	document.Social.Description = `"><script>alert(1)</script>`
+
+	// act
+	result := renderTestDocument(t, document)
+
+	// assert
+	if strings.Contains(result, "<script>alert(1)</script>") {
+		t.Errorf("The social description should have been escaped.")
+	}
+
+	expectedTags := []string{
+		`<meta name="description" content="A &#34;quoted&#34; &lt;description&gt;">`,
+		`<meta property="og:title" content="Fish &amp; &#34;Chips&#34;" />`,
+		`<meta property="og:description" content="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;" />`,
+		`<meta name="twitter:title" content="Fish &amp; &#34;Chips&#34;" />`,
+		`<meta name="twitter:description" content="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;" />`,
+	}
+
+	for _, expectedTag := range expectedTags {
+		if !strings.Contains(result, expectedTag) {
+			t.Errorf("The rendered document should contain %q.", expectedTag)
+		}
+	}
+}
+
+func Test_DocumentTemplate_StructuredData_JSONLDBlockIsValid(t *testing.T) {
+	// arrange
+	document := newTestSocialDocument("")
+	document.StructuredData = &viewmodel.StructuredData{
+		Context:       "http://schema.org",
+		Type:          "Article",
+		Headline:      "Sample </script> Document",
+		DatePublished: "2015-03-01T00:00:00Z",
+		Author:        &viewmodel.StructuredDataPerson{Type: "Person", Name: "John Doe"},
+	}
+
+	// act
+	result := renderTestDocument(t, document)
+
+	// assert
+	startTag := `<script type="application/ld+json">`
+	start := strings.Index(result, startTag)
+	if start == -1 {
+		t.Fatalf("The rendered document should contain a JSON-LD block.")
+	}
+
+	code := result[start+len(startTag):]
+	code = code[:strings.Index(code, "</script>")]
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(code), &data); err != nil {
+		t.Fatalf("The JSON-LD block %q could not be parsed. Error: %s", code, err)
+	}
+
+	expectedFields := map[string]string{
+		"@context":      "http://schema.org",
+		"@type":         "Article",
+		"headline":      "Sample </script> Document",
+		"datePublished": "2015-03-01T00:00:00Z",
+	}
+
+	for name, expectedValue := range expectedFields {
+		if data[name] != expectedValue {
+			t.Errorf("The field %q should be %q but was %v.", name, expectedValue, data[name])
+		}
+	}
+
+	if author, ok := data["author"].(map[string]interface{}); !ok || author["name"] != "John Doe" {
+		t.Errorf("The author should be %q but was %v.", "John Doe", data["author"])
+	}
+
+	for _, omittedField := range []string{"url", "image", "geo"} {
+		if _, exists := data[omittedField]; exists {
+			t.Errorf("The unavailable field %q should be omitted.", omittedField)
+		}
+	}
+}
@@ -13,17 +13,24 @@ const (
 	Document     = "document"
 	Presentation = "presentation"
 	Repository   = "repository"
+	Message      = "message"
 
 	Sitemap      = "sitemap"
 	SitemapEntry = "sitemap-entry"
 
 	XMLSitemap = "xmlsitemap"
 	RSSFeed    = "rssfeed"
+	AtomFeed   = "atomfeed"
 	TagMap     = "tagmap"
+	TagsOPML   = "tagsopml"
 	AliasIndex = "aliasindex"
+	Timeline   = "timeline"
 	Search     = "search"
 	Conversion = "converter"
-	RobotsTxt  = "robotstxt"
+
+	PresentationConversion = "presentationconverter"
+	CombinedConversion     = "combinedconverter"
+	RobotsTxt              = "robotstxt"
 
 	Aliases              = "aliases-snippet"
 	Tags                 = "tags-snippet"
@@ -31,6 +38,7 @@ const (
 	ToplevelNavigation   = "toplevelnavigation-snippet"
 	BreadcrumbNavigation = "breadcrumbnavigation-snippet"
 	ItemNavigation       = "itemnavigation-snippet"
-	Children               = "children-snippet"
+	Children             = "children-snippet"
 	TagCloud             = "tagcloud-snippet"
+	Comments             = "comments-snippet"
 )
@@ -0,0 +1,227 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/common/util/fsutil"
+)
+
+const (
+	// ManifestFileName is the name of the file which describes a theme in its folder.
+	ManifestFileName = "theme.json"
+
+	// maxBaseThemeDepth limits the chain of base themes (e.g. a -> b -> default).
+	maxBaseThemeDepth = 10
+)
+
+// A Manifest describes a theme which is stored in a sub folder of the themes folder
+// (e.g. "themes/solarized/theme.json").
+type Manifest struct {
+	// Name is the name of the theme which is used to select it (default: the folder name).
+	Name string `json:"name"`
+
+	// Description is an optional description of the theme.
+	Description string `json:"description"`
+
+	// Base is the name of the theme which provides the default files for all files
+	// which are not contained in this theme (default: "default").
+	Base string `json:"base"`
+
+	// Files contains the paths of the theme files relative to the theme folder (e.g. "screen.css").
+	// If empty, all files of the theme folder belong to the theme.
+	Files []string `json:"files"`
+}
+
+// builtInThemes returns the themes which are compiled into allmark.
+func builtInThemes() map[string]*Theme {
+	return map[string]*Theme{
+		config.DefaultThemeName: defaultTheme,
+	}
+}
+
+// GetThemeByName returns the theme with the given name. The theme is either one of the built-in themes
+// or a theme with a manifest in a sub folder of the given themes folder. An empty name selects the default theme.
+// If there is no theme with the given name an error listing the available themes is returned.
+func GetThemeByName(themesFolder, name string) (*Theme, error) {
+	if strings.TrimSpace(name) == "" {
+		name = config.DefaultThemeName
+	}
+
+	manifests, err := loadManifests(themesFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	return getTheme(name, manifests, 0)
+}
+
+func getTheme(name string, manifests map[string]manifestInfo, depth int) (*Theme, error) {
+	if depth > maxBaseThemeDepth {
+		return nil, fmt.Errorf("The base themes of theme %q are nested too deeply (or contain a cycle).", name)
+	}
+
+	// the themes folder can override built-in themes
+	manifest, isThemeFolder := manifests[name]
+	if !isThemeFolder {
+		if theme, isBuiltIn := builtInThemes()[name]; isBuiltIn {
+			return theme, nil
+		}
+
+		return nil, fmt.Errorf("The theme %q does not exist. Available themes: %s", name, strings.Join(getAvailableThemeNames(manifests), ", "))
+	}
+
+	baseName := manifest.Base
+	if strings.TrimSpace(baseName) == "" {
+		baseName = config.DefaultThemeName
+	}
+
+	var base *Theme
+	if baseName != name {
+		var err error
+		base, err = getTheme(baseName, manifests, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot load the base theme of theme %q. Error: %s", name, err.Error())
+		}
+	} else {
+		base = defaultTheme
+	}
+
+	files, err := loadThemeFiles(manifest.folder, manifest.Files)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot load the files of theme %q. Error: %s", name, err.Error())
+	}
+
+	return &Theme{
+		Name:        name,
+		Description: manifest.Description,
+		Files:       files,
+		base:        base,
+	}, nil
+}
+
+// A manifestInfo is a manifest together with the folder it has been loaded from.
+type manifestInfo struct {
+	Manifest
+	folder string
+}
+
+// loadManifests reads the manifests of all sub folders of the given themes folder.
+// Sub folders without a manifest are ignored. The manifests are indexed by theme name.
+func loadManifests(themesFolder string) (map[string]manifestInfo, error) {
+	manifests := make(map[string]manifestInfo)
+	if !fsutil.DirectoryExists(themesFolder) {
+		return manifests, nil
+	}
+
+	entries, err := ioutil.ReadDir(themesFolder)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read the themes folder %q. Error: %s", themesFolder, err.Error())
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		folder := filepath.Join(themesFolder, entry.Name())
+		manifestFilePath := filepath.Join(folder, ManifestFileName)
+		if !fsutil.FileExists(manifestFilePath) {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(manifestFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot read the theme manifest %q. Error: %s", manifestFilePath, err.Error())
+		}
+
+		var manifest Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("The theme manifest %q is invalid. Error: %s", manifestFilePath, err.Error())
+		}
+
+		if strings.TrimSpace(manifest.Name) == "" {
+			manifest.Name = entry.Name()
+		}
+
+		manifests[manifest.Name] = manifestInfo{manifest, folder}
+	}
+
+	return manifests, nil
+}
+
+// loadThemeFiles reads the given files (or all files if none are given) of the supplied theme folder into memory.
+func loadThemeFiles(folder string, paths []string) ([]*ThemeFile, error) {
+	if len(paths) == 0 {
+		err := filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				return nil
+			}
+
+			relativePath, err := filepath.Rel(folder, path)
+			if err != nil {
+				return err
+			}
+
+			if relativePath != ManifestFileName {
+				paths = append(paths, filepath.ToSlash(relativePath))
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]*ThemeFile, 0, len(paths))
+	for _, path := range paths {
+		filePath := filepath.Join(folder, filepath.FromSlash(path))
+		if !strings.HasPrefix(filePath, filepath.Clean(folder)+string(filepath.Separator)) {
+			return nil, fmt.Errorf("The theme file %q is outside of the theme folder.", path)
+		}
+
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, &ThemeFile{
+			path: filepath.ToSlash(path),
+			data: data,
+		})
+	}
+
+	return files, nil
+}
+
+func getAvailableThemeNames(manifests map[string]manifestInfo) []string {
+	names := make([]string, 0)
+	for name := range builtInThemes() {
+		if _, isOverridden := manifests[name]; !isOverridden {
+			names = append(names, name)
+		}
+	}
+
+	for name := range manifests {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
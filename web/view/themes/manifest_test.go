@@ -0,0 +1,110 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestThemesFolder(t *testing.T, files map[string]string) string {
+	themesFolder, err := ioutil.TempDir("", "allmark-themes-test")
+	if err != nil {
+		t.Fatalf("Could not create the themes folder. Error: %s", err)
+	}
+
+	for path, content := range files {
+		filePath := filepath.Join(themesFolder, filepath.FromSlash(path))
+		os.MkdirAll(filepath.Dir(filePath), 0700)
+		if err := ioutil.WriteFile(filePath, []byte(content), 0600); err != nil {
+			t.Fatalf("Could not create the file %q. Error: %s", filePath, err)
+		}
+	}
+
+	return themesFolder
+}
+
+func Test_GetThemeByName_EmptyName_DefaultThemeIsReturned(t *testing.T) {
+	// act
+	theme, err := GetThemeByName("", "")
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetThemeByName should not return an error but returned: %s", err)
+	}
+
+	if theme != GetTheme() {
+		t.Errorf("GetThemeByName should return the default theme but returned %q.", theme.Name)
+	}
+}
+
+func Test_GetThemeByName_ThemeWithManifest_FilesOverrideTheBaseTheme(t *testing.T) {
+	// arrange
+	themesFolder := newTestThemesFolder(t, map[string]string{
+		"solarized/theme.json": `{"name": "solarized", "description": "Solarized colors"}`,
+		"solarized/screen.css": "body { background: #fdf6e3; }",
+	})
+	defer os.RemoveAll(themesFolder)
+
+	// act
+	theme, err := GetThemeByName(themesFolder, "solarized")
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetThemeByName should not return an error but returned: %s", err)
+	}
+
+	if data := string(theme.Get("screen.css").Data()); data != "body { background: #fdf6e3; }" {
+		t.Errorf("The theme should contain its own screen.css but contained %q.", data)
+	}
+
+	if theme.Get("presentation.js") == nil {
+		t.Errorf("Files which are not part of the theme should be provided by the default theme.")
+	}
+
+	if theme.Get(ManifestFileName) != nil {
+		t.Errorf("The manifest should not be served as a theme file.")
+	}
+}
+
+func Test_GetThemeByName_UnknownTheme_ErrorListsTheAvailableThemes(t *testing.T) {
+	// arrange
+	themesFolder := newTestThemesFolder(t, map[string]string{
+		"solarized/theme.json": `{}`,
+	})
+	defer os.RemoveAll(themesFolder)
+
+	// act
+	_, err := GetThemeByName(themesFolder, "does-not-exist")
+
+	// assert
+	if err == nil {
+		t.Fatalf("GetThemeByName should return an error for an unknown theme.")
+	}
+
+	if !strings.Contains(err.Error(), "does-not-exist") || !strings.Contains(err.Error(), "default, solarized") {
+		t.Errorf("The error should name the theme and list the available themes but was %q.", err.Error())
+	}
+}
+
+func Test_GetThemeByName_BaseThemeCycle_ErrorIsReturned(t *testing.T) {
+	// arrange
+	themesFolder := newTestThemesFolder(t, map[string]string{
+		"a/theme.json": `{"base": "b"}`,
+		"b/theme.json": `{"base": "a"}`,
+	})
+	defer os.RemoveAll(themesFolder)
+
+	// act
+	_, err := GetThemeByName(themesFolder, "a")
+
+	// assert
+	if err == nil {
+		t.Errorf("GetThemeByName should return an error for cyclic base themes.")
+	}
+}
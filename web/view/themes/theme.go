@@ -4,16 +4,25 @@
 
 package themes
 
+import (
+	"sort"
+)
+
 func GetTheme() *Theme {
 	return defaultTheme
 }
 
 type Theme struct {
-	Name  string
-	Files []*ThemeFile
+	Name        string
+	Description string
+	Files       []*ThemeFile
+
+	// base is the theme which provides all files this theme does not contain
+	base *Theme
 }
 
 // Get the theme file that matches the specified uri (e.g. "favicon.ico"); returns nil if the theme file was not found.
+// Files which are not part of the theme are looked up in the base theme.
 func (theme *Theme) Get(uri string) *ThemeFile {
 	for _, themeFile := range theme.Files {
 		if themeFile.Path() == uri {
@@ -21,9 +30,31 @@ func (theme *Theme) Get(uri string) *ThemeFile {
 		}
 	}
 
+	if theme.base != nil {
+		return theme.base.Get(uri)
+	}
+
 	return nil
 }
 
+// Paths returns the paths of all files of the theme and its base themes in alphabetical order.
+func (theme *Theme) Paths() []string {
+	distinctPaths := make(map[string]bool)
+	for current := theme; current != nil; current = current.base {
+		for _, themeFile := range current.Files {
+			distinctPaths[themeFile.Path()] = true
+		}
+	}
+
+	paths := make([]string, 0, len(distinctPaths))
+	for path := range distinctPaths {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
 func (theme *Theme) StoreOnDisc(baseFolder string) (success bool, err error) {
 	for _, file := range theme.Files {
 		if ok, err := file.StoreOnDisc(baseFolder); !ok {
@@ -135,6 +135,12 @@ const AutoupdateJs = `var Autoupdate = (function () {
             // unwrap the message
             message = JSON.parse(evt.data);
 
+            // reload the page if requested (e.g. because the item has been removed)
+            if (message !== null && typeof(message) === 'object' && message.name === "reload") {
+                document.location.reload();
+                return;
+            }
+
             // check if all required fields are present
             if (message === null || typeof(message) !== 'object' || typeof(message.route) !== 'string' || message.model === null || typeof(message.model) !== 'object') {
                 console.log("Invalid response format.", message);
@@ -0,0 +1,115 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const CodeHighlightingJs = `
+/**
+ * Highlights the code blocks of the current page. highlight.js and its style sheet
+ * are only loaded if the page contains code blocks.
+ */
+var CodeHighlighting = (function() {
+
+  // diagrams (see mermaid.js) are not highlighted
+  var codeBlockSelector = "pre code:not(.language-mermaid)";
+
+  // the highlight.js loading state (0: not loaded, 1: loading, 2: loaded)
+  var loadingState = 0;
+  var pendingCallbacks = [];
+
+  /**
+   * Load highlight.js and execute the callback as soon as it is available
+   */
+  var loadHighlighter = function(callback) {
+    if (loadingState === 2) {
+      callback();
+      return;
+    }
+
+    pendingCallbacks.push(callback);
+    if (loadingState === 1) {
+      return;
+    }
+
+    loadingState = 1;
+    appendStyleSheet("/theme/codehighlighting/highlight.css");
+    $.getScript("/theme/codehighlighting/highlight.js", function() {
+      loadingState = 2;
+
+      var callbacks = pendingCallbacks;
+      pendingCallbacks = [];
+      for (var i = 0; i < callbacks.length; i++) {
+        callbacks[i]();
+      }
+    });
+  };
+
+  /**
+   * Highlight a single code block. The language is taken from the
+   * language class of fenced code blocks (e.g. "language-go" or "go") if present,
+   * otherwise highlight.js detects the language automatically.
+   */
+  var highlightBlock = function(block) {
+    var language = getLanguage(block);
+    if (language !== "" && !hljs.getLanguage(language)) {
+      // unknown language: use auto-detection
+      block.className = "";
+    }
+
+    hljs.highlightBlock(block);
+  };
+
+  /**
+   * Get the language name from the class of the supplied code block or an empty string
+   */
+  var getLanguage = function(block) {
+    var classes = (block.className || "").split(/\s+/);
+    for (var i = 0; i < classes.length; i++) {
+      var className = classes[i].replace(/^lang(uage)?-/, "");
+      if (className !== "" && className !== "hljs") {
+        return className;
+      }
+    }
+
+    return "";
+  };
+
+  /**
+   * Highlight all code blocks of the current page
+   */
+  var highlight = function() {
+    var blocks = $(codeBlockSelector);
+    if (blocks.length === 0) {
+      return;
+    }
+
+    loadHighlighter(function() {
+      $(codeBlockSelector).each(function(i, block) {
+        highlightBlock(block);
+      });
+    });
+  };
+
+  return {
+    highlight: highlight
+  };
+
+})();
+
+$(function() {
+
+  CodeHighlighting.highlight();
+
+  // re-highlight the code blocks when the content changes
+  if (typeof(autoupdate) === 'object' && typeof(autoupdate.onchange) === 'function') {
+    autoupdate.onchange(
+      "Code Highlighting",
+      function() {
+        CodeHighlighting.highlight();
+      }
+    );
+  }
+
+});
+`
@@ -0,0 +1,117 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const CopyCodeJs = `
+/**
+ * Adds a "Copy" button to all code blocks which copies the code to the clipboard.
+ */
+var CopyCode = (function() {
+
+  var buttonClass = "copy-code-button";
+
+  /**
+   * Get the raw text of the supplied pre element (without the markup of the code highlighter)
+   */
+  var getCode = function(pre) {
+    var code = $(pre).children("code");
+    if (code.length > 0) {
+      return code[0].textContent;
+    }
+
+    return $(pre).clone().children("." + buttonClass).remove().end()[0].textContent;
+  };
+
+  /**
+   * Select the text of the supplied pre element so the user can copy it manually
+   */
+  var selectCode = function(pre) {
+    var element = $(pre).children("code")[0] || pre;
+    if (!window.getSelection || !document.createRange) {
+      return false;
+    }
+
+    var range = document.createRange();
+    range.selectNodeContents(element);
+
+    var selection = window.getSelection();
+    selection.removeAllRanges();
+    selection.addRange(range);
+
+    try {
+      return document.execCommand("copy");
+    } catch (e) {
+      return false;
+    }
+  };
+
+  var showConfirmation = function(button, text) {
+    button.text(text);
+    setTimeout(function() {
+      button.text("Copy");
+    }, 1500);
+  };
+
+  var copy = function(button, pre) {
+    var code = getCode(pre);
+
+    if (navigator.clipboard && typeof(navigator.clipboard.writeText) === 'function') {
+      navigator.clipboard.writeText(code).then(
+        function() {
+          showConfirmation(button, "Copied!");
+        },
+        function() {
+          showConfirmation(button, selectCode(pre) ? "Copied!" : "Press Ctrl+C");
+        }
+      );
+
+      return;
+    }
+
+    // fallback: select the code
+    showConfirmation(button, selectCode(pre) ? "Copied!" : "Press Ctrl+C");
+  };
+
+  /**
+   * Add the copy buttons to all code blocks which don't have one yet
+   */
+  var attach = function() {
+    $("pre").each(function(i, pre) {
+      if ($(pre).children("." + buttonClass).length > 0) {
+        return;
+      }
+
+      var button = $('<button type="button" class="' + buttonClass + '" title="Copy to clipboard">Copy</button>');
+      button.on("click", function(e) {
+        e.preventDefault();
+        copy(button, pre);
+      });
+
+      $(pre).addClass("copy-code").prepend(button);
+    });
+  };
+
+  return {
+    attach: attach
+  };
+
+})();
+
+$(function() {
+
+  CopyCode.attach();
+
+  // re-attach the buttons when the content changes
+  if (typeof(autoupdate) === 'object' && typeof(autoupdate.onchange) === 'function') {
+    autoupdate.onchange(
+      "Copy Code",
+      function() {
+        CopyCode.attach();
+      }
+    );
+  }
+
+});
+`
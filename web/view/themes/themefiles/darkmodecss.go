@@ -0,0 +1,177 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const DarkModeCss = `
+/* the dark theme is active if the html element has the attribute data-theme="dark" (see darkmode.js) */
+html[data-theme="dark"] {
+    background: #1b1d1f;
+}
+
+html[data-theme="dark"] body {
+    color: #d6d6d6;
+    background: #222427;
+    outline-color: #1b1d1f;
+}
+
+html[data-theme="dark"] h1,
+html[data-theme="dark"] h2,
+html[data-theme="dark"] h3,
+html[data-theme="dark"] h4,
+html[data-theme="dark"] h5,
+html[data-theme="dark"] h6 {
+    color: #ececec;
+}
+
+html[data-theme="dark"] a {
+    color: #6ea8fe;
+}
+
+html[data-theme="dark"] a:visited {
+    color: #b392f0;
+}
+
+html[data-theme="dark"] a:hover {
+    color: #9ec5fe;
+}
+
+html[data-theme="dark"] pre,
+html[data-theme="dark"] code,
+html[data-theme="dark"] kbd,
+html[data-theme="dark"] samp {
+    color: #e6e6e6;
+    background-color: #2d3034;
+    border-color: #44484d;
+}
+
+html[data-theme="dark"] pre code {
+    background-color: transparent;
+}
+
+html[data-theme="dark"] hr {
+    border-color: #44484d;
+}
+
+html[data-theme="dark"] blockquote {
+    color: #b8b8b8;
+    border-color: #44484d;
+}
+
+html[data-theme="dark"] body>nav.toplevel>ul>li,
+html[data-theme="dark"] .typeahead,
+html[data-theme="dark"] .tt-dropdown-menu {
+    color: #d6d6d6;
+    background-color: #2d3034;
+}
+
+html[data-theme="dark"] body>aside.export>ul>li>a,
+html[data-theme="dark"] body>footer>nav>ul>li>a {
+    color: #d6d6d6;
+}
+
+html[data-theme="dark"] aside.sidebar>.children>.list>.child:nth-child(odd) {
+    background-color: #2a2d30;
+}
+
+html[data-theme="dark"] .tree li {
+    background-color: transparent;
+}
+
+html[data-theme="dark"] .csv>table,
+html[data-theme="dark"] .csv>table thead {
+    border-color: #44484d;
+    color: #9ec5fe;
+}
+
+html[data-theme="dark"] .csv>table td {
+    color: #d6d6d6;
+}
+
+html[data-theme="dark"] .csv>table tbody tr:hover td {
+    background: #2d3034;
+}
+
+/* keep images and image galleries legible */
+html[data-theme="dark"] .imagegallery img,
+html[data-theme="dark"] .content img {
+    background-color: #ffffff;
+    border-color: #44484d;
+}
+
+/* code highlighting */
+html[data-theme="dark"] .hljs {
+    color: #d6d6d6;
+    background: #2d3034;
+}
+
+html[data-theme="dark"] .hljs-comment,
+html[data-theme="dark"] .hljs-template_comment,
+html[data-theme="dark"] .hljs-doctype,
+html[data-theme="dark"] .hljs-pi {
+    color: #8b949e;
+}
+
+html[data-theme="dark"] .hljs-keyword,
+html[data-theme="dark"] .hljs-tag,
+html[data-theme="dark"] .hljs-title,
+html[data-theme="dark"] .hljs-built_in {
+    color: #ff7b72;
+}
+
+html[data-theme="dark"] .hljs-string,
+html[data-theme="dark"] .hljs-attribute,
+html[data-theme="dark"] .hljs-value {
+    color: #a5d6ff;
+}
+
+html[data-theme="dark"] .hljs-number,
+html[data-theme="dark"] .hljs-literal,
+html[data-theme="dark"] .hljs-variable {
+    color: #79c0ff;
+}
+
+html[data-theme="dark"] nav.toc {
+    background: #2a2d30;
+    border-color: #44484d;
+}
+
+html[data-theme="dark"] .copy-code-button {
+    color: #d6d6d6;
+    background: #222427;
+    border-color: #44484d;
+}
+
+/* the theme toggle */
+.theme-toggle {
+    position: fixed;
+    right: 1em;
+    bottom: 1em;
+    z-index: 1000;
+    padding: 0.3em 0.6em;
+    font-size: 1.2em;
+    line-height: 1em;
+    color: #444;
+    background: #fefefe;
+    border: 1px solid #ccc;
+    border-radius: 3px;
+    cursor: pointer;
+}
+
+.theme-toggle:focus {
+    outline: 2px solid #0097cf;
+}
+
+html[data-theme="dark"] .theme-toggle {
+    color: #d6d6d6;
+    background: #2d3034;
+    border-color: #44484d;
+}
+
+@media print {
+    .theme-toggle {
+        display: none;
+    }
+}
+`
@@ -0,0 +1,134 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const DarkModeJs = `
+/**
+ * Switches between the light and the dark theme and remembers the choice of the user.
+ * This script is loaded in the head of the document so the theme is applied before the page is rendered.
+ */
+var DarkMode = (function() {
+
+  var storageKey = "allmark-theme";
+  var themeAttribute = "data-theme";
+
+  /**
+   * Get the theme ("light" or "dark") which has been stored in the local storage or null
+   */
+  var getStoredTheme = function() {
+    try {
+      return window.localStorage.getItem(storageKey);
+    } catch (e) {
+      // local storage is not available (e.g. in private mode)
+      return null;
+    }
+  };
+
+  /**
+   * Remember the supplied theme
+   */
+  var storeTheme = function(theme) {
+    try {
+      window.localStorage.setItem(storageKey, theme);
+    } catch (e) {
+    }
+  };
+
+  /**
+   * Get the theme that should be used initially: the stored theme or the preferred color scheme of the system
+   */
+  var getInitialTheme = function() {
+    var storedTheme = getStoredTheme();
+    if (storedTheme === "light" || storedTheme === "dark") {
+      return storedTheme;
+    }
+
+    if (window.matchMedia && window.matchMedia("(prefers-color-scheme: dark)").matches) {
+      return "dark";
+    }
+
+    return "light";
+  };
+
+  var getTheme = function() {
+    return document.documentElement.getAttribute(themeAttribute) || "light";
+  };
+
+  var updateToggle = function(toggle, theme) {
+    var isDark = theme === "dark";
+    toggle.setAttribute("aria-pressed", isDark ? "true" : "false");
+    toggle.setAttribute("title", isDark ? "Switch to the light theme" : "Switch to the dark theme");
+    toggle.innerHTML = isDark ? "&#9788;" : "&#9790;";
+  };
+
+  /**
+   * Apply the supplied theme and notify listeners with a "themechange" event (e.g. the diagrams)
+   */
+  var setTheme = function(theme) {
+    var previousTheme = document.documentElement.getAttribute(themeAttribute);
+    document.documentElement.setAttribute(themeAttribute, theme);
+
+    if (previousTheme !== null && previousTheme !== theme && typeof(window.CustomEvent) === 'function') {
+      document.dispatchEvent(new CustomEvent("themechange", { detail: { theme: theme } }));
+    }
+
+    var toggle = document.querySelector(".theme-toggle");
+    if (toggle) {
+      updateToggle(toggle, theme);
+    }
+  };
+
+  /**
+   * Add the toggle button to the page. A button element is focusable
+   * and can be activated with the enter and space keys.
+   */
+  var addToggle = function() {
+    var toggle = document.createElement("button");
+    toggle.setAttribute("type", "button");
+    toggle.setAttribute("aria-label", "Dark mode");
+    toggle.className = "theme-toggle";
+    updateToggle(toggle, getTheme());
+
+    toggle.addEventListener("click", function() {
+      var theme = getTheme() === "dark" ? "light" : "dark";
+      storeTheme(theme);
+      setTheme(theme);
+    });
+
+    document.body.appendChild(toggle);
+  };
+
+  // apply the theme before the page is rendered
+  setTheme(getInitialTheme());
+
+  // follow changes of the system preference as long as the user has not made a choice
+  if (window.matchMedia) {
+    var colorSchemeQuery = window.matchMedia("(prefers-color-scheme: dark)");
+    var onColorSchemeChange = function(e) {
+      if (getStoredTheme() === null) {
+        setTheme(e.matches ? "dark" : "light");
+      }
+    };
+
+    if (colorSchemeQuery.addEventListener) {
+      colorSchemeQuery.addEventListener("change", onColorSchemeChange);
+    } else if (colorSchemeQuery.addListener) {
+      colorSchemeQuery.addListener(onColorSchemeChange);
+    }
+  }
+
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", addToggle);
+  } else {
+    addToggle();
+  }
+
+  return {
+    getTheme: getTheme,
+    setTheme: setTheme
+  };
+
+})();
+`
@@ -0,0 +1,220 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const LightboxJs = `
+/**
+ * Opens the images of image galleries in a full-size overlay
+ * with next/previous navigation (buttons, arrow keys and swipes).
+ */
+var Lightbox = (function() {
+
+  var imageSelector = "a.imagegallery-image";
+  var swipeDistance = 50;
+
+  var overlay = null;
+  var images = [];
+  var currentIndex = 0;
+  var previouslyFocusedElement = null;
+
+  /**
+   * Get the caption of the supplied gallery link (the alt text of the thumbnail or the link title)
+   */
+  var getCaption = function(link) {
+    return $(link).find("img").attr("alt") || $(link).attr("title") || "";
+  };
+
+  var preload = function(index) {
+    if (index < 0 || index >= images.length) {
+      return;
+    }
+
+    var image = new Image();
+    image.src = images[index].href;
+  };
+
+  var show = function(index) {
+    if (images.length === 0) {
+      return;
+    }
+
+    currentIndex = (index + images.length) % images.length;
+    var image = images[currentIndex];
+
+    overlay.find(".lightbox-image").attr("src", image.href).attr("alt", image.caption);
+    overlay.find(".lightbox-caption").text(image.caption);
+    overlay.find(".lightbox-counter").text((currentIndex + 1) + " / " + images.length);
+    overlay.find(".lightbox-previous, .lightbox-next").toggle(images.length > 1);
+
+    // preload the adjacent images
+    preload(currentIndex + 1);
+    preload(currentIndex - 1);
+  };
+
+  var next = function() {
+    show(currentIndex + 1);
+  };
+
+  var previous = function() {
+    show(currentIndex - 1);
+  };
+
+  var close = function() {
+    overlay.hide().attr("aria-hidden", "true");
+    $("body").removeClass("lightbox-open");
+
+    if (previouslyFocusedElement) {
+      previouslyFocusedElement.focus();
+      previouslyFocusedElement = null;
+    }
+  };
+
+  var open = function(link) {
+    var gallery = $(link).attr("data-gallery");
+
+    images = [];
+    $(imageSelector).filter(function() {
+      return $(this).attr("data-gallery") === gallery;
+    }).each(function(i, galleryLink) {
+      if (galleryLink === link) {
+        currentIndex = i;
+      }
+
+      images.push({ href: galleryLink.href, caption: getCaption(galleryLink) });
+    });
+
+    previouslyFocusedElement = document.activeElement;
+
+    createOverlay();
+    show(currentIndex);
+
+    overlay.show().attr("aria-hidden", "false");
+    $("body").addClass("lightbox-open");
+    overlay.find(".lightbox-close").focus();
+  };
+
+  /**
+   * Keep the focus inside of the overlay while it is open
+   */
+  var trapFocus = function(e) {
+    var focusableElements = overlay.find("button:visible");
+    if (focusableElements.length === 0) {
+      return;
+    }
+
+    var first = focusableElements.first()[0];
+    var last = focusableElements.last()[0];
+
+    if (e.shiftKey && document.activeElement === first) {
+      e.preventDefault();
+      last.focus();
+    } else if (!e.shiftKey && document.activeElement === last) {
+      e.preventDefault();
+      first.focus();
+    }
+  };
+
+  var createOverlay = function() {
+    if (overlay !== null) {
+      return;
+    }
+
+    overlay = $('<div class="lightbox" role="dialog" aria-modal="true" aria-label="Image viewer" aria-hidden="true" style="display: none;">' +
+      '<button type="button" class="lightbox-close" title="Close (Esc)" aria-label="Close">&#215;</button>' +
+      '<button type="button" class="lightbox-previous" title="Previous image" aria-label="Previous image">&#8592;</button>' +
+      '<figure><img class="lightbox-image" src="" alt=""><figcaption><span class="lightbox-caption"></span> <span class="lightbox-counter"></span></figcaption></figure>' +
+      '<button type="button" class="lightbox-next" title="Next image" aria-label="Next image">&#8594;</button>' +
+      '</div>');
+
+    overlay.find(".lightbox-close").on("click", close);
+    overlay.find(".lightbox-previous").on("click", previous);
+    overlay.find(".lightbox-next").on("click", next);
+
+    // close the overlay when the background is clicked
+    overlay.on("click", function(e) {
+      if (e.target === overlay[0] || $(e.target).is("figure")) {
+        close();
+      }
+    });
+
+    overlay.on("keydown", function(e) {
+      switch (e.which) {
+        case 27: // escape
+          e.preventDefault();
+          close();
+          break;
+
+        case 37: // left arrow
+          e.preventDefault();
+          previous();
+          break;
+
+        case 39: // right arrow
+          e.preventDefault();
+          next();
+          break;
+
+        case 9: // tab
+          trapFocus(e);
+          break;
+      }
+    });
+
+    // swipe navigation
+    var touchStart = null;
+    overlay[0].addEventListener("touchstart", function(e) {
+      if (e.touches.length === 1) {
+        touchStart = { x: e.touches[0].clientX, y: e.touches[0].clientY };
+      }
+    }, false);
+
+    overlay[0].addEventListener("touchend", function(e) {
+      if (touchStart === null || e.changedTouches.length !== 1) {
+        return;
+      }
+
+      var deltaX = e.changedTouches[0].clientX - touchStart.x;
+      var deltaY = e.changedTouches[0].clientY - touchStart.y;
+      touchStart = null;
+
+      if (Math.abs(deltaX) < swipeDistance || Math.abs(deltaX) < Math.abs(deltaY)) {
+        return;
+      }
+
+      if (deltaX < 0) {
+        next();
+      } else {
+        previous();
+      }
+    }, false);
+
+    $("body").append(overlay);
+  };
+
+  /**
+   * Open the gallery images in the lightbox instead of following the link
+   */
+  var attach = function() {
+    $(document).off("click.lightbox").on("click.lightbox", imageSelector, function(e) {
+      if (e.ctrlKey || e.metaKey || e.shiftKey || e.which === 2) {
+        // let the browser open the image in a new tab
+        return;
+      }
+
+      e.preventDefault();
+      open(this);
+    });
+  };
+
+  return {
+    attach: attach
+  };
+
+})();
+
+$(function() {
+  Lightbox.attach();
+});
+`
@@ -0,0 +1,91 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const MapJs = `
+$(function() {
+
+	var mapSelector = 'article .map';
+
+	// abort if there is no map container
+	if ($(mapSelector).length === 0) {
+		return;
+	}
+
+	// the leaflet distribution (with the subresource integrity hashes of its files)
+	// and the local copy in the vendor folder of the theme
+	var leafletSources = {
+		"leaflet.css": [
+			{ url: "https://unpkg.com/leaflet@1.9.4/dist/leaflet.css", integrity: "sha256-p4NxAoJBhIIN+hmNHrzRCf9tD/miZyoHS5obTRR9BMY=" },
+			{ url: vendorPath + "/leaflet/leaflet.css" }
+		],
+		"leaflet.js": [
+			{ url: "https://unpkg.com/leaflet@1.9.4/dist/leaflet.js", integrity: "sha256-20nQCchB9co0qIjJZRGuk2/Z9VM+kNiyxNV1lvTlZBo=" },
+			{ url: vendorPath + "/leaflet/leaflet.js" }
+		]
+	};
+
+	/**
+	 * Parse a coordinate from the supplied value
+	 * @param string value The coordinate value (e.g. "48.137")
+	 * @param int limit The maximum absolute value of the coordinate (90 for latitudes, 180 for longitudes)
+	 * @return number|null The coordinate or null if the value is not a valid coordinate
+	 */
+	var parseCoordinate = function(value, limit) {
+		var coordinate = parseFloat(value);
+		if (isNaN(coordinate) || Math.abs(coordinate) > limit) {
+			return null;
+		}
+
+		return coordinate;
+	};
+
+	/**
+	 * Render a map with a marker for the supplied map container
+	 * @param object container The map container element
+	 */
+	var renderMap = function(container) {
+		var latitude = parseCoordinate($(container).data("latitude"), 90);
+		var longitude = parseCoordinate($(container).data("longitude"), 180);
+
+		// hide the map if the coordinates are invalid
+		if (latitude === null || longitude === null) {
+			$(container).hide();
+			return;
+		}
+
+		var zoom = parseInt($(container).data("zoom"), 10);
+		if (isNaN(zoom)) {
+			zoom = 13;
+		}
+
+		var map = L.map(container).setView([latitude, longitude], zoom);
+
+		L.tileLayer('https://{s}.tile.openstreetmap.org/{z}/{x}/{y}.png', {
+			attribution: '&copy; <a href="https://www.openstreetmap.org/copyright">OpenStreetMap</a> contributors',
+			maxZoom: 19
+		}).addTo(map);
+
+		var title = $(container).data("title");
+		var marker = L.marker([latitude, longitude]).addTo(map);
+		if (title) {
+			marker.bindPopup($("<div>").text(title).html());
+		}
+
+		$(container).show();
+	};
+
+	// load the leaflet library and render all maps
+	appendExternalStyleSheet(leafletSources["leaflet.css"]);
+	loadExternalScript(leafletSources["leaflet.js"], function() {
+		$(mapSelector).each(function(index, container) {
+			renderMap(container);
+		});
+	}, function() {
+		$(mapSelector).hide();
+	});
+
+});
+`
@@ -0,0 +1,272 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const MathJs = `
+/**
+ * Renders the math expressions of the current document with KaTeX.
+ * KaTeX is only loaded if the document contains math (see mathSettings for the delimiters).
+ */
+var MathRendering = (function() {
+
+  var contentSelector = "article > section.content";
+
+  // elements whose content is never treated as math
+  var ignoredTags = /^(script|noscript|style|textarea|pre|code|kbd|samp|option)$/i;
+
+  // the KaTeX loading state (0: not loaded, 1: loading, 2: loaded)
+  var loadingState = 0;
+  var pendingCallbacks = [];
+
+  // the default KaTeX distribution and the subresource integrity hashes of its files
+  var defaultKaTeXURL = "https://cdn.jsdelivr.net/npm/katex@0.16.3/dist";
+  var defaultKaTeXIntegrity = {
+    "katex.min.css": "sha384-Juol1FqnotbkyZUT5Z7gUPjQ9gzlwCENvUZTpQBAPxtusdwFLRy382PSDx5UUJ4/",
+    "katex.min.js": "sha384-97gW6UIJxnlKemYavrqDHSX3SiygeOwIZhwyOKRfSaf0JWKRVj9hLASHgFTzT+0O"
+  };
+
+  var getSettings = function() {
+    var settings = { enabled: true, delimiters: "dollars", katexURL: defaultKaTeXURL };
+    if (typeof(mathSettings) === 'object' && mathSettings !== null) {
+      $.extend(settings, mathSettings);
+    }
+
+    return settings;
+  };
+
+  /**
+   * Get the patterns which match block and inline math for the selected delimiters.
+   * Inline dollar math follows the pandoc rules so that amounts like "$5 and $10" are not treated as math:
+   * the opening $ must be followed and the closing $ preceded by a non-space character,
+   * and the closing $ must not be followed by a digit.
+   */
+  var getPatterns = function(delimiters) {
+    if (delimiters === "brackets") {
+      return {
+        block: /\\\[([\s\S]+?)\\\]/g,
+        inline: /\\\(([\s\S]+?)\\\)/g
+      };
+    }
+
+    return {
+      block: /\$\$([\s\S]+?)\$\$/g,
+      inline: /(^|[^\\$])\$(?![\s$])((?:\\\$|[^$])*?[^\s\\])\$(?!\d)/g
+    };
+  };
+
+  /**
+   * Get the sources of the KaTeX file with the given name: the configured distribution
+   * and the local copy in the vendor folder of the theme
+   */
+  var getSources = function(baseURL, fileName) {
+    var integrity = baseURL === defaultKaTeXURL ? defaultKaTeXIntegrity[fileName] : "";
+    return [
+      { url: baseURL + "/" + fileName, integrity: integrity },
+      { url: vendorPath + "/katex/" + fileName }
+    ];
+  };
+
+  var loadKaTeX = function(baseURL, callback) {
+    if (loadingState === 2) {
+      callback();
+      return;
+    }
+
+    pendingCallbacks.push(callback);
+    if (loadingState === 1) {
+      return;
+    }
+
+    loadingState = 1;
+    appendExternalStyleSheet(getSources(baseURL, "katex.min.css"));
+    loadExternalScript(getSources(baseURL, "katex.min.js"), function() {
+      loadingState = 2;
+
+      var callbacks = pendingCallbacks;
+      pendingCallbacks = [];
+      for (var i = 0; i < callbacks.length; i++) {
+        callbacks[i]();
+      }
+    }, function() {
+      loadingState = 0;
+      pendingCallbacks = [];
+    });
+  };
+
+  /**
+   * Collect the text nodes of the supplied element (skipping code blocks and other ignored elements)
+   */
+  var getTextNodes = function(element, textNodes) {
+    for (var child = element.firstChild; child !== null; child = child.nextSibling) {
+      if (child.nodeType === 3) {
+        textNodes.push(child);
+      } else if (child.nodeType === 1 && !ignoredTags.test(child.tagName) && !$(child).hasClass("katex")) {
+        getTextNodes(child, textNodes);
+      }
+    }
+
+    return textNodes;
+  };
+
+  var renderExpression = function(expression, displayMode) {
+    var element = document.createElement(displayMode ? "div" : "span");
+    element.className = displayMode ? "math math-block" : "math math-inline";
+
+    try {
+      katex.render(expression, element, { displayMode: displayMode, throwOnError: false });
+    } catch (e) {
+      element.textContent = expression;
+    }
+
+    return element;
+  };
+
+  /**
+   * Replace the math expressions of the supplied text with rendered elements.
+   * Returns null if the text does not contain any math.
+   */
+  var renderText = function(text, patterns) {
+    var matches = [];
+    var match;
+
+    patterns.block.lastIndex = 0;
+    while ((match = patterns.block.exec(text)) !== null) {
+      matches.push({ start: match.index, end: match.index + match[0].length, expression: match[1], displayMode: true });
+    }
+
+    patterns.inline.lastIndex = 0;
+    while ((match = patterns.inline.exec(text)) !== null) {
+      // the dollar pattern includes the preceding character
+      var prefixLength = match.length > 2 ? match[1].length : 0;
+      var expression = match.length > 2 ? match[2] : match[1];
+      var start = match.index + prefixLength;
+      var end = match.index + match[0].length;
+
+      var overlapsBlock = false;
+      for (var i = 0; i < matches.length; i++) {
+        if (start < matches[i].end && end > matches[i].start) {
+          overlapsBlock = true;
+          break;
+        }
+      }
+
+      if (!overlapsBlock) {
+        matches.push({ start: start, end: end, expression: expression, displayMode: false });
+      }
+    }
+
+    if (matches.length === 0) {
+      return null;
+    }
+
+    matches.sort(function(a, b) {
+      return a.start - b.start;
+    });
+
+    var fragment = document.createDocumentFragment();
+    var position = 0;
+    for (var j = 0; j < matches.length; j++) {
+      fragment.appendChild(document.createTextNode(unescapeDollars(text.substring(position, matches[j].start))));
+      fragment.appendChild(renderExpression(matches[j].expression, matches[j].displayMode));
+      position = matches[j].end;
+    }
+
+    fragment.appendChild(document.createTextNode(unescapeDollars(text.substring(position))));
+    return fragment;
+  };
+
+  // "\$" is a literal dollar sign
+  var unescapeDollars = function(text) {
+    return text.replace(/\\\$/g, "$");
+  };
+
+  /**
+   * Block expressions can span multiple lines which are rendered as <br> separated text nodes.
+   * Paragraphs which only contain text and line breaks are rendered as a whole.
+   */
+  var renderParagraphs = function(content, patterns) {
+    $(content).find("p, li, td, th, dd").each(function(i, element) {
+      if ($(element).children().not("br").length > 0) {
+        return;
+      }
+
+      var text = "";
+      for (var child = element.firstChild; child !== null; child = child.nextSibling) {
+        text += child.nodeType === 1 ? "\n" : child.nodeValue;
+      }
+
+      patterns.block.lastIndex = 0;
+      if (!patterns.block.test(text)) {
+        return;
+      }
+
+      var fragment = renderText(text, patterns);
+      if (fragment !== null) {
+        $(element).empty().append(fragment);
+      }
+    });
+  };
+
+  var renderTextNodes = function(content, patterns) {
+    var textNodes = getTextNodes(content, []);
+    for (var i = 0; i < textNodes.length; i++) {
+      var fragment = renderText(textNodes[i].nodeValue, patterns);
+      if (fragment !== null) {
+        textNodes[i].parentNode.replaceChild(fragment, textNodes[i]);
+      }
+    }
+  };
+
+  /**
+   * Render all math expressions of the current document
+   */
+  var render = function() {
+    var settings = getSettings();
+    if (!settings.enabled) {
+      return;
+    }
+
+    var content = $(contentSelector)[0];
+    if (!content) {
+      return;
+    }
+
+    // don't load KaTeX for documents without math
+    var patterns = getPatterns(settings.delimiters);
+    var text = getTextNodes(content, []).map(function(node) { return node.nodeValue; }).join("\n");
+    patterns.block.lastIndex = 0;
+    patterns.inline.lastIndex = 0;
+    if (!patterns.block.test(text) && !patterns.inline.test(text)) {
+      return;
+    }
+
+    loadKaTeX(settings.katexURL, function() {
+      renderParagraphs(content, patterns);
+      renderTextNodes(content, patterns);
+    });
+  };
+
+  return {
+    render: render
+  };
+
+})();
+
+$(function() {
+
+  MathRendering.render();
+
+  // render the math expressions again when the content changes
+  if (typeof(autoupdate) === 'object' && typeof(autoupdate.onchange) === 'function') {
+    autoupdate.onchange(
+      "Math Rendering",
+      function() {
+        MathRendering.render();
+      }
+    );
+  }
+
+});
+`
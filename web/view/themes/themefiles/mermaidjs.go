@@ -0,0 +1,155 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const MermaidJs = `
+/**
+ * Renders "mermaid" fenced code blocks as diagrams.
+ * The Mermaid library is only loaded if the page contains such a block.
+ */
+var MermaidDiagrams = (function() {
+
+  // the Mermaid distribution and the local copy in the vendor folder of the theme
+  // TODO: add the subresource integrity hash of the distribution
+  var mermaidSources = [
+    { url: "https://cdn.jsdelivr.net/npm/mermaid@10.9.1/dist/mermaid.min.js" },
+    { url: vendorPath + "/mermaid/mermaid.min.js" }
+  ];
+  var codeBlockSelector = "pre code.language-mermaid";
+  var diagramSelector = "div.mermaid-diagram";
+
+  // the Mermaid loading state (0: not loaded, 1: loading, 2: loaded)
+  var loadingState = 0;
+  var pendingCallbacks = [];
+  var diagramCounter = 0;
+
+  var loadMermaid = function(callback) {
+    if (loadingState === 2) {
+      callback();
+      return;
+    }
+
+    pendingCallbacks.push(callback);
+    if (loadingState === 1) {
+      return;
+    }
+
+    loadingState = 1;
+    loadExternalScript(mermaidSources, function() {
+      loadingState = 2;
+
+      var callbacks = pendingCallbacks;
+      pendingCallbacks = [];
+      for (var i = 0; i < callbacks.length; i++) {
+        callbacks[i]();
+      }
+    }, function() {
+      loadingState = 0;
+      pendingCallbacks = [];
+    });
+  };
+
+  /**
+   * Get the Mermaid theme matching the current page theme (see darkmode.js)
+   */
+  var getMermaidTheme = function() {
+    if (typeof(DarkMode) === 'object' && DarkMode.getTheme() === "dark") {
+      return "dark";
+    }
+
+    return "default";
+  };
+
+  var showError = function(container, source, error) {
+    var pre = $('<pre class="mermaid-source"></pre>').append($('<code></code>').text(source));
+    var message = $('<p class="mermaid-error"></p>').text("Invalid Mermaid diagram: " + (error && error.message ? error.message : error));
+    container.empty().addClass("mermaid-invalid").append(message).append(pre);
+  };
+
+  /**
+   * Render the source of the supplied diagram container
+   */
+  var renderDiagram = function(container) {
+    var source = container.data("source");
+    var id = "mermaid-diagram-" + (++diagramCounter);
+
+    var onSuccess = function(svg) {
+      container.removeClass("mermaid-invalid").html(svg);
+    };
+
+    var onError = function(error) {
+      // mermaid leaves an error element in the body
+      $("#d" + id).remove();
+      showError(container, source, error);
+    };
+
+    try {
+      var result = mermaid.render(id, source);
+      if (result && typeof(result.then) === 'function') {
+        result.then(function(output) {
+          onSuccess(output.svg);
+        }, onError);
+      } else {
+        onSuccess(result);
+      }
+    } catch (e) {
+      onError(e);
+    }
+  };
+
+  /**
+   * Render all mermaid code blocks and (optionally) re-render the existing diagrams
+   */
+  var render = function(rerender) {
+    var codeBlocks = $(codeBlockSelector);
+    if (codeBlocks.length === 0 && (!rerender || $(diagramSelector).length === 0)) {
+      return;
+    }
+
+    loadMermaid(function() {
+      mermaid.initialize({ startOnLoad: false, securityLevel: "strict", theme: getMermaidTheme() });
+
+      // replace the code blocks with diagram containers
+      $(codeBlockSelector).each(function(i, code) {
+        var container = $('<div class="mermaid-diagram"></div>').data("source", $(code).text());
+        $(code).closest("pre").replaceWith(container);
+        renderDiagram(container);
+      });
+
+      if (rerender) {
+        $(diagramSelector).each(function(i, container) {
+          renderDiagram($(container));
+        });
+      }
+    });
+  };
+
+  return {
+    render: render
+  };
+
+})();
+
+$(function() {
+
+  MermaidDiagrams.render(false);
+
+  // switch the diagram theme together with the page theme
+  document.addEventListener("themechange", function() {
+    MermaidDiagrams.render(true);
+  });
+
+  // render the diagrams again when the content changes
+  if (typeof(autoupdate) === 'object' && typeof(autoupdate.onchange) === 'function') {
+    autoupdate.onchange(
+      "Mermaid Diagrams",
+      function() {
+        MermaidDiagrams.render(false);
+      }
+    );
+  }
+
+});
+`
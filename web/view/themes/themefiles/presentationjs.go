@@ -14,20 +14,155 @@ $(function() {
     return;
   }
 
+  // the speaker notes of all slides (by slide index)
+  var speakerNotes = [];
+
+  // the "???" paragraph which separates the slide content from the speaker notes
+  var speakerNotesSeparator = /<p>\s*\?\?\?\s*<\/p>/;
+
+  /**
+   * Remove the speaker notes (the content after a "???" paragraph and <aside class="notes"> elements)
+   * from the supplied slide and return the slide and its notes
+   */
+  var extractSpeakerNotes = function(slideHtml) {
+    var notes = [];
+
+    var parts = slideHtml.split(speakerNotesSeparator);
+    if (parts.length > 1) {
+      slideHtml = parts.shift();
+      notes.push(parts.join(""));
+    }
+
+    var slide = $("<div>").html(slideHtml);
+    slide.find("aside.notes").each(function() {
+      notes.push($(this).html());
+      $(this).remove();
+    });
+
+    return {
+      html: notes.length > 0 ? slide.html() : slideHtml,
+      notes: notes.join("")
+    };
+  };
+
   /**
    * Split the document body into separate slides
    */
   var transformPresentationStructure = function() {
     var presentationContent = $(presentationSelector).html();
     var slides = presentationContent.split("<hr>")
+
+    speakerNotes = [];
+    for (var i = 0; i < slides.length; i++) {
+      var slide = extractSpeakerNotes(slides[i]);
+      slides[i] = slide.html;
+      speakerNotes.push(slide.notes);
+    }
+
     var newHtml = '<section class="slide">' + slides.join('</section><section class="slide">') + '</section>';
     $(presentationSelector).html(newHtml);
   };
 
+  /**
+   * Get the key code from the supplied value or the default key code if the value is not a valid key code
+   */
+  var getKeyCode = function(value, defaultKeyCode) {
+    var keyCode = parseInt(value, 10);
+    if (isNaN(keyCode) || keyCode < 1 || keyCode > 255) {
+      return defaultKeyCode;
+    }
+
+    return keyCode;
+  };
+
+  // keyboard shortcuts (see the presentationSettings defined by the template)
+  var settings = (typeof(presentationSettings) === 'object' && presentationSettings !== null) ? presentationSettings : {};
+  var gotoKey = getKeyCode(settings.gotoKey, 71); // 'g'
+  var toggleKey = getKeyCode(settings.toggleKey, 16); // <shift>
+  var notesKey = getKeyCode(settings.notesKey, 78); // 'n'
+
+  // touch navigation (see registerSwipeNavigation)
+  var swipeEnabled = settings.swipeEnabled !== false;
+  var swipeDistance = settings.swipeDistance > 0 ? settings.swipeDistance : 50;
+
+  // progress indicator (see updateProgress)
+  var showProgress = settings.showProgress !== false;
+  var progressAlwaysVisible = settings.progressAlwaysVisible === true;
+
+  // timer (see startTimer)
+  var showTimer = settings.showTimer === true;
+  var timerPauseKey = getKeyCode(settings.timerPauseKey, 80); // 'p'
+  var duration = (settings.duration > 0 ? settings.duration : 20) * 60;
+  var warningTime = (settings.warningTime > 0 && settings.warningTime * 60 < duration ? settings.warningTime : 5) * 60;
+
   var originalWidth = "";
   var originalFontSize = "";
 
 
+  var timer = {
+    elapsed: 0,
+    interval: null
+  };
+
+  /**
+   * Format the supplied number of seconds as minutes and seconds (e.g. "4:05")
+   */
+  var formatTime = function(seconds) {
+    var minutes = Math.floor(seconds / 60);
+    var remainingSeconds = seconds % 60;
+    return minutes + ":" + (remainingSeconds < 10 ? "0" : "") + remainingSeconds;
+  };
+
+  /**
+   * Display the elapsed time and warn if the presentation is running out of time
+   */
+  var renderTimer = function() {
+    $(".presentation-timer")
+      .text(formatTime(timer.elapsed) + " / " + formatTime(duration))
+      .toggleClass("presentation-timer-warning", duration - timer.elapsed <= warningTime)
+      .toggleClass("presentation-timer-paused", timer.interval === null);
+  };
+
+  /**
+   * Start or resume the timer
+   */
+  var startTimer = function() {
+    if (!showTimer || timer.interval !== null) {
+      return;
+    }
+
+    if ($(".presentation-timer").length === 0) {
+      $("body").append('<div class="presentation-timer"></div>');
+    }
+
+    $(".presentation-timer").show();
+
+    timer.interval = window.setInterval(function() {
+      timer.elapsed++;
+      renderTimer();
+    }, 1000);
+
+    renderTimer();
+  };
+
+  /**
+   * Pause the timer
+   */
+  var pauseTimer = function() {
+    window.clearInterval(timer.interval);
+    timer.interval = null;
+    renderTimer();
+  };
+
+  /**
+   * Stop the timer and hide it
+   */
+  var resetTimer = function() {
+    pauseTimer();
+    timer.elapsed = 0;
+    $(".presentation-timer").hide();
+  };
+
   /**
    * Toggle the page header elements
    */
@@ -48,6 +183,17 @@ $(function() {
     $(".ribbon").toggle();
     $(".allmark-promo").toggle();
 
+    if (!progressAlwaysVisible) {
+      $(".presentation-progress").toggle();
+    }
+
+    // the timer only runs in presentation mode
+    if ($("article.presentation").hasClass("presentation-mode")) {
+      startTimer();
+    } else {
+      resetTimer();
+    }
+
     // toggle width and font size
     if (originalWidth === "" && originalFontSize === "") {
       originalWidth = $("body").css("width");
@@ -64,6 +210,72 @@ $(function() {
     }
   };
 
+  /**
+   * Display the number of the current slide and a progress bar
+   */
+  var updateProgress = function(currentSlideIndex) {
+    if (!showProgress) {
+      return;
+    }
+
+    var progress = $(".presentation-progress");
+    if (progress.length === 0) {
+      progress = $('<div class="presentation-progress"><span class="presentation-progress-counter"></span><div class="presentation-progress-bar"><div class="presentation-progress-value"></div></div></div>');
+
+      // hide the indicator if the presentation mode is already active
+      if (!progressAlwaysVisible && $("article.presentation").hasClass("presentation-mode")) {
+        progress.hide();
+      }
+
+      $("body").append(progress);
+    }
+
+    var total = $.deck('getSlides').length;
+    if (total === 0) {
+      return;
+    }
+
+    var current = currentSlideIndex + 1;
+    progress.find(".presentation-progress-counter").text(current + " / " + total);
+    progress.find(".presentation-progress-value").css("width", (current / total * 100) + "%");
+  };
+
+  var currentSlideIndex = 0;
+  var presenterView = null;
+
+  /**
+   * Display the speaker notes of the current slide in the presenter view (if it is open)
+   */
+  var updatePresenterView = function() {
+    if (presenterView === null || presenterView.closed) {
+      return;
+    }
+
+    var notes = speakerNotes[currentSlideIndex] || "<p><em>No notes for this slide.</em></p>";
+    var title = "Slide " + (currentSlideIndex + 1) + " / " + speakerNotes.length;
+    $(presenterView.document.body).html("<h1>" + title + "</h1>" + notes);
+  };
+
+  /**
+   * Open the presenter view which shows the speaker notes of the current slide
+   */
+  var openPresenterView = function() {
+    presenterView = window.open("", "allmark-presenter-view", "width=640,height=480");
+    if (presenterView === null) {
+      // the popup has been blocked
+      return;
+    }
+
+    presenterView.document.title = "Speaker notes - " + document.title;
+    updatePresenterView();
+  };
+
+  $(document).bind('deck.change', function(event, from, to) {
+    currentSlideIndex = to;
+    updateProgress(to);
+    updatePresenterView();
+  });
+
   /**
    * Transform all slides into a presentation
    */
@@ -85,21 +297,84 @@ $(function() {
       },
 
       keys: {
-        goto: 71 // 'g'
+        goto: gotoKey
       }
     });
 
+    currentSlideIndex = 0;
+    updateProgress(0);
+    updatePresenterView();
+
+  };
+
+  /**
+   * Go to the next slide on a left swipe and to the previous slide on a right swipe.
+   * Swipes shorter than the swipe distance and mostly vertical swipes (scrolling) are ignored.
+   */
+  var registerSwipeNavigation = function() {
+    if (!swipeEnabled) {
+      return;
+    }
+
+    var start = null;
+
+    $(presentationSelector).bind("touchstart", function(e) {
+      var touches = e.originalEvent.touches;
+      if (touches.length !== 1) {
+        start = null;
+        return;
+      }
+
+      start = {
+        x: touches[0].clientX,
+        y: touches[0].clientY
+      };
+    });
+
+    $(presentationSelector).bind("touchend", function(e) {
+      var touches = e.originalEvent.changedTouches;
+      if (start === null || touches.length === 0) {
+        return;
+      }
+
+      var deltaX = touches[0].clientX - start.x;
+      var deltaY = touches[0].clientY - start.y;
+      start = null;
+
+      if (Math.abs(deltaX) < swipeDistance || Math.abs(deltaX) <= Math.abs(deltaY)) {
+        return;
+      }
+
+      if (deltaX < 0) {
+        $.deck('next');
+      } else {
+        $.deck('prev');
+      }
+    });
   };
 
   // handle keyboard shortcuts
   $(document).keydown(function(e) {
 
-    /* <ctrl> + <shift> */
-    if (e.ctrlKey && (e.which === 16) ) {
-      console.log( "You pressed Ctrl + Shift" );
+    /* <ctrl> + <toggle key> (default: <shift>) */
+    if (e.ctrlKey && (e.which === toggleKey) ) {
       togglePresentationMode();
     }
 
+    /* <timer pause key> (default: 'p') in presentation mode */
+    if (showTimer && !e.ctrlKey && !e.altKey && !e.metaKey && e.which === timerPauseKey && $("article.presentation").hasClass("presentation-mode") && !$(e.target).is("input, textarea, select")) {
+      if (timer.interval === null) {
+        startTimer();
+      } else {
+        pauseTimer();
+      }
+    }
+
+    /* <notes key> (default: 'n') outside of input fields */
+    if (!e.ctrlKey && !e.altKey && !e.metaKey && e.which === notesKey && !$(e.target).is("input, textarea, select")) {
+      openPresenterView();
+    }
+
   });
 
     // load deck.js
@@ -109,6 +384,9 @@ $(function() {
     // render the presentaton
     renderPresentation();
 
+    // touch navigation
+    registerSwipeNavigation();
+
       // register a on change listener
       if (typeof(autoupdate) === 'object' && typeof(autoupdate.onchange) === 'function') {
           autoupdate.onchange(
@@ -17,10 +17,21 @@ body {
     text-rendering: optimizeLegibility;
     cursor: default;
     max-width: 100%;
-    width: 100%
+    width: 100%;
+    margin: 0;
+    padding: 0;
     outline: none;
 }
 
+body>article {
+    float: none;
+    width: 100% !important;
+    max-width: 100% !important;
+    margin: 0 !important;
+    padding: 0 !important;
+    border: none !important;
+}
+
 a, a:visited {
     font-weight: normal;
     text-decoration: none;
@@ -52,7 +63,29 @@ pre, blockquote {
     border: 1px solid #999;
     padding: 1em;
     page-break-inside: avoid;
+    break-inside: avoid;
+    white-space: pre-wrap;
+}
+
+pre code {
     white-space: pre-wrap;
+    word-wrap: break-word;
+}
+
+pre a[href]:after, code a[href]:after {
+    content: "";
+}
+
+.copy-code-button {
+    display: none !important;
+}
+
+.lightbox {
+    display: none !important;
+}
+
+nav.toc a[href]:after {
+    content: "";
 }
 
 ol, ul, tr, img {
@@ -123,4 +156,35 @@ body>footer {
 
 .presentation nav {
     display: none;
+}
+
+.presentation-progress, .presentation-timer {
+    display: none !important;
+}
+
+/* presentations: one slide per page */
+.presentation .deck-container {
+    overflow: visible !important;
+}
+
+.presentation .slide {
+    display: block !important;
+    position: static !important;
+    visibility: visible !important;
+    opacity: 1 !important;
+    transform: none !important;
+    -webkit-transform: none !important;
+    width: auto !important;
+    height: auto !important;
+    min-height: 0 !important;
+    margin: 0 !important;
+    page-break-after: always;
+    break-after: page;
+    page-break-inside: avoid;
+    break-inside: avoid;
+}
+
+.presentation .slide:last-child {
+    page-break-after: auto;
+    break-after: auto;
 }`
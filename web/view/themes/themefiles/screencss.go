@@ -179,6 +179,30 @@ code {
     padding: 0 3px 0 3px;
 }
 
+pre.copy-code {
+    position: relative;
+}
+
+.copy-code-button {
+    position: absolute;
+    top: 4px;
+    right: 4px;
+    padding: 2px 6px;
+    font-family: sans-serif;
+    font-size: 0.85em;
+    line-height: 1.2em;
+    color: #444;
+    background: #fefefe;
+    border: 1px solid #ccc;
+    border-radius: 3px;
+    cursor: pointer;
+    opacity: 0.6;
+}
+
+pre.copy-code:hover .copy-code-button, .copy-code-button:focus {
+    opacity: 1;
+}
+
 b, strong {
     font-weight: bold;
 }
@@ -224,6 +248,14 @@ li p:last-child {
     margin: 0;
 }
 
+li.task-list-item {
+    list-style-type: none;
+}
+
+li.task-list-item input[type="checkbox"] {
+    margin: 0 0.5em 0 -1.5em;
+}
+
 ul.tree, ul.tree ul {
     list-style-type: none;
     background: url(tree-vertical-line.png) repeat-y;
@@ -582,6 +614,77 @@ aside.sidebar>.tagcloud>h1 {
     font-size: 1.5em;
 }
 
+.mermaid-diagram {
+    margin: 1em 0;
+    overflow-x: auto;
+    text-align: center;
+}
+
+.mermaid-diagram.mermaid-invalid {
+    text-align: left;
+}
+
+.mermaid-error {
+    color: #c0392b;
+}
+
+.math-block {
+    margin: 1em 0;
+    overflow-x: auto;
+    overflow-y: hidden;
+}
+
+nav.toc {
+    display: inline-block;
+    margin: 1em 0;
+    padding: 0.5em 1.5em 0.5em 0;
+    border: 1px solid #EAEAEA;
+    border-radius: 3px;
+    background: #F8F8F8;
+}
+
+nav.toc ul {
+    margin: 0 0 0 1.5em;
+    padding: 0;
+}
+
+nav.toc li {
+    margin: 0.2em 0;
+}
+
+.heading-anchor {
+    margin-left: 0.3em;
+    color: #CCC;
+    text-decoration: none;
+    visibility: hidden;
+}
+
+h1:hover>.heading-anchor,
+h2:hover>.heading-anchor,
+h3:hover>.heading-anchor,
+h4:hover>.heading-anchor,
+h5:hover>.heading-anchor,
+h6:hover>.heading-anchor,
+.heading-anchor:focus {
+    visibility: visible;
+}
+
+.include-error {
+    margin: 1em 0;
+    padding: 0.5em 1em;
+    border: 1px solid #D9534F;
+    background-color: #F9E2E2;
+    color: #A94442;
+}
+
+.shortcode-error {
+    display: inline-block;
+    padding: 0 0.5em;
+    border: 1px solid #D9534F;
+    background-color: #F9E2E2;
+    color: #A94442;
+}
+
 .imagegallery {
     margin: 2em 0;
 }
@@ -590,6 +693,76 @@ aside.sidebar>.tagcloud>h1 {
     font-size: 1.2em;
 }
 
+body.lightbox-open {
+    overflow: hidden;
+}
+
+.lightbox {
+    position: fixed;
+    top: 0;
+    right: 0;
+    bottom: 0;
+    left: 0;
+    z-index: 2000;
+    background: rgba(0, 0, 0, 0.9);
+    text-align: center;
+}
+
+.lightbox figure {
+    display: flex;
+    flex-direction: column;
+    align-items: center;
+    justify-content: center;
+    height: 100%;
+    margin: 0 4em;
+}
+
+.lightbox-image {
+    max-width: 100%;
+    max-height: 85%;
+    box-shadow: 0 0 20px rgba(0, 0, 0, 0.8);
+}
+
+.lightbox figcaption {
+    margin-top: 1em;
+    color: #eee;
+    font-size: 1.2em;
+}
+
+.lightbox-counter {
+    color: #999;
+    margin-left: 1em;
+}
+
+.lightbox button {
+    position: absolute;
+    padding: 0.2em 0.5em;
+    font-size: 2em;
+    color: #eee;
+    background: transparent;
+    border: none;
+    cursor: pointer;
+}
+
+.lightbox button:focus {
+    outline: 2px solid #0097cf;
+}
+
+.lightbox-close {
+    top: 0.5em;
+    right: 0.5em;
+}
+
+.lightbox-previous {
+    top: 50%;
+    left: 0.2em;
+}
+
+.lightbox-next {
+    top: 50%;
+    right: 0.2em;
+}
+
 .imagegallery ol {
     list-style: none;
     margin-left: 0;
@@ -694,6 +867,49 @@ article.presentation .slide {
     padding: 10px;
 }
 
+.presentation-progress {
+    position: fixed;
+    right: 1em;
+    bottom: 1em;
+    width: 8em;
+    padding: 0.3em 0.5em;
+    font-size: 0.8em;
+    text-align: right;
+    background: rgba(255, 255, 255, 0.8);
+    z-index: 100;
+}
+
+.presentation-progress-bar {
+    height: 3px;
+    margin-top: 0.2em;
+    background: #dddddd;
+}
+
+.presentation-progress-value {
+    width: 0;
+    height: 100%;
+    background: #333333;
+}
+
+.presentation-timer {
+    position: fixed;
+    top: 1em;
+    right: 1em;
+    padding: 0.3em 0.5em;
+    font-size: 0.8em;
+    background: rgba(255, 255, 255, 0.8);
+    z-index: 100;
+}
+
+.presentation-timer-warning {
+    color: #ffffff;
+    background: #c0392b;
+}
+
+.presentation-timer-paused {
+    opacity: 0.5;
+}
+
 .filepreview {
     margin: 2em 0;
 }
@@ -796,6 +1012,12 @@ article.presentation .slide {
 .aliasindex>.content>.shortlinks>.shortlink>a {
 }
 
+.wikilink-broken {
+    color: #b00;
+    border-bottom: 1px dashed #b00;
+    cursor: help;
+}
+
 .search>.content>header {
     margin: 10px 0 10px 0;
 }
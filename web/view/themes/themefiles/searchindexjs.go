@@ -0,0 +1,159 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const SearchIndexJs = `
+$(function() {
+
+	var inputSelector = '.instant-search';
+	var resultsSelector = '.instant-search-results';
+	var maximumNumberOfResults = 10;
+	var snippetLength = 160;
+
+	// abort if there is no search box
+	if ($(inputSelector).length === 0) {
+		return;
+	}
+
+	var searchIndex = null;
+
+	/**
+	 * Load the search index (only once)
+	 * @param function callback The function which is called with the search index
+	 */
+	var loadSearchIndex = function(callback) {
+		if (searchIndex !== null) {
+			callback(searchIndex);
+			return;
+		}
+
+		$.getJSON('/search-index.json', function(entries) {
+			searchIndex = $.map(entries, function(entry) {
+				entry.normalizedTitle = entry.title.toLowerCase();
+				entry.normalizedText = entry.text.toLowerCase();
+				return entry;
+			});
+
+			callback(searchIndex);
+		});
+	};
+
+	/**
+	 * Check if all characters of the term appear in the given order in the value (e.g. "mnch" in "munich")
+	 * @param string value The value to search in
+	 * @param string term The term to search for
+	 * @return bool true if the value contains the characters of the term in the given order
+	 */
+	var isFuzzyMatch = function(value, term) {
+		var position = 0;
+		for (var i = 0; i < term.length; i++) {
+			position = value.indexOf(term.charAt(i), position);
+			if (position === -1) {
+				return false;
+			}
+
+			position++;
+		}
+
+		return true;
+	};
+
+	/**
+	 * Calculate the score of an index entry for the supplied terms
+	 * @param object entry The search index entry
+	 * @param array terms The lowercased search terms
+	 * @return int The score of the entry (0 if at least one term does not match)
+	 */
+	var getScore = function(entry, terms) {
+		var score = 0;
+		for (var i = 0; i < terms.length; i++) {
+			var term = terms[i];
+
+			if (entry.normalizedTitle.indexOf(term) !== -1) {
+				score += 10;
+			} else if (entry.normalizedText.indexOf(term) !== -1) {
+				score += 3;
+			} else if (isFuzzyMatch(entry.normalizedTitle, term)) {
+				score += 1;
+			} else {
+				return 0;
+			}
+		}
+
+		return score;
+	};
+
+	/**
+	 * Get a text snippet around the first occurrence of the supplied terms
+	 * @param object entry The search index entry
+	 * @param array terms The lowercased search terms
+	 * @return string The text snippet
+	 */
+	var getSnippet = function(entry, terms) {
+		var start = 0;
+		for (var i = 0; i < terms.length; i++) {
+			var position = entry.normalizedText.indexOf(terms[i]);
+			if (position !== -1) {
+				start = Math.max(0, position - snippetLength / 4);
+				break;
+			}
+		}
+
+		var snippet = entry.text.substr(start, snippetLength);
+		if (start > 0) {
+			snippet = '… ' + snippet;
+		}
+
+		if (start + snippetLength < entry.text.length) {
+			snippet += ' …';
+		}
+
+		return snippet;
+	};
+
+	/**
+	 * Render the search results for the supplied query
+	 * @param string query The search query
+	 */
+	var search = function(query) {
+		var resultList = $(resultsSelector);
+		var terms = $.grep(query.toLowerCase().split(/\s+/), function(term) {
+			return term.length > 0;
+		});
+
+		if (terms.length === 0) {
+			resultList.empty();
+			return;
+		}
+
+		loadSearchIndex(function(entries) {
+			var results = [];
+			$.each(entries, function(index, entry) {
+				var score = getScore(entry, terms);
+				if (score > 0) {
+					results.push({ entry: entry, score: score });
+				}
+			});
+
+			results.sort(function(result1, result2) {
+				return result2.score - result1.score;
+			});
+
+			resultList.empty();
+			$.each(results.slice(0, maximumNumberOfResults), function(index, result) {
+				var item = $('<li>');
+				$('<a class="title">').attr('href', result.entry.url).text(result.entry.title).appendTo(item);
+				$('<p class="description">').text(getSnippet(result.entry, terms)).appendTo(item);
+				resultList.append(item);
+			});
+		});
+	};
+
+	$(inputSelector).on('input', function() {
+		search($(this).val());
+	});
+
+});
+`
@@ -17,6 +17,77 @@ function appendStyleSheet(path) {
 	}).appendTo('head');
 }
 
+/**
+ * vendorPath is the theme folder which can contain local copies of the third-party libraries
+ * (e.g. ".allmark/theme/vendor/katex/katex.min.js"). They are used if the CDN is not available.
+ */
+var vendorPath = "/theme/vendor";
+
+/**
+ * loadExternalFile adds a script or style-sheet element for the first source of the given list which can be loaded.
+ * Sources with an integrity hash are loaded with subresource integrity checks (and CORS).
+ * @param {string} tagName "script" or "link"
+ * @param {Array} sources The sources which are tried in order ({ url: "...", integrity: "sha384-..." })
+ * @param {function} onSuccess Is called when one of the sources was loaded
+ * @param {function} onError Is called when none of the sources could be loaded
+ */
+function loadExternalFile(tagName, sources, onSuccess, onError) {
+	var index = 0;
+
+	var loadNextSource = function() {
+		if (index >= sources.length) {
+			if (typeof(onError) === 'function') {
+				onError();
+			}
+			return;
+		}
+
+		var source = sources[index++];
+		var element = document.createElement(tagName);
+		if (tagName === "link") {
+			element.rel = "stylesheet";
+			element.type = "text/css";
+			element.href = source.url;
+		} else {
+			element.src = source.url;
+		}
+
+		if (source.integrity) {
+			element.integrity = source.integrity;
+			element.crossOrigin = "anonymous";
+		}
+
+		element.onload = function() {
+			if (typeof(onSuccess) === 'function') {
+				onSuccess();
+			}
+		};
+
+		element.onerror = function() {
+			$(element).remove();
+			loadNextSource();
+		};
+
+		document.getElementsByTagName("head")[0].appendChild(element);
+	};
+
+	loadNextSource();
+}
+
+/**
+ * loadExternalScript loads the first script of the given sources which can be loaded (see loadExternalFile)
+ */
+function loadExternalScript(sources, onSuccess, onError) {
+	loadExternalFile("script", sources, onSuccess, onError);
+}
+
+/**
+ * appendExternalStyleSheet adds the first style sheet of the given sources which can be loaded (see loadExternalFile)
+ */
+function appendExternalStyleSheet(sources) {
+	loadExternalFile("link", sources);
+}
+
 /**
  * getAnchorNameFromText returnes a normalized anchor name for the given text
  * @param {string} text
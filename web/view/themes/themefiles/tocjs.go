@@ -0,0 +1,168 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package themefiles
+
+const TocJs = `
+/**
+ * Generates a table of contents from the h2 and h3 headings of the current document.
+ * The table of contents is inserted at a "[TOC]" paragraph or into the container element
+ * (default: "#toc", see tocSettings.container).
+ */
+var TableOfContents = (function() {
+
+  var contentSelector = "article > section.content";
+  var headingSelector = "h2, h3";
+  var placeholderText = "[TOC]";
+
+  var getContainerSelector = function() {
+    if (typeof(tocSettings) === 'object' && typeof(tocSettings.container) === 'string' && tocSettings.container !== "") {
+      return tocSettings.container;
+    }
+
+    return "#toc";
+  };
+
+  /**
+   * Get a unique slug for the supplied heading text
+   */
+  var getSlug = function(text, usedSlugs) {
+    var slug = text.toLowerCase().replace(/[\s]+/g, "-").replace(/[^\w\d-]/g, "").replace(/-{2,}/g, "-").replace(/^-|-$/g, "");
+    if (slug === "") {
+      slug = "section";
+    }
+
+    var uniqueSlug = slug;
+    var suffix = 2;
+    while (usedSlugs[uniqueSlug] || document.getElementById(uniqueSlug)) {
+      uniqueSlug = slug + "-" + suffix;
+      suffix++;
+    }
+
+    usedSlugs[uniqueSlug] = true;
+    return uniqueSlug;
+  };
+
+  /**
+   * Get the text of the supplied heading without its anchor link
+   */
+  var getHeadingText = function(heading) {
+    return $.trim($(heading).clone().find(".heading-anchor").remove().end().text());
+  };
+
+  /**
+   * Build the nested list of the supplied headings
+   */
+  var buildList = function(headings) {
+    var root = $('<ul></ul>');
+    var currentSubList = null;
+    var lastItem = null;
+
+    headings.each(function(i, heading) {
+      var link = $('<a></a>').attr("href", "#" + heading.id).text(getHeadingText(heading));
+      var item = $('<li></li>').append(link);
+
+      if (heading.tagName.toLowerCase() === "h3" && lastItem !== null) {
+        if (currentSubList === null) {
+          currentSubList = $('<ul></ul>');
+          lastItem.append(currentSubList);
+        }
+
+        currentSubList.append(item);
+        return;
+      }
+
+      root.append(item);
+      lastItem = item;
+      currentSubList = null;
+    });
+
+    return root;
+  };
+
+  /**
+   * (Re-)generate the table of contents
+   */
+  var generate = function() {
+    var content = $(contentSelector);
+    var headings = content.find(headingSelector);
+
+    // find the target: a "[TOC]" paragraph or the container
+    var target = null;
+    content.find("p").each(function(i, paragraph) {
+      if (target === null && $.trim($(paragraph).text()) === placeholderText) {
+        var nav = $('<nav class="toc"></nav>');
+        $(paragraph).replaceWith(nav);
+        target = nav;
+      }
+    });
+
+    if (target === null) {
+      target = $(getContainerSelector()).first();
+    }
+
+    if (target.length === 0) {
+      return;
+    }
+
+    target.addClass("toc").empty();
+
+    // skip pages without headings
+    if (headings.length === 0) {
+      target.hide();
+      return;
+    }
+
+    // assign ids to the headings which don't have one
+    var usedSlugs = {};
+    headings.each(function(i, heading) {
+      if (!heading.id) {
+        heading.id = getSlug(getHeadingText(heading), usedSlugs);
+      }
+    });
+
+    target.append(buildList(headings)).show();
+
+    // smooth scrolling
+    target.find("a").on("click", function(e) {
+      var heading = document.getElementById($(this).attr("href").substring(1));
+      if (!heading) {
+        return;
+      }
+
+      e.preventDefault();
+      if (typeof(heading.scrollIntoView) === 'function') {
+        heading.scrollIntoView({ behavior: "smooth", block: "start" });
+      }
+
+      if (window.history && typeof(window.history.pushState) === 'function') {
+        window.history.pushState(null, "", "#" + heading.id);
+      } else {
+        window.location.hash = heading.id;
+      }
+    });
+  };
+
+  return {
+    generate: generate
+  };
+
+})();
+
+$(function() {
+
+  TableOfContents.generate();
+
+  // regenerate the table of contents when the content changes
+  if (typeof(autoupdate) === 'object' && typeof(autoupdate.onchange) === 'function') {
+    autoupdate.onchange(
+      "Table of Contents",
+      function() {
+        TableOfContents.generate();
+      }
+    );
+  }
+
+});
+`
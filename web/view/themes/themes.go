@@ -4,7 +4,10 @@
 
 package themes
 
-import "github.com/andreaskoch/allmark/web/view/themes/themefiles"
+import (
+	"github.com/andreaskoch/allmark/common/config"
+	"github.com/andreaskoch/allmark/web/view/themes/themefiles"
+)
 
 var defaultTheme *Theme
 
@@ -12,12 +15,13 @@ var defaultTheme *Theme
 func init() {
 
 	defaultTheme = &Theme{
-		Name: "default",
+		Name: config.DefaultThemeName,
 		Files: []*ThemeFile{
 
 			// styles
 			newFileFromText("screen.css", themefiles.ScreenCss),
 			newFileFromText("print.css", themefiles.PrintCss),
+			newFileFromText("darkmode.css", themefiles.DarkModeCss),
 
 			// assets
 			newFileFromBase64("tree-node.png", themefiles.NodePng),
@@ -45,10 +49,13 @@ func init() {
 			// auto-suggest
 			newFileFromText("typeahead.js", themefiles.TypeAheadJs),
 			newFileFromText("search.js", themefiles.SearchJs),
+			newFileFromText("searchindex.js", themefiles.SearchIndexJs),
 
 			// code highlighting
 			newFileFromBase64("codehighlighting/highlight.js", themefiles.HighlightJs),
 			newFileFromText("codehighlighting/highlight.css", themefiles.HighlightCss),
+			newFileFromText("codehighlighting/codehighlighting.js", themefiles.CodeHighlightingJs),
+			newFileFromText("copycode.js", themefiles.CopyCodeJs),
 
 			// latest/preview
 			newFileFromText("latest.js", themefiles.LatestJs),
@@ -57,8 +64,26 @@ func init() {
 			// lazy-loading
 			newFileFromText("lazysizes.js", themefiles.LazySizesJs),
 
+			// location maps
+			newFileFromText("map.js", themefiles.MapJs),
+
+			// image gallery lightbox
+			newFileFromText("lightbox.js", themefiles.LightboxJs),
+
+			// math rendering (KaTeX)
+			newFileFromText("math.js", themefiles.MathJs),
+
+			// diagrams (Mermaid)
+			newFileFromText("mermaid.js", themefiles.MermaidJs),
+
+			// table of contents
+			newFileFromText("toc.js", themefiles.TocJs),
+
 			// global
 			newFileFromText("site.js", themefiles.SiteJs),
+
+			// light/dark theme toggle
+			newFileFromText("darkmode.js", themefiles.DarkModeJs),
 		},
 	}
 
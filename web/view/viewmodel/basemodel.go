@@ -25,6 +25,7 @@ type Base struct {
 	JSONURL     string `json:"jsonURL"`
 	MarkdownURL string `json:"markdownURL"`
 	DOCXURL     string `json:"docxURL"`
+	PDFURL      string `json:"pdfURL"`
 
 	PageTitle   string `json:"pageTitle"`
 	Title       string `json:"title"`
@@ -0,0 +1,16 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+// A Comment is a comment on an item together with its replies.
+// The Level is the depth of the comment in the thread (0 = top-level comment).
+type Comment struct {
+	Route   string    `json:"route"`
+	Author  string    `json:"author"`
+	Date    string    `json:"date"`
+	Content string    `json:"content"`
+	Level   int       `json:"level"`
+	Replies []Comment `json:"replies"`
+}
@@ -11,3 +11,29 @@ type ConversionModel struct {
 
 	Files []File `json:"files"`
 }
+
+// PresentationConversionModel contains the slides of a presentation for the PDF conversion.
+type PresentationConversionModel struct {
+	ConversionModel
+
+	Slides []string `json:"slides"`
+}
+
+// CombinedConversionModel contains all documents of a repository for the single-page view ("all.html").
+type CombinedConversionModel struct {
+	Base
+
+	Sections []CombinedSection `json:"sections"`
+}
+
+// A CombinedSection is a single item of the combined view. The content of linked items
+// (e.g. presentations) is not included; they are linked instead.
+type CombinedSection struct {
+	Base
+
+	// ID is the anchor of the section (e.g. "documents-sample").
+	ID string `json:"id"`
+
+	Content string `json:"content"`
+	Linked  bool   `json:"linked"`
+}
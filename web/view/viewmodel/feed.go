@@ -10,8 +10,11 @@ type Feed struct {
 }
 
 type FeedEntry struct {
+	ID          string `json:"id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	Content     string `json:"content"`
 	Link        string `json:"link"`
 	PubDate     string `json:"pubDate"`
+	Updated     string `json:"updated"`
 }
@@ -17,4 +17,7 @@ type File struct {
 	Hash         string    `json:"hash"`
 	LastModified time.Time `json:"lastModified"`
 	MimeType     string    `json:"mimeType"`
+
+	// the path of the gallery thumbnail (only available for images)
+	Thumbnail string `json:"thumbnail"`
 }
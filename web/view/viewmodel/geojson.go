@@ -0,0 +1,30 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+// GeoJSONFeatureCollection is a GeoJSON FeatureCollection (see http://geojson.org).
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a single GeoJSON Feature with a point geometry.
+type GeoJSONFeature struct {
+	Type       string                   `json:"type"`
+	Geometry   GeoJSONGeometry          `json:"geometry"`
+	Properties GeoJSONFeatureProperties `json:"properties"`
+}
+
+// GeoJSONGeometry is a GeoJSON geometry. The coordinates of points are [longitude, latitude].
+type GeoJSONGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// GeoJSONFeatureProperties contains the title and the relative URL of the item a feature belongs to.
+type GeoJSONFeatureProperties struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
@@ -16,5 +16,7 @@ type GeoLocation struct {
 	Latitude  string `json:"latitude"`
 	Longitude string `json:"longitude"`
 	MapType   string `json:"mapType"`
-	Zoom      int    `json:"zoom"`
+
+	// the zoom level of the location map (1-19)
+	Zoom int `json:"zoom"`
 }
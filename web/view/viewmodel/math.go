@@ -0,0 +1,26 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+import (
+	"encoding/json"
+)
+
+// MathSettings contains the settings of the math rendering script.
+type MathSettings struct {
+	Enabled    bool   `json:"enabled"`
+	Delimiters string `json:"delimiters"`
+	KaTeXURL   string `json:"katexURL"`
+}
+
+// JSON returns the JSON representation of the math settings for the math rendering script.
+func (settings MathSettings) JSON() string {
+	bytes, err := json.Marshal(settings)
+	if err != nil {
+		return "{}"
+	}
+
+	return string(bytes)
+}
@@ -0,0 +1,20 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+// Timeline is the chronological list of all messages (newest first).
+type Timeline struct {
+	Model
+
+	Messages []Message `json:"messages"`
+}
+
+// A Message is a short, timestamped post.
+type Message struct {
+	Route     string `json:"route"`
+	Permalink string `json:"permalink"`
+	Date      string `json:"date"`
+	Content   string `json:"content"`
+}
@@ -17,7 +17,8 @@ type Model struct {
 	Publisher Publisher `json:"publisher"`
 	Author    Author    `json:"author"`
 
-	Children []Base `json:"children"`
+	Children           []Base     `json:"children"`
+	ChildrenPagination Pagination `json:"childrenPagination"`
 
 	ToplevelNavigation   ToplevelNavigation   `json:"toplevelNavigation"`
 	BreadcrumbNavigation BreadcrumbNavigation `json:"breadcrumbNavigation"`
@@ -26,13 +27,31 @@ type Model struct {
 	Tags     []Tag    `json:"tags"`
 	TagCloud TagCloud `json:"tagCloud"`
 
+	// Related contains the items which share the most tags with this item.
+	Related []Base `json:"related"`
+
+	// Comments contains the threaded comments on this item.
+	Comments []Comment `json:"comments"`
+
 	Files  []File  `json:"files"`
 	Images []Image `json:"images"`
 
 	GeoLocation GeoLocation `json:"geoLocation"`
 
+	// Social contains the properties for the OpenGraph and Twitter Card meta tags.
+	Social SocialMetaData `json:"social"`
+
+	// Permalink is the date-based route of messages (e.g. "message/20150301-153000").
+	Permalink string `json:"permalink,omitempty"`
+
+	// StructuredData contains the schema.org data of the item (nil if there is none).
+	StructuredData *StructuredData `json:"structuredData,omitempty"`
+
 	Analytics Analytics `json:"-"`
 
+	Presentation PresentationSettings `json:"-"`
+	Math         MathSettings         `json:"-"`
+
 	Hash string `json:"hash"`
 
 	IsRepositoryItem bool
@@ -0,0 +1,20 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+// TagsOPML contains the outline of all tags of a repository and the items carrying them.
+type TagsOPML struct {
+	Title        string
+	DateModified string
+
+	Tags []OPMLOutline
+}
+
+// An OPMLOutline is a node of an OPML outline which links to the given URL.
+type OPMLOutline struct {
+	Text     string
+	URL      string
+	Children []OPMLOutline
+}
@@ -0,0 +1,22 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+// Pagination contains the navigation between the pages of a paged list.
+type Pagination struct {
+	Page      int `json:"page"`
+	PageCount int `json:"pageCount"`
+
+	PreviousURL string     `json:"previousURL"`
+	NextURL     string     `json:"nextURL"`
+	Pages       []PageLink `json:"pages"`
+}
+
+// PageLink is the link to a single page of a paged list.
+type PageLink struct {
+	Number    int    `json:"number"`
+	URL       string `json:"url"`
+	IsCurrent bool   `json:"isCurrent"`
+}
@@ -0,0 +1,37 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+import (
+	"encoding/json"
+)
+
+// PresentationSettings contains the keyboard shortcuts, the touch navigation, the progress indicator and the timer settings of presentations.
+type PresentationSettings struct {
+	GotoKey   int `json:"gotoKey"`
+	ToggleKey int `json:"toggleKey"`
+	NotesKey  int `json:"notesKey"`
+
+	SwipeEnabled  bool `json:"swipeEnabled"`
+	SwipeDistance int  `json:"swipeDistance"`
+
+	ShowProgress          bool `json:"showProgress"`
+	ProgressAlwaysVisible bool `json:"progressAlwaysVisible"`
+
+	ShowTimer     bool `json:"showTimer"`
+	TimerPauseKey int  `json:"timerPauseKey"`
+	Duration      int  `json:"duration"`
+	WarningTime   int  `json:"warningTime"`
+}
+
+// JSON returns the JSON representation of the presentation settings for the presentation script.
+func (settings PresentationSettings) JSON() string {
+	bytes, err := json.Marshal(settings)
+	if err != nil {
+		return "{}"
+	}
+
+	return string(bytes)
+}
@@ -0,0 +1,21 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+// SocialMetaData contains the properties of an item which are shown
+// when it is shared on social media (OpenGraph and Twitter Card meta tags).
+type SocialMetaData struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	// URL is the route of the item (e.g. "documents/sample").
+	URL string `json:"url"`
+
+	// Image is the route (e.g. "documents/sample/files/photo.jpg") or URL of the preview image.
+	Image string `json:"image"`
+
+	// TwitterCard is the Twitter Card type ("summary_large_image" for items with an image, otherwise "summary").
+	TwitterCard string `json:"twitterCard"`
+}
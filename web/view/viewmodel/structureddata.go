@@ -0,0 +1,41 @@
+// Copyright 2015 Andreas Koch. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package viewmodel
+
+// StructuredData is a schema.org object (e.g. an "Article" or a "Place")
+// which is embedded as JSON-LD into the head of the item page.
+// Properties which are not available are omitted.
+type StructuredData struct {
+	Context string `json:"@context"`
+	Type    string `json:"@type"`
+
+	// Article properties
+	Headline      string                `json:"headline,omitempty"`
+	DatePublished string                `json:"datePublished,omitempty"`
+	Author        *StructuredDataPerson `json:"author,omitempty"`
+
+	// Place properties
+	Name    string                        `json:"name,omitempty"`
+	Address string                        `json:"address,omitempty"`
+	Geo     *StructuredDataGeoCoordinates `json:"geo,omitempty"`
+
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Image       string `json:"image,omitempty"`
+}
+
+// StructuredDataPerson is a schema.org "Person" (e.g. the author of an article).
+type StructuredDataPerson struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// StructuredDataGeoCoordinates are the schema.org "GeoCoordinates" of a place.
+type StructuredDataGeoCoordinates struct {
+	Type      string  `json:"@type"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
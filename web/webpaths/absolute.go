@@ -28,6 +28,8 @@ func (webPathProvider *AbsoluteWebPathProvider) Path(itemPath string) string {
 		return itemPath
 	}
 
+	itemPath = toURLPath(itemPath)
+
 	// don't do it twice
 	if strings.HasPrefix(itemPath, webPathProvider.prefix) {
 		return itemPath
@@ -55,3 +55,19 @@ func Test_AbsoluteWebPathProvider_Path_ParameterIsAbsolute_ReturnsPathWithoutMod
 		t.Errorf("The result for pathProvider.Path(%q) with a prefix of %q should be %q but was %q.", inputPath, prefix, expected, result)
 	}
 }
+
+func Test_AbsoluteWebPathProvider_WindowsPath_Path_ReturnsPathWithForwardSlashes(t *testing.T) {
+	// arrange
+	prefix := "/"
+	pathProvider := newAbsoluteWebPathProvider(prefix)
+	inputPath := `guides\install\index.html`
+	expected := "/guides/install/index.html"
+
+	// act
+	result := pathProvider.Path(inputPath)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result for pathProvider.Path(%q) with a prefix of %q should be %q but was %q.", inputPath, prefix, expected, result)
+	}
+}
@@ -46,10 +46,12 @@ func (webPathProvider *RelativeWebPathProvider) Path(itemPath string) string {
 
 		// intersect the child route with the base route to get full path
 		path := strings.TrimPrefix(route.Value(), webPathProvider.baseRoute.Value())
-		return strings.TrimPrefix(path, "/")
+		return strings.TrimPrefix(toURLPath(path), "/")
 	}
 
-	return strings.TrimPrefix(strings.Replace(itemPath, webPathProvider.baseRoute.Value(), "", 1), "/")
+	// fallback: strip the base route from the supplied path (paths outside the base route are returned as they are)
+	relativePath, _ := stripBasePath(itemPath, webPathProvider.baseRoute.Value())
+	return relativePath
 
 }
 
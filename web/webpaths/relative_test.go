@@ -50,6 +50,27 @@ func Test_RelativeWebPathProvider_RootAsBasePath_Path_ParameterIsAbsolute_Return
 	}
 }
 
+func Test_RelativeWebPathProvider_WindowsPath_Path_ReturnsPathWithForwardSlashes(t *testing.T) {
+	// arrange
+	baseRoute := route.NewFromRequest("guides")
+	routes := getRoutesFromStrings([]string{
+		"",
+		"guides",
+	})
+	routesProvider := dummyRoutesProvider{routes}
+	pathProvider := newRelativeWebPathProvider(routesProvider, baseRoute)
+	inputPath := `guides\install\index.html`
+	expected := "install/index.html"
+
+	// act
+	result := pathProvider.Path(inputPath)
+
+	// assert
+	if result != expected {
+		t.Errorf("The result for pathProvider.Path(%q) should be %q but was %q.", inputPath, expected, result)
+	}
+}
+
 // Get an array of route.Route objects from a string array of URIs.
 func getRoutesFromStrings(uris []string) []route.Route {
 
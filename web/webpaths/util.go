@@ -6,6 +6,7 @@ package webpaths
 
 import (
 	"regexp"
+	"strings"
 )
 
 var (
@@ -18,3 +19,31 @@ func IsAbsoluteURI(uri string) bool {
 	uriHasProtocolPrefix := protocolPrefixPattern.MatchString(uri)
 	return uriHasProtocolPrefix
 }
+
+// toURLPath converts all backslashes (e.g. from Windows file paths) in the supplied path to forward slashes.
+func toURLPath(path string) string {
+	return strings.Replace(path, "\\", "/", -1)
+}
+
+// stripBasePath removes the supplied base path from the beginning of the given path.
+// Leading and trailing slashes are ignored and the comparison is case-insensitive (like route.IsMatch).
+// The base path must match complete path components ("guides" is a prefix of "guides/install" but not of "guidesbook").
+// If the base path is not a prefix of the path, the path is returned unchanged (without leading slashes) and isPrefix is false.
+func stripBasePath(path, basePath string) (relativePath string, isPrefix bool) {
+	path = strings.Trim(toURLPath(path), "/")
+	basePath = strings.Trim(toURLPath(basePath), "/")
+
+	if basePath == "" {
+		return path, true
+	}
+
+	if strings.EqualFold(path, basePath) {
+		return "", true
+	}
+
+	if len(path) > len(basePath) && strings.EqualFold(path[:len(basePath)], basePath) && path[len(basePath)] == '/' {
+		return path[len(basePath)+1:], true
+	}
+
+	return path, false
+}
@@ -85,3 +85,52 @@ func Test_IsAbsoluteURI_ParametersAreAbsoluteURLs_ResultIsTrue(t *testing.T) {
 		}
 	}
 }
+
+func Test_stripBasePath_TrailingSlashOnBasePath_BasePathIsRemoved(t *testing.T) {
+	// arrange
+	path := "/guides/install/index.html"
+	basePath := "guides/"
+	expected := "install/index.html"
+
+	// act
+	result, isPrefix := stripBasePath(path, basePath)
+
+	// assert
+	if !isPrefix || result != expected {
+		t.Errorf("stripBasePath(%q, %q) should return %q but returned %q (isPrefix: %t).", path, basePath, expected, result, isPrefix)
+	}
+}
+
+func Test_stripBasePath_MixedCaseBasePath_BasePathIsRemoved(t *testing.T) {
+	// arrange
+	path := "Guides/Install/index.html"
+	basePath := "/guides/install"
+	expected := "index.html"
+
+	// act
+	result, isPrefix := stripBasePath(path, basePath)
+
+	// assert
+	if !isPrefix || result != expected {
+		t.Errorf("stripBasePath(%q, %q) should return %q but returned %q (isPrefix: %t).", path, basePath, expected, result, isPrefix)
+	}
+}
+
+func Test_stripBasePath_BasePathIsNotAPrefix_PathIsReturnedUnchanged(t *testing.T) {
+	// arrange
+	inputs := map[string]string{
+		"documents/guides/install": "guides",
+		"guidesbook/install":       "guides",
+	}
+
+	for path, basePath := range inputs {
+
+		// act
+		result, isPrefix := stripBasePath(path, basePath)
+
+		// assert
+		if isPrefix || result != path {
+			t.Errorf("stripBasePath(%q, %q) should return the path unchanged but returned %q (isPrefix: %t).", path, basePath, result, isPrefix)
+		}
+	}
+}